@@ -2,6 +2,7 @@ package incus
 
 import (
 	"context"
+	"crypto"
 	"crypto/sha256"
 	"fmt"
 	"net/http"
@@ -65,6 +66,9 @@ type ConnectionArgs struct {
 	// Do not block for OIDC authentication
 	OIDCNonInteractive bool
 
+	// API token (used with the "token" authentication type)
+	APIToken string
+
 	// Skip the event listener endpoint
 	SkipGetEvents bool
 
@@ -77,6 +81,12 @@ type ConnectionArgs struct {
 
 	// Temp storage.
 	TempPath string
+
+	// Registry mirrors to try before the main server (OCI only)
+	Mirrors []string
+
+	// Public keys images must be signed with (simplestreams and OCI only)
+	VerifyKeys []crypto.PublicKey
 }
 
 // ConnectIncus lets you connect to a remote Incus daemon over HTTPs.
@@ -306,6 +316,10 @@ func ConnectSimpleStreams(uri string, args *ConnectionArgs) (ImageServer, error)
 	ssClient := simplestreams.NewClient(uri, *httpClient, args.UserAgent)
 	server.ssClient = ssClient
 
+	if len(args.VerifyKeys) > 0 {
+		ssClient.SetVerifyKeys(args.VerifyKeys)
+	}
+
 	// Setup the cache
 	if args.CachePath != "" {
 		if !util.PathExists(args.CachePath) {
@@ -353,9 +367,21 @@ func ConnectOCI(uri string, args *ConnectionArgs) (ImageServer, error) {
 		httpUserAgent:   args.UserAgent,
 		httpCertificate: args.TLSServerCert,
 
-		cache:    map[string]ociInfo{},
-		errors:   map[string]error{},
-		tempPath: args.TempPath,
+		cache:      map[string]ociInfo{},
+		errors:     map[string]error{},
+		mirrors:    args.Mirrors,
+		tokens:     map[string]string{},
+		tempPath:   args.TempPath,
+		verifyKeys: args.VerifyKeys,
+	}
+
+	// Setup the layer cache, shared by all registries as it's content-addressed.
+	if args.CachePath != "" {
+		if !util.PathExists(args.CachePath) {
+			return nil, fmt.Errorf("Cache directory %q doesn't exist", args.CachePath)
+		}
+
+		server.blobPath = filepath.Join(args.CachePath, "oci-blobs")
 	}
 
 	// Setup the HTTP client
@@ -398,10 +424,14 @@ func httpsIncus(ctx context.Context, requestURL string, args *ConnectionArgs) (I
 		tempPath:           args.TempPath,
 	}
 
-	if slices.Contains([]string{api.AuthenticationMethodOIDC}, args.AuthType) {
+	if slices.Contains([]string{api.AuthenticationMethodOIDC, api.AuthenticationMethodToken}, args.AuthType) {
 		server.RequireAuthenticated(true)
 	}
 
+	if args.AuthType == api.AuthenticationMethodToken {
+		server.apiToken = args.APIToken
+	}
+
 	// Setup the HTTP client
 	httpClient, err := tlsHTTPClient(args.HTTPClient, args.TLSClientCert, args.TLSClientKey, args.TLSCA, args.TLSServerCert, args.InsecureSkipVerify, args.IdenticalCertificate, args.Proxy, args.TransportWrapper)
 	if err != nil {
@@ -0,0 +1,299 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// GetAuthGroupNames returns a list of authorization group names.
+func (r *ProtocolIncus) GetAuthGroupNames() ([]string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/auth/groups"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetAuthGroups returns a list of authorization group structs.
+func (r *ProtocolIncus) GetAuthGroups() ([]api.AuthGroup, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	groups := []api.AuthGroup{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns an authorization group entry.
+func (r *ProtocolIncus) GetAuthGroup(name string) (*api.AuthGroup, string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, "", errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	group := api.AuthGroup{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateAuthGroup defines a new authorization group using the provided struct.
+func (r *ProtocolIncus) CreateAuthGroup(group api.AuthGroupsPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/auth/groups", group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthGroup updates the authorization group to match the provided struct.
+func (r *ProtocolIncus) UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames an existing authorization group entry.
+func (r *ProtocolIncus) RenameAuthGroup(name string, group api.AuthGroupPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthGroup deletes an existing authorization group.
+func (r *ProtocolIncus) DeleteAuthGroup(name string) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetIdentities returns a list of identity structs.
+func (r *ProtocolIncus) GetIdentities() ([]api.Identity, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	identities := []api.Identity{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/identities?recursion=1", nil, "", &identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetIdentity returns an identity entry.
+func (r *ProtocolIncus) GetIdentity(authenticationMethod string, identifier string) (*api.Identity, string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, "", errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	identity := api.Identity{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), nil, "", &identity)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &identity, etag, nil
+}
+
+// CreateIdentity defines a new identity using the provided struct.
+func (r *ProtocolIncus) CreateIdentity(identity api.IdentitiesPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/auth/identities", identity, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateIdentity updates the identity to match the provided struct.
+func (r *ProtocolIncus) UpdateIdentity(authenticationMethod string, identifier string, identity api.IdentityPut, ETag string) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), identity, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIdentity deletes an existing identity.
+func (r *ProtocolIncus) DeleteIdentity(authenticationMethod string, identifier string) error {
+	if !r.HasExtension("auth_builtin") {
+		return errors.New(`The server is missing the required "auth_builtin" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthTokenNames returns a list of API token names.
+func (r *ProtocolIncus) GetAuthTokenNames() ([]string, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/auth/tokens"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetAuthTokens returns a list of API token structs.
+func (r *ProtocolIncus) GetAuthTokens() ([]api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	tokens := []api.AuthToken{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/tokens?recursion=1", nil, "", &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns an API token entry.
+func (r *ProtocolIncus) GetAuthToken(name string) (*api.AuthToken, string, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, "", errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	token := api.AuthToken{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "", &token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &token, etag, nil
+}
+
+// CreateAuthToken defines a new API token using the provided struct and returns its secret.
+func (r *ProtocolIncus) CreateAuthToken(token api.AuthTokensPost) (*api.AuthTokenSecret, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	secret := api.AuthTokenSecret{}
+
+	// Send the request.
+	_, err := r.queryStruct("POST", "/auth/tokens", token, "", &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// UpdateAuthToken updates the API token to match the provided struct.
+func (r *ProtocolIncus) UpdateAuthToken(name string, token api.AuthTokenPut, ETag string) error {
+	if !r.HasExtension("auth_tokens") {
+		return errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), token, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthToken revokes an existing API token.
+func (r *ProtocolIncus) DeleteAuthToken(name string) error {
+	if !r.HasExtension("auth_tokens") {
+		return errors.New(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -349,3 +349,46 @@ func (r *ProtocolIncus) GetClusterGroup(name string) (*api.ClusterGroup, string,
 
 	return &group, etag, nil
 }
+
+// GetClusterRebalancePlan returns the instance migrations that cluster re-balancing would perform.
+func (r *ProtocolIncus) GetClusterRebalancePlan() (*api.ClusterRebalancePlan, error) {
+	if !r.HasExtension("cluster_rebalance_scriptlet") {
+		return nil, errors.New("The server is missing the required \"cluster_rebalance_scriptlet\" API extension")
+	}
+
+	plan := api.ClusterRebalancePlan{}
+	_, err := r.queryStruct("GET", "/cluster/rebalance", nil, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// RebalanceCluster re-balances the cluster now.
+func (r *ProtocolIncus) RebalanceCluster() (Operation, error) {
+	if !r.HasExtension("cluster_rebalance_scriptlet") {
+		return nil, errors.New("The server is missing the required \"cluster_rebalance_scriptlet\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/rebalance", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpgradeCluster performs a rolling upgrade of the cluster members.
+func (r *ProtocolIncus) UpgradeCluster(upgrade api.ClusterUpgradePost) (Operation, error) {
+	if !r.HasExtension("cluster_rolling_upgrade") {
+		return nil, errors.New("The server is missing the required \"cluster_rolling_upgrade\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/upgrade", upgrade, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -1083,6 +1083,10 @@ func (r *ProtocolIncus) ExportImage(fingerprint string, image api.ImageExportPos
 		return nil, errors.New("The server is missing the required \"images_push_relay\" API extension")
 	}
 
+	if image.Protocol == "oci" && !r.HasExtension("image_export_oci") {
+		return nil, errors.New("The server is missing the required \"image_export_oci\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/images/%s/export", url.PathEscape(fingerprint)), &image, "")
 	if err != nil {
@@ -2614,6 +2614,10 @@ func (r *ProtocolIncus) ConsoleInstance(instanceName string, console api.Instanc
 		return nil, errors.New(`The server is missing the required "console_force" API extension`)
 	}
 
+	if console.Device != "" && !r.HasExtension("instance_serial_device") {
+		return nil, errors.New(`The server is missing the required "instance_serial_device" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/console", path, url.PathEscape(instanceName)), console, "")
 	if err != nil {
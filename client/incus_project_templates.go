@@ -0,0 +1,121 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// GetProjectTemplateNames returns a list of project template names.
+func (r *ProtocolIncus) GetProjectTemplateNames() ([]string, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/project-templates"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetProjectTemplates returns a list of project template structs.
+func (r *ProtocolIncus) GetProjectTemplates() ([]api.ProjectTemplate, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	templates := []api.ProjectTemplate{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/project-templates?recursion=1", nil, "", &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetProjectTemplate returns a project template entry.
+func (r *ProtocolIncus) GetProjectTemplate(name string) (*api.ProjectTemplate, string, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, "", errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	template := api.ProjectTemplate{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), nil, "", &template)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &template, etag, nil
+}
+
+// CreateProjectTemplate defines a new project template using the provided struct.
+func (r *ProtocolIncus) CreateProjectTemplate(template api.ProjectTemplatesPost) error {
+	if !r.HasExtension("projects_templates") {
+		return errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/project-templates", template, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProjectTemplate updates the project template to match the provided struct.
+func (r *ProtocolIncus) UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) error {
+	if !r.HasExtension("projects_templates") {
+		return errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), template, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameProjectTemplate renames an existing project template entry.
+func (r *ProtocolIncus) RenameProjectTemplate(name string, template api.ProjectTemplatePost) error {
+	if !r.HasExtension("projects_templates") {
+		return errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), template, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProjectTemplate deletes an existing project template.
+func (r *ProtocolIncus) DeleteProjectTemplate(name string) error {
+	if !r.HasExtension("projects_templates") {
+		return errors.New(`The server is missing the required "projects_templates" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
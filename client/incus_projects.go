@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v7/shared/api"
 )
@@ -99,6 +100,36 @@ func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error)
 	return &projectState, nil
 }
 
+// GetProjectUsage returns the historical resource usage of a project.
+// Zero times and an empty resolution let the server pick its defaults.
+func (r *ProtocolIncus) GetProjectUsage(name string, resolution string, since time.Time, until time.Time) (*api.ProjectUsage, error) {
+	if !r.HasExtension("project_usage_history") {
+		return nil, errors.New("The server is missing the required \"project_usage_history\" API extension")
+	}
+
+	u := api.NewURL().Path("projects", name, "usage")
+	if resolution != "" {
+		u = u.WithQuery("resolution", resolution)
+	}
+
+	if !since.IsZero() {
+		u = u.WithQuery("since", since.Format(time.RFC3339))
+	}
+
+	if !until.IsZero() {
+		u = u.WithQuery("until", until.Format(time.RFC3339))
+	}
+
+	usage := api.ProjectUsage{}
+
+	_, err := r.queryStruct("GET", u.String(), nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
 // GetProjectAccess returns an Access entry for the specified project.
 func (r *ProtocolIncus) GetProjectAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -122,6 +153,10 @@ func (r *ProtocolIncus) CreateProject(project api.ProjectsPost) error {
 		return errors.New("The server is missing the required \"projects\" API extension")
 	}
 
+	if project.Template != "" && !r.HasExtension("projects_templates") {
+		return errors.New("The server is missing the required \"projects_templates\" API extension")
+	}
+
 	// Send the request
 	_, _, err := r.query("POST", "/projects", project, "")
 	if err != nil {
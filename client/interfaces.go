@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -70,6 +71,14 @@ type ImageServer interface {
 	ExportImage(fingerprint string, image api.ImageExportPost) (Operation, error)
 }
 
+// The OCIServer type represents an OCI registry.
+type OCIServer interface {
+	ImageServer
+
+	// Image upload functions
+	PushImage(names []string, args *OCIImagePushArgs) error
+}
+
 // The InstanceServer type represents a full featured Incus server.
 type InstanceServer interface {
 	ImageServer
@@ -86,6 +95,26 @@ type InstanceServer interface {
 	UseTarget(name string) (client InstanceServer)
 	UseProject(name string) (client InstanceServer)
 
+	// Authorization functions
+	GetAuthGroupNames() (names []string, err error)
+	GetAuthGroups() (groups []api.AuthGroup, err error)
+	GetAuthGroup(name string) (group *api.AuthGroup, ETag string, err error)
+	CreateAuthGroup(group api.AuthGroupsPost) (err error)
+	UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) (err error)
+	RenameAuthGroup(name string, group api.AuthGroupPost) (err error)
+	DeleteAuthGroup(name string) (err error)
+	GetIdentities() (identities []api.Identity, err error)
+	GetIdentity(authenticationMethod string, identifier string) (identity *api.Identity, ETag string, err error)
+	CreateIdentity(identity api.IdentitiesPost) (err error)
+	UpdateIdentity(authenticationMethod string, identifier string, identity api.IdentityPut, ETag string) (err error)
+	DeleteIdentity(authenticationMethod string, identifier string) (err error)
+	GetAuthTokenNames() (names []string, err error)
+	GetAuthTokens() (tokens []api.AuthToken, err error)
+	GetAuthToken(name string) (token *api.AuthToken, ETag string, err error)
+	CreateAuthToken(token api.AuthTokensPost) (secret *api.AuthTokenSecret, err error)
+	UpdateAuthToken(name string, token api.AuthTokenPut, ETag string) (err error)
+	DeleteAuthToken(name string) (err error)
+
 	// Certificate functions
 	GetCertificateFingerprints() (fingerprints []string, err error)
 	GetCertificates() (certificates []api.Certificate, err error)
@@ -316,6 +345,7 @@ type InstanceServer interface {
 	GetProjectsWithFilter(filters []string) (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	GetProjectUsage(name string, resolution string, since time.Time, until time.Time) (usage *api.ProjectUsage, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
@@ -323,6 +353,15 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Project template functions ("projects_templates" API extension)
+	GetProjectTemplateNames() (names []string, err error)
+	GetProjectTemplates() (templates []api.ProjectTemplate, err error)
+	GetProjectTemplate(name string) (template *api.ProjectTemplate, ETag string, err error)
+	CreateProjectTemplate(template api.ProjectTemplatesPost) (err error)
+	UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) (err error)
+	RenameProjectTemplate(name string, template api.ProjectTemplatePost) (err error)
+	DeleteProjectTemplate(name string) (err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
@@ -451,6 +490,9 @@ type InstanceServer interface {
 	DeleteClusterGroup(name string) error
 	UpdateClusterGroup(name string, group api.ClusterGroupPut, ETag string) error
 	GetClusterGroup(name string) (*api.ClusterGroup, string, error)
+	GetClusterRebalancePlan() (plan *api.ClusterRebalancePlan, err error)
+	RebalanceCluster() (op Operation, err error)
+	UpgradeCluster(upgrade api.ClusterUpgradePost) (op Operation, err error)
 
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
@@ -547,6 +589,30 @@ type ImageFileResponse struct {
 
 	// Size of the rootfs file
 	RootfsSize int64
+
+	// Fingerprint of the image the rootfs delta was applied to (empty if the whole rootfs was downloaded)
+	DeltaSource string
+
+	// Size of the downloaded rootfs delta
+	DeltaSize int64
+}
+
+// The OCIImagePushArgs struct is used for pushing an image to an OCI registry.
+type OCIImagePushArgs struct {
+	// Architecture of the image
+	Architecture string
+
+	// Reader for the uncompressed root filesystem tarball
+	Rootfs io.Reader
+
+	// Process configuration of the image
+	Entrypoint []string
+	Env        []string
+	WorkingDir string
+	User       string
+
+	// Progress handler (called whenever some progress is made)
+	ProgressHandler func(progress ioprogress.ProgressData)
 }
 
 // The ImageCopyArgs struct is used to pass additional options during image copy.
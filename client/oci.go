@@ -1,6 +1,7 @@
 package incus
 
 import (
+	"crypto"
 	"errors"
 	"net/http"
 )
@@ -18,6 +19,18 @@ type ProtocolOCI struct {
 	// Error tracking for images.
 	errors map[string]error
 
+	// Registry mirrors to try first.
+	mirrors []string
+
+	// Authorization headers by registry and repository.
+	tokens map[string]string
+
+	// Content-addressed blob cache.
+	blobPath string
+
+	// Public keys images must be signed with.
+	verifyKeys []crypto.PublicKey
+
 	tempPath string
 }
 
@@ -1,22 +1,22 @@
 package incus
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/ioprogress"
 	"github.com/lxc/incus/v7/shared/logger"
@@ -28,29 +28,14 @@ import (
 
 type ociInfo struct {
 	Alias        string
-	Name         string    `json:"Name"`
-	Digest       string    `json:"Digest"`
-	Created      time.Time `json:"Created"`
-	Architecture string    `json:"Architecture"`
-	Layers       []string  `json:"Layers"`
-	LayersData   []struct {
-		Size int64 `json:"Size"`
-	} `json:"LayersData"`
-}
-
-// Get the proxy host value.
-func (r *ProtocolOCI) getProxyHost() (*url.URL, error) {
-	req, err := http.NewRequest("GET", r.httpHost, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	proxy, err := r.http.Transport.(*http.Transport).Proxy(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return proxy, nil
+	Name         string
+	Repository   string
+	Digest       string
+	Created      time.Time
+	Architecture string
+	Manifest     digest.Digest
+	Config       ispec.Descriptor
+	Layers       []ispec.Descriptor
 }
 
 // Image handling functions
@@ -84,11 +69,6 @@ func (r *ProtocolOCI) GetImagesWithFilter(_ []string) ([]api.Image, error) {
 func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
 	info, ok := r.cache[fingerprint]
 	if !ok {
-		_, err := exec.LookPath("skopeo")
-		if err != nil {
-			return nil, "", errors.New("OCI container handling requires \"skopeo\" be present on the system")
-		}
-
 		err, ok := r.errors[fingerprint]
 		if ok {
 			return nil, "", err
@@ -118,7 +98,7 @@ func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
 	}
 
 	var size int64
-	for _, layer := range info.LayersData {
+	for _, layer := range info.Layers {
 		size += layer.Size
 	}
 
@@ -134,11 +114,6 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 	// Get the cached entry.
 	info, ok := r.cache[fingerprint]
 	if !ok {
-		_, err := exec.LookPath("skopeo")
-		if err != nil {
-			return nil, errors.New("OCI container handling requires \"skopeo\" be present on the system")
-		}
-
 		err, ok := r.errors[fingerprint]
 		if ok {
 			return nil, err
@@ -174,23 +149,30 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 		return nil, err
 	}
 
-	// Copy the image.
+	// Retrieve the image.
 	if req.ProgressHandler != nil {
 		req.ProgressHandler(ioprogress.ProgressData{Text: "Retrieving OCI image from registry"})
 	}
 
 	imageTag := "latest"
 
-	stdout, err := r.runSkopeo(
-		"copy", info.Alias,
-		"--remove-signatures",
-		fmt.Sprintf("oci:%s:%s", filepath.Join(ociPath, "oci"), imageTag),
-	)
+	// Without a cache, blobs are stored alongside the temporary image.
+	storePath := r.blobPath
+	if storePath == "" {
+		storePath = filepath.Join(ociPath, "blobs")
+	}
+
+	err = r.ociDownloadImage(ctx, info, storePath, filepath.Join(ociPath, "oci"), imageTag, req.ProgressHandler)
 	if err != nil {
-		logger.Debug("Error copying remote image to local", logger.Ctx{"image": info.Alias, "stdout": stdout, "stderr": err})
+		logger.Debug("Error retrieving remote image", logger.Ctx{"image": info.Alias, "err": err})
 		return nil, err
 	}
 
+	err = r.ociPruneBlobs()
+	if err != nil {
+		logger.Warn("Failed to prune the OCI blob cache", logger.Ctx{"err": err})
+	}
+
 	// Convert to something usable.
 	if req.ProgressHandler != nil {
 		req.ProgressHandler(ioprogress.ProgressData{Text: "Unpacking the OCI image"})
@@ -332,141 +314,67 @@ func (r *ProtocolOCI) GetImageAliasNames() ([]string, error) {
 	return nil, errors.New("Can't list image aliases from OCI registry")
 }
 
-func (r *ProtocolOCI) runSkopeo(action string, image string, args ...string) (string, error) {
-	// Parse and mangle the server URL.
-	uri, err := url.Parse(r.httpHost)
-	if err != nil {
-		return "", err
-	}
-
-	// Get proxy details.
-	proxy, err := r.getProxyHost()
-	if err != nil {
-		return "", err
-	}
-
-	var env []string
-	if proxy != nil {
-		env = []string{
-			fmt.Sprintf("HTTPS_PROXY=%s", proxy),
-			fmt.Sprintf("HTTP_PROXY=%s", proxy),
-		}
-	}
-
-	// Handle authentication.
-	if uri.User != nil {
-		creds, err := json.Marshal(map[string]any{
-			"auths": map[string]any{
-				uri.Scheme + "://" + uri.Host: map[string]string{
-					"auth": base64.StdEncoding.EncodeToString([]byte(uri.User.String())),
-				},
-			},
-		})
-		if err != nil {
-			return "", err
-		}
-
-		authFile, err := os.CreateTemp(r.tempPath, "incus_client_auth_")
-		if err != nil {
-			return "", err
-		}
-
-		defer logger.WarnOnError(authFile.Close, "Failed to close auth file")
-		defer os.Remove(authFile.Name())
-
-		err = authFile.Chmod(0o600)
-		if err != nil {
-			return "", err
-		}
-
-		_, err = fmt.Fprintf(authFile, "%s", creds)
-		if err != nil {
-			return "", err
-		}
-
-		uri.User = nil
-
-		args = append(args, fmt.Sprintf("--authfile=%s", authFile.Name()))
-	}
-
-	// Prepare the arguments.
-	uri.Scheme = "docker"
-	args = append([]string{"--insecure-policy", action, fmt.Sprintf("%s/%s", uri.String(), image)}, args...)
-
-	// Get the image information from skopeo.
-	stdout, _, err := subprocess.RunCommandSplit(
-		context.TODO(),
-		env,
-		nil,
-		"skopeo",
-		args...,
-	)
-	if err != nil {
-		return "", err
-	}
-
-	return stdout, nil
-}
-
 // GetImageAlias returns an existing alias as an ImageAliasesEntry struct.
 func (r *ProtocolOCI) GetImageAlias(name string) (*api.ImageAliasesEntry, string, error) {
-	// If image name is "IMAGE:TAG@HASH", drop ":TAG" so that skopeo uses the pinned hash instead.
-	imageWithoutHash, hash, hasHash := strings.Cut(name, "@")
-	if hasHash {
-		imageWithoutTag, _, _ := strings.Cut(imageWithoutHash, ":")
-		name = fmt.Sprintf("%s@%s", imageWithoutTag, hash)
-	}
-
-	// Get the image information from skopeo.
-	stdout, err := r.runSkopeo("inspect", name, "--no-tags")
+	images, err := r.ociResolve(context.Background(), name)
 	if err != nil {
-		logger.Debug("Error getting image alias", logger.Ctx{"name": name, "stdout": stdout, "stderr": err})
+		logger.Debug("Error getting image alias", logger.Ctx{"name": name, "err": err})
 		r.errors[name] = err
 
 		return nil, "", err
 	}
 
-	// Parse the image info.
-	var info ociInfo
-	err = json.Unmarshal([]byte(stdout), &info)
+	// Pick the image for the local architecture, or for one it can run.
+	info, err := r.ociLocalImage(images)
 	if err != nil {
 		r.errors[name] = err
 
 		return nil, "", err
 	}
 
-	info.Alias = name
-	info.Digest = r.computeFingerprint(info.Layers)
+	return r.ociAliasEntry(name, info), "", nil
+}
 
-	archID, err := osarch.ArchitectureID(info.Architecture)
+// ociLocalImage returns the image matching the local architecture or one of its personalities.
+func (r *ProtocolOCI) ociLocalImage(images map[string]ociInfo) (ociInfo, error) {
+	localArchID, err := osarch.ArchitectureGetLocalID()
 	if err != nil {
-		r.errors[name] = err
-
-		return nil, "", err
+		return ociInfo{}, err
 	}
 
-	archName, err := osarch.ArchitectureName(archID)
+	personalities, err := osarch.ArchitecturePersonalities(localArchID)
 	if err != nil {
-		r.errors[name] = err
+		return ociInfo{}, err
+	}
 
-		return nil, "", err
+	for _, archID := range append([]int{localArchID}, personalities...) {
+		archName, err := osarch.ArchitectureName(archID)
+		if err != nil {
+			continue
+		}
+
+		info, ok := images[archName]
+		if ok {
+			return info, nil
+		}
 	}
 
-	info.Architecture = archName
+	return ociInfo{}, errors.New("No image available for the local architecture")
+}
 
+// ociAliasEntry caches an image and returns the alias entry pointing to it.
+func (r *ProtocolOCI) ociAliasEntry(name string, info ociInfo) *api.ImageAliasesEntry {
 	// Store it in the cache.
 	r.cache[info.Digest] = info
 
 	// Prepare the alias entry.
-	alias := api.ImageAliasesEntry{
+	return &api.ImageAliasesEntry{
 		ImageAliasesEntryPut: api.ImageAliasesEntryPut{
 			Target: info.Digest,
 		},
 		Name: name,
 		Type: string(api.InstanceTypeContainer),
 	}
-
-	return &alias, "", nil
 }
 
 // GetImageAliasType returns an existing alias as an ImageAliasesEntry struct.
@@ -484,17 +392,19 @@ func (r *ProtocolOCI) GetImageAliasArchitectures(imageType string, name string)
 		return nil, errors.New("OCI images are only supported for containers")
 	}
 
-	alias, _, err := r.GetImageAlias(name)
+	images, err := r.ociResolve(context.Background(), name)
 	if err != nil {
+		r.errors[name] = err
+
 		return nil, err
 	}
 
-	localArch, err := osarch.ArchitectureGetLocal()
-	if err != nil {
-		return nil, err
+	aliases := make(map[string]*api.ImageAliasesEntry, len(images))
+	for archName, info := range images {
+		aliases[archName] = r.ociAliasEntry(name, info)
 	}
 
-	return map[string]*api.ImageAliasesEntry{localArch: alias}, nil
+	return aliases, nil
 }
 
 // ExportImage exports (copies) an image to a remote server.
@@ -502,6 +412,158 @@ func (r *ProtocolOCI) ExportImage(_ string, _ api.ImageExportPost) (Operation, e
 	return nil, errors.New("Exporting images is not supported with OCI registry")
 }
 
+// PushImage builds an OCI image from a root filesystem and uploads it to the registry under the given names.
+func (r *ProtocolOCI) PushImage(names []string, args *OCIImagePushArgs) error {
+	ctx := context.Background()
+
+	if len(names) == 0 {
+		return errors.New("No image name provided")
+	}
+
+	if args == nil || args.Rootfs == nil {
+		return errors.New("No root filesystem provided")
+	}
+
+	architecture, variant, err := ociPlatform(args.Architecture)
+	if err != nil {
+		return err
+	}
+
+	refs := make([]*ociReference, 0, len(names))
+	for _, name := range names {
+		ref, err := r.parseOCIReference(name)
+		if err != nil {
+			return err
+		}
+
+		if ref.digest != "" {
+			return fmt.Errorf("Can't push image %q to a digest", name)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	// Compress the root filesystem into a layer, computing both its digests in one pass.
+	layerFile, err := os.CreateTemp(r.tempPath, "incus-oci-layer-")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(layerFile.Name()) }()
+	defer logger.WarnOnError(layerFile.Close, "Failed to close layer file")
+
+	var rootfs io.Reader = args.Rootfs
+	if args.ProgressHandler != nil {
+		rootfs = &ioprogress.ProgressReader{
+			Reader: args.Rootfs,
+			Tracker: &ioprogress.ProgressTracker{
+				Handler: func(received int64, speed int64) {
+					args.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("Generating image layer: %s (%s/s)", units.GetByteSizeString(received, 2), units.GetByteSizeString(speed, 2))})
+				},
+			},
+		}
+	}
+
+	diffID := digest.SHA256.Digester()
+	layerDigest := digest.SHA256.Digester()
+
+	compressWrite := gzip.NewWriter(io.MultiWriter(layerFile, layerDigest.Hash()))
+
+	_, err = io.Copy(io.MultiWriter(compressWrite, diffID.Hash()), rootfs)
+	if err != nil {
+		return fmt.Errorf("Failed generating image layer: %w", err)
+	}
+
+	err = compressWrite.Close()
+	if err != nil {
+		return err
+	}
+
+	layerSize, err := layerFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	layer := ispec.Descriptor{
+		MediaType: ispec.MediaTypeImageLayerGzip,
+		Digest:    layerDigest.Digest(),
+		Size:      layerSize,
+	}
+
+	// Generate the image configuration and manifest.
+	created := time.Now().UTC()
+	config := ispec.Image{
+		Created: &created,
+		Platform: ispec.Platform{
+			Architecture: architecture,
+			OS:           "linux",
+			Variant:      variant,
+		},
+		Config: ispec.ImageConfig{
+			User:       args.User,
+			Env:        args.Env,
+			Entrypoint: args.Entrypoint,
+			WorkingDir: args.WorkingDir,
+		},
+		RootFS: ispec.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{diffID.Digest()},
+		},
+	}
+
+	configContent, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	manifest := ispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageManifest,
+		Config: ispec.Descriptor{
+			MediaType: ispec.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(configContent),
+			Size:      int64(len(configContent)),
+		},
+		Layers: []ispec.Descriptor{layer},
+	}
+
+	manifestContent, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	// Images are pushed to the registry itself, never to its mirrors.
+	endpoints, err := r.ociEndpoints()
+	if err != nil {
+		return err
+	}
+
+	endpoint := endpoints[len(endpoints)-1]
+
+	for _, ref := range refs {
+		if args.ProgressHandler != nil {
+			args.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("Uploading image %s:%s (%s)", ref.repository, ref.tag, units.GetByteSizeString(layerSize, 2))})
+		}
+
+		err = r.ociPushBlob(ctx, endpoint, ref.repository, layer, layerFile)
+		if err != nil {
+			return err
+		}
+
+		err = r.ociPushBlob(ctx, endpoint, ref.repository, manifest.Config, bytes.NewReader(configContent))
+		if err != nil {
+			return err
+		}
+
+		err = r.ociPushManifest(ctx, endpoint, ref.repository, ref.tag, manifestContent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *ProtocolOCI) computeFingerprint(layers []string) string {
 	h := sha256.New()
 
@@ -0,0 +1,1064 @@
+package incus
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/lxc/incus/v7/shared/ioprogress"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/osarch"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+	"github.com/lxc/incus/v7/shared/units"
+)
+
+// Docker media types which are converted to their OCI equivalent.
+const (
+	ociDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	ociDockerConfig       = "application/vnd.docker.container.image.v1+json"
+	ociDockerLayerGzip    = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+	ociDockerLayerTar     = "application/vnd.docker.image.rootfs.diff.tar"
+)
+
+// Cosign signature media type and annotation.
+const (
+	ociCosignPayload   = "application/vnd.dev.cosign.simplesigning.v1+json"
+	ociCosignSignature = "dev.cosignproject.cosign/signature"
+)
+
+// The Docker Hub serves its registry API from a different host.
+const (
+	ociDockerHubRegistry = "docker.io"
+	ociDockerHubEndpoint = "registry-1.docker.io"
+)
+
+// ociBlobCacheExpiry is how long unused blobs are kept in the layer cache.
+const ociBlobCacheExpiry = 30 * 24 * time.Hour
+
+// ociMaxManifestSize is the largest manifest, configuration or token response accepted.
+const ociMaxManifestSize = 4 * 1024 * 1024
+
+// ociManifestAccept lists the manifest media types understood by the client.
+var ociManifestAccept = strings.Join([]string{
+	ispec.MediaTypeImageIndex,
+	ispec.MediaTypeImageManifest,
+	ociDockerManifestList,
+	ociDockerManifest,
+}, ", ")
+
+// ociReference is a parsed image reference.
+type ociReference struct {
+	repository string
+	tag        string
+	digest     digest.Digest
+}
+
+// reference returns the tag or digest to request the manifest for.
+func (ref ociReference) reference() string {
+	if ref.digest != "" {
+		return ref.digest.String()
+	}
+
+	return ref.tag
+}
+
+// ociEndpoint is a registry endpoint, either the registry itself or one of its mirrors.
+type ociEndpoint struct {
+	url      *url.URL
+	username string
+	password string
+}
+
+// parseOCIReference parses an image reference in the IMAGE[:TAG][@DIGEST] form.
+func (r *ProtocolOCI) parseOCIReference(name string) (*ociReference, error) {
+	ref := ociReference{}
+
+	// Digests take precedence over tags.
+	name, pinned, hasDigest := strings.Cut(name, "@")
+	if hasDigest {
+		d, err := digest.Parse(pinned)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid image digest %q: %w", pinned, err)
+		}
+
+		ref.digest = d
+	}
+
+	// The tag separator is the last colon after the last slash.
+	ref.repository = name
+	idx := strings.LastIndex(name, ":")
+	if idx > strings.LastIndex(name, "/") {
+		ref.repository = name[:idx]
+		ref.tag = name[idx+1:]
+	}
+
+	if ref.tag == "" {
+		ref.tag = "latest"
+	}
+
+	if ref.repository == "" {
+		return nil, errors.New("Missing image name")
+	}
+
+	// Official images on the Docker Hub live in the "library" namespace.
+	uri, err := url.Parse(r.httpHost)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.isDockerHub(uri.Host) && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+
+	return &ref, nil
+}
+
+// isDockerHub returns whether the host is the Docker Hub.
+func (r *ProtocolOCI) isDockerHub(host string) bool {
+	return host == ociDockerHubRegistry || host == "index.docker.io" || host == ociDockerHubEndpoint
+}
+
+// ociEndpoints returns the endpoints to try in order, mirrors first.
+func (r *ProtocolOCI) ociEndpoints() ([]ociEndpoint, error) {
+	endpoints := make([]ociEndpoint, 0, len(r.mirrors)+1)
+
+	for _, address := range append(append([]string{}, r.mirrors...), r.httpHost) {
+		uri, err := url.Parse(strings.TrimSuffix(address, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid registry address %q: %w", address, err)
+		}
+
+		endpoint := ociEndpoint{url: uri}
+		if uri.User != nil {
+			endpoint.username = uri.User.Username()
+			endpoint.password, _ = uri.User.Password()
+			uri.User = nil
+		}
+
+		if r.isDockerHub(uri.Host) {
+			uri.Host = ociDockerHubEndpoint
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// ociParseChallenge parses the parameters of a WWW-Authenticate header.
+func ociParseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+
+	for rest != "" {
+		rest = strings.TrimLeft(rest, ", ")
+
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, "\"") {
+			end := strings.Index(value[1:], "\"")
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+// ociGetToken retrieves a bearer token following a registry authentication challenge.
+func (r *ProtocolOCI) ociGetToken(ctx context.Context, endpoint ociEndpoint, repository string, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("Invalid authentication realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+
+	service := params["service"]
+	if service == "" {
+		service = "registry"
+	}
+
+	query.Set("service", service)
+
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", repository)
+	}
+
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if endpoint.username != "" {
+		req.SetBasicAuth(endpoint.username, endpoint.password)
+	}
+
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer logger.WarnOnError(resp.Body.Close, "Failed to close response body")
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to get registry token: %s", resp.Status)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, ociMaxManifestSize)).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse registry token: %w", err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+
+	return "", errors.New("Registry didn't return a token")
+}
+
+// ociURL returns the URL of a registry API path for a repository.
+func ociURL(endpoint ociEndpoint, repository string, path string) string {
+	return fmt.Sprintf("%s/v2/%s/%s", endpoint.url.String(), repository, path)
+}
+
+// ociRequest performs a registry API request against an endpoint, handling authentication.
+func (r *ProtocolOCI) ociRequest(ctx context.Context, endpoint ociEndpoint, repository string, method string, requestURL string, header http.Header, body io.ReadSeeker) (*http.Response, error) {
+	tokenKey := endpoint.url.Host + "/" + repository
+
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(req.Header, header)
+
+		// Rewind the body so it can be sent again after authenticating.
+		if body != nil {
+			size, err := body.Seek(0, io.SeekEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = body.Seek(0, io.SeekStart)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = io.NopCloser(body)
+			req.ContentLength = size
+		}
+
+		token, ok := r.tokens[tokenKey]
+		if ok {
+			req.Header.Set("Authorization", token)
+		}
+
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	// Authenticate and try again.
+	_ = resp.Body.Close()
+
+	scheme, params := ociParseChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "bearer":
+		token, err := r.ociGetToken(ctx, endpoint, repository, params)
+		if err != nil {
+			return nil, err
+		}
+
+		r.tokens[tokenKey] = "Bearer " + token
+	case "basic":
+		if endpoint.username == "" {
+			return nil, errors.New("Registry requires authentication")
+		}
+
+		req.SetBasicAuth(endpoint.username, endpoint.password)
+		r.tokens[tokenKey] = req.Header.Get("Authorization")
+	default:
+		return nil, fmt.Errorf("Unsupported registry authentication scheme %q", scheme)
+	}
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DoHTTP(req)
+}
+
+// ociFetch performs a registry API request, trying the mirrors before the registry itself.
+func (r *ProtocolOCI) ociFetch(ctx context.Context, repository string, path string, accept string) (*http.Response, error) {
+	endpoints, err := r.ociEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		header := http.Header{}
+		if accept != "" {
+			header.Set("Accept", accept)
+		}
+
+		resp, err := r.ociRequest(ctx, endpoint, repository, http.MethodGet, ociURL(endpoint, repository, path), header, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.url.Host, err))
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			errs = append(errs, fmt.Errorf("%s: %s", endpoint.url.Host, resp.Status))
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("Failed to retrieve %q from registry: %w", repository+"/"+path, errors.Join(errs...))
+}
+
+// ociGetManifest retrieves a manifest or index, verifying its digest.
+func (r *ProtocolOCI) ociGetManifest(ctx context.Context, repository string, reference string) (*ispec.Descriptor, []byte, error) {
+	resp, err := r.ociFetch(ctx, repository, "manifests/"+reference, ociManifestAccept)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer logger.WarnOnError(resp.Body.Close, "Failed to close response body")
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, ociMaxManifestSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(content) > ociMaxManifestSize {
+		return nil, nil, fmt.Errorf("Manifest %q is too large", reference)
+	}
+
+	desc := ispec.Descriptor{
+		MediaType: strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0]),
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+
+	// Pinned manifests must match their digest.
+	expected, err := digest.Parse(reference)
+	if err == nil && expected != desc.Digest {
+		return nil, nil, fmt.Errorf("Manifest digest mismatch, expected %q, got %q", expected, desc.Digest)
+	}
+
+	// Fallback to the media type of the content.
+	if desc.MediaType == "" || desc.MediaType == "application/json" || desc.MediaType == "text/plain" {
+		versioned := struct {
+			MediaType string `json:"mediaType"`
+		}{}
+
+		err = json.Unmarshal(content, &versioned)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse manifest: %w", err)
+		}
+
+		desc.MediaType = versioned.MediaType
+	}
+
+	return &desc, content, nil
+}
+
+// ociVerifySignature checks for a cosign signature of the manifest made by one of the trusted keys.
+// Cosign stores the signatures of a manifest under the "sha256-<hex>.sig" tag of the repository.
+func (r *ProtocolOCI) ociVerifySignature(ctx context.Context, repository string, manifestDigest digest.Digest) error {
+	tag := fmt.Sprintf("%s-%s.sig", manifestDigest.Algorithm(), manifestDigest.Encoded())
+
+	_, content, err := r.ociGetManifest(ctx, repository, tag)
+	if err != nil {
+		return fmt.Errorf("No signature found: %w", err)
+	}
+
+	manifest := ispec.Manifest{}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return fmt.Errorf("Failed to parse signature manifest: %w", err)
+	}
+
+	err = errors.New("No signature found")
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != ociCosignPayload || layer.Annotations[ociCosignSignature] == "" {
+			continue
+		}
+
+		err = r.ociVerifyCosignLayer(ctx, repository, manifestDigest, layer)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// ociVerifyCosignLayer checks a single cosign signature, and that its payload refers to the manifest.
+func (r *ProtocolOCI) ociVerifyCosignLayer(ctx context.Context, repository string, manifestDigest digest.Digest, layer ispec.Descriptor) error {
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[ociCosignSignature])
+	if err != nil {
+		return fmt.Errorf("Failed decoding signature: %w", err)
+	}
+
+	payload, err := r.ociReadBlob(ctx, repository, layer)
+	if err != nil {
+		return err
+	}
+
+	err = localtls.VerifySignature(r.verifyKeys, payload, signature)
+	if err != nil {
+		return err
+	}
+
+	simpleSigning := struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}{}
+
+	err = json.Unmarshal(payload, &simpleSigning)
+	if err != nil {
+		return fmt.Errorf("Failed to parse signature payload: %w", err)
+	}
+
+	if simpleSigning.Critical.Image.DockerManifestDigest != manifestDigest.String() {
+		return fmt.Errorf("Signature is for %q rather than %q", simpleSigning.Critical.Image.DockerManifestDigest, manifestDigest)
+	}
+
+	return nil
+}
+
+// ociArchitecture converts an OCI platform architecture to an Incus architecture name.
+func ociArchitecture(architecture string, variant string) (string, error) {
+	if architecture == "arm" {
+		switch variant {
+		case "v7":
+			architecture = "armv7l"
+		case "v8":
+			architecture = "armv8l"
+		default:
+			architecture = "armv6l"
+		}
+	}
+
+	archID, err := osarch.ArchitectureID(architecture)
+	if err != nil {
+		return "", err
+	}
+
+	return osarch.ArchitectureName(archID)
+}
+
+// ociResolve resolves an image reference to the images available for each architecture.
+func (r *ProtocolOCI) ociResolve(ctx context.Context, name string) (map[string]ociInfo, error) {
+	ref, err := r.parseOCIReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, content, err := r.ociGetManifest(ctx, ref.repository, ref.reference())
+	if err != nil {
+		return nil, err
+	}
+
+	// The manifests and layers below are all pinned by digest, so checking the signature of the top-level one is enough.
+	if len(r.verifyKeys) > 0 {
+		err = r.ociVerifySignature(ctx, ref.repository, desc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("Failed verifying the signature of %q: %w", name, err)
+		}
+	}
+
+	var manifests []ispec.Descriptor
+	switch desc.MediaType {
+	case ispec.MediaTypeImageIndex, ociDockerManifestList:
+		index := ispec.Index{}
+		err = json.Unmarshal(content, &index)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse image index: %w", err)
+		}
+
+		for _, entry := range index.Manifests {
+			// Skip attestations and images for other operating systems.
+			if entry.Platform == nil || entry.Platform.OS != "linux" {
+				continue
+			}
+
+			manifests = append(manifests, entry)
+		}
+
+	case ispec.MediaTypeImageManifest, ociDockerManifest:
+		manifests = []ispec.Descriptor{*desc}
+	default:
+		return nil, fmt.Errorf("Unsupported manifest type %q", desc.MediaType)
+	}
+
+	images := map[string]ociInfo{}
+	for _, entry := range manifests {
+		if entry.Platform != nil {
+			archName, err := ociArchitecture(entry.Platform.Architecture, entry.Platform.Variant)
+			if err != nil {
+				logger.Debug("Skipping OCI image for unsupported architecture", logger.Ctx{"image": name, "architecture": entry.Platform.Architecture})
+				continue
+			}
+
+			// Keep the first matching image for each architecture.
+			_, ok := images[archName]
+			if ok {
+				continue
+			}
+		}
+
+		info, err := r.ociGetImage(ctx, ref, name, entry, content)
+		if err != nil {
+			return nil, err
+		}
+
+		_, ok := images[info.Architecture]
+		if ok {
+			continue
+		}
+
+		images[info.Architecture] = *info
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("No Linux image found for %q", name)
+	}
+
+	return images, nil
+}
+
+// ociGetImage retrieves the manifest and configuration of a single image.
+func (r *ProtocolOCI) ociGetImage(ctx context.Context, ref *ociReference, name string, desc ispec.Descriptor, content []byte) (*ociInfo, error) {
+	var err error
+
+	// Fetch the manifest unless it was the one requested.
+	if content == nil || desc.Digest != digest.FromBytes(content) {
+		_, content, err = r.ociGetManifest(ctx, ref.repository, desc.Digest.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := ispec.Manifest{}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse image manifest: %w", err)
+	}
+
+	if manifest.SchemaVersion != 2 {
+		return nil, fmt.Errorf("Unsupported manifest schema version %d", manifest.SchemaVersion)
+	}
+
+	// Retrieve the image configuration.
+	configContent, err := r.ociReadBlob(ctx, ref.repository, manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	config := ispec.Image{}
+	err = json.Unmarshal(configContent, &config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse image configuration: %w", err)
+	}
+
+	archName, err := ociArchitecture(config.Architecture, config.Variant)
+	if err != nil {
+		return nil, err
+	}
+
+	uri, err := url.Parse(r.httpHost)
+	if err != nil {
+		return nil, err
+	}
+
+	info := ociInfo{
+		Alias:        name,
+		Name:         uri.Host + "/" + ref.repository,
+		Repository:   ref.repository,
+		Architecture: archName,
+		Manifest:     desc.Digest,
+		Config:       manifest.Config,
+		Layers:       manifest.Layers,
+	}
+
+	if config.Created != nil {
+		info.Created = *config.Created
+	}
+
+	layers := make([]string, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		layers = append(layers, layer.Digest.String())
+	}
+
+	info.Digest = r.computeFingerprint(layers)
+
+	return &info, nil
+}
+
+// ociReadBlob retrieves a small blob, like an image configuration, verifying its digest.
+func (r *ProtocolOCI) ociReadBlob(ctx context.Context, repository string, desc ispec.Descriptor) ([]byte, error) {
+	err := desc.Digest.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid blob digest %q: %w", desc.Digest, err)
+	}
+
+	resp, err := r.ociFetch(ctx, repository, "blobs/"+desc.Digest.String(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	defer logger.WarnOnError(resp.Body.Close, "Failed to close response body")
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, ociMaxManifestSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > ociMaxManifestSize {
+		return nil, fmt.Errorf("Blob %q is too large", desc.Digest)
+	}
+
+	if digest.FromBytes(content) != desc.Digest {
+		return nil, fmt.Errorf("Blob %q digest mismatch", desc.Digest)
+	}
+
+	return content, nil
+}
+
+// ociGetBlob retrieves a blob into a content-addressed store and returns its path.
+func (r *ProtocolOCI) ociGetBlob(ctx context.Context, storePath string, repository string, desc ispec.Descriptor, tracker *ioprogress.ProgressTracker) (string, error) {
+	err := desc.Digest.Validate()
+	if err != nil {
+		return "", fmt.Errorf("Invalid blob digest %q: %w", desc.Digest, err)
+	}
+
+	blobPath := filepath.Join(storePath, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+
+	// Use the cached blob if present, refreshing its expiry.
+	_, err = os.Stat(blobPath)
+	if err == nil {
+		now := time.Now()
+		_ = os.Chtimes(blobPath, now, now)
+
+		if tracker != nil && tracker.Handler != nil {
+			tracker.Handler(tracker.Length, 0)
+		}
+
+		return blobPath, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(blobPath), 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.ociFetch(ctx, repository, "blobs/"+desc.Digest.String(), "")
+	if err != nil {
+		return "", err
+	}
+
+	defer logger.WarnOnError(resp.Body.Close, "Failed to close response body")
+
+	var body io.ReadCloser = resp.Body
+	if tracker != nil {
+		body = &ioprogress.ProgressReader{
+			ReadCloser: resp.Body,
+			Tracker:    tracker,
+		}
+	}
+
+	// Download to a temporary file and only move it in place once verified.
+	tmpFile, err := os.CreateTemp(filepath.Dir(blobPath), ".download-")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	// The file is closed explicitly once the blob is verified.
+	defer func() { _ = tmpFile.Close() }()
+
+	verifier := desc.Digest.Verifier()
+
+	// Don't write past the expected size of the blob.
+	var reader io.Reader = body
+	if desc.Size > 0 {
+		reader = io.LimitReader(body, desc.Size+1)
+	}
+
+	size, err := io.Copy(io.MultiWriter(tmpFile, verifier), reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download blob %q: %w", desc.Digest, err)
+	}
+
+	if desc.Size > 0 && size != desc.Size {
+		return "", fmt.Errorf("Blob %q size mismatch, expected %d, got %d", desc.Digest, desc.Size, size)
+	}
+
+	if !verifier.Verified() {
+		return "", fmt.Errorf("Blob %q digest mismatch", desc.Digest)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmpFile.Name(), blobPath)
+	if err != nil {
+		return "", err
+	}
+
+	return blobPath, nil
+}
+
+// ociConvertManifest returns an OCI version of an image manifest.
+func ociConvertManifest(info ociInfo) (*ispec.Manifest, error) {
+	manifest := ispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageManifest,
+		Config:    info.Config,
+		Layers:    make([]ispec.Descriptor, 0, len(info.Layers)),
+	}
+
+	if manifest.Config.MediaType == ociDockerConfig {
+		manifest.Config.MediaType = ispec.MediaTypeImageConfig
+	}
+
+	for _, layer := range info.Layers {
+		switch layer.MediaType {
+		case ociDockerLayerGzip:
+			layer.MediaType = ispec.MediaTypeImageLayerGzip
+		case ociDockerLayerTar:
+			layer.MediaType = ispec.MediaTypeImageLayer
+		case ispec.MediaTypeImageLayer, ispec.MediaTypeImageLayerGzip, ispec.MediaTypeImageLayerZstd:
+		default:
+			return nil, fmt.Errorf("Unsupported layer type %q", layer.MediaType)
+		}
+
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+
+	return &manifest, nil
+}
+
+// ociWriteJSONBlob writes a JSON object as a blob of an OCI layout.
+func ociWriteJSONBlob(layoutPath string, mediaType string, object any) (*ispec.Descriptor, error) {
+	content, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := ispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+
+	err = os.WriteFile(filepath.Join(layoutPath, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()), content, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc, nil
+}
+
+// ociDownloadImage retrieves an image and writes it as an OCI layout with the given tag.
+// The layout blobs are links into the blob store so layers shared between images are only downloaded and stored once.
+func (r *ProtocolOCI) ociDownloadImage(ctx context.Context, info ociInfo, storePath string, layoutPath string, tag string, progress func(ioprogress.ProgressData)) error {
+	manifest, err := ociConvertManifest(info)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Join(layoutPath, "blobs", string(digest.SHA256)), 0o700)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the configuration and layers.
+	var total int64
+	for _, layer := range manifest.Layers {
+		total += layer.Size
+	}
+
+	var done int64
+	for _, blob := range append([]ispec.Descriptor{manifest.Config}, manifest.Layers...) {
+		var tracker *ioprogress.ProgressTracker
+		if progress != nil {
+			offset := done
+			tracker = &ioprogress.ProgressTracker{
+				Length: blob.Size,
+				Handler: func(received int64, speed int64) {
+					progress(ioprogress.ProgressData{Text: fmt.Sprintf("Retrieving OCI image from registry: %s/%s (%s/s)", units.GetByteSizeString(offset+received, 2), units.GetByteSizeString(total, 2), units.GetByteSizeString(speed, 2))})
+				},
+			}
+		}
+
+		blobPath, err := r.ociGetBlob(ctx, storePath, info.Repository, blob, tracker)
+		if err != nil {
+			return err
+		}
+
+		if blob.Digest != manifest.Config.Digest {
+			done += blob.Size
+		}
+
+		targetDir := filepath.Join(layoutPath, "blobs", blob.Digest.Algorithm().String())
+		err = os.MkdirAll(targetDir, 0o700)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(targetDir, blob.Digest.Encoded())
+		_, err = os.Lstat(target)
+		if err == nil {
+			continue
+		}
+
+		err = os.Symlink(blobPath, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the manifest, index and layout marker.
+	manifestDesc, err := ociWriteJSONBlob(layoutPath, ispec.MediaTypeImageManifest, manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestDesc.Annotations = map[string]string{ispec.AnnotationRefName: tag}
+
+	index := ispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageIndex,
+		Manifests: []ispec.Descriptor{*manifestDesc},
+	}
+
+	content, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(layoutPath, "index.json"), content, 0o600)
+	if err != nil {
+		return err
+	}
+
+	content, err = json.Marshal(ispec.ImageLayout{Version: ispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(layoutPath, ispec.ImageLayoutFile), content, 0o600)
+}
+
+// ociPruneBlobs removes the cached blobs which haven't been used recently.
+func (r *ProtocolOCI) ociPruneBlobs() error {
+	if r.blobPath == "" {
+		return nil
+	}
+
+	return filepath.WalkDir(r.blobPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		fi, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if time.Since(fi.ModTime()) < ociBlobCacheExpiry {
+			return nil
+		}
+
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ociPlatform converts an Incus architecture name to an OCI platform architecture and variant.
+func ociPlatform(architecture string) (string, string, error) {
+	archID, err := osarch.ArchitectureID(architecture)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch archID {
+	case osarch.ARCH_32BIT_INTEL_X86:
+		return "386", "", nil
+	case osarch.ARCH_64BIT_INTEL_X86:
+		return "amd64", "", nil
+	case osarch.ARCH_32BIT_ARMV6_LITTLE_ENDIAN:
+		return "arm", "v6", nil
+	case osarch.ARCH_32BIT_ARMV7_LITTLE_ENDIAN:
+		return "arm", "v7", nil
+	case osarch.ARCH_32BIT_ARMV8_LITTLE_ENDIAN:
+		return "arm", "v8", nil
+	case osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN:
+		return "arm64", "", nil
+	case osarch.ARCH_64BIT_POWERPC_BIG_ENDIAN:
+		return "ppc64", "", nil
+	case osarch.ARCH_64BIT_POWERPC_LITTLE_ENDIAN:
+		return "ppc64le", "", nil
+	case osarch.ARCH_64BIT_S390_BIG_ENDIAN:
+		return "s390x", "", nil
+	case osarch.ARCH_64BIT_RISCV_LITTLE_ENDIAN:
+		return "riscv64", "", nil
+	case osarch.ARCH_64BIT_LOONGARCH:
+		return "loong64", "", nil
+	}
+
+	return "", "", fmt.Errorf("Architecture %q isn't supported by OCI registries", architecture)
+}
+
+// ociPushBlob uploads a blob to a repository unless it's already present.
+func (r *ProtocolOCI) ociPushBlob(ctx context.Context, endpoint ociEndpoint, repository string, desc ispec.Descriptor, content io.ReadSeeker) error {
+	resp, err := r.ociRequest(ctx, endpoint, repository, http.MethodHead, ociURL(endpoint, repository, "blobs/"+desc.Digest.String()), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// Start an upload session.
+	resp, err = r.ociRequest(ctx, endpoint, repository, http.MethodPost, ociURL(endpoint, repository, "blobs/uploads/"), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("Failed to start blob upload: %s", resp.Status)
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("Failed to get blob upload location: %w", err)
+	}
+
+	query := location.Query()
+	query.Set("digest", desc.Digest.String())
+	location.RawQuery = query.Encode()
+
+	// Upload the whole blob at once.
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = r.ociRequest(ctx, endpoint, repository, http.MethodPut, location.String(), header, content)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Failed to upload blob %q: %s", desc.Digest, resp.Status)
+	}
+
+	return nil
+}
+
+// ociPushManifest uploads an image manifest under the given tag.
+func (r *ProtocolOCI) ociPushManifest(ctx context.Context, endpoint ociEndpoint, repository string, tag string, manifest []byte) error {
+	header := http.Header{}
+	header.Set("Content-Type", ispec.MediaTypeImageManifest)
+
+	resp, err := r.ociRequest(ctx, endpoint, repository, http.MethodPut, ociURL(endpoint, repository, "manifests/"+tag), header, bytes.NewReader(manifest))
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Failed to upload manifest for %q: %s", repository+":"+tag, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,465 @@
+package incus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ociTestContent is a blob or manifest served by the test registry.
+type ociTestContent struct {
+	mediaType string
+	data      []byte
+}
+
+// ociTestRegistry is a minimal registry serving and receiving content, requiring a bearer token.
+type ociTestRegistry struct {
+	server  *httptest.Server
+	content map[string]ociTestContent
+	mu      sync.Mutex
+}
+
+func newOCITestRegistry(t *testing.T) *ociTestRegistry {
+	registry := &ociTestRegistry{content: map[string]ociTestContent{}}
+
+	registry.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "secret"})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+registry.server.URL+`/token",service="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPost:
+			// Blob uploads are done in a single request to the session.
+			w.Header().Set("Location", registry.server.URL+r.URL.Path+"session")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			contentPath, ok := strings.CutSuffix(r.URL.Path, "/uploads/session")
+			if ok {
+				blobDigest := digest.Digest(r.URL.Query().Get("digest"))
+				if blobDigest != digest.FromBytes(data) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				contentPath += "/" + blobDigest.String()
+			}
+
+			registry.content[contentPath] = ociTestContent{mediaType: r.Header.Get("Content-Type"), data: data}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		content, ok := registry.content[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if content.mediaType != "" {
+			w.Header().Set("Content-Type", content.mediaType)
+		}
+
+		_, _ = w.Write(content.data)
+	}))
+
+	t.Cleanup(registry.server.Close)
+
+	return registry
+}
+
+// client returns an OCI client for the test registry.
+func (registry *ociTestRegistry) client() *ProtocolOCI {
+	return &ProtocolOCI{
+		http:     registry.server.Client(),
+		httpHost: registry.server.URL,
+		cache:    map[string]ociInfo{},
+		errors:   map[string]error{},
+		tokens:   map[string]string{},
+	}
+}
+
+// get returns the content stored at a path of the registry.
+func (registry *ociTestRegistry) get(path string) (ociTestContent, bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	content, ok := registry.content[path]
+
+	return content, ok
+}
+
+// set stores content at a path of the registry.
+func (registry *ociTestRegistry) set(path string, content ociTestContent) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.content[path] = content
+}
+
+// addBlob serves a blob in the repository and returns its descriptor.
+func (registry *ociTestRegistry) addBlob(repository string, mediaType string, data []byte) ispec.Descriptor {
+	desc := ispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	registry.set("/v2/"+repository+"/blobs/"+desc.Digest.String(), ociTestContent{data: data})
+
+	return desc
+}
+
+// addManifest serves a manifest in the repository under its digest and the optional tag.
+func (registry *ociTestRegistry) addManifest(t *testing.T, repository string, tag string, mediaType string, manifest any) ispec.Descriptor {
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	desc := ispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	registry.set("/v2/"+repository+"/manifests/"+desc.Digest.String(), ociTestContent{mediaType: mediaType, data: data})
+	if tag != "" {
+		registry.set("/v2/"+repository+"/manifests/"+tag, ociTestContent{mediaType: mediaType, data: data})
+	}
+
+	return desc
+}
+
+// addImage serves a single image manifest for the architecture and returns its descriptor.
+func (registry *ociTestRegistry) addImage(t *testing.T, repository string, architecture string, variant string) ispec.Descriptor {
+	config, err := json.Marshal(ispec.Image{Platform: ispec.Platform{OS: "linux", Architecture: architecture, Variant: variant}})
+	require.NoError(t, err)
+
+	manifest := ispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageManifest,
+		Config:    registry.addBlob(repository, ispec.MediaTypeImageConfig, config),
+		Layers:    []ispec.Descriptor{registry.addBlob(repository, ispec.MediaTypeImageLayerGzip, []byte(architecture+variant))},
+	}
+
+	return registry.addManifest(t, repository, "", ispec.MediaTypeImageManifest, manifest)
+}
+
+func TestOCIParseChallenge(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		scheme string
+		params map[string]string
+	}{
+		{
+			name:   "docker hub",
+			header: `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"`,
+			scheme: "bearer",
+			params: map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:library/alpine:pull"},
+		},
+		{
+			name:   "basic",
+			header: `Basic realm="Registry"`,
+			scheme: "basic",
+			params: map[string]string{"realm": "Registry"},
+		},
+		{
+			name:   "unquoted values and spaces",
+			header: `Bearer realm="https://example.com/token", Service=registry`,
+			scheme: "bearer",
+			params: map[string]string{"realm": "https://example.com/token", "service": "registry"},
+		},
+		{
+			name:   "comma in quoted value",
+			header: `Bearer realm="https://example.com/token",scope="repository:foo:pull,push"`,
+			scheme: "bearer",
+			params: map[string]string{"realm": "https://example.com/token", "scope": "repository:foo:pull,push"},
+		},
+		{
+			name:   "unterminated quote",
+			header: `Bearer realm="https://example.com/token`,
+			scheme: "bearer",
+			params: map[string]string{"realm": "https://example.com/token"},
+		},
+		{
+			name:   "no parameters",
+			header: "Bearer",
+			scheme: "bearer",
+			params: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		scheme, params := ociParseChallenge(test.header)
+		assert.Equal(t, test.scheme, scheme, test.name)
+		assert.Equal(t, test.params, params, test.name)
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	pinned := digest.FromString("manifest")
+
+	tests := []struct {
+		name       string
+		host       string
+		reference  string
+		repository string
+		tag        string
+		digest     digest.Digest
+		err        bool
+	}{
+		{name: "official image", host: "https://docker.io", reference: "alpine", repository: "library/alpine", tag: "latest"},
+		{name: "official image with tag", host: "https://docker.io", reference: "alpine:3.20", repository: "library/alpine", tag: "3.20"},
+		{name: "user image", host: "https://docker.io", reference: "user/app:1", repository: "user/app", tag: "1"},
+		{name: "other registry", host: "https://ghcr.io", reference: "app", repository: "app", tag: "latest"},
+		{name: "digest", host: "https://ghcr.io", reference: "org/app@" + pinned.String(), repository: "org/app", tag: "latest", digest: pinned},
+		{name: "tag and digest", host: "https://ghcr.io", reference: "org/app:1@" + pinned.String(), repository: "org/app", tag: "1", digest: pinned},
+		{name: "invalid digest", host: "https://ghcr.io", reference: "org/app@sha256:1234", err: true},
+		{name: "missing name", host: "https://ghcr.io", reference: ":1", err: true},
+	}
+
+	for _, test := range tests {
+		r := &ProtocolOCI{httpHost: test.host}
+
+		ref, err := r.parseOCIReference(test.reference)
+		if test.err {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.repository, ref.repository, test.name)
+		assert.Equal(t, test.tag, ref.tag, test.name)
+		assert.Equal(t, test.digest, ref.digest, test.name)
+
+		// Digests are preferred over tags when fetching the manifest.
+		if test.digest != "" {
+			assert.Equal(t, test.digest.String(), ref.reference(), test.name)
+		} else {
+			assert.Equal(t, test.tag, ref.reference(), test.name)
+		}
+	}
+}
+
+func TestOCIResolveManifestList(t *testing.T) {
+	registry := newOCITestRegistry(t)
+
+	amd64 := registry.addImage(t, "org/app", "amd64", "")
+	amd64.Platform = &ispec.Platform{OS: "linux", Architecture: "amd64"}
+
+	arm64 := registry.addImage(t, "org/app", "arm64", "")
+	arm64.Platform = &ispec.Platform{OS: "linux", Architecture: "arm64"}
+
+	armv7 := registry.addImage(t, "org/app", "arm", "v7")
+	armv7.Platform = &ispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+
+	// A second amd64 image, only the first one is kept.
+	other := registry.addImage(t, "org/app", "amd64", "v2")
+	other.Platform = &ispec.Platform{OS: "linux", Architecture: "amd64"}
+
+	// Images for other operating systems, attestations and unknown architectures are skipped.
+	windows := amd64
+	windows.Platform = &ispec.Platform{OS: "windows", Architecture: "amd64"}
+
+	attestation := amd64
+	attestation.Platform = &ispec.Platform{OS: "unknown", Architecture: "unknown"}
+
+	unknown := amd64
+	unknown.Platform = &ispec.Platform{OS: "linux", Architecture: "riscv128"}
+
+	registry.addManifest(t, "org/app", "1.0", ispec.MediaTypeImageIndex, ispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageIndex,
+		Manifests: []ispec.Descriptor{windows, attestation, unknown, amd64, other, arm64, armv7},
+	})
+
+	images, err := registry.client().ociResolve(context.Background(), "org/app:1.0")
+	require.NoError(t, err)
+	require.Len(t, images, 3)
+
+	assert.Equal(t, amd64.Digest, images["x86_64"].Manifest)
+	assert.Equal(t, arm64.Digest, images["aarch64"].Manifest)
+	assert.Equal(t, armv7.Digest, images["armv7l"].Manifest)
+	assert.Equal(t, "org/app", images["x86_64"].Repository)
+	assert.Len(t, images["x86_64"].Layers, 1)
+
+	// Single image manifests resolve to their own architecture.
+	single := registry.addImage(t, "org/single", "arm64", "")
+	content, _ := registry.get("/v2/org/single/manifests/" + single.Digest.String())
+	registry.set("/v2/org/single/manifests/latest", content)
+
+	images, err = registry.client().ociResolve(context.Background(), "org/single")
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, single.Digest, images["aarch64"].Manifest)
+
+	// Pinned manifests must match their digest.
+	_, err = registry.client().ociResolve(context.Background(), "org/app@"+digest.FromString("other").String())
+	assert.Error(t, err)
+}
+
+func TestOCIGetBlob(t *testing.T) {
+	registry := newOCITestRegistry(t)
+	storePath := t.TempDir()
+	r := registry.client()
+
+	// Valid blobs are stored under their digest.
+	desc := registry.addBlob("org/app", ispec.MediaTypeImageLayerGzip, []byte("layer"))
+
+	blobPath, err := r.ociGetBlob(context.Background(), storePath, "org/app", desc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(storePath, "sha256", desc.Digest.Encoded()), blobPath)
+
+	content, err := os.ReadFile(blobPath)
+	require.NoError(t, err)
+	assert.Equal(t, "layer", string(content))
+
+	// Blobs not matching their digest are rejected and not kept.
+	bad := ispec.Descriptor{Digest: digest.FromString("expected"), Size: 8}
+	registry.set("/v2/org/app/blobs/"+bad.Digest.String(), ociTestContent{data: []byte("tampered")})
+
+	_, err = r.ociGetBlob(context.Background(), storePath, "org/app", bad, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "digest mismatch")
+	assert.NoFileExists(t, filepath.Join(storePath, "sha256", bad.Digest.Encoded()))
+
+	// Blobs larger than announced are rejected.
+	large := ispec.Descriptor{Digest: digest.FromString("large"), Size: 2}
+	registry.set("/v2/org/app/blobs/"+large.Digest.String(), ociTestContent{data: []byte(strings.Repeat("a", 1024))})
+
+	_, err = r.ociGetBlob(context.Background(), storePath, "org/app", large, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "size mismatch")
+
+	// No temporary file is left behind.
+	entries, err := os.ReadDir(filepath.Join(storePath, "sha256"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestOCIPlatform(t *testing.T) {
+	tests := []struct {
+		architecture string
+		platform     string
+		variant      string
+		err          bool
+	}{
+		{architecture: "x86_64", platform: "amd64"},
+		{architecture: "i686", platform: "386"},
+		{architecture: "aarch64", platform: "arm64"},
+		{architecture: "armv7l", platform: "arm", variant: "v7"},
+		{architecture: "riscv64", platform: "riscv64"},
+		{architecture: "unknown", err: true},
+	}
+
+	for _, test := range tests {
+		platform, variant, err := ociPlatform(test.architecture)
+		if test.err {
+			assert.Error(t, err, test.architecture)
+			continue
+		}
+
+		require.NoError(t, err, test.architecture)
+		assert.Equal(t, test.platform, platform, test.architecture)
+		assert.Equal(t, test.variant, variant, test.architecture)
+	}
+}
+
+func TestOCIPushImage(t *testing.T) {
+	registry := newOCITestRegistry(t)
+	r := registry.client()
+	rootfs := []byte("rootfs tarball")
+
+	newArgs := func() *OCIImagePushArgs {
+		return &OCIImagePushArgs{
+			Architecture: "armv7l",
+			Rootfs:       bytes.NewReader(rootfs),
+			Entrypoint:   []string{"/bin/app", "--serve"},
+			Env:          []string{"HOME=/root", "PATH=/bin"},
+			WorkingDir:   "/srv",
+			User:         "1000:1000",
+		}
+	}
+
+	// Invalid pushes are rejected before uploading anything.
+	assert.Error(t, r.PushImage(nil, newArgs()))
+	assert.Error(t, r.PushImage([]string{"org/app:1.0"}, &OCIImagePushArgs{Architecture: "armv7l"}))
+	assert.Error(t, r.PushImage([]string{"org/app@" + digest.FromString("manifest").String()}, newArgs()))
+
+	args := newArgs()
+	args.Architecture = "unknown"
+	assert.Error(t, r.PushImage([]string{"org/app:1.0"}, args))
+
+	// Images are pushed under every name.
+	err := r.PushImage([]string{"org/app:1.0", "org/app"}, newArgs())
+	require.NoError(t, err)
+
+	content, ok := registry.get("/v2/org/app/manifests/1.0")
+	require.True(t, ok)
+	assert.Equal(t, ispec.MediaTypeImageManifest, content.mediaType)
+
+	latest, ok := registry.get("/v2/org/app/manifests/latest")
+	require.True(t, ok)
+	assert.Equal(t, content.data, latest.data)
+
+	manifest := ispec.Manifest{}
+	err = json.Unmarshal(content.data, &manifest)
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.SchemaVersion)
+	assert.Equal(t, ispec.MediaTypeImageManifest, manifest.MediaType)
+	assert.Equal(t, ispec.MediaTypeImageConfig, manifest.Config.MediaType)
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, ispec.MediaTypeImageLayerGzip, manifest.Layers[0].MediaType)
+
+	// The layer is the compressed root filesystem.
+	layer, ok := registry.get("/v2/org/app/blobs/" + manifest.Layers[0].Digest.String())
+	require.True(t, ok)
+	assert.Equal(t, manifest.Layers[0].Size, int64(len(layer.data)))
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(layer.data))
+	require.NoError(t, err)
+
+	uncompressed, err := io.ReadAll(gzReader)
+	require.NoError(t, err)
+	assert.Equal(t, rootfs, uncompressed)
+
+	// The configuration holds the platform, the process and the uncompressed layer digest.
+	configContent, ok := registry.get("/v2/org/app/blobs/" + manifest.Config.Digest.String())
+	require.True(t, ok)
+	assert.Equal(t, manifest.Config.Size, int64(len(configContent.data)))
+
+	config := ispec.Image{}
+	err = json.Unmarshal(configContent.data, &config)
+	require.NoError(t, err)
+	assert.Equal(t, ispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, config.Platform)
+	assert.Equal(t, ispec.ImageConfig{User: "1000:1000", Env: []string{"HOME=/root", "PATH=/bin"}, Entrypoint: []string{"/bin/app", "--serve"}, WorkingDir: "/srv"}, config.Config)
+	assert.Equal(t, ispec.RootFS{Type: "layers", DiffIDs: []digest.Digest{digest.FromBytes(rootfs)}}, config.RootFS)
+	assert.NotNil(t, config.Created)
+
+	// Pushed images can be pulled back.
+	images, err := registry.client().ociResolve(context.Background(), "org/app:1.0")
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, digest.FromBytes(content.data), images["armv7l"].Manifest)
+}
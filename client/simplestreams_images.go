@@ -14,10 +14,13 @@ import (
 	"time"
 
 	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/ioprogress"
 	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/revert"
 	"github.com/lxc/incus/v7/shared/simplestreams"
 	"github.com/lxc/incus/v7/shared/subprocess"
 	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/vcdiff"
 )
 
 // Image handling functions
@@ -165,15 +168,14 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 	// Download the rootfs
 	rootfs, ok := files["root"]
 	if ok && req.RootfsFile != nil {
-		// Look for deltas (requires xdelta3)
+		// Look for deltas
 		downloaded := false
-		_, err := exec.LookPath("xdelta3")
-		if err == nil && req.DeltaSourceRetriever != nil {
-			applyDelta := func(file simplestreams.DownloadableFile, srcPath string, target io.Writer) (int64, error) {
+		if req.DeltaSourceRetriever != nil {
+			applyDelta := func(file simplestreams.DownloadableFile, srcPath string) (*os.File, int64, error) {
 				// Create temporary file for the delta
 				deltaFile, err := os.CreateTemp(r.tempPath, "incus_image_")
 				if err != nil {
-					return -1, err
+					return nil, -1, err
 				}
 
 				defer logger.WarnOnError(deltaFile.Close, "Failed to close temporary file")
@@ -181,34 +183,58 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 				defer logger.WarnOnError(func() error { return os.Remove(deltaFile.Name()) }, "Failed to remove temporary file")
 
 				// Download the delta
-				_, err = download(file.Path, "rootfs delta", file.Sha256, deltaFile)
+				deltaSize, err := download(file.Path, "rootfs delta", file.Sha256, deltaFile)
 				if err != nil {
-					return -1, err
+					return nil, -1, err
 				}
 
-				// Create temporary file for the delta
+				// Create temporary file for the patched rootfs
 				patchedFile, err := os.CreateTemp(r.tempPath, "incus_image_")
 				if err != nil {
-					return -1, err
+					return nil, -1, err
 				}
 
-				defer logger.WarnOnError(patchedFile.Close, "Failed to close temporary file")
+				reverter := revert.New()
+				defer reverter.Fail()
 
-				defer logger.WarnOnError(func() error { return os.Remove(patchedFile.Name()) }, "Failed to remove temporary file")
+				reverter.Add(func() {
+					_ = patchedFile.Close()
+					_ = os.Remove(patchedFile.Name())
+				})
 
 				// Apply it
-				_, err = subprocess.RunCommand("xdelta3", "-f", "-d", "-s", srcPath, deltaFile.Name(), patchedFile.Name())
+				err = applyImageDelta(srcPath, deltaFile, deltaSize, patchedFile, req.ProgressHandler)
 				if err != nil {
-					return -1, err
+					return nil, -1, err
 				}
 
-				// Copy to the target
-				size, err := util.SafeCopy(req.RootfsFile, patchedFile)
+				// Check the result against the index
+				if rootfs.Sha256 != "" {
+					_, err = patchedFile.Seek(0, io.SeekStart)
+					if err != nil {
+						return nil, -1, err
+					}
+
+					hash256 := sha256.New()
+					_, err = util.SafeCopy(hash256, patchedFile)
+					if err != nil {
+						return nil, -1, err
+					}
+
+					hash := fmt.Sprintf("%x", hash256.Sum(nil))
+					if hash != rootfs.Sha256 {
+						return nil, -1, fmt.Errorf("Hash mismatch for patched rootfs: %s != %s", hash, rootfs.Sha256)
+					}
+				}
+
+				_, err = patchedFile.Seek(0, io.SeekStart)
 				if err != nil {
-					return -1, err
+					return nil, -1, err
 				}
 
-				return size, nil
+				reverter.Success()
+
+				return patchedFile, deltaSize, nil
 			}
 
 			for filename, file := range files {
@@ -223,7 +249,22 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 					continue
 				}
 
-				size, err := applyDelta(file, srcPath, req.RootfsFile)
+				patchedFile, deltaSize, err := applyDelta(file, srcPath)
+				if err != nil {
+					// Handle cancellation
+					if err.Error() == "net/http: request canceled" {
+						return nil, err
+					}
+
+					// Deltas are only an optimization, fall back to the full rootfs.
+					logger.Warn("Failed applying image delta", logger.Ctx{"fingerprint": fingerprint, "source": srcFingerprint, "err": err})
+					continue
+				}
+
+				// Copy to the target
+				size, err := util.SafeCopy(req.RootfsFile, patchedFile)
+				_ = patchedFile.Close()
+				_ = os.Remove(patchedFile.Name())
 				if err != nil {
 					return nil, err
 				}
@@ -231,7 +272,11 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 				parts := strings.Split(rootfs.Path, "/")
 				resp.RootfsName = parts[len(parts)-1]
 				resp.RootfsSize = size
+				resp.DeltaSource = srcFingerprint
+				resp.DeltaSize = deltaSize
 				downloaded = true
+
+				break
 			}
 		}
 
@@ -289,6 +334,58 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 	return &resp, nil
 }
 
+// applyImageDelta applies a VCDIFF delta to the source file.
+// The xdelta3 tool is only used for deltas relying on features the built-in decoder doesn't support.
+func applyImageDelta(srcPath string, delta *os.File, deltaSize int64, target *os.File, progress func(ioprogress.ProgressData)) error {
+	source, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = source.Close() }()
+
+	_, err = delta.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = delta
+	if progress != nil {
+		reader = &ioprogress.ProgressReader{
+			Reader: delta,
+			Tracker: &ioprogress.ProgressTracker{
+				Length: deltaSize,
+				Handler: func(percent int64, _ int64) {
+					progress(ioprogress.ProgressData{Text: fmt.Sprintf("Applying rootfs delta: %d%%", percent)})
+				},
+			},
+		}
+	}
+
+	_, err = vcdiff.Decode(source, reader, target)
+	if err == nil {
+		return nil
+	}
+
+	_, lookErr := exec.LookPath("xdelta3")
+	if !errors.Is(err, vcdiff.ErrUnsupported) || lookErr != nil {
+		return err
+	}
+
+	// Fallback to xdelta3.
+	err = target.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = subprocess.RunCommand("xdelta3", "-f", "-d", "-s", srcPath, delta.Name(), target.Name())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetImageSecret isn't relevant for the simplestreams protocol.
 func (r *ProtocolSimpleStreams) GetImageSecret(_ string) (string, error) {
 	return "", errors.New("Private images aren't supported by the simplestreams protocol")
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/lxc/incus/v7/shared/simplestreams"
+	"github.com/lxc/incus/v7/shared/subprocess"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// deltaFileTypes lists the image file types for which deltas are generated.
+var deltaFileTypes = []string{"squashfs", "disk-kvm.img"}
+
+// generateDeltas adds deltas from up to count previous versions of the product to the given version.
+// Deltas are skipped when xdelta3 isn't available or when they aren't smaller than the full file.
+func generateDeltas(product *simplestreams.Product, versionName string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+
+	_, err := exec.LookPath("xdelta3")
+	if err != nil {
+		fmt.Println("Skipping delta generation as xdelta3 isn't available")
+		return nil
+	}
+
+	version := product.Versions[versionName]
+
+	for _, fileType := range deltaFileTypes {
+		target, ok := version.Items[fileType]
+		if !ok {
+			continue
+		}
+
+		for _, baseName := range deltaBaseNames(product, versionName, fileType, count) {
+			base := product.Versions[baseName].Items[fileType]
+
+			item, err := generateDelta(base, target, baseName)
+			if err != nil {
+				return fmt.Errorf("Failed generating delta from %q: %w", baseName, err)
+			}
+
+			if item == nil {
+				continue
+			}
+
+			version.Items[fmt.Sprintf("delta-%s.%s", baseName, item.FileType)] = *item
+		}
+	}
+
+	product.Versions[versionName] = version
+
+	return nil
+}
+
+// deltaBaseNames returns the up to count most recent versions before the given one having a file of that type.
+func deltaBaseNames(product *simplestreams.Product, versionName string, fileType string, count int) []string {
+	baseNames := []string{}
+	for name, base := range product.Versions {
+		_, ok := base.Items[fileType]
+		if ok && name < versionName {
+			baseNames = append(baseNames, name)
+		}
+	}
+
+	slices.Sort(baseNames)
+	if len(baseNames) > count {
+		baseNames = baseNames[len(baseNames)-count:]
+	}
+
+	return baseNames
+}
+
+// generateDelta computes the VCDIFF delta between two files.
+// Secondary compression is disabled so that Incus can apply the delta without xdelta3.
+func generateDelta(base simplestreams.ProductVersionItem, target simplestreams.ProductVersionItem, baseName string) (*simplestreams.ProductVersionItem, error) {
+	deltaPath := fmt.Sprintf("images/%s.%s.vcdiff", target.HashSha256, base.HashSha256)
+
+	if !util.PathExists(deltaPath) {
+		_, err := subprocess.RunCommand("xdelta3", "-e", "-f", "-S", "none", "-s", base.Path, target.Path, deltaPath)
+		if err != nil {
+			_ = os.Remove(deltaPath)
+			return nil, err
+		}
+	}
+
+	deltaStat, err := os.Stat(deltaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not worth it.
+	if deltaStat.Size() >= target.Size {
+		_ = os.Remove(deltaPath)
+		return nil, nil
+	}
+
+	body, err := os.ReadFile(deltaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &simplestreams.ProductVersionItem{
+		FileType:   target.FileType + ".vcdiff",
+		HashSha256: fmt.Sprintf("%x", sha256.Sum256(body)),
+		Size:       deltaStat.Size(),
+		Path:       deltaPath,
+		DeltaBase:  baseName,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/shared/simplestreams"
+)
+
+func TestDeltaBaseNames(t *testing.T) {
+	squashfs := map[string]simplestreams.ProductVersionItem{"squashfs": {FileType: "squashfs"}}
+	disk := map[string]simplestreams.ProductVersionItem{"disk-kvm.img": {FileType: "disk-kvm.img"}}
+
+	product := &simplestreams.Product{
+		Versions: map[string]simplestreams.ProductVersion{
+			"20240101_00:00": {Items: squashfs},
+			"20240102_00:00": {Items: disk},
+			"20240103_00:00": {Items: squashfs},
+			"20240104_00:00": {Items: squashfs},
+			"20240105_00:00": {Items: squashfs},
+			"20240106_00:00": {Items: squashfs},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		fileType string
+		count    int
+		bases    []string
+	}{
+		{name: "most recent", version: "20240105_00:00", fileType: "squashfs", count: 2, bases: []string{"20240103_00:00", "20240104_00:00"}},
+		{name: "fewer than count", version: "20240103_00:00", fileType: "squashfs", count: 3, bases: []string{"20240101_00:00"}},
+		{name: "other file type", version: "20240106_00:00", fileType: "disk-kvm.img", count: 3, bases: []string{"20240102_00:00"}},
+		{name: "first version", version: "20240101_00:00", fileType: "squashfs", count: 3, bases: []string{}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.bases, deltaBaseNames(product, test.version, test.fileType, test.count), test.name)
+	}
+}
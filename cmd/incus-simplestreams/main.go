@@ -10,6 +10,7 @@ import (
 
 type cmdGlobal struct {
 	flagHelp    bool
+	flagSignKey string
 	flagVersion bool
 }
 
@@ -30,6 +31,7 @@ func main() {
 	globalCmd := cmdGlobal{}
 	app.PersistentFlags().BoolVar(&globalCmd.flagVersion, "version", false, "Print version number")
 	app.PersistentFlags().BoolVarP(&globalCmd.flagHelp, "help", "h", false, "Print help")
+	app.PersistentFlags().StringVar(&globalCmd.flagSignKey, "sign-key", "", "Private key (PEM) to sign the index files with"+"``")
 
 	// Help handling.
 	app.SetHelpCommand(&cobra.Command{
@@ -53,10 +55,22 @@ func main() {
 	listCmd := cmdList{global: &globalCmd}
 	app.AddCommand(listCmd.command())
 
+	// mirror sub-command.
+	mirrorCmd := cmdMirror{global: &globalCmd}
+	app.AddCommand(mirrorCmd.command())
+
 	// remove sub-command.
 	removeCmd := cmdRemove{global: &globalCmd}
 	app.AddCommand(removeCmd.command())
 
+	// serve sub-command.
+	serveCmd := cmdServe{global: &globalCmd}
+	app.AddCommand(serveCmd.command())
+
+	// sign sub-command.
+	signCmd := cmdSign{global: &globalCmd}
+	app.AddCommand(signCmd.command())
+
 	// verify sub-command.
 	verifyCmd := cmdVerify{global: &globalCmd}
 	app.AddCommand(verifyCmd.command())
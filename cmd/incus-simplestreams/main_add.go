@@ -30,6 +30,7 @@ type cmdAdd struct {
 	global *cmdGlobal
 
 	flagAliases        []string
+	flagDeltas         int
 	flagNoDefaultAlias bool
 	flagProductName    string
 }
@@ -62,10 +63,14 @@ If one argument is specified, it is assumed to be a unified image,
 with both the metadata and rootfs in a single tarball.
 
 Otherwise, it is a split image (separate files for metadata and rootfs/disk).
+
+For split images, VCDIFF deltas from the previous versions of the product
+are generated using xdelta3 when available.
 `)
 	cmd.RunE = c.run
 
 	cmd.Flags().StringArrayVar(&c.flagAliases, "alias", nil, "Add alias")
+	cmd.Flags().IntVar(&c.flagDeltas, "deltas", 1, "Number of previous versions to generate deltas from (0 to disable)"+"``")
 	cmd.Flags().BoolVar(&c.flagNoDefaultAlias, "no-default-alias", false, "Do not add the default alias")
 	cmd.Flags().StringVar(&c.flagProductName, "product-name", "", "Set the product name")
 
@@ -478,6 +483,14 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 	// Update the version.
 	product.Versions[versionName] = version
 
+	// Generate the deltas from the previous versions.
+	if !isUnifiedTarball {
+		err = generateDeltas(&product, versionName, c.flagDeltas)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update the product.
 	products.Products[productName] = product
 
@@ -493,7 +506,7 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Re-generate the index.
-	err = writeIndex(&products)
+	err = writeIndex(&products, c.global.flagSignKey)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lxc/incus/v7/internal/version"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/simplestreams"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// mirrorFileTypes maps the file types Incus can use to whether they're for containers or virtual machines.
+var mirrorFileTypes = map[string]string{
+	"incus.tar.xz":        "",
+	"root.tar.xz":         "container",
+	"squashfs":            "container",
+	"squashfs.vcdiff":     "container",
+	"disk-kvm.img":        "virtual-machine",
+	"disk-kvm.img.vcdiff": "virtual-machine",
+	"disk1.img":           "virtual-machine",
+	"uefi1.img":           "virtual-machine",
+}
+
+type cmdMirror struct {
+	global *cmdGlobal
+
+	flagFilters   []string
+	flagLatest    int
+	flagPublicKey string
+}
+
+func (c *cmdMirror) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "mirror <URL>"
+	cmd.Short = "Mirror images from another server"
+	cmd.Long = cli.FormatSection("Description:",
+		`Mirror images from another simplestreams server
+
+This command copies the images of a remote simplestreams server into the
+local tree. Only missing files are downloaded, so it can be run periodically
+to keep the mirror up to date. Versions which were removed from the remote
+server are removed from the mirror too.
+
+Images can be selected with --filter, using the os, release, variant,
+architecture and type (container or virtual-machine) keys. Filters on
+different keys must all match, while filters repeated for the same key
+match any of their values.
+
+Files which are no longer used by the mirror can then be removed with the
+prune command.
+`)
+	cmd.Example = `  incus-simplestreams mirror https://images.linuxcontainers.org --filter os=Debian --filter release=12 --filter architecture=amd64 --latest 2`
+	cmd.RunE = c.run
+	cmd.Flags().StringArrayVar(&c.flagFilters, "filter", nil, "Only mirror images matching KEY=VALUE"+"``")
+	cmd.Flags().IntVar(&c.flagLatest, "latest", 0, "Number of most recent versions to mirror per image (0 for all)"+"``")
+	cmd.Flags().StringVar(&c.flagPublicKey, "public-key", "", "Public key (PEM) the remote index must be signed with"+"``")
+
+	return cmd
+}
+
+// parseFilters returns the accepted values for each filter key.
+func (c *cmdMirror) parseFilters() (map[string][]string, error) {
+	filters := map[string][]string{}
+	for _, entry := range c.flagFilters {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("Invalid filter %q, must be KEY=VALUE", entry)
+		}
+
+		if !slices.Contains([]string{"os", "release", "variant", "architecture", "type"}, key) {
+			return nil, fmt.Errorf("Unsupported filter key %q", key)
+		}
+
+		filters[key] = append(filters[key], value)
+	}
+
+	return filters, nil
+}
+
+// matchProduct checks whether a product matches the filters.
+func matchProduct(product simplestreams.Product, filters map[string][]string) bool {
+	fields := map[string]string{
+		"os":           product.OperatingSystem,
+		"release":      product.Release,
+		"variant":      product.Variant,
+		"architecture": product.Architecture,
+	}
+
+	for key, values := range filters {
+		if key == "type" {
+			continue
+		}
+
+		if !slices.ContainsFunc(values, func(value string) bool { return strings.EqualFold(value, fields[key]) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *cmdMirror) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := cli.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	filters, err := c.parseFilters()
+	if err != nil {
+		return err
+	}
+
+	// Connect to the remote server.
+	ss := simplestreams.NewClient(args[0], *http.DefaultClient, version.UserAgent)
+	if c.flagPublicKey != "" {
+		keyData, err := os.ReadFile(c.flagPublicKey)
+		if err != nil {
+			return err
+		}
+
+		keys, err := localtls.ParsePublicKeys(keyData)
+		if err != nil {
+			return err
+		}
+
+		ss.SetVerifyKeys(keys)
+	}
+
+	stream, err := ss.GetStream()
+	if err != nil {
+		return err
+	}
+
+	// Load the local images file.
+	products := simplestreams.Products{
+		ContentID: "images",
+		DataType:  "image-downloads",
+		Format:    "products:1.0",
+		Products:  map[string]simplestreams.Product{},
+	}
+
+	body, err := os.ReadFile("streams/v1/images.json")
+	if err == nil {
+		err = json.Unmarshal(body, &products)
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for _, dir := range []string{"images", "streams/v1"} {
+		err = os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Go through the remote products.
+	for _, entry := range stream.Index {
+		if entry.DataType != "image-downloads" {
+			continue
+		}
+
+		remoteProducts, err := ss.GetProducts(entry.Path)
+		if err != nil {
+			return err
+		}
+
+		for name, remoteProduct := range remoteProducts.Products {
+			if !matchProduct(remoteProduct, filters) {
+				continue
+			}
+
+			product, err := c.mirrorProduct(args[0], remoteProduct, filters["type"])
+			if err != nil {
+				return fmt.Errorf("Failed mirroring %q: %w", name, err)
+			}
+
+			if product == nil {
+				continue
+			}
+
+			products.Products[name] = *product
+		}
+	}
+
+	// Write back the images file.
+	body, err = json.Marshal(&products)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile("streams/v1/images.json", body, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// Re-generate the index.
+	err = writeIndex(&products, c.global.flagSignKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mirrorItemType returns whether an item is for containers, virtual machines or both, and whether Incus can use it at all.
+func mirrorItemType(item simplestreams.ProductVersionItem) (string, bool) {
+	if item.FileType == "incus_combined.tar.gz" {
+		if item.CombinedType == "" {
+			return "container", true
+		}
+
+		return item.CombinedType, true
+	}
+
+	itemType, ok := mirrorFileTypes[item.FileType]
+
+	return itemType, ok
+}
+
+// mirrorProduct downloads the missing files of a product and returns its local definition.
+func (c *cmdMirror) mirrorProduct(serverURL string, product simplestreams.Product, types []string) (*simplestreams.Product, error) {
+	versionNames := make([]string, 0, len(product.Versions))
+	for name := range product.Versions {
+		versionNames = append(versionNames, name)
+	}
+
+	slices.Sort(versionNames)
+	if c.flagLatest > 0 && len(versionNames) > c.flagLatest {
+		versionNames = versionNames[len(versionNames)-c.flagLatest:]
+	}
+
+	versions := map[string]simplestreams.ProductVersion{}
+	for _, versionName := range versionNames {
+		productVersion := product.Versions[versionName]
+		items := map[string]simplestreams.ProductVersionItem{}
+
+		for key, item := range productVersion.Items {
+			itemType, ok := mirrorItemType(item)
+			if !ok || (itemType != "" && len(types) > 0 && !slices.Contains(types, itemType)) {
+				continue
+			}
+
+			// Skip deltas from versions which aren't mirrored.
+			if item.DeltaBase != "" && !slices.Contains(versionNames, item.DeltaBase) {
+				continue
+			}
+
+			localPath, err := mirrorFile(serverURL, item)
+			if err != nil {
+				return nil, err
+			}
+
+			item.Path = localPath
+			items[key] = item
+		}
+
+		// Only keep versions with an image of the requested types.
+		hasImage := false
+		for _, item := range items {
+			itemType, _ := mirrorItemType(item)
+			if itemType != "" {
+				hasImage = true
+				break
+			}
+		}
+
+		if !hasImage {
+			continue
+		}
+
+		productVersion.Items = items
+		versions[versionName] = productVersion
+	}
+
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	product.Versions = versions
+
+	return &product, nil
+}
+
+// mirrorFile downloads an image file unless already present, and returns its local path.
+// Files are stored by hash, so identical files are only downloaded once.
+func mirrorFile(serverURL string, item simplestreams.ProductVersionItem) (string, error) {
+	if len(item.HashSha256) != 64 || strings.ContainsAny(item.HashSha256, "/.") {
+		return "", fmt.Errorf("Invalid hash for %q", item.Path)
+	}
+
+	localPath := fmt.Sprintf("images/%s.%s", item.HashSha256, item.FileType)
+
+	fi, err := os.Stat(localPath)
+	if err == nil && fi.Size() == item.Size {
+		return localPath, nil
+	}
+
+	fileURL, err := url.JoinPath(serverURL, item.Path)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Downloading %s\n", item.Path)
+
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Unable to fetch %s: %s", fileURL, resp.Status)
+	}
+
+	// Download to a temporary file, only keeping it if it matches the index.
+	tmpFile, err := os.CreateTemp("images", ".mirror-")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	defer func() { _ = tmpFile.Close() }()
+
+	hash256 := sha256.New()
+	size, err := util.SafeCopy(io.MultiWriter(tmpFile, hash256), resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if size != item.Size || fmt.Sprintf("%x", hash256.Sum(nil)) != item.HashSha256 {
+		return "", fmt.Errorf("File %q doesn't match the index", item.Path)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Chmod(tmpFile.Name(), 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmpFile.Name(), localPath)
+	if err != nil {
+		return "", err
+	}
+
+	return localPath, nil
+}
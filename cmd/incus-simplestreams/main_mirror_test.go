@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/simplestreams"
+)
+
+func TestMirrorParseFilters(t *testing.T) {
+	c := &cmdMirror{flagFilters: []string{"os=Debian", "architecture=amd64", "architecture=arm64", "type=container"}}
+
+	filters, err := c.parseFilters()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"os": {"Debian"}, "architecture": {"amd64", "arm64"}, "type": {"container"}}, filters)
+
+	for _, filter := range []string{"os", "os=", "arch=amd64"} {
+		c := &cmdMirror{flagFilters: []string{filter}}
+
+		_, err := c.parseFilters()
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestMatchProduct(t *testing.T) {
+	product := simplestreams.Product{OperatingSystem: "Debian", Release: "12", Variant: "cloud", Architecture: "amd64"}
+
+	tests := []struct {
+		name    string
+		filters map[string][]string
+		match   bool
+	}{
+		{name: "no filters", filters: map[string][]string{}, match: true},
+		{name: "case insensitive", filters: map[string][]string{"os": {"debian"}}, match: true},
+		{name: "any value", filters: map[string][]string{"architecture": {"arm64", "amd64"}}, match: true},
+		{name: "all keys", filters: map[string][]string{"os": {"Debian"}, "release": {"12"}, "variant": {"cloud"}}, match: true},
+		{name: "type is per item", filters: map[string][]string{"type": {"virtual-machine"}}, match: true},
+		{name: "other value", filters: map[string][]string{"release": {"11"}}},
+		{name: "one key not matching", filters: map[string][]string{"os": {"Debian"}, "variant": {"default"}}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, matchProduct(product, test.filters), test.name)
+	}
+}
+
+func TestMirrorItemType(t *testing.T) {
+	tests := []struct {
+		item     simplestreams.ProductVersionItem
+		itemType string
+		usable   bool
+	}{
+		{item: simplestreams.ProductVersionItem{FileType: "incus.tar.xz"}, itemType: "", usable: true},
+		{item: simplestreams.ProductVersionItem{FileType: "squashfs"}, itemType: "container", usable: true},
+		{item: simplestreams.ProductVersionItem{FileType: "disk-kvm.img.vcdiff"}, itemType: "virtual-machine", usable: true},
+		{item: simplestreams.ProductVersionItem{FileType: "incus_combined.tar.gz"}, itemType: "container", usable: true},
+		{item: simplestreams.ProductVersionItem{FileType: "incus_combined.tar.gz", CombinedType: "virtual-machine"}, itemType: "virtual-machine", usable: true},
+		{item: simplestreams.ProductVersionItem{FileType: "lxd.tar.xz"}},
+	}
+
+	for _, test := range tests {
+		itemType, usable := mirrorItemType(test.item)
+		assert.Equal(t, test.itemType, itemType, test.item.FileType)
+		assert.Equal(t, test.usable, usable, test.item.FileType)
+	}
+}
+
+func TestMirrorProduct(t *testing.T) {
+	files := map[string][]byte{}
+	newItem := func(path string, fileType string, deltaBase string) simplestreams.ProductVersionItem {
+		content := []byte(path)
+		files["/"+path] = content
+
+		return simplestreams.ProductVersionItem{FileType: fileType, Path: path, HashSha256: fmt.Sprintf("%x", sha256.Sum256(content)), Size: int64(len(content)), DeltaBase: deltaBase}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(content)
+	}))
+
+	defer server.Close()
+
+	product := simplestreams.Product{
+		Versions: map[string]simplestreams.ProductVersion{
+			"1": {Items: map[string]simplestreams.ProductVersionItem{
+				"incus.tar.xz": newItem("1/incus.tar.xz", "incus.tar.xz", ""),
+				"squashfs":     newItem("1/rootfs.squashfs", "squashfs", ""),
+			}},
+			"2": {Items: map[string]simplestreams.ProductVersionItem{
+				"incus.tar.xz": newItem("2/incus.tar.xz", "incus.tar.xz", ""),
+				"squashfs":     newItem("2/rootfs.squashfs", "squashfs", ""),
+				"disk-kvm.img": newItem("2/disk.qcow2", "disk-kvm.img", ""),
+			}},
+			"3": {Items: map[string]simplestreams.ProductVersionItem{
+				"incus.tar.xz":         newItem("3/incus.tar.xz", "incus.tar.xz", ""),
+				"squashfs":             newItem("3/rootfs.squashfs", "squashfs", ""),
+				"disk-kvm.img":         newItem("3/disk.qcow2", "disk-kvm.img", ""),
+				"delta-1.squashfs":     newItem("3/delta-1.vcdiff", "squashfs.vcdiff", "1"),
+				"delta-2.squashfs":     newItem("3/delta-2.vcdiff", "squashfs.vcdiff", "2"),
+				"delta-2.disk-kvm.img": newItem("3/delta-2.disk.vcdiff", "disk-kvm.img.vcdiff", "2"),
+				"root.tar.xz.asc":      newItem("3/root.tar.xz.asc", "root.tar.xz.asc", ""),
+			}},
+			"4": {Items: map[string]simplestreams.ProductVersionItem{
+				"incus.tar.xz": newItem("4/incus.tar.xz", "incus.tar.xz", ""),
+				"disk-kvm.img": newItem("4/disk.qcow2", "disk-kvm.img", ""),
+			}},
+		},
+	}
+
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("images", 0o755))
+
+	// Only the latest versions with images of the requested types are kept.
+	c := &cmdMirror{flagLatest: 3}
+	mirrored, err := c.mirrorProduct(server.URL, product, []string{"container"})
+	require.NoError(t, err)
+	require.NotNil(t, mirrored)
+
+	versions := map[string][]string{}
+	for name, version := range mirrored.Versions {
+		for key, item := range version.Items {
+			versions[name] = append(versions[name], key)
+			assert.FileExists(t, item.Path)
+			assert.Equal(t, fmt.Sprintf("images/%s.%s", item.HashSha256, item.FileType), item.Path)
+		}
+	}
+
+	assert.Len(t, versions, 2)
+	assert.ElementsMatch(t, []string{"incus.tar.xz", "squashfs"}, versions["2"])
+	assert.ElementsMatch(t, []string{"incus.tar.xz", "squashfs", "delta-2.squashfs"}, versions["3"])
+
+	// Products without any image of the requested types are skipped.
+	mirrored, err = c.mirrorProduct(server.URL, product, []string{"unknown"})
+	require.NoError(t, err)
+	assert.Nil(t, mirrored)
+
+	// Files not matching the index are rejected.
+	files["/4/disk.qcow2"] = []byte("tampered")
+
+	_, err = c.mirrorProduct(server.URL, product, []string{"virtual-machine"})
+	assert.Error(t, err)
+	assert.NoFileExists(t, "images/"+product.Versions["4"].Items["disk-kvm.img"].HashSha256+".disk-kvm.img")
+}
@@ -199,7 +199,7 @@ func (c *cmdPrune) prune() error {
 		}
 
 		// Re-generate the index.
-		err = writeIndex(&products)
+		err = writeIndex(&products, c.global.flagSignKey)
 		if err != nil {
 			return err
 		}
@@ -168,7 +168,7 @@ func (c *cmdRemove) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Re-generate the index.
-	err = writeIndex(&products)
+	err = writeIndex(&products, c.global.flagSignKey)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+)
+
+type cmdServe struct {
+	global *cmdGlobal
+
+	flagAddress string
+	flagTLSCert string
+	flagTLSKey  string
+}
+
+func (c *cmdServe) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "serve"
+	cmd.Short = "Serve the image server over HTTPS"
+	cmd.Long = cli.FormatSection("Description:",
+		`Serve the image server over HTTPS
+
+This command serves the index and image files of the current directory.
+Other files, like signing keys, are never served.
+
+Unless a certificate and key are provided, a self-signed certificate is
+generated and its fingerprint printed, to be passed to "incus remote add".
+`)
+	cmd.RunE = c.run
+	cmd.Flags().StringVar(&c.flagAddress, "address", ":8443", "Address to listen on"+"``")
+	cmd.Flags().StringVar(&c.flagTLSCert, "tls-cert", "", "TLS certificate (PEM) to serve with"+"``")
+	cmd.Flags().StringVar(&c.flagTLSKey, "tls-key", "", "TLS private key (PEM) to serve with"+"``")
+
+	return cmd
+}
+
+func (c *cmdServe) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := cli.CheckArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	if (c.flagTLSCert == "") != (c.flagTLSKey == "") {
+		return errors.New("Both --tls-cert and --tls-key must be provided")
+	}
+
+	// Load or generate the certificate.
+	var certPEM, keyPEM []byte
+	if c.flagTLSCert != "" {
+		certPEM, err = os.ReadFile(c.flagTLSCert)
+		if err != nil {
+			return err
+		}
+
+		keyPEM, err = os.ReadFile(c.flagTLSKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		certPEM, keyPEM, err = localtls.GenerateMemCert(false, true)
+		if err != nil {
+			return err
+		}
+
+		fingerprint, err := localtls.CertFingerprintStr(string(certPEM))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Certificate fingerprint: %s\n", fingerprint)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig := localtls.InitTLSConfig()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	server := &http.Server{
+		Addr:              c.flagAddress,
+		Handler:           http.HandlerFunc(c.serveFile),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	fmt.Printf("Serving on %s\n", c.flagAddress)
+
+	return server.ListenAndServeTLS("", "")
+}
+
+// serveFile serves a single index or image file.
+func (c *cmdServe) serveFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only serve the index and image files.
+	name := path.Clean("/" + r.URL.Path)
+	if !strings.HasPrefix(name, "/streams/v1/") && !strings.HasPrefix(name, "/images/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open("." + name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(name, ".json"):
+		w.Header().Set("Content-Type", "application/json")
+	case strings.HasSuffix(name, ".sig"):
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	default:
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+
+	// The index must be re-fetched to notice new images.
+	if strings.HasPrefix(name, "/streams/") {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v7/shared/cmd"
+)
+
+type cmdSign struct {
+	global *cmdGlobal
+}
+
+func (c *cmdSign) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "sign"
+	cmd.Short = "Sign the index files"
+	cmd.Long = cli.FormatSection("Description:",
+		`Sign the index files
+
+This command writes a detached signature next to each of the index files
+using the private key passed with --sign-key.
+
+Incus servers can then be configured to only accept images from signed indexes.
+`)
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdSign) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := cli.CheckArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	if c.global.flagSignKey == "" {
+		return errors.New("A private key must be provided with --sign-key")
+	}
+
+	return signIndex(c.global.flagSignKey)
+}
@@ -17,6 +17,8 @@ import (
 
 type cmdVerify struct {
 	global *cmdGlobal
+
+	flagPublicKey string
 }
 
 func (c *cmdVerify) command() *cobra.Command {
@@ -29,8 +31,11 @@ func (c *cmdVerify) command() *cobra.Command {
 This command will analyze the image index and for every image and file
 in the index, will validate that the files on disk exist and are of the
 correct size and content.
+
+With --public-key, the signatures of the index files are checked too.
 `)
 	cmd.RunE = c.run
+	cmd.Flags().StringVar(&c.flagPublicKey, "public-key", "", "Public key (PEM) to check the index signatures with"+"``")
 
 	return cmd
 }
@@ -60,6 +65,14 @@ func (c *cmdVerify) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check the index signatures.
+	if c.flagPublicKey != "" {
+		err = verifyIndex(c.flagPublicKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Go over all the files.
 	for _, product := range products.Products {
 		for _, version := range product.Versions {
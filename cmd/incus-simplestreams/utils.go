@@ -1,13 +1,21 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 
 	"github.com/lxc/incus/v7/shared/simplestreams"
+	localtls "github.com/lxc/incus/v7/shared/tls"
 )
 
-func writeIndex(products *simplestreams.Products) error {
+// indexFiles lists the index files which get a detached signature.
+var indexFiles = []string{"streams/v1/index.json", "streams/v1/images.json"}
+
+func writeIndex(products *simplestreams.Products, signKey string) error {
 	// Update the product list.
 	productNames := make([]string, 0, len(products.Products))
 	for name := range products.Products {
@@ -37,5 +45,90 @@ func writeIndex(products *simplestreams.Products) error {
 		return err
 	}
 
+	return signIndex(signKey)
+}
+
+// signIndex writes the detached signatures of the index files.
+// Without a key, any existing signature is removed as it no longer matches.
+func signIndex(signKey string) error {
+	if signKey == "" {
+		for _, path := range indexFiles {
+			err := os.Remove(path + ".sig")
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	keyData, err := os.ReadFile(signKey)
+	if err != nil {
+		return err
+	}
+
+	key, err := localtls.ParsePrivateKey(keyData)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range indexFiles {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		signature, err := localtls.SignContent(key, body)
+		if err != nil {
+			return fmt.Errorf("Failed signing %q: %w", path, err)
+		}
+
+		err = os.WriteFile(path+".sig", []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), 0o644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyIndex checks the detached signatures of the index files.
+func verifyIndex(publicKey string) error {
+	keyData, err := os.ReadFile(publicKey)
+	if err != nil {
+		return err
+	}
+
+	keys, err := localtls.ParsePublicKeys(keyData)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range indexFiles {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		encoded, err := os.ReadFile(path + ".sig")
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("Missing signature for %q", path)
+			}
+
+			return err
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(string(encoded))
+		if err != nil {
+			return fmt.Errorf("Invalid signature for %q: %w", path, err)
+		}
+
+		err = localtls.VerifySignature(keys, body, signature)
+		if err != nil {
+			return fmt.Errorf("Invalid signature for %q: %w", path, err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,1071 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/termios"
+)
+
+type cmdAuth struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuth) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("auth")
+	cmd.Short = i18n.G("Manage authorization")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Manage the identities, groups and permissions used by the built-in authorization driver`,
+	))
+
+	// Group
+	authGroupCmd := cmdAuthGroup{global: c.global}
+	cmd.AddCommand(authGroupCmd.command())
+
+	// Identity
+	authIdentityCmd := cmdAuthIdentity{global: c.global}
+	cmd.AddCommand(authIdentityCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group.
+type cmdAuthGroup struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthGroup) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("group")
+	cmd.Short = i18n.G("Manage authorization groups")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage authorization groups`))
+
+	// Create
+	authGroupCreateCmd := cmdAuthGroupCreate{global: c.global}
+	cmd.AddCommand(authGroupCreateCmd.command())
+
+	// Delete
+	authGroupDeleteCmd := cmdAuthGroupDelete{global: c.global}
+	cmd.AddCommand(authGroupDeleteCmd.command())
+
+	// Edit
+	authGroupEditCmd := cmdAuthGroupEdit{global: c.global}
+	cmd.AddCommand(authGroupEditCmd.command())
+
+	// List
+	authGroupListCmd := cmdAuthGroupList{global: c.global}
+	cmd.AddCommand(authGroupListCmd.command())
+
+	// Permission
+	authGroupPermissionCmd := cmdAuthGroupPermission{global: c.global}
+	cmd.AddCommand(authGroupPermissionCmd.command())
+
+	// Rename
+	authGroupRenameCmd := cmdAuthGroupRename{global: c.global}
+	cmd.AddCommand(authGroupRenameCmd.command())
+
+	// Show
+	authGroupShowCmd := cmdAuthGroupShow{global: c.global}
+	cmd.AddCommand(authGroupShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group create.
+type cmdAuthGroupCreate struct {
+	global *cmdGlobal
+
+	flagDescription string
+}
+
+var cmdAuthGroupCreateUsage = u.Usage{u.NewName(u.Group).Remote()}
+
+func (c *cmdAuthGroupCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdAuthGroupCreateUsage...)
+	cmd.Short = i18n.G("Create authorization groups")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Create authorization groups`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group create developers
+    Create an authorization group named developers
+
+incus auth group create developers < group.yaml
+    Create an authorization group named developers with the permissions from group.yaml`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Group description"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupCreate) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupCreateUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+	var stdinData api.AuthGroupPut
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = loader.Load(&stdinData)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	// Create the group
+	group := api.AuthGroupsPost{
+		Name:         groupName,
+		AuthGroupPut: stdinData,
+	}
+
+	if c.flagDescription != "" {
+		group.Description = c.flagDescription
+	}
+
+	err = d.CreateAuthGroup(group)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s created")+"\n", formatRemote(c.global.conf, parsed[0]))
+	}
+
+	return nil
+}
+
+// Group delete.
+type cmdAuthGroupDelete struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupDeleteUsage = u.Usage{u.Group.Remote().List(1)}
+
+func (c *cmdAuthGroupDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdAuthGroupDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete authorization groups")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Delete authorization groups`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupDelete) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupDeleteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, p := range parsed[0].List {
+		d := p.RemoteServer
+		groupName := p.RemoteObject.String
+
+		// Delete the group
+		err = d.DeleteAuthGroup(groupName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !c.global.flagQuiet {
+			fmt.Printf(i18n.G("Authorization group %s deleted")+"\n", formatRemote(c.global.conf, p))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Group edit.
+type cmdAuthGroupEdit struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupEditUsage = u.Usage{u.Group.Remote()}
+
+func (c *cmdAuthGroupEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdAuthGroupEditUsage...)
+	cmd.Short = i18n.G("Edit authorization groups as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Edit authorization groups as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth group edit <group> < group.yaml
+    Update an authorization group using the content of group.yaml`,
+	))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the authorization group.
+### Any line starting with a '# will be ignored.
+###
+### A group grants entitlements on objects to its members, for example:
+###
+### description: Web developers
+### permissions:
+### - entitlement: operator
+###   object: project:web
+### - entitlement: can_exec
+###   object: instance:default/db
+###
+### Note that the name is shown but cannot be changed`,
+	)
+}
+
+func (c *cmdAuthGroupEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthGroupPut{}
+		err = loader.Load(&newdata)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateAuthGroup(groupName, newdata, "")
+	}
+
+	// Extract the current value
+	group, etag, err := d.GetAuthGroup(groupName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&group, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthGroupPut{}
+		err = yaml.Load(content, &newdata)
+		if err == nil {
+			err = d.UpdateAuthGroup(groupName, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Group list.
+type cmdAuthGroupList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+var cmdAuthGroupListUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdAuthGroupList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdAuthGroupListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List authorization groups")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`List authorization groups`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	// List the groups
+	groups, err := d.GetAuthGroups()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, group := range groups {
+		data = append(data, []string{group.Name, group.Description, fmt.Sprintf("%d", len(group.Permissions)), fmt.Sprintf("%d", len(group.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PERMISSIONS"),
+		i18n.G("IDENTITIES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, groups)
+}
+
+// Group permission.
+type cmdAuthGroupPermission struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthGroupPermission) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("permission")
+	cmd.Short = i18n.G("Manage authorization group permissions")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage authorization group permissions`))
+
+	// Add
+	authGroupPermissionAddCmd := cmdAuthGroupPermissionAdd{global: c.global}
+	cmd.AddCommand(authGroupPermissionAddCmd.command())
+
+	// Remove
+	authGroupPermissionRemoveCmd := cmdAuthGroupPermissionRemove{global: c.global}
+	cmd.AddCommand(authGroupPermissionRemoveCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group permission add.
+type cmdAuthGroupPermissionAdd struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupPermissionAddUsage = u.Usage{u.Group.Remote(), u.Entitlement, u.Object}
+
+func (c *cmdAuthGroupPermissionAdd) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("add", cmdAuthGroupPermissionAddUsage...)
+	cmd.Short = i18n.G("Grant an entitlement to an authorization group")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Grant an entitlement to an authorization group`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group permission add developers operator project:web
+    Make the members of developers operators of the web project
+
+incus auth group permission add developers can_exec instance:default/db
+    Allow the members of developers to run commands in the db instance`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupPermissionAdd) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupPermissionAddUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+	permission := api.Permission{Entitlement: parsed[1].String, Object: parsed[2].String}
+
+	// Get the group
+	group, etag, err := d.GetAuthGroup(groupName)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(group.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Group %q already has entitlement %q on %q"), groupName, permission.Entitlement, permission.Object)
+	}
+
+	group.Permissions = append(group.Permissions, permission)
+
+	return d.UpdateAuthGroup(groupName, group.Writable(), etag)
+}
+
+// Group permission remove.
+type cmdAuthGroupPermissionRemove struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupPermissionRemoveUsage = u.Usage{u.Group.Remote(), u.Entitlement, u.Object}
+
+func (c *cmdAuthGroupPermissionRemove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("remove", cmdAuthGroupPermissionRemoveUsage...)
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Revoke an entitlement from an authorization group")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Revoke an entitlement from an authorization group`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupPermissionRemove) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupPermissionRemoveUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+	permission := api.Permission{Entitlement: parsed[1].String, Object: parsed[2].String}
+
+	// Get the group
+	group, etag, err := d.GetAuthGroup(groupName)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(group.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Group %q doesn't have entitlement %q on %q"), groupName, permission.Entitlement, permission.Object)
+	}
+
+	group.Permissions = slices.DeleteFunc(group.Permissions, func(p api.Permission) bool { return p == permission })
+
+	return d.UpdateAuthGroup(groupName, group.Writable(), etag)
+}
+
+// Group rename.
+type cmdAuthGroupRename struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupRenameUsage = u.Usage{u.Group.Remote(), u.NewName(u.Group)}
+
+func (c *cmdAuthGroupRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("rename", cmdAuthGroupRenameUsage...)
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename authorization groups")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Rename authorization groups`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupRename) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupRenameUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+	newGroupName := parsed[1].String
+
+	// Rename the group
+	err = d.RenameAuthGroup(groupName, api.AuthGroupPost{Name: newGroupName})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s renamed to %s")+"\n", formatRemote(c.global.conf, parsed[0]), newGroupName)
+	}
+
+	return nil
+}
+
+// Group show.
+type cmdAuthGroupShow struct {
+	global *cmdGlobal
+}
+
+var cmdAuthGroupShowUsage = u.Usage{u.Group.Remote()}
+
+func (c *cmdAuthGroupShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdAuthGroupShowUsage...)
+	cmd.Short = i18n.G("Show authorization group details")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Show authorization group details`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthGroupShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	groupName := parsed[0].RemoteObject.String
+
+	// Show the group
+	group, _, err := d.GetAuthGroup(groupName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&group, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Identity.
+type cmdAuthIdentity struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthIdentity) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("identity")
+	cmd.Short = i18n.G("Manage identities")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Manage identities
+
+Identities are identified by their authentication method (tls or oidc)
+and their identifier (certificate fingerprint or OIDC user name).`,
+	))
+
+	// Create
+	authIdentityCreateCmd := cmdAuthIdentityCreate{global: c.global}
+	cmd.AddCommand(authIdentityCreateCmd.command())
+
+	// Delete
+	authIdentityDeleteCmd := cmdAuthIdentityDelete{global: c.global}
+	cmd.AddCommand(authIdentityDeleteCmd.command())
+
+	// Edit
+	authIdentityEditCmd := cmdAuthIdentityEdit{global: c.global}
+	cmd.AddCommand(authIdentityEditCmd.command())
+
+	// Group
+	authIdentityGroupCmd := cmdAuthIdentityGroup{global: c.global}
+	cmd.AddCommand(authIdentityGroupCmd.command())
+
+	// List
+	authIdentityListCmd := cmdAuthIdentityList{global: c.global}
+	cmd.AddCommand(authIdentityListCmd.command())
+
+	// Show
+	authIdentityShowCmd := cmdAuthIdentityShow{global: c.global}
+	cmd.AddCommand(authIdentityShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Identity create.
+type cmdAuthIdentityCreate struct {
+	global *cmdGlobal
+
+	flagDescription string
+	flagGroups      []string
+}
+
+var cmdAuthIdentityCreateUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier}
+
+func (c *cmdAuthIdentityCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdAuthIdentityCreateUsage...)
+	cmd.Short = i18n.G("Create identities")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Create identities`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth identity create oidc jane@example.com --group developers
+    Create an identity for the OIDC user jane@example.com in the developers group`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Identity description"))
+	cli.AddStringArrayFlag(cmd.Flags(), &c.flagGroups, "group|g", i18n.G("Group to add the identity to"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityCreate) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityCreateUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+	var stdinData api.IdentityPut
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = loader.Load(&stdinData)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	// Create the identity
+	identity := api.IdentitiesPost{
+		AuthenticationMethod: authenticationMethod,
+		Identifier:           identifier,
+		IdentityPut:          stdinData,
+	}
+
+	if c.flagDescription != "" {
+		identity.Description = c.flagDescription
+	}
+
+	if len(c.flagGroups) > 0 {
+		identity.Groups = c.flagGroups
+	}
+
+	err = d.CreateIdentity(identity)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s/%s created")+"\n", authenticationMethod, identifier)
+	}
+
+	return nil
+}
+
+// Identity delete.
+type cmdAuthIdentityDelete struct {
+	global *cmdGlobal
+}
+
+var cmdAuthIdentityDeleteUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier}
+
+func (c *cmdAuthIdentityDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdAuthIdentityDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete identities")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Delete identities`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityDelete) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityDeleteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+
+	// Delete the identity
+	err = d.DeleteIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s/%s deleted")+"\n", authenticationMethod, identifier)
+	}
+
+	return nil
+}
+
+// Identity edit.
+type cmdAuthIdentityEdit struct {
+	global *cmdGlobal
+}
+
+var cmdAuthIdentityEditUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier}
+
+func (c *cmdAuthIdentityEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdAuthIdentityEditUsage...)
+	cmd.Short = i18n.G("Edit identities as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Edit identities as YAML`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the identity.
+### Any line starting with a '# will be ignored.
+###
+### Note that the authentication method and identifier are shown but cannot be changed`,
+	)
+}
+
+func (c *cmdAuthIdentityEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.IdentityPut{}
+		err = loader.Load(&newdata)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateIdentity(authenticationMethod, identifier, newdata, "")
+	}
+
+	// Extract the current value
+	identity, etag, err := d.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&identity, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.IdentityPut{}
+		err = yaml.Load(content, &newdata)
+		if err == nil {
+			err = d.UpdateIdentity(authenticationMethod, identifier, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Identity group.
+type cmdAuthIdentityGroup struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthIdentityGroup) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("group")
+	cmd.Short = i18n.G("Manage identity group membership")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage identity group membership`))
+
+	// Add
+	authIdentityGroupAddCmd := cmdAuthIdentityGroupAdd{global: c.global}
+	cmd.AddCommand(authIdentityGroupAddCmd.command())
+
+	// Remove
+	authIdentityGroupRemoveCmd := cmdAuthIdentityGroupRemove{global: c.global}
+	cmd.AddCommand(authIdentityGroupRemoveCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Identity group add.
+type cmdAuthIdentityGroupAdd struct {
+	global *cmdGlobal
+}
+
+var cmdAuthIdentityGroupAddUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier, u.Group}
+
+func (c *cmdAuthIdentityGroupAdd) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("add", cmdAuthIdentityGroupAddUsage...)
+	cmd.Short = i18n.G("Add an identity to an authorization group")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Add an identity to an authorization group`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityGroupAdd) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityGroupAddUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+	groupName := parsed[2].String
+
+	// Get the identity
+	identity, etag, err := d.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(identity.Groups, groupName) {
+		return fmt.Errorf(i18n.G("Identity %s/%s is already in group %q"), authenticationMethod, identifier, groupName)
+	}
+
+	identity.Groups = append(identity.Groups, groupName)
+
+	return d.UpdateIdentity(authenticationMethod, identifier, identity.Writable(), etag)
+}
+
+// Identity group remove.
+type cmdAuthIdentityGroupRemove struct {
+	global *cmdGlobal
+}
+
+var cmdAuthIdentityGroupRemoveUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier, u.Group}
+
+func (c *cmdAuthIdentityGroupRemove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("remove", cmdAuthIdentityGroupRemoveUsage...)
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove an identity from an authorization group")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Remove an identity from an authorization group`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityGroupRemove) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityGroupRemoveUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+	groupName := parsed[2].String
+
+	// Get the identity
+	identity, etag, err := d.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(identity.Groups, groupName) {
+		return fmt.Errorf(i18n.G("Identity %s/%s isn't in group %q"), authenticationMethod, identifier, groupName)
+	}
+
+	identity.Groups = slices.DeleteFunc(identity.Groups, func(g string) bool { return g == groupName })
+
+	return d.UpdateIdentity(authenticationMethod, identifier, identity.Writable(), etag)
+}
+
+// Identity list.
+type cmdAuthIdentityList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+var cmdAuthIdentityListUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdAuthIdentityList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdAuthIdentityListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List identities")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`List identities`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	// List the identities
+	identities, err := d.GetIdentities()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, identity := range identities {
+		data = append(data, []string{identity.AuthenticationMethod, identity.Identifier, identity.Description, strings.Join(identity.Groups, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("AUTHENTICATION METHOD"),
+		i18n.G("IDENTIFIER"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("GROUPS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, identities)
+}
+
+// Identity show.
+type cmdAuthIdentityShow struct {
+	global *cmdGlobal
+}
+
+var cmdAuthIdentityShowUsage = u.Usage{u.AuthMethod.Remote(), u.Identifier}
+
+func (c *cmdAuthIdentityShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdAuthIdentityShowUsage...)
+	cmd.Short = i18n.G("Show identity details")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Show identity details`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdAuthIdentityShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	authenticationMethod := parsed[0].RemoteObject.String
+	identifier := parsed[1].String
+
+	// Show the identity
+	identity, _, err := d.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&identity, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
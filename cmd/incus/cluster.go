@@ -118,6 +118,14 @@ func (c *cmdCluster) command() *cobra.Command {
 	cmdClusterRestore := cmdClusterRestore{global: c.global, cluster: c}
 	cmd.AddCommand(cmdClusterRestore.command())
 
+	// Re-balance cluster
+	cmdClusterRebalance := cmdClusterRebalance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterRebalance.command())
+
+	// Upgrade
+	cmdClusterUpgrade := cmdClusterUpgrade{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterUpgrade.command())
+
 	clusterGroupCmd := cmdClusterGroup{global: c.global, cluster: c}
 	cmd.AddCommand(clusterGroupCmd.command())
 
@@ -1576,6 +1584,194 @@ func (c *cmdClusterEvacuateAction) run(cmd *cobra.Command, args []string) error
 	return nil
 }
 
+// Cluster re-balancing.
+type cmdClusterRebalance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagDryRun bool
+}
+
+var cmdClusterRebalanceUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdClusterRebalance) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("rebalance", cmdClusterRebalanceUsage...)
+	cmd.Short = i18n.G("Re-balance instances across the cluster")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Re-balance instances across the cluster
+
+The dry-run flag shows the member scores and the instance migrations that would be performed, without moving anything.`))
+
+	cli.AddBoolFlag(cmd.Flags(), &c.flagDryRun, "dry-run", i18n.G("Only show the re-balancing plan"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRebalance) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdClusterRebalanceUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	if c.flagDryRun {
+		plan, err := d.GetClusterRebalancePlan()
+		if err != nil {
+			return err
+		}
+
+		// Render as YAML.
+		data, err := yaml.Dump(plan, yaml.WithV2Defaults())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s", data)
+		return nil
+	}
+
+	op, err := d.RebalanceCluster()
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Re-balancing cluster: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+	return nil
+}
+
+// Rolling cluster upgrade.
+type cmdClusterUpgrade struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagMembers []string
+	flagVersion string
+	flagTimeout int
+}
+
+var cmdClusterUpgradeUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdClusterUpgrade) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("upgrade", cmdClusterUpgradeUsage...)
+	cmd.Short = i18n.G("Perform a rolling upgrade of the cluster")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Perform a rolling upgrade of the cluster
+
+Cluster members are upgraded one at a time. Each member is evacuated, upgraded,
+waited for until it comes back with a new version and then restored.`))
+
+	cli.AddStringArrayFlag(cmd.Flags(), &c.flagMembers, "member", i18n.G("Cluster member to upgrade (can be repeated, defaults to all members)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagVersion, "version", "", "", i18n.G("Version the members are expected to come back with"))
+	cli.AddIntFlag(cmd.Flags(), &c.flagTimeout, "timeout", 0, i18n.G("Time in seconds to wait for each member to come back"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterUpgrade) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdClusterUpgradeUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	remoteName := parsed[0].RemoteName
+	d := parsed[0].RemoteServer
+
+	op, err := d.UpgradeCluster(api.ClusterUpgradePost{
+		Members: c.flagMembers,
+		Version: c.flagVersion,
+		Timeout: int64(c.flagTimeout),
+	})
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Upgrading cluster: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	// Follow the upgrade, including when it gets handed over to another member. The member handing
+	// it over gets upgraded next, so the handover operation is followed through the other member.
+	opID := op.Get().ID
+	for {
+		current, _, err := d.GetOperationWait(opID, 5)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+
+		progress.UpdateOp(*current)
+
+		handover, _ := current.Metadata["handover_operation"].(string)
+		if handover != "" {
+			memberName, _ := current.Metadata["handover_member"].(string)
+
+			member, _, err := d.GetClusterMember(memberName)
+			if err != nil {
+				progress.Done("")
+				return err
+			}
+
+			d, err = c.global.conf.GetInstanceServerAddress(remoteName, member.URL)
+			if err != nil {
+				progress.Done("")
+				return fmt.Errorf(i18n.G("Failed connecting to cluster member %q: %w"), memberName, err)
+			}
+
+			opID = handover
+			continue
+		}
+
+		if current.StatusCode.IsFinal() {
+			progress.Done("")
+
+			if current.Err != "" {
+				return errors.New(current.Err)
+			}
+
+			return nil
+		}
+	}
+}
+
 // prepareClusterMemberServerFilters processes and formats filter criteria
 // for cluster members, ensuring they are in a format that the server can interpret.
 func prepareClusterMemberServerFilters(filters []string, i any) []string {
@@ -69,6 +69,10 @@ func (c *cmdConfigTrust) command() *cobra.Command {
 	configTrustShowCmd := cmdConfigTrustShow{global: c.global, config: c.config, configTrust: c}
 	cmd.AddCommand(configTrustShowCmd.command())
 
+	// Token
+	configTrustTokenCmd := cmdConfigTrustToken{global: c.global}
+	cmd.AddCommand(configTrustTokenCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
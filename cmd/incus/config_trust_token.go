@@ -0,0 +1,452 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/termios"
+)
+
+type cmdConfigTrustToken struct {
+	global *cmdGlobal
+}
+
+func (c *cmdConfigTrustToken) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("token")
+	cmd.Short = i18n.G("Manage API tokens")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Manage API tokens
+
+API tokens can be used as bearer tokens to access the API without a client certificate.`,
+	))
+
+	// Create
+	configTrustTokenCreateCmd := cmdConfigTrustTokenCreate{global: c.global}
+	cmd.AddCommand(configTrustTokenCreateCmd.command())
+
+	// Edit
+	configTrustTokenEditCmd := cmdConfigTrustTokenEdit{global: c.global}
+	cmd.AddCommand(configTrustTokenEditCmd.command())
+
+	// List
+	configTrustTokenListCmd := cmdConfigTrustTokenList{global: c.global}
+	cmd.AddCommand(configTrustTokenListCmd.command())
+
+	// Revoke
+	configTrustTokenRevokeCmd := cmdConfigTrustTokenRevoke{global: c.global}
+	cmd.AddCommand(configTrustTokenRevokeCmd.command())
+
+	// Show
+	configTrustTokenShowCmd := cmdConfigTrustTokenShow{global: c.global}
+	cmd.AddCommand(configTrustTokenShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdConfigTrustTokenCreate struct {
+	global *cmdGlobal
+
+	flagDescription  string
+	flagExpiry       string
+	flagProjects     string
+	flagEntitlements string
+}
+
+var cmdConfigTrustTokenCreateUsage = u.Usage{u.NewName(u.Token).Remote()}
+
+func (c *cmdConfigTrustTokenCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdConfigTrustTokenCreateUsage...)
+	cmd.Short = i18n.G("Create API tokens")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Create API tokens
+
+The secret of the token is only shown once and can't be retrieved later.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus config trust token create ci --expiry 30d --projects ci
+    Create an API token named ci, expiring in 30 days and restricted to the ci project
+
+incus config trust token create monitoring --entitlements can_view,can_view_metrics
+    Create a read-only API token named monitoring`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Token description"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagExpiry, "expiry", "", "", i18n.G("Expiry of the token (either a time span like `1d 3H` or a date in `2006/01/02 15:04 MST` format)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagProjects, "projects", "", "", i18n.G("List of projects to restrict the token to"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagEntitlements, "entitlements", "", "", i18n.G("List of entitlements to restrict the token to"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenCreate) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdConfigTrustTokenCreateUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	tokenName := parsed[0].RemoteObject.String
+	var stdinData api.AuthTokenPut
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = loader.Load(&stdinData)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	// Prepare the request.
+	token := api.AuthTokensPost{
+		Name:         tokenName,
+		AuthTokenPut: stdinData,
+	}
+
+	if c.flagDescription != "" {
+		token.Description = c.flagDescription
+	}
+
+	if c.flagExpiry != "" {
+		// Try to parse as a duration.
+		expiry, err := instance.GetExpiry(time.Now(), c.flagExpiry)
+		if err != nil {
+			if !errors.Is(err, instance.ErrInvalidExpiry) {
+				return err
+			}
+
+			// Fallback to date parsing.
+			expiry, err = time.Parse(dateLayout, c.flagExpiry)
+			if err != nil {
+				return err
+			}
+		}
+
+		token.ExpiresAt = expiry
+	}
+
+	if c.flagProjects != "" {
+		token.Restricted = true
+		token.Projects = strings.Split(c.flagProjects, ",")
+	}
+
+	if c.flagEntitlements != "" {
+		token.Entitlements = strings.Split(c.flagEntitlements, ",")
+	}
+
+	// Create the token.
+	secret, err := d.CreateAuthToken(token)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("API token %s created:")+"\n", formatRemote(c.global.conf, parsed[0]))
+	}
+
+	fmt.Println(secret.Token)
+
+	return nil
+}
+
+// Edit.
+type cmdConfigTrustTokenEdit struct {
+	global *cmdGlobal
+}
+
+var cmdConfigTrustTokenEditUsage = u.Usage{u.Token.Remote()}
+
+func (c *cmdConfigTrustTokenEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdConfigTrustTokenEditUsage...)
+	cmd.Short = i18n.G("Edit API tokens as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Edit API tokens as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus config trust token edit <token> < token.yaml
+    Update an API token using the content of token.yaml`,
+	))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the API token.
+### Any line starting with a '# will be ignored.
+###
+### An API token may be restricted to some projects and entitlements, for example:
+###
+### description: CI pipeline
+### expires_at: 2026-12-31T00:00:00Z
+### restricted: true
+### projects:
+### - ci
+### entitlements:
+### - can_view
+### - can_edit
+###
+### Note that the name is shown but cannot be changed`,
+	)
+}
+
+func (c *cmdConfigTrustTokenEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdConfigTrustTokenEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	tokenName := parsed[0].RemoteObject.String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthTokenPut{}
+		err = loader.Load(&newdata)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateAuthToken(tokenName, newdata, "")
+	}
+
+	// Extract the current value
+	token, etag, err := d.GetAuthToken(tokenName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&token, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthTokenPut{}
+		err = yaml.Load(content, &newdata)
+		if err == nil {
+			err = d.UpdateAuthToken(tokenName, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdConfigTrustTokenList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+var cmdConfigTrustTokenListUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdConfigTrustTokenList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdConfigTrustTokenListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List API tokens")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`List API tokens`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdConfigTrustTokenListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	// List the tokens
+	tokens, err := d.GetAuthTokens()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, token := range tokens {
+		expiresAt := ""
+		if !token.ExpiresAt.IsZero() {
+			expiresAt = token.ExpiresAt.Local().Format(dateLayout)
+		}
+
+		projects := ""
+		if token.Restricted {
+			projects = strings.Join(token.Projects, "\n")
+		}
+
+		data = append(data, []string{token.Name, token.Description, expiresAt, projects, strings.Join(token.Entitlements, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("EXPIRES AT"),
+		i18n.G("PROJECTS"),
+		i18n.G("ENTITLEMENTS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, tokens)
+}
+
+// Revoke.
+type cmdConfigTrustTokenRevoke struct {
+	global *cmdGlobal
+}
+
+var cmdConfigTrustTokenRevokeUsage = u.Usage{u.Token.Remote().List(1)}
+
+func (c *cmdConfigTrustTokenRevoke) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("revoke", cmdConfigTrustTokenRevokeUsage...)
+	cmd.Aliases = []string{"rm", "remove", "delete"}
+	cmd.Short = i18n.G("Revoke API tokens")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Revoke API tokens`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenRevoke) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdConfigTrustTokenRevokeUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, p := range parsed[0].List {
+		d := p.RemoteServer
+		tokenName := p.RemoteObject.String
+
+		// Revoke the token
+		err = d.DeleteAuthToken(tokenName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !c.global.flagQuiet {
+			fmt.Printf(i18n.G("API token %s revoked")+"\n", formatRemote(c.global.conf, p))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Show.
+type cmdConfigTrustTokenShow struct {
+	global *cmdGlobal
+}
+
+var cmdConfigTrustTokenShowUsage = u.Usage{u.Token.Remote()}
+
+func (c *cmdConfigTrustTokenShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdConfigTrustTokenShowUsage...)
+	cmd.Short = i18n.G("Show API token details")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Show API token details`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdConfigTrustTokenShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	tokenName := parsed[0].RemoteObject.String
+
+	// Show the token
+	token, _, err := d.GetAuthToken(tokenName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&token, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -35,6 +35,7 @@ type cmdConsole struct {
 	flagForce   bool
 	flagShowLog bool
 	flagType    string
+	flagPort    string
 
 	withLog bool
 }
@@ -56,6 +57,7 @@ as well as retrieve past log entries from it.`,
 	cli.AddBoolFlag(cmd.Flags(), &c.flagForce, "force|f", i18n.G("Forces a connection to the console, even if there is already an active session"))
 	cli.AddBoolFlag(cmd.Flags(), &c.flagShowLog, "show-log", i18n.G("Retrieve the instance's console log"))
 	cli.AddStringFlag(cmd.Flags(), &c.flagType, "type|t", c.global.defaultConsoleType(), "", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagPort, "port", "", "", i18n.G("Serial device to attach to instead of the main console"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return c.global.cmpInstances(toComplete)
@@ -126,6 +128,10 @@ func (c *cmdConsole) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Unknown output type %q"), c.flagType)
 	}
 
+	if c.flagPort != "" && c.flagType != "console" {
+		return errors.New(i18n.G("The --port flag is only supported by the 'console' output type"))
+	}
+
 	return c.console(d, instanceName)
 }
 
@@ -136,6 +142,10 @@ func (c *cmdConsole) console(d incus.InstanceServer, name string) error {
 			return errors.New(i18n.G("The --show-log flag is only supported for by 'console' output type"))
 		}
 
+		if c.flagPort != "" {
+			return errors.New(i18n.G("The --show-log flag can't be used with --port"))
+		}
+
 		console := &incus.InstanceConsoleLogArgs{}
 		log, err := d.GetInstanceConsoleLog(name, console)
 		if err != nil {
@@ -191,6 +201,7 @@ func (c *cmdConsole) text(d incus.InstanceServer, name string) error {
 		Height: height,
 		Type:   "console",
 		Force:  c.flagForce,
+		Device: c.flagPort,
 	}
 
 	consoleDisconnect := make(chan bool)
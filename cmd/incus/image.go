@@ -72,6 +72,10 @@ hash or alias name (if one is set).`,
 	imageAliasCmd := cmdImageAlias{global: c.global, image: c}
 	cmd.AddCommand(imageAliasCmd.command())
 
+	// Build
+	imageBuildCmd := cmdImageBuild{global: c.global, image: c}
+	cmd.AddCommand(imageBuildCmd.command())
+
 	// Copy
 	imageCopyCmd := cmdImageCopy{global: c.global, image: c}
 	cmd.AddCommand(imageCopyCmd.command())
@@ -104,6 +108,10 @@ hash or alias name (if one is set).`,
 	imageListCmd := cmdImageList{global: c.global, image: c}
 	cmd.AddCommand(imageListCmd.command())
 
+	// Push
+	imagePushCmd := cmdImagePush{global: c.global, image: c}
+	cmd.AddCommand(imagePushCmd.command())
+
 	// Refresh
 	imageRefreshCmd := cmdImageRefresh{global: c.global, image: c}
 	cmd.AddCommand(imageRefreshCmd.command())
@@ -1377,6 +1385,132 @@ func (c *cmdImageList) run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, headers, data, rawData)
 }
 
+// Push.
+type cmdImagePush struct {
+	global *cmdGlobal
+	image  *cmdImage
+}
+
+var cmdImagePushUsage = u.Usage{u.RemoteImage, u.RemoteImage}
+
+func (c *cmdImagePush) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("push", cmdImagePushUsage...)
+	cmd.Short = i18n.G("Push images to OCI registries")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Push images to OCI registries
+
+The target must be an OCI remote, followed by the repository and tag to push to.
+Only container images can be pushed.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus image push my-image my-registry:my-org/my-app:1.0
+    Push the local my-image image to the my-registry OCI registry as my-org/my-app:1.0.`,
+	))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpImages(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdImagePush) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdImagePushUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	remoteName := parsed[0].List[0].Get(c.global.conf.DefaultRemote)
+	imageName := parsed[0].List[1].String
+	targetRemote := parsed[1].List[0].Get(c.global.conf.DefaultRemote)
+	targetName := parsed[1].List[1].String
+
+	d, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	fingerprint := c.image.dereferenceAlias(d, "container", imageName)
+
+	err = pushImageOCI(c.global, d, fingerprint, targetRemote, []string{targetName}, nil)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Image pushed to %s:%s")+"\n", targetRemote, targetName)
+	}
+
+	return nil
+}
+
+// pushImageOCI has the server push one of its images to an OCI registry remote.
+func pushImageOCI(global *cmdGlobal, d incus.InstanceServer, fingerprint string, remoteName string, names []string, config map[string]string) error {
+	remote, ok := global.conf.Remotes[remoteName]
+	if !ok || remote.Protocol != "oci" {
+		return fmt.Errorf(i18n.G("Remote %s isn't an OCI registry"), remoteName)
+	}
+
+	registry, err := global.conf.GetImageServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	// The connection URL includes the registry credentials, if any.
+	info, err := registry.GetConnectionInfo()
+	if err != nil {
+		return err
+	}
+
+	req := api.ImageExportPost{
+		Target:      info.URL,
+		Certificate: info.Certificate,
+		Protocol:    "oci",
+		Config:      config,
+	}
+
+	for _, name := range names {
+		req.Aliases = append(req.Aliases, api.ImageAlias{Name: name})
+	}
+
+	op, err := d.ExportImage(fingerprint, req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Pushing the image: %s"),
+		Quiet:  global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return nil
+}
+
 // Refresh.
 type cmdImageRefresh struct {
 	global *cmdGlobal
@@ -0,0 +1,579 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// imageBuildRecipe describes how to build images from an existing image.
+type imageBuildRecipe struct {
+	// Base image, as [<remote>:]<image>
+	Image string `yaml:"image"`
+
+	// Types of images to build (container or virtual-machine)
+	Types []string `yaml:"types"`
+
+	// Profiles and configuration of the build instance
+	Profiles []string          `yaml:"profiles"`
+	Config   map[string]string `yaml:"config"`
+
+	// Environment variables for the commands
+	Environment map[string]string `yaml:"environment"`
+
+	// Steps to run, in order
+	Steps []imageBuildStep `yaml:"steps"`
+
+	// Properties of the resulting images
+	Properties map[string]string `yaml:"properties"`
+
+	// Metadata templates of the resulting images, indexed by path
+	Templates map[string]imageBuildTemplate `yaml:"templates"`
+
+	// Aliases of the resulting images, indexed by type
+	Aliases map[string][]string `yaml:"aliases"`
+
+	// Whether the resulting images are public
+	Public bool `yaml:"public"`
+}
+
+// imageBuildStep is a single recipe step, either running a command or copying files.
+type imageBuildStep struct {
+	// Shell command to run
+	Run string `yaml:"run"`
+
+	// Files to copy into the instance
+	Copy *imageBuildCopy `yaml:"copy"`
+
+	// Only run the step for these image types
+	Types []string `yaml:"types"`
+}
+
+// imageBuildCopy copies a local file or directory into the instance.
+type imageBuildCopy struct {
+	Source string `yaml:"source"`
+	Path   string `yaml:"path"`
+	UID    int64  `yaml:"uid"`
+	GID    int64  `yaml:"gid"`
+	Mode   string `yaml:"mode"`
+}
+
+// imageBuildTemplate is a metadata template added to the image.
+type imageBuildTemplate struct {
+	Source     string            `yaml:"source"`
+	When       []string          `yaml:"when"`
+	CreateOnly bool              `yaml:"create_only"`
+	Properties map[string]string `yaml:"properties"`
+}
+
+// Build.
+type cmdImageBuild struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagCompressionAlgorithm string
+	flagReuse                bool
+}
+
+var cmdImageBuildUsage = u.Usage{u.File, u.RemoteColonOpt}
+
+func (c *cmdImageBuild) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("build", cmdImageBuildUsage...)
+	cmd.Short = i18n.G("Build images from a recipe")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Build images from a recipe
+
+A temporary instance is launched from the recipe's base image, for each of
+the requested image types. The recipe's steps are then run in it, after which
+it's published as an image and deleted.
+
+Paths to local files in the recipe are relative to the recipe itself.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus image build recipe.yaml
+    Build the images described in recipe.yaml on the default remote.
+
+incus image build recipe.yaml my-server: --reuse
+    Build the images on my-server, replacing the images using the same aliases.`,
+	))
+
+	cmd.RunE = c.run
+	cli.AddStringFlag(cmd.Flags(), &c.flagCompressionAlgorithm, "compression", "", "", i18n.G("Compression algorithm to use (`none` for uncompressed)"))
+	cli.AddBoolFlag(cmd.Flags(), &c.flagReuse, "reuse", i18n.G("If the image alias already exists, delete and create a new one"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// loadRecipe parses and validates a recipe, making its local paths absolute.
+func (c *cmdImageBuild) loadRecipe(recipePath string) (*imageBuildRecipe, error) {
+	content, err := os.ReadFile(recipePath)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := imageBuildRecipe{}
+	err = yaml.Load(content, &recipe, yaml.WithKnownFields())
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed parsing recipe: %w"), err)
+	}
+
+	if recipe.Image == "" {
+		return nil, errors.New(i18n.G("The recipe must specify a base image"))
+	}
+
+	if len(recipe.Types) == 0 {
+		recipe.Types = []string{"container"}
+	}
+
+	validType := func(imageType string) error {
+		if imageType != "container" && imageType != "virtual-machine" {
+			return fmt.Errorf(i18n.G("Invalid image type %q"), imageType)
+		}
+
+		return nil
+	}
+
+	for _, imageType := range recipe.Types {
+		err := validType(imageType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for imageType := range recipe.Aliases {
+		if !slices.Contains(recipe.Types, imageType) {
+			return nil, fmt.Errorf(i18n.G("Aliases given for %q which isn't built"), imageType)
+		}
+	}
+
+	recipeDir := filepath.Dir(recipePath)
+	localPath := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+
+		return filepath.Join(recipeDir, p)
+	}
+
+	for i, step := range recipe.Steps {
+		if (step.Run == "") == (step.Copy == nil) {
+			return nil, fmt.Errorf(i18n.G("Step %d must either run a command or copy files"), i+1)
+		}
+
+		for _, imageType := range step.Types {
+			err := validType(imageType)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if step.Copy != nil {
+			if step.Copy.Source == "" || !filepath.IsAbs(step.Copy.Path) {
+				return nil, fmt.Errorf(i18n.G("Step %d must copy from a source to an absolute path"), i+1)
+			}
+
+			if step.Copy.Mode != "" {
+				_, err := strconv.ParseUint(step.Copy.Mode, 8, 32)
+				if err != nil {
+					return nil, fmt.Errorf(i18n.G("Invalid mode %q in step %d"), step.Copy.Mode, i+1)
+				}
+			}
+
+			step.Copy.Source = localPath(step.Copy.Source)
+		}
+	}
+
+	templateNames := map[string]string{}
+	for templatePath, template := range recipe.Templates {
+		if template.Source == "" || !filepath.IsAbs(templatePath) {
+			return nil, fmt.Errorf(i18n.G("Template for %q must have a source and an absolute path"), templatePath)
+		}
+
+		// Templates are stored by file name in the image.
+		name := filepath.Base(template.Source)
+		otherPath, ok := templateNames[name]
+		if ok {
+			return nil, fmt.Errorf(i18n.G("Templates for %q and %q have the same file name"), otherPath, templatePath)
+		}
+
+		templateNames[name] = templatePath
+		template.Source = localPath(template.Source)
+		recipe.Templates[templatePath] = template
+	}
+
+	return &recipe, nil
+}
+
+func (c *cmdImageBuild) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdImageBuildUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[1].RemoteServer
+	remoteName := parsed[1].RemoteName
+
+	recipe, err := c.loadRecipe(parsed[0].String)
+	if err != nil {
+		return err
+	}
+
+	imgRemote, imgName, err := c.global.conf.ParseRemote(recipe.Image)
+	if err != nil {
+		return err
+	}
+
+	// Check the aliases before building anything.
+	aliases := map[string][]api.ImageAlias{}
+	for _, imageType := range recipe.Types {
+		for _, name := range recipe.Aliases[imageType] {
+			aliases[imageType] = append(aliases[imageType], api.ImageAlias{Name: name})
+		}
+
+		existingAliases, err := getCommonAliases(d, aliases[imageType]...)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
+		}
+
+		if !c.flagReuse && len(existingAliases) > 0 {
+			names := []string{}
+			for _, alias := range existingAliases {
+				names = append(names, alias.Name)
+			}
+
+			return fmt.Errorf(i18n.G("Aliases already exists: %s"), strings.Join(names, ", "))
+		}
+	}
+
+	for _, imageType := range recipe.Types {
+		fingerprint, err := c.build(d, remoteName, imgRemote, imgName, recipe, imageType)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed building %s image: %w"), imageType, err)
+		}
+
+		// Delete images if necessary
+		if c.flagReuse {
+			err = deleteImagesByAliases(d, aliases[imageType])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = ensureImageAliases(d, aliases[imageType], fingerprint)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf(i18n.G("Built %s image with fingerprint: %s")+"\n", imageType, fingerprint)
+	}
+
+	return nil
+}
+
+// build runs the recipe in a temporary instance of the given type and publishes it, returning the image fingerprint.
+func (c *cmdImageBuild) build(d incus.InstanceServer, remoteName string, imgRemote string, imgName string, recipe *imageBuildRecipe, imageType string) (string, error) {
+	name := "build-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	req := api.InstancesPost{
+		Name:  name,
+		Type:  api.InstanceType(imageType),
+		Start: true,
+	}
+
+	req.Config = recipe.Config
+	req.Profiles = recipe.Profiles
+
+	imgServer, imgInfo, err := getImgInfo(d, c.global.conf, imgRemote, remoteName, imgName, &req.Source)
+	if err != nil {
+		return "", err
+	}
+
+	if c.global.conf.Remotes[imgRemote].Protocol == "incus" {
+		baseType := imgInfo.Type
+		if baseType == "" {
+			baseType = "container"
+		}
+
+		if baseType != imageType {
+			return "", fmt.Errorf(i18n.G("Image %q is of type %s"), recipe.Image, baseType)
+		}
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Launching %s from %s")+"\n", name, recipe.Image)
+	}
+
+	// Always remove the build instance, even if its creation failed half-way.
+	defer func() {
+		op, err := d.UpdateInstanceState(name, api.InstanceStatePut{Action: string(instance.Stop), Timeout: -1, Force: true}, "")
+		if err == nil {
+			_ = op.Wait()
+		}
+
+		op, err = d.DeleteInstance(name)
+		if err == nil {
+			err = op.Wait()
+		}
+
+		// The instance doesn't exist if its creation failed early on.
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			fmt.Fprintf(os.Stderr, i18n.G("Failed deleting build instance %q, it must be deleted manually: %v")+"\n", name, err)
+		}
+	}()
+
+	op, err := d.CreateInstanceFromImage(imgServer, *imgInfo, req)
+	if err != nil {
+		return "", err
+	}
+
+	// Watch the background operation
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Retrieving image: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return "", err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return "", err
+	}
+
+	progress.Done("")
+
+	err = c.waitReady(d, name)
+	if err != nil {
+		return "", err
+	}
+
+	// Run the steps.
+	for i, step := range recipe.Steps {
+		if len(step.Types) > 0 && !slices.Contains(step.Types, imageType) {
+			continue
+		}
+
+		if step.Copy != nil {
+			if !c.global.flagQuiet {
+				fmt.Printf(i18n.G("Step %d: Copying %s to %s")+"\n", i+1, step.Copy.Source, step.Copy.Path)
+			}
+
+			err = c.copyFiles(d, name, step.Copy)
+			if err != nil {
+				return "", fmt.Errorf(i18n.G("Step %d failed: %w"), i+1, err)
+			}
+
+			continue
+		}
+
+		if !c.global.flagQuiet {
+			command, _, _ := strings.Cut(strings.TrimSpace(step.Run), "\n")
+			fmt.Printf(i18n.G("Step %d: Running %s")+"\n", i+1, command)
+		}
+
+		ret, err := c.exec(d, name, []string{"/bin/sh", "-c", step.Run}, recipe.Environment, !c.global.flagQuiet)
+		if err != nil {
+			return "", fmt.Errorf(i18n.G("Step %d failed: %w"), i+1, err)
+		}
+
+		if ret != 0 {
+			return "", fmt.Errorf(i18n.G("Step %d failed with exit code %d"), i+1, ret)
+		}
+	}
+
+	// Cleanly stop the instance so everything is written out.
+	stopOp, err := d.UpdateInstanceState(name, api.InstanceStatePut{Action: string(instance.Stop), Timeout: 60}, "")
+	if err != nil {
+		return "", err
+	}
+
+	err = stopOp.Wait()
+	if err != nil {
+		return "", fmt.Errorf(i18n.G("Failed stopping the instance: %w"), err)
+	}
+
+	// Add the templates.
+	if len(recipe.Templates) > 0 {
+		metadata, etag, err := d.GetInstanceMetadata(name)
+		if err != nil {
+			return "", err
+		}
+
+		if metadata.Templates == nil {
+			metadata.Templates = map[string]*api.ImageMetadataTemplate{}
+		}
+
+		for templatePath, template := range recipe.Templates {
+			templateName := filepath.Base(template.Source)
+
+			content, err := os.ReadFile(template.Source)
+			if err != nil {
+				return "", err
+			}
+
+			err = d.CreateInstanceTemplateFile(name, templateName, bytes.NewReader(content))
+			if err != nil {
+				return "", err
+			}
+
+			metadata.Templates[templatePath] = &api.ImageMetadataTemplate{
+				When:       template.When,
+				CreateOnly: template.CreateOnly,
+				Template:   templateName,
+				Properties: template.Properties,
+			}
+		}
+
+		err = d.UpdateInstanceMetadata(name, *metadata, etag)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Publish the instance.
+	imgReq := api.ImagesPost{
+		Source: &api.ImagesPostSource{
+			Type: "instance",
+			Name: name,
+		},
+		CompressionAlgorithm: c.flagCompressionAlgorithm,
+	}
+
+	imgReq.Public = recipe.Public
+
+	if len(recipe.Properties) > 0 {
+		imgReq.Properties = recipe.Properties
+	}
+
+	return createInstanceImage(d, imgReq, c.global.flagQuiet)
+}
+
+// waitReady waits for commands to be runnable in the instance, which for virtual machines requires the agent.
+func (c *cmdImageBuild) waitReady(d incus.InstanceServer, name string) error {
+	deadline := time.Now().Add(5 * time.Minute)
+
+	for {
+		_, err := c.exec(d, name, []string{"true"}, nil, false)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf(i18n.G("Instance didn't become ready: %w"), err)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// exec runs a command in the instance and returns its exit code.
+func (c *cmdImageBuild) exec(d incus.InstanceServer, name string, command []string, env map[string]string, showOutput bool) (int, error) {
+	var stdout, stderr io.Writer = io.Discard, io.Discard
+	if showOutput {
+		stdout = os.Stdout
+		stderr = os.Stderr
+	}
+
+	req := api.InstanceExecPost{
+		Command:     command,
+		WaitForWS:   true,
+		Environment: env,
+	}
+
+	execArgs := incus.InstanceExecArgs{
+		Stdin:    bytes.NewReader(nil),
+		Stdout:   stdout,
+		Stderr:   stderr,
+		DataDone: make(chan bool),
+	}
+
+	op, err := d.ExecInstance(name, req, &execArgs)
+	if err != nil {
+		return -1, err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return -1, err
+	}
+
+	// Wait for any remaining I/O to be flushed
+	<-execArgs.DataDone
+
+	ret, ok := op.Get().Metadata["return"].(float64)
+	if !ok {
+		return -1, errors.New(i18n.G("Missing command exit code"))
+	}
+
+	return int(ret), nil
+}
+
+// copyFiles copies a local file or directory into the instance.
+func (c *cmdImageBuild) copyFiles(d incus.InstanceServer, name string, files *imageBuildCopy) error {
+	sftpConn, err := d.GetInstanceFileSFTP(name)
+	if err != nil {
+		return err
+	}
+
+	defer logger.WarnOnError(sftpConn.Close, "Failed to close SFTP connection")
+
+	args := incus.InstanceFileArgs{
+		UID:  files.UID,
+		GID:  files.GID,
+		Mode: -1,
+	}
+
+	if files.Mode != "" {
+		mode, err := strconv.ParseUint(files.Mode, 8, 32)
+		if err != nil {
+			return err
+		}
+
+		args.Mode = int(os.FileMode(mode).Perm())
+	}
+
+	dirMode := os.FileMode(DirMode)
+	err = sftpRecursiveMkdir(sftpConn, filepath.Dir(files.Path), &dirMode, files.UID, files.GID)
+	if err != nil {
+		return err
+	}
+
+	return sftpRecursivePushFile(sftpConn, files.Source, files.Source, files.Path, args, c.global.flagQuiet, true, false)
+}
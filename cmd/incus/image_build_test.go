@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageBuildLoadRecipe(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		valid  bool
+	}{
+		{name: "minimal", recipe: "image: images:debian/12", valid: true},
+		{name: "both types", recipe: "image: images:debian/12\ntypes: [container, virtual-machine]", valid: true},
+		{name: "missing image", recipe: "types: [container]"},
+		{name: "unknown type", recipe: "image: images:debian/12\ntypes: [vm]"},
+		{name: "unknown field", recipe: "image: images:debian/12\nstep: []"},
+		{name: "aliases of other type", recipe: "image: images:debian/12\naliases:\n  virtual-machine: [debian]"},
+		{name: "run step", recipe: "image: images:debian/12\nsteps:\n- run: apt-get update", valid: true},
+		{name: "copy step", recipe: "image: images:debian/12\nsteps:\n- copy:\n    source: files\n    path: /etc/app\n    mode: \"0644\"", valid: true},
+		{name: "empty step", recipe: "image: images:debian/12\nsteps:\n- types: [container]"},
+		{name: "run and copy step", recipe: "image: images:debian/12\nsteps:\n- run: \"true\"\n  copy:\n    source: files\n    path: /etc/app"},
+		{name: "step for unknown type", recipe: "image: images:debian/12\nsteps:\n- run: \"true\"\n  types: [vm]"},
+		{name: "copy to relative path", recipe: "image: images:debian/12\nsteps:\n- copy:\n    source: files\n    path: etc/app"},
+		{name: "copy without source", recipe: "image: images:debian/12\nsteps:\n- copy:\n    path: /etc/app"},
+		{name: "copy with invalid mode", recipe: "image: images:debian/12\nsteps:\n- copy:\n    source: files\n    path: /etc/app\n    mode: rwx"},
+		{name: "template", recipe: "image: images:debian/12\ntemplates:\n  /etc/hostname:\n    source: hostname.tpl\n    when: [create]", valid: true},
+		{name: "template to relative path", recipe: "image: images:debian/12\ntemplates:\n  etc/hostname:\n    source: hostname.tpl"},
+		{name: "templates with the same name", recipe: "image: images:debian/12\ntemplates:\n  /etc/hostname:\n    source: a/name.tpl\n  /etc/hosts:\n    source: b/name.tpl"},
+	}
+
+	c := &cmdImageBuild{}
+	dir := t.TempDir()
+
+	for _, test := range tests {
+		recipePath := filepath.Join(dir, "recipe.yaml")
+		err := os.WriteFile(recipePath, []byte(test.recipe), 0o600)
+		require.NoError(t, err)
+
+		_, err = c.loadRecipe(recipePath)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestImageBuildLoadRecipeDefaults(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "recipe.yaml")
+
+	err := os.WriteFile(recipePath, []byte(`image: images:debian/12
+steps:
+- run: apt-get update
+- copy:
+    source: files
+    path: /etc/app
+- copy:
+    source: /srv/files
+    path: /etc/other
+  types: [container]
+templates:
+  /etc/hostname:
+    source: hostname.tpl
+`), 0o600)
+	require.NoError(t, err)
+
+	recipe, err := (&cmdImageBuild{}).loadRecipe(recipePath)
+	require.NoError(t, err)
+
+	// Containers are built by default.
+	assert.Equal(t, []string{"container"}, recipe.Types)
+
+	// Local paths are relative to the recipe.
+	require.Len(t, recipe.Steps, 3)
+	assert.Equal(t, "apt-get update", recipe.Steps[0].Run)
+	assert.Nil(t, recipe.Steps[0].Copy)
+	assert.Equal(t, filepath.Join(dir, "files"), recipe.Steps[1].Copy.Source)
+	assert.Equal(t, "/etc/app", recipe.Steps[1].Copy.Path)
+	assert.Equal(t, "/srv/files", recipe.Steps[2].Copy.Source)
+	assert.Equal(t, []string{"container"}, recipe.Steps[2].Types)
+	assert.Equal(t, filepath.Join(dir, "hostname.tpl"), recipe.Templates["/etc/hostname"].Source)
+}
@@ -815,6 +815,8 @@ type cmdNetworkForwardPort struct {
 	networkForward  *cmdNetworkForward
 	flagRemoveForce bool
 	flagDescription string
+	flagHost        string
+	flagPath        string
 }
 
 func (c *cmdNetworkForwardPort) command() *cobra.Command {
@@ -844,6 +846,8 @@ func (c *cmdNetworkForwardPort) commandAdd() *cobra.Command {
 
 	cli.AddStringFlag(cmd.Flags(), &c.networkForward.flagTarget, "target", "", "", i18n.G("Cluster member name"))
 	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Port description"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagHost, "host", "", "", i18n.G("Host name to route (http and https only)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagPath, "path", "", "", i18n.G("Path prefix to route (http and https only)"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -855,7 +859,7 @@ func (c *cmdNetworkForwardPort) commandAdd() *cobra.Command {
 		}
 
 		if len(args) == 2 {
-			return []string{"tcp", "udp"}, cobra.ShellCompDirectiveNoFileComp
+			return []string{"tcp", "udp", "http", "https"}, cobra.ShellCompDirectiveNoFileComp
 		}
 
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -897,6 +901,8 @@ func (c *cmdNetworkForwardPort) runAdd(cmd *cobra.Command, args []string) error
 		TargetAddress: targetAddress,
 		TargetPort:    targetPorts,
 		Description:   c.flagDescription,
+		Host:          c.flagHost,
+		Path:          c.flagPath,
 	})
 
 	forward.Normalise()
@@ -11,6 +11,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.yaml.in/yaml/v4"
@@ -80,6 +81,14 @@ func (c *cmdProject) command() *cobra.Command {
 	projectGetInfo := cmdProjectInfo{global: c.global, project: c}
 	cmd.AddCommand(projectGetInfo.command())
 
+	// Usage
+	projectUsageCmd := cmdProjectUsage{global: c.global, project: c}
+	cmd.AddCommand(projectUsageCmd.command())
+
+	// Template
+	projectTemplateCmd := cmdProjectTemplate{global: c.global}
+	cmd.AddCommand(projectTemplateCmd.command())
+
 	// Set default
 	projectSwitchCmd := cmdProjectSwitch{global: c.global, project: c}
 	cmd.AddCommand(projectSwitchCmd.command())
@@ -100,6 +109,7 @@ type cmdProjectCreate struct {
 	project         *cmdProject
 	flagConfig      []string
 	flagDescription string
+	flagTemplate    string
 }
 
 var cmdProjectCreateUsage = u.Usage{u.NewName(u.Project).Remote()}
@@ -114,10 +124,14 @@ func (c *cmdProjectCreate) command() *cobra.Command {
     Create a project named p1
 
 incus project create p1 < config.yaml
-    Create a project named p1 with configuration from config.yaml`))
+    Create a project named p1 with configuration from config.yaml
+
+incus project create p1 --template tenant
+    Create a project named p1 from the tenant project template`))
 
 	cli.AddStringArrayFlag(cmd.Flags(), &c.flagConfig, "config|c", i18n.G("Config key/value to apply to the new project"))
 	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Project description"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagTemplate, "template", "", "", i18n.G("Project template to create the project from"))
 
 	cmd.RunE = c.run
 
@@ -176,6 +190,8 @@ func (c *cmdProjectCreate) run(cmd *cobra.Command, args []string) error {
 		project.Description = c.flagDescription
 	}
 
+	project.Template = c.flagTemplate
+
 	err = d.CreateProject(project)
 	if err != nil {
 		return err
@@ -1047,7 +1063,7 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render the output
-	byteLimits := []string{"disk", "memory"}
+	byteLimits := []string{"backups", "disk", "images", "memory"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey, _, _ := strings.Cut(k, ".")
@@ -1088,6 +1104,135 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectState)
 }
 
+// Usage.
+type cmdProjectUsage struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagSince      string
+	flagUntil      string
+	flagResolution string
+	flagFormat     string
+}
+
+var cmdProjectUsageUsage = u.Usage{u.Project.Remote()}
+
+func (c *cmdProjectUsage) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("usage", cmdProjectUsageUsage...)
+	cmd.Short = i18n.G("Show the historical resource usage of a project")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Show the historical resource usage of a project
+
+The usage is aggregated hourly or daily. Times are given as dates (2026-10-01) or RFC3339 timestamps.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project usage foo --since 2026-09-01 --until 2026-10-01 --resolution daily
+    Show the daily usage of project "foo" for September 2026`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagSince, "since", "", "", i18n.G("Start of the time range (defaults to 24 hours ago)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagUntil, "until", "", "", i18n.G("End of the time range (defaults to now)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagResolution, "resolution", "hourly", "", i18n.G("Aggregation period (hourly or daily)"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseTime parses a date or a RFC3339 timestamp.
+func (c *cmdProjectUsage) parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(i18n.G("Invalid time %q, expected a date or a RFC3339 timestamp"), value)
+	}
+
+	return t, nil
+}
+
+func (c *cmdProjectUsage) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectUsageUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	projectName := parsed[0].RemoteObject.String
+
+	since, err := c.parseTime(c.flagSince)
+	if err != nil {
+		return err
+	}
+
+	until, err := c.parseTime(c.flagUntil)
+	if err != nil {
+		return err
+	}
+
+	usage, err := d.GetProjectUsage(projectName, c.flagResolution, since, until)
+	if err != nil {
+		return err
+	}
+
+	// Render the output
+	timeFormat := "2006/01/02 15:04"
+	if usage.Resolution == "daily" {
+		timeFormat = "2006/01/02"
+	}
+
+	row := func(start string, entry api.ProjectUsageEntry) []string {
+		return []string{
+			start,
+			fmt.Sprintf("%.0f", entry.CPUSeconds),
+			units.GetByteSizeStringIEC(int64(entry.MemoryByteHours), 2),
+			units.GetByteSizeStringIEC(int64(entry.DiskByteHours), 2),
+			units.GetByteSizeStringIEC(entry.NetworkBytesReceived, 2),
+			units.GetByteSizeStringIEC(entry.NetworkBytesSent, 2),
+			fmt.Sprintf("%.2f", entry.InstanceHours),
+		}
+	}
+
+	data := [][]string{}
+	for _, entry := range usage.Entries {
+		data = append(data, row(entry.Start.Local().Format(timeFormat), entry))
+	}
+
+	data = append(data, row(i18n.G("TOTAL"), usage.Total))
+
+	header := []string{
+		i18n.G("START"),
+		i18n.G("CPU (SECONDS)"),
+		i18n.G("MEMORY (HOURS)"),
+		i18n.G("DISK (HOURS)"),
+		i18n.G("RECEIVED"),
+		i18n.G("SENT"),
+		i18n.G("INSTANCE HOURS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, usage)
+}
+
 // Get current project.
 type cmdProjectGetCurrent struct {
 	global  *cmdGlobal
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/cmd/incus/color"
+	u "github.com/lxc/incus/v7/cmd/incus/usage"
+	"github.com/lxc/incus/v7/internal/i18n"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/termios"
+)
+
+type cmdProjectTemplate struct {
+	global *cmdGlobal
+}
+
+func (c *cmdProjectTemplate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("template")
+	cmd.Short = i18n.G("Manage project templates")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Manage project templates`))
+
+	// Create
+	projectTemplateCreateCmd := cmdProjectTemplateCreate{global: c.global}
+	cmd.AddCommand(projectTemplateCreateCmd.command())
+
+	// Delete
+	projectTemplateDeleteCmd := cmdProjectTemplateDelete{global: c.global}
+	cmd.AddCommand(projectTemplateDeleteCmd.command())
+
+	// Edit
+	projectTemplateEditCmd := cmdProjectTemplateEdit{global: c.global}
+	cmd.AddCommand(projectTemplateEditCmd.command())
+
+	// List
+	projectTemplateListCmd := cmdProjectTemplateList{global: c.global}
+	cmd.AddCommand(projectTemplateListCmd.command())
+
+	// Rename
+	projectTemplateRenameCmd := cmdProjectTemplateRename{global: c.global}
+	cmd.AddCommand(projectTemplateRenameCmd.command())
+
+	// Show
+	projectTemplateShowCmd := cmdProjectTemplateShow{global: c.global}
+	cmd.AddCommand(projectTemplateShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Template create.
+type cmdProjectTemplateCreate struct {
+	global *cmdGlobal
+
+	flagDescription string
+}
+
+var cmdProjectTemplateCreateUsage = u.Usage{u.NewName(u.Template).Remote()}
+
+func (c *cmdProjectTemplateCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdProjectTemplateCreateUsage...)
+	cmd.Short = i18n.G("Create project templates")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Create project templates`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project template create tenant < template.yaml
+    Create a project template named tenant with the content of template.yaml`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagDescription, "description", "", "", i18n.G("Template description"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateCreate) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateCreateUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	templateName := parsed[0].RemoteObject.String
+	var stdinData api.ProjectTemplatePut
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = loader.Load(&stdinData)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	// Create the template
+	template := api.ProjectTemplatesPost{
+		Name:               templateName,
+		ProjectTemplatePut: stdinData,
+	}
+
+	if c.flagDescription != "" {
+		template.Description = c.flagDescription
+	}
+
+	err = d.CreateProjectTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s created")+"\n", formatRemote(c.global.conf, parsed[0]))
+	}
+
+	return nil
+}
+
+// Template delete.
+type cmdProjectTemplateDelete struct {
+	global *cmdGlobal
+}
+
+var cmdProjectTemplateDeleteUsage = u.Usage{u.Template.Remote().List(1)}
+
+func (c *cmdProjectTemplateDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdProjectTemplateDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete project templates")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Delete project templates`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateDelete) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateDeleteUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, p := range parsed[0].List {
+		d := p.RemoteServer
+		templateName := p.RemoteObject.String
+
+		// Delete the template
+		err = d.DeleteProjectTemplate(templateName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !c.global.flagQuiet {
+			fmt.Printf(i18n.G("Project template %s deleted")+"\n", formatRemote(c.global.conf, p))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Template edit.
+type cmdProjectTemplateEdit struct {
+	global *cmdGlobal
+}
+
+var cmdProjectTemplateEditUsage = u.Usage{u.Template.Remote()}
+
+func (c *cmdProjectTemplateEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdProjectTemplateEditUsage...)
+	cmd.Short = i18n.G("Edit project templates as YAML")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Edit project templates as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project template edit <template> < template.yaml
+    Update an project template using the content of template.yaml`,
+	))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the project template.
+### Any line starting with a '# will be ignored.
+###
+### A template holds the configuration of the projects created from it
+### and the profiles, networks and network ACLs to create in them, for example:
+###
+### description: Restricted tenant project
+### self_service: true
+### config:
+###   features.networks: "true"
+###   restricted: "true"
+###   limits.instances: "10"
+### profiles:
+### - name: default
+###   devices:
+###     root:
+###       path: /
+###       pool: default
+###       type: disk
+###
+### Note that the name is shown but cannot be changed`,
+	)
+}
+
+func (c *cmdProjectTemplateEdit) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateEditUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	templateName := parsed[0].RemoteObject.String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		loader, err := yaml.NewLoader(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.ProjectTemplatePut{}
+		err = loader.Load(&newdata)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return d.UpdateProjectTemplate(templateName, newdata, "")
+	}
+
+	// Extract the current value
+	template, etag, err := d.GetProjectTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&template, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.ProjectTemplatePut{}
+		err = yaml.Load(content, &newdata)
+		if err == nil {
+			err = d.UpdateProjectTemplate(templateName, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Template list.
+type cmdProjectTemplateList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+var cmdProjectTemplateListUsage = u.Usage{u.RemoteColonOpt}
+
+func (c *cmdProjectTemplateList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdProjectTemplateListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List project templates")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`List project templates`))
+
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format|f", c.global.defaultListFormat(), "", i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`))
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateList) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateListUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+
+	// List the templates
+	templates, err := d.GetProjectTemplates()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, template := range templates {
+		data = append(data, []string{template.Name, template.Description, strconv.FormatBool(template.SelfService)})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("SELF-SERVICE"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, templates)
+}
+
+// Template rename.
+type cmdProjectTemplateRename struct {
+	global *cmdGlobal
+}
+
+var cmdProjectTemplateRenameUsage = u.Usage{u.Template.Remote(), u.NewName(u.Template)}
+
+func (c *cmdProjectTemplateRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("rename", cmdProjectTemplateRenameUsage...)
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename project templates")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Rename project templates`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateRename) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateRenameUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	templateName := parsed[0].RemoteObject.String
+	newTemplateName := parsed[1].String
+
+	// Rename the template
+	err = d.RenameProjectTemplate(templateName, api.ProjectTemplatePost{Name: newTemplateName})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s renamed to %s")+"\n", formatRemote(c.global.conf, parsed[0]), newTemplateName)
+	}
+
+	return nil
+}
+
+// Template show.
+type cmdProjectTemplateShow struct {
+	global *cmdGlobal
+}
+
+var cmdProjectTemplateShowUsage = u.Usage{u.Template.Remote()}
+
+func (c *cmdProjectTemplateShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdProjectTemplateShowUsage...)
+	cmd.Short = i18n.G("Show project template details")
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Show project template details`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateShow) run(cmd *cobra.Command, args []string) error {
+	parsed, err := c.global.Parse(cmdProjectTemplateShowUsage, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	templateName := parsed[0].RemoteObject.String
+
+	// Show the template
+	template, _, err := d.GetProjectTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Dump(&template, yaml.WithV2Defaults())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
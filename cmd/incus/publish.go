@@ -31,11 +31,25 @@ type cmdPublish struct {
 
 var cmdPublishUsage = u.Usage{u.MakePath(u.Instance, u.Snapshot.Optional()).Remote(), u.RemoteColonOpt, u.LegacyKV.List(0)}
 
+var cmdPublishOCIUsage = u.Usage{u.MakePath(u.Instance, u.Snapshot.Optional()).Remote(), u.RemoteImage, u.LegacyKV.List(0)}
+
 func (c *cmdPublish) command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = cli.U("publish", cmdPublishUsage...)
 	cmd.Short = i18n.G("Publish instances as images")
-	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(`Publish instances as images`))
+	cmd.Long = cli.FormatSection(color.DescriptionPrefix, i18n.G(
+		`Publish instances as images
+
+With --format=oci, the image is pushed to an OCI registry remote instead,
+under the given repository and tag. Only containers can be published this way.`,
+	))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus publish c1 --alias my-image
+    Publish the c1 instance as a local image with the my-image alias.
+
+incus publish c1 my-registry:my-org/my-app:1.0 --format=oci
+    Publish the c1 instance to the my-registry OCI registry as my-org/my-app:1.0.`,
+	))
 
 	cmd.RunE = c.run
 	cli.AddBoolFlag(cmd.Flags(), &c.flagMakePublic, "public", i18n.G("Make the image public"))
@@ -44,7 +58,7 @@ func (c *cmdPublish) command() *cobra.Command {
 	cli.AddStringFlag(cmd.Flags(), &c.flagCompressionAlgorithm, "compression", "", "", i18n.G("Compression algorithm to use (`none` for uncompressed)"))
 	cli.AddStringFlag(cmd.Flags(), &c.flagExpiresAt, "expire", "", "", i18n.G("Image expiration date (format: rfc3339)"))
 	cli.AddBoolFlag(cmd.Flags(), &c.flagReuse, "reuse", i18n.G("If the image alias already exists, delete and create a new one"))
-	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format", "unified", "", i18n.G("Image format"))
+	cli.AddStringFlag(cmd.Flags(), &c.flagFormat, "format", "unified", "", i18n.G("Image format (`split`, `unified` or `oci`)"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -62,7 +76,14 @@ func (c *cmdPublish) command() *cobra.Command {
 }
 
 func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
-	parsed, err := c.global.Parse(cmdPublishUsage, cmd, args)
+	isOCI := c.flagFormat == "oci"
+
+	usage := cmdPublishUsage
+	if isOCI {
+		usage = cmdPublishOCIUsage
+	}
+
+	parsed, err := c.global.Parse(usage, cmd, args)
 	if err != nil {
 		return err
 	}
@@ -70,12 +91,40 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 	srcServer := parsed[0].RemoteServer
 	isSnapshot := !parsed[0].RemoteObject.List[1].Skipped
 	objectName := parsed[0].RemoteObject.String
-	dstServer := parsed[1].RemoteServer
 	keys, err := kvToMap(parsed[2])
 	if err != nil {
 		return err
 	}
 
+	// OCI images are built locally, then pushed to the registry.
+	dstServer := srcServer
+	ociRemote := ""
+	ociName := ""
+	var ociConfig map[string]string
+	if isOCI {
+		if len(c.flagAliases) > 0 || c.flagMakePublic || c.flagExpiresAt != "" {
+			return errors.New(i18n.G("The --alias, --public and --expire flags can't be used with --format=oci"))
+		}
+
+		ociRemote = parsed[1].List[0].Get(c.global.conf.DefaultRemote)
+		ociName = parsed[1].List[1].String
+
+		// Carry the process configuration of the instance over to the OCI image.
+		inst, _, err := srcServer.GetInstance(strings.Split(objectName, "/")[0])
+		if err != nil {
+			return err
+		}
+
+		ociConfig = map[string]string{}
+		for key, value := range inst.ExpandedConfig {
+			if strings.HasPrefix(key, "oci.") || strings.HasPrefix(key, "environment.") {
+				ociConfig[key] = value
+			}
+		}
+	} else {
+		dstServer = parsed[1].RemoteServer
+	}
+
 	if !isSnapshot {
 		inst, etag, err := srcServer.GetInstance(objectName)
 		if err != nil {
@@ -212,39 +261,26 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 	}
 
 	req.Format = c.flagFormat
-
-	op, err := srcServer.CreateImage(req, nil)
-	if err != nil {
-		return err
-	}
-
-	// Watch the background operation
-	progress := cli.ProgressRenderer{
-		Format: i18n.G("Publishing instance: %s"),
-		Quiet:  c.global.flagQuiet,
-	}
-
-	_, err = op.AddHandler(progress.UpdateOp)
-	if err != nil {
-		progress.Done("")
-		return err
+	if isOCI {
+		req.Format = "unified"
 	}
 
-	// Wait for the copy to complete
-	err = cli.CancelableWait(op, &progress)
+	fingerprint, err := createInstanceImage(srcServer, req, c.global.flagQuiet)
 	if err != nil {
-		progress.Done("")
 		return err
 	}
 
-	progress.Done("")
+	// For OCI publish, push the image to the registry and drop the local copy.
+	if isOCI {
+		defer func() { _, _ = srcServer.DeleteImage(fingerprint) }()
 
-	opAPI := op.Get()
+		err = pushImageOCI(c.global, srcServer, fingerprint, ociRemote, []string{ociName}, ociConfig)
+		if err != nil {
+			return err
+		}
 
-	// Grab the fingerprint
-	fingerprint, ok := opAPI.Metadata["fingerprint"].(string)
-	if !ok {
-		return errors.New("Bad fingerprint")
+		fmt.Printf(i18n.G("Instance published to %s:%s")+"\n", ociRemote, ociName)
+		return nil
 	}
 
 	// For remote publish, copy to target now
@@ -290,3 +326,42 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 	fmt.Printf(i18n.G("Instance published with fingerprint: %s")+"\n", fingerprint)
 	return nil
 }
+
+// createInstanceImage has the server publish an instance or snapshot as an image, returning its fingerprint.
+func createInstanceImage(d incus.InstanceServer, req api.ImagesPost, quiet bool) (string, error) {
+	op, err := d.CreateImage(req, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Watch the background operation
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Publishing instance: %s"),
+		Quiet:  quiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return "", err
+	}
+
+	// Wait for the copy to complete
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return "", err
+	}
+
+	progress.Done("")
+
+	opAPI := op.Get()
+
+	// Grab the fingerprint
+	fingerprint, ok := opAPI.Metadata["fingerprint"].(string)
+	if !ok {
+		return "", errors.New("Bad fingerprint")
+	}
+
+	return fingerprint, nil
+}
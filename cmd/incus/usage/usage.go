@@ -814,6 +814,7 @@ var (
 	Address            = placeholder{i18n.G("address")}
 	AddressSet         = placeholder{i18n.G("address set")}
 	Alias              = placeholder{i18n.G("alias")}
+	AuthMethod         = placeholder{i18n.G("authentication method")}
 	Backend            = placeholder{i18n.G("backend")}
 	BackupFile         = placeholder{i18n.G("backup file")}
 	Bucket             = placeholder{i18n.G("bucket")}
@@ -824,12 +825,14 @@ var (
 	Direction          = alternative{[]Atom{verbatim{"ingress"}, verbatim{"egress"}}}
 	Directory          = placeholder{i18n.G("directory")}
 	Driver             = placeholder{i18n.G("driver")}
+	Entitlement        = placeholder{i18n.G("entitlement")}
 	EndOfFlags         = hide{optional{verbatim{"--"}}, verbatim{"[flags] [--]"}}
 	Expiry             = placeholder{i18n.G("expiry")}
 	File               = placeholder{i18n.G("file")}
 	Filter             = placeholder{i18n.G("filter")}
 	Fingerprint        = placeholder{i18n.G("fingerprint")}
 	Group              = placeholder{i18n.G("group")}
+	Identifier         = placeholder{i18n.G("identifier")}
 	Image              = placeholder{i18n.G("image")}
 	Instance           = placeholder{i18n.G("instance")}
 	Interface          = placeholder{i18n.G("interface")}
@@ -841,6 +844,7 @@ var (
 	Member             = placeholder{i18n.G("member")}
 	Network            = placeholder{i18n.G("network")}
 	NetworkIntegration = placeholder{i18n.G("network integration")}
+	Object             = placeholder{i18n.G("object")}
 	Operation          = placeholder{i18n.G("operation")}
 	Path               = placeholder{i18n.G("path")}
 	Peer               = placeholder{i18n.G("peer")}
@@ -43,11 +43,19 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	authGroupCmd,
+	authGroupsCmd,
+	authIdentitiesCmd,
+	authIdentityCmd,
+	authTokenCmd,
+	authTokensCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
 	clusterGroupCmd,
 	clusterGroupsCmd,
+	clusterRebalanceCmd,
+	clusterUpgradeCmd,
 	clusterNodeCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
@@ -114,7 +122,10 @@ var api10 = []APIEndpoint{
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
+	projectUsageCmd,
 	projectAccessCmd,
+	projectTemplatesCmd,
+	projectTemplateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
 	storagePoolsCmd,
@@ -235,7 +246,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Get the authentication methods.
-	authMethods := []string{api.AuthenticationMethodTLS}
+	authMethods := []string{api.AuthenticationMethodTLS, api.AuthenticationMethodToken}
 
 	oidcIssuer, oidcClientID, _, _, _ := s.GlobalConfig.OIDCServer()
 	if oidcIssuer != "" && oidcClientID != "" {
@@ -809,6 +820,7 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	s := d.State()
 
 	acmeChanged := false
+	auditChanged := false
 	bgpChanged := false
 	dnsChanged := false
 	oidcChanged := false
@@ -824,6 +836,9 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "acme.agree_tos", "acme.ca_url", "acme.challenge", "acme.domain", "acme.email", "acme.provider", "acme.provider.environment", "acme.provider.resolvers", "acme.http.port":
 			acmeChanged = true
 
+		case "audit.enabled", "audit.max_files", "audit.max_size":
+			auditChanged = true
+
 		case "cluster.images_minimal_replica":
 			err := autoSyncImages(s.ShutdownCtx, s)
 			if err != nil {
@@ -976,6 +991,13 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if auditChanged {
+		err := d.auditLog.Configure(clusterConf.Audit())
+		if err != nil {
+			return fmt.Errorf("Failed to configure audit log: %w", err)
+		}
+	}
+
 	if bgpChanged {
 		address := nodeConfig.BGPAddress()
 		asn := clusterConf.BGPASN()
@@ -1061,6 +1083,24 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Compile and load the cluster re-balancing scriptlet.
+	value, ok = clusterChanged["cluster.rebalance.scriptlet"]
+	if ok {
+		err := scriptletLoad.ClusterRebalanceSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving cluster re-balancing scriptlet: %w", err)
+		}
+	}
+
+	// Compile and load the cluster fencing scriptlet.
+	value, ok = clusterChanged["cluster.healing_fencing.scriptlet"]
+	if ok {
+		err := scriptletLoad.ClusterFencingSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving cluster fencing scriptlet: %w", err)
+		}
+	}
+
 	// Setup the authorization scriptlet.
 	value, ok = clusterChanged["authorization.scriptlet"]
 	if ok {
@@ -1070,5 +1110,14 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Setup the built-in authorization driver.
+	_, ok = clusterChanged["authorization.builtin"]
+	if ok {
+		err := d.setupAuthorizationBuiltin(d.globalConfig.AuthorizationBuiltin())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,1104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+var authGroupsCmd = APIEndpoint{
+	Path: "auth/groups",
+
+	Get:  APIEndpointAction{Handler: authGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authGroupCmd = APIEndpoint{
+	Path: "auth/groups/{name}",
+
+	Delete: APIEndpointAction{Handler: authGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: authGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: authGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentitiesCmd = APIEndpoint{
+	Path: "auth/identities",
+
+	Get:  APIEndpointAction{Handler: authIdentitiesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authIdentitiesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentityCmd = APIEndpoint{
+	Path: "auth/identities/{authenticationMethod}/{identifier}",
+
+	Delete: APIEndpointAction{Handler: authIdentityDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: authIdentityGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// authPermissionStore provides the built-in authorization driver with access to the cluster database.
+type authPermissionStore struct {
+	db *db.Cluster
+}
+
+// GetIdentityPermissions returns the permissions of an identity and whether the identity is known.
+func (s *authPermissionStore) GetIdentityPermissions(ctx context.Context, authenticationMethod string, identifier string) ([]auth.Permission, bool, error) {
+	var permissions []api.Permission
+	var ownedProjects []string
+	var found bool
+
+	err := s.db.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		permissions, found, err = tx.GetIdentityPermissions(ctx, authenticationMethod, identifier)
+		if err != nil || !found {
+			return err
+		}
+
+		ownedProjects, err = tx.GetOwnedProjects(ctx, authenticationMethod, identifier)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := authPermissions(permissions)
+
+	// Identities administer the projects they created through self-service.
+	for _, projectName := range ownedProjects {
+		result = append(result, auth.ProjectOwnerPermission(projectName))
+	}
+
+	return result, found, nil
+}
+
+// GetAllIdentityPermissions returns the permissions of all known identities.
+func (s *authPermissionStore) GetAllIdentityPermissions(ctx context.Context) ([]auth.IdentityPermissions, error) {
+	var identities []api.Identity
+	var groups []api.AuthGroup
+	ownedProjects := map[string][]string{}
+
+	err := s.db.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		identities, err = tx.GetIdentities(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, identity := range identities {
+			ownedProjects[identity.AuthenticationMethod+"/"+identity.Identifier], err = tx.GetOwnedProjects(ctx, identity.AuthenticationMethod, identity.Identifier)
+			if err != nil {
+				return err
+			}
+		}
+
+		groups, err = tx.GetAuthGroups(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groupPermissions := make(map[string][]api.Permission, len(groups))
+	for _, group := range groups {
+		groupPermissions[group.Name] = group.Permissions
+	}
+
+	result := make([]auth.IdentityPermissions, 0, len(identities))
+	for _, identity := range identities {
+		entry := auth.IdentityPermissions{
+			AuthenticationMethod: identity.AuthenticationMethod,
+			Identifier:           identity.Identifier,
+		}
+
+		for _, group := range identity.Groups {
+			entry.Permissions = append(entry.Permissions, authPermissions(groupPermissions[group])...)
+		}
+
+		for _, projectName := range ownedProjects[identity.AuthenticationMethod+"/"+identity.Identifier] {
+			entry.Permissions = append(entry.Permissions, auth.ProjectOwnerPermission(projectName))
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// RenameObjects updates the permissions on objects, and any object below them, after a rename.
+func (s *authPermissionStore) RenameObjects(ctx context.Context, renames map[auth.Object]auth.Object) error {
+	return s.db.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for oldObject, newObject := range renames {
+			err := tx.RenameAuthObjects(ctx, oldObject.String(), newObject.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteObjects removes the permissions on objects, and any object below them, after a deletion.
+func (s *authPermissionStore) DeleteObjects(ctx context.Context, objects ...auth.Object) error {
+	return s.db.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, object := range objects {
+			err := tx.DeleteAuthObjects(ctx, object.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// authPermissions converts API permissions into authorization permissions.
+func authPermissions(permissions []api.Permission) []auth.Permission {
+	result := make([]auth.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, auth.Permission{
+			Entitlement: auth.Entitlement(permission.Entitlement),
+			Object:      auth.Object(permission.Object),
+		})
+	}
+
+	return result
+}
+
+// authGroupValidate validates the permissions of an authorization group.
+func authGroupValidate(group api.AuthGroupPut) error {
+	for _, permission := range group.Permissions {
+		err := auth.ValidatePermission(permission.Entitlement, permission.Object)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/auth/groups auth auth_groups_get
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/groups/developers",
+//	              "/1.0/auth/groups/operators"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/groups?recursion=1 auth auth_groups_get_recursion1
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization groups
+//	          items:
+//	            $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var groups []api.AuthGroup
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groups, err = tx.GetAuthGroups(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, groups)
+	}
+
+	urls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "groups", group.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/groups auth auth_groups_post
+//
+//	Add an authorization group
+//
+//	Creates a new authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthGroupsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = validate.IsAPIName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid group name: %w", err))
+	}
+
+	err = authGroupValidate(req.AuthGroupPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Create the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthGroup(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.AuthGroupCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/groups/{name} auth auth_group_get
+//
+//	Get the authorization group
+//
+//	Gets a specific authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Group name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    description: Group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetAuthGroup(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, group, group.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/groups/{name} auth auth_group_put
+//
+//	Update the authorization group
+//
+//	Updates the entire authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Group name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: group
+//	    description: Group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/groups/{name} auth auth_group_patch
+//
+//	Partially update the authorization group
+//
+//	Updates a subset of the authorization group, adding the given permissions to the existing ones.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Group name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: group
+//	    description: Group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetAuthGroup(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, group.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.AuthGroupPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Add the permissions to the existing ones on PATCH.
+	if r.Method == http.MethodPatch {
+		if req.Description == "" {
+			req.Description = group.Description
+		}
+
+		for _, permission := range group.Permissions {
+			if !slices.Contains(req.Permissions, permission) {
+				req.Permissions = append(req.Permissions, permission)
+			}
+		}
+	}
+
+	err = authGroupValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Update the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateAuthGroup(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/auth/groups/{name} auth auth_group_post
+//
+//	Rename the authorization group
+//
+//	Renames an existing authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Group name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: group
+//	    description: Group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = validate.IsAPIName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid group name: %w", err))
+	}
+
+	// Rename the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameAuthGroup(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.AuthGroupRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/auth/groups/{name} auth auth_group_delete
+//
+//	Delete the authorization group
+//
+//	Removes the authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Group name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Delete the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthGroup(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/auth/identities auth auth_identities_get
+//
+//	Get the identities
+//
+//	Returns a list of identities (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/identities/oidc/jane@example.com",
+//	              "/1.0/auth/identities/tls/636b69519d27ae3b0e398cb7928043846ce1e3842f0ca7a589993dd913ab8cc9"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/identities?recursion=1 auth auth_identities_get_recursion1
+//
+//	Get the identities
+//
+//	Returns a list of identities (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of identities
+//	          items:
+//	            $ref: "#/definitions/Identity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var identities []api.Identity
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		identities, err = tx.GetIdentities(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, identities)
+	}
+
+	urls := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "identities", identity.AuthenticationMethod, identity.Identifier).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/identities auth auth_identities_post
+//
+//	Add an identity
+//
+//	Creates a new identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentitiesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.IdentitiesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodOIDC, api.AuthenticationMethodToken}, req.AuthenticationMethod) {
+		return response.BadRequest(fmt.Errorf("Invalid authentication method %q", req.AuthenticationMethod))
+	}
+
+	if req.Identifier == "" {
+		return response.BadRequest(errors.New("Identifier is required"))
+	}
+
+	// Create the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateIdentity(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.IdentityCreated.Event(req.AuthenticationMethod, req.Identifier, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_get
+//
+//	Get the identity
+//
+//	Gets a specific identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: authenticationMethod
+//	    description: Authentication method
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: identifier
+//	    description: Identifier
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    description: Identity
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Identity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityPathVars(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.Identity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = tx.GetIdentity(ctx, authenticationMethod, identifier)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, identity, identity.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_put
+//
+//	Update the identity
+//
+//	Updates the entire identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: authenticationMethod
+//	    description: Authentication method
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: identifier
+//	    description: Identifier
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_patch
+//
+//	Partially update the identity
+//
+//	Updates a subset of the identity, adding the given groups to the existing ones.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: authenticationMethod
+//	    description: Authentication method
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: identifier
+//	    description: Identifier
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityPathVars(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.Identity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = tx.GetIdentity(ctx, authenticationMethod, identifier)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, identity.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.IdentityPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Add the groups to the existing ones on PATCH.
+	if r.Method == http.MethodPatch {
+		if req.Description == "" {
+			req.Description = identity.Description
+		}
+
+		for _, group := range identity.Groups {
+			if !slices.Contains(req.Groups, group) {
+				req.Groups = append(req.Groups, group)
+			}
+		}
+	}
+
+	// Update the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateIdentity(ctx, authenticationMethod, identifier, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityUpdated.Event(authenticationMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_delete
+//
+//	Delete the identity
+//
+//	Removes the identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: authenticationMethod
+//	    description: Authentication method
+//	    type: string
+//	    required: true
+//	  - in: path
+//	    name: identifier
+//	    description: Identifier
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityPathVars(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Delete the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteIdentity(ctx, authenticationMethod, identifier)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityDeleted.Event(authenticationMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authIdentityPathVars returns the authentication method and identifier from the request path.
+func authIdentityPathVars(r *http.Request) (string, string, error) {
+	authenticationMethod, err := pathVar(r, "authenticationMethod")
+	if err != nil {
+		return "", "", err
+	}
+
+	identifier, err := pathVar(r, "identifier")
+	if err != nil {
+		return "", "", err
+	}
+
+	return authenticationMethod, identifier, nil
+}
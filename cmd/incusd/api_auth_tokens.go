@@ -0,0 +1,631 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/jmap"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+// authTokenPrefix is the prefix of all API token secrets, used to tell them apart from other bearer tokens.
+const authTokenPrefix = "incus_"
+
+var authTokensCmd = APIEndpoint{
+	Path: "auth/tokens",
+
+	Get:  APIEndpointAction{Handler: authTokensGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authTokensPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authTokenCmd = APIEndpoint{
+	Path: "auth/tokens/{name}",
+
+	Delete: APIEndpointAction{Handler: authTokenDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: authTokenGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authTokenPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authTokenPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// authTokenHash returns the hash of an API token secret as stored in the database.
+func authTokenHash(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// authTokenSecret returns the API token secret passed as a bearer token, if any.
+func authTokenSecret(r *http.Request) string {
+	fields := strings.Fields(r.Header.Get("Authorization"))
+	if len(fields) != 2 || strings.ToLower(fields[0]) != "bearer" || !strings.HasPrefix(fields[1], authTokenPrefix) {
+		return ""
+	}
+
+	return fields[1]
+}
+
+// authTokenLookup returns the API token matching the secret, failing if it has expired.
+func (d *Daemon) authTokenLookup(ctx context.Context, secret string) (*api.AuthToken, error) {
+	var token *api.AuthToken
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		token, err = tx.GetAuthTokenByHash(ctx, authTokenHash(secret))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Invalid API token: %w", err)
+	}
+
+	err = authTokenCheckExpiry(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// authTokenForwarded returns the API token used for a request forwarded by another cluster member, if any.
+// Forwarded requests carry the name of the token rather than its secret.
+func (d *Daemon) authTokenForwarded(r *http.Request) (*api.AuthToken, error) {
+	if r.Header.Get(request.HeaderForwardedProtocol) != api.AuthenticationMethodToken {
+		return nil, nil
+	}
+
+	var token *api.AuthToken
+
+	err := d.db.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		token, err = tx.GetAuthToken(ctx, r.Header.Get(request.HeaderForwardedUsername))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The token may have expired since the request was authenticated by the other member.
+	err = authTokenCheckExpiry(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// authTokenCheckExpiry returns an error if the API token has expired.
+func authTokenCheckExpiry(token *api.AuthToken) error {
+	if !token.ExpiresAt.IsZero() && token.ExpiresAt.Before(time.Now()) {
+		return fmt.Errorf("API token %q has expired", token.Name)
+	}
+
+	return nil
+}
+
+// authTokenValidate validates the modifiable fields of an API token.
+func authTokenValidate(token api.AuthTokenPut) error {
+	for _, entitlement := range token.Entitlements {
+		err := auth.ValidateEntitlement(entitlement)
+		if err != nil {
+			return err
+		}
+	}
+
+	if token.Restricted && len(token.Projects) == 0 {
+		return errors.New("Restricted tokens must be given at least one project")
+	}
+
+	return nil
+}
+
+// authTokenCheckScope returns an error if the token would grant more than the API token used for the request.
+func authTokenCheckScope(r *http.Request, token api.AuthTokenPut) error {
+	caller, ok := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+	if !ok || caller == nil {
+		return nil
+	}
+
+	return auth.CheckTokenScope(caller, token)
+}
+
+// authTokenCreator returns the authentication method and identifier of the identity creating a token.
+// Tokens created using a token are attributed to the identity which created that token.
+func authTokenCreator(r *http.Request) (string, string) {
+	caller, ok := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+	if ok && caller != nil {
+		return caller.CreatorAuthenticationMethod, caller.CreatorIdentifier
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	return requestor.Protocol, requestor.Username
+}
+
+// swagger:operation GET /1.0/auth/tokens auth auth_tokens_get
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/tokens/ci",
+//	              "/1.0/auth/tokens/backups"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/tokens?recursion=1 auth auth_tokens_get_recursion1
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of API tokens
+//	          items:
+//	            $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var tokens []api.AuthToken
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		tokens, err = tx.GetAuthTokens(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, tokens)
+	}
+
+	urls := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "tokens", token.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/tokens auth auth_tokens_post
+//
+//	Add an API token
+//
+//	Creates a new API token and returns its secret.
+//	The secret isn't stored by the server and can't be retrieved later.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: token
+//	    description: API token
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokensPost"
+//	responses:
+//	  "200":
+//	    description: API token secret
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthTokenSecret"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthTokensPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = validate.IsAPIName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid token name: %w", err))
+	}
+
+	err = authTokenValidate(req.AuthTokenPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !req.ExpiresAt.IsZero() && req.ExpiresAt.Before(time.Now()) {
+		return response.BadRequest(errors.New("Expiry date is in the past"))
+	}
+
+	err = authTokenCheckScope(r, req.AuthTokenPut)
+	if err != nil {
+		return response.Forbidden(err)
+	}
+
+	// Generate the secret.
+	random, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	secret := authTokenPrefix + random
+
+	// Create the DB record.
+	creatorAuthenticationMethod, creatorIdentifier := authTokenCreator(r)
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthToken(ctx, req, authTokenHash(secret), creatorAuthenticationMethod, creatorIdentifier)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.AuthTokenCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, api.AuthTokenSecret{Name: req.Name, Token: secret}, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/tokens/{name} auth auth_token_get
+//
+//	Get the API token
+//
+//	Gets a specific API token.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Token name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    description: API token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var token *api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		token, err = tx.GetAuthToken(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, token, token.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/tokens/{name} auth auth_token_put
+//
+//	Update the API token
+//
+//	Updates the entire API token.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Token name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: token
+//	    description: API token
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokenPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/tokens/{name} auth auth_token_patch
+//
+//	Partially update the API token
+//
+//	Updates a subset of the API token.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Token name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: token
+//	    description: API token
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokenPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var token *api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		token, err = tx.GetAuthToken(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, token.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	req := api.AuthTokenPut{}
+	err = json.NewDecoder(bytes.NewReader(body)).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if r.Method == http.MethodPatch {
+		reqRaw := jmap.Map{}
+		err = json.NewDecoder(bytes.NewReader(body)).Decode(&reqRaw)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if req.Description == "" {
+			req.Description = token.Description
+		}
+
+		if req.ExpiresAt.IsZero() {
+			req.ExpiresAt = token.ExpiresAt
+		}
+
+		// Check if restricted was passed, so that it can be cleared.
+		_, err = reqRaw.GetBool("restricted")
+		if err != nil {
+			req.Restricted = token.Restricted
+		}
+
+		if req.Projects == nil {
+			req.Projects = token.Projects
+		}
+
+		if req.Entitlements == nil {
+			req.Entitlements = token.Entitlements
+		}
+	}
+
+	err = authTokenValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authTokenCheckScope(r, req)
+	if err != nil {
+		return response.Forbidden(err)
+	}
+
+	// Update the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateAuthToken(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/tokens/{name} auth auth_token_delete
+//
+//	Revoke the API token
+//
+//	Removes the API token, immediately rejecting any further request using it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Token name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Delete the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthToken(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestAuthTokenCheckExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		ok        bool
+	}{
+		{name: "no expiry", ok: true},
+		{name: "future expiry", expiresAt: time.Now().Add(time.Hour), ok: true},
+		{name: "expired", expiresAt: time.Now().Add(-time.Hour), ok: false},
+	}
+
+	for _, test := range tests {
+		token := &api.AuthToken{Name: "ci", AuthTokenPut: api.AuthTokenPut{ExpiresAt: test.expiresAt}}
+
+		err := authTokenCheckExpiry(token)
+		if test.ok {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
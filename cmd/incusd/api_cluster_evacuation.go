@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
+	"os"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -290,23 +293,56 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 	// Limit the number of concurrent evacuations to run at the same time
 	numParallelEvacs := max(runtime.NumCPU()/16, 1)
 
-	group, groupCtx := errgroup.WithContext(ctx)
-	group.SetLimit(numParallelEvacs)
-
-	for _, inst := range opts.instances {
-		group.Go(func() error {
-			return evacuateInstancesFunc(groupCtx, inst, opts)
-		})
+	// When healing, restart the instances with the highest priority first.
+	tiers := [][]instance.Instance{opts.instances}
+	if opts.mode == "heal" {
+		tiers = evacuateHealingTiers(opts.instances)
 	}
 
-	err := group.Wait()
-	if err != nil {
-		return fmt.Errorf("Failed to evacuate instances: %w", err)
+	for _, tier := range tiers {
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(numParallelEvacs)
+
+		for _, inst := range tier {
+			group.Go(func() error {
+				return evacuateInstancesFunc(groupCtx, inst, opts)
+			})
+		}
+
+		err := group.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed to evacuate instances: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// evacuateHealingTiers groups the instances by healing priority, highest priority first.
+func evacuateHealingTiers(instances []instance.Instance) [][]instance.Instance {
+	byPriority := map[int][]instance.Instance{}
+	for _, inst := range instances {
+		priority, _ := strconv.Atoi(inst.ExpandedConfig()["cluster.healing_priority"])
+		byPriority[priority] = append(byPriority[priority], inst)
+	}
+
+	priorities := slices.Sorted(maps.Keys(byPriority))
+	slices.Reverse(priorities)
+
+	tiers := make([][]instance.Instance, 0, len(priorities))
+	for _, priority := range priorities {
+		tiers = append(tiers, byPriority[priority])
+	}
+
+	return tiers
+}
+
+// evacuateHealingSkipped records that an instance of an offline cluster member isn't being restarted elsewhere.
+func evacuateHealingSkipped(s *state.State, inst instance.Instance, reason string) {
+	logger.Warn("Skipping instance healing", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "reason": reason})
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealingSkipped.Event(inst, map[string]any{"reason": reason}))
+}
+
 func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts evacuateOpts) error {
 	instProject := inst.Project()
 	l := logger.AddContext(logger.Ctx{"project": instProject.Name, "instance": inst.Name()})
@@ -324,6 +360,7 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 
 			if action != "migrate" {
 				// We can only migrate instances or leave them as they are.
+				evacuateHealingSkipped(opts.s, inst, fmt.Sprintf("Instance can't be migrated (%s)", action))
 				return nil
 			}
 		} else if opts.mode != "auto" {
@@ -358,6 +395,11 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 		if api.StatusErrorCheck(err, http.StatusNotFound) {
 			// Skip migration if no target is available.
 			l.Warn("No migration target available for instance")
+
+			if opts.mode == "heal" {
+				evacuateHealingSkipped(opts.s, inst, "No migration target available")
+			}
+
 			return nil
 		}
 
@@ -683,6 +725,7 @@ func restoreClusterMemberFunc(inst instance.Instance, op *operations.Operation,
 func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst instance.Instance) (*db.NodeInfo, *db.NodeInfo, error) {
 	var sourceMemberInfo *db.NodeInfo
 	var targetMemberInfo *db.NodeInfo
+	var placementRules *instance.PlacementRules
 
 	// Get candidate cluster members to move instances to.
 	var candidateMembers []db.NodeInfo
@@ -709,6 +752,12 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			return err
 		}
 
+		// Restrict the candidates to the cluster members respecting the placement rules.
+		placementRules, candidateMembers, err = instancePlacementFilter(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), candidateMembers)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -765,6 +814,8 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		return nil, nil, fmt.Errorf("Couldn't find a cluster member for instance %q in project %q", inst.Name(), inst.Project().Name)
 	}
 
+	instancePlacementWarn(s, placementRules, inst.Project().Name, inst.ID(), targetMemberInfo.Name)
+
 	return sourceMemberInfo, targetMemberInfo, nil
 }
 
@@ -833,7 +884,14 @@ func autoHealClusterTask(d *Daemon) (task.Func, task.Schedule) {
 
 		opRun := func(op *operations.Operation) error {
 			for _, member := range offlineMembers {
-				err := healClusterMember(d, op, member.Name)
+				// Make sure the member is fenced before restarting its instances elsewhere.
+				err := healClusterFence(ctx, s, op, member)
+				if err != nil {
+					logger.Error("Failed fencing cluster member, skipping healing", logger.Ctx{"server": member.Name, "err": err})
+					continue
+				}
+
+				err = healClusterMember(d, op, member.Name)
 				if err != nil {
 					logger.Error("Failed healing cluster instances", logger.Ctx{"server": member.Name, "err": err})
 					return err
@@ -865,6 +923,74 @@ func autoHealClusterTask(d *Daemon) (task.Func, task.Schedule) {
 	return f, task.Every(time.Minute)
 }
 
+// healClusterFence fences an offline cluster member using the configured method.
+// Both the outcome and the method are recorded as lifecycle events.
+func healClusterFence(ctx context.Context, s *state.State, op *operations.Operation, member db.NodeInfo) error {
+	fencing, command := s.GlobalConfig.ClusterHealingFencing()
+	if fencing == "none" {
+		return nil
+	}
+
+	fence := func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		switch fencing {
+		case "command":
+			if command == "" {
+				return errors.New("No fencing command configured")
+			}
+
+			env := append(os.Environ(), "INCUS_MEMBER_NAME="+member.Name, "INCUS_MEMBER_ADDRESS="+member.Address)
+			_, _, err := subprocess.RunCommandSplit(ctx, env, nil, "sh", "-c", command)
+			if err != nil {
+				return err
+			}
+
+		case "scriptlet":
+			archName, _ := osarch.ArchitectureName(member.Architecture)
+
+			req := apiScriptlet.ClusterFencingMember{
+				Name:          member.Name,
+				Address:       member.Address,
+				Architecture:  archName,
+				Groups:        member.Groups,
+				Config:        member.Config,
+				LastHeartbeat: member.Heartbeat,
+			}
+
+			for _, role := range member.Roles {
+				req.Roles = append(req.Roles, string(role))
+			}
+
+			fenced, err := scriptlet.ClusterFencingRun(ctx, logger.Log, &req)
+			if err != nil {
+				return fmt.Errorf("Failed cluster fencing scriptlet: %w", err)
+			}
+
+			if !fenced {
+				return errors.New("Cluster fencing scriptlet didn't fence the member")
+			}
+
+		default:
+			return fmt.Errorf("Unknown fencing method %q", fencing)
+		}
+
+		return nil
+	}
+
+	err := fence()
+	if err != nil {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFencingFailed.Event(member.Name, op.Requestor(), map[string]any{"method": fencing, "error": err.Error()}))
+		return err
+	}
+
+	logger.Info("Fenced offline cluster member", logger.Ctx{"server": member.Name, "method": fencing})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFenced.Event(member.Name, op.Requestor(), map[string]any{"method": fencing}))
+
+	return nil
+}
+
 func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 	s := d.State()
 
@@ -890,6 +1016,7 @@ func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 
 		// Ignore anything using a local storage pool.
 		if !pool.Driver().Info().Remote {
+			evacuateHealingSkipped(s, inst, fmt.Sprintf("Storage pool %q isn't remote", poolName))
 			return nil
 		}
 
@@ -916,7 +1043,15 @@ func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 			return err
 		}
 
+		healedCtx := map[string]any{
+			"source":   sourceMemberInfo.Name,
+			"target":   targetMemberInfo.Name,
+			"priority": inst.ExpandedConfig()["cluster.healing_priority"],
+			"started":  startInstance,
+		}
+
 		if !startInstance {
+			s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealed.Event(inst, healedCtx))
 			return nil
 		}
 
@@ -931,6 +1066,8 @@ func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 			return err
 		}
 
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealed.Event(inst, healedCtx))
+
 		return nil
 	}
 
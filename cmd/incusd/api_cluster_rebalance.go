@@ -5,18 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"slices"
 	"sort"
 	"strconv"
 	"time"
 
 	internalInstance "github.com/lxc/incus/v7/internal/instance"
+	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/cluster"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
 	"github.com/lxc/incus/v7/internal/server/instance"
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/internal/server/operations"
 	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/response"
 	"github.com/lxc/incus/v7/internal/server/scriptlet"
 	"github.com/lxc/incus/v7/internal/server/state"
 	"github.com/lxc/incus/v7/internal/server/task"
@@ -81,8 +86,24 @@ func calculateScore(su *ServerUsage, au *ServerUsage) uint8 {
 	return (memoryScore + cpuScore) / 2
 }
 
+// clusterRebalanceMember returns the representation of a scored cluster member passed to the re-balancing scriptlet.
+func clusterRebalanceMember(server *ServerScore) apiScriptlet.ClusterRebalanceMember {
+	archName, _ := osarch.ArchitectureName(server.NodeInfo.Architecture)
+
+	return apiScriptlet.ClusterRebalanceMember{
+		Name:         server.NodeInfo.Name,
+		Address:      server.NodeInfo.Address,
+		Architecture: archName,
+		Groups:       server.NodeInfo.Groups,
+		Config:       server.NodeInfo.Config,
+		Resources:    server.Resources,
+		Score:        server.Score,
+	}
+}
+
 // calculateServersScore calculates score based on memory and CPU usage for servers in cluster.
-func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]*ServerScore, error) {
+// The score can be overridden by the cluster re-balancing scriptlet.
+func calculateServersScore(ctx context.Context, s *state.State, members []db.NodeInfo) (map[string][]*ServerScore, error) {
 	scores := []*ServerScore{}
 	for _, member := range members {
 		clusterMember, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
@@ -102,16 +123,83 @@ func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]
 			CPUTotal:    res.CPU.Total,
 		}
 
-		serverScore := calculateScore(su, nil)
-		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Score: serverScore})
+		serverScore := &ServerScore{NodeInfo: member, Resources: res, Score: calculateScore(su, nil)}
+
+		if s.GlobalConfig.ClusterRebalanceScriptlet() != "" {
+			scriptletMember := clusterRebalanceMember(serverScore)
+			score, ok, err := scriptlet.ClusterRebalanceScore(ctx, logger.Log, &scriptletMember)
+			if err != nil {
+				return nil, fmt.Errorf("Failed cluster re-balancing scriptlet for member %q: %w", member.Name, err)
+			}
+
+			if ok {
+				serverScore.Score = score
+			}
+		}
+
+		scores = append(scores, serverScore)
 	}
 
 	return sortAndGroupByArch(scores), nil
 }
 
-// clusterRebalanceServers is responsible for instances migration from the most busy server to less busy candidates.
-func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *ServerScore, candidates []*ServerScore, leaderAddress string, maxToMigrate int64) (int64, error) {
-	numOfMigrated := int64(0)
+// rebalanceMove represents an instance migration planned by cluster re-balancing.
+type rebalanceMove struct {
+	inst   instance.Instance
+	target db.NodeInfo
+}
+
+// clusterRebalanceCandidates returns the candidates allowed by the project restrictions and the placement rules of the instance.
+// The placement rules account for the moves already planned.
+func clusterRebalanceCandidates(ctx context.Context, s *state.State, inst instance.Instance, candidates []*ServerScore, moves []rebalanceMove) ([]*ServerScore, error) {
+	var instanceCandidates []*ServerScore
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), inst.Project().Name)
+		if err != nil {
+			return fmt.Errorf("Failed to get project: %w", err)
+		}
+
+		apiProject, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed to load project: %w", err)
+		}
+
+		placementRules, err := instance.LoadPlacementRules(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig())
+		if err != nil {
+			return err
+		}
+
+		for _, move := range moves {
+			placementRules.Relocate(move.inst.Project().Name, move.inst.Name(), move.target.Name)
+		}
+
+		for _, c := range candidates {
+			_, _, err := project.CheckTarget(ctx, s.Authorizer, nil, tx, apiProject, c.NodeInfo.Name, []db.NodeInfo{c.NodeInfo})
+			if err != nil {
+				continue
+			}
+
+			// Re-balancing never moves instances against their placement rules.
+			if placementRules.Check(c.NodeInfo.Name) != nil {
+				continue
+			}
+
+			instanceCandidates = append(instanceCandidates, c)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to filter candidates for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	return instanceCandidates, nil
+}
+
+// clusterRebalancePlanServer plans instances migration from the most busy server to less busy candidates.
+func clusterRebalancePlanServer(ctx context.Context, s *state.State, srcServer *ServerScore, candidates []*ServerScore, leaderAddress string, maxToMigrate int64) ([]rebalanceMove, error) {
+	moves := []rebalanceMove{}
 
 	// Restrict candidates to servers less loaded than the source.
 	lessLoadedCandidates := make([]*ServerScore, 0, len(candidates))
@@ -124,7 +212,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 	}
 
 	if len(lessLoadedCandidates) == 0 {
-		return numOfMigrated, nil
+		return moves, nil
 	}
 
 	// The default target is the least-loaded candidate (last in the sorted list).
@@ -144,7 +232,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		return nil
 	})
 	if err != nil {
-		return -1, fmt.Errorf("Failed to get instances: %w", err)
+		return nil, fmt.Errorf("Failed to get instances: %w", err)
 	}
 
 	// Filter for instances that can be live migrated to a new target.
@@ -152,7 +240,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 	for _, dbInst := range dbInstances {
 		inst, err := instance.LoadByProjectAndName(s, dbInst.Project, dbInst.Name)
 		if err != nil {
-			return -1, fmt.Errorf("Failed to load instance: %w", err)
+			return nil, fmt.Errorf("Failed to load instance: %w", err)
 		}
 
 		// Do not allow to migrate instance which doesn't support live migration.
@@ -166,12 +254,12 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		if lastMove != "" {
 			v, err := strconv.ParseInt(lastMove, 10, 64)
 			if err != nil {
-				return -1, fmt.Errorf("Failed to parse last_move value: %w", err)
+				return nil, fmt.Errorf("Failed to parse last_move value: %w", err)
 			}
 
 			expiry, err := internalInstance.GetExpiry(time.Unix(v, 0), cooldown)
 			if err != nil {
-				return -1, fmt.Errorf("Failed to calculate expiration for cooldown time: %w", err)
+				return nil, fmt.Errorf("Failed to calculate expiration for cooldown time: %w", err)
 			}
 
 			if time.Now().Before(expiry) {
@@ -182,6 +270,16 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		instances = append(instances, inst)
 	}
 
+	// Let the re-balancing scriptlet pick the instances to move, if it wants to.
+	scriptletMoves, ok, err := clusterRebalanceSelect(ctx, s, srcServer, lessLoadedCandidates, instances, maxToMigrate)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		return scriptletMoves, nil
+	}
+
 	// Map candidate name to its score data for quick lookup.
 	candidateByName := make(map[string]*ServerScore, len(lessLoadedCandidates))
 	for _, c := range lessLoadedCandidates {
@@ -204,44 +302,16 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 
 	placementScriptletEnabled := s.GlobalConfig.InstancesPlacementScriptlet() != ""
 
-	// Prepare the source API client.
-	srcClient, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
-	if err != nil {
-		return -1, fmt.Errorf("Failed to connect to cluster member: %w", err)
-	}
-
 	for _, inst := range instances {
-		if numOfMigrated >= maxToMigrate {
+		if int64(len(moves)) >= maxToMigrate {
 			// We're done moving instances for now.
-			return numOfMigrated, nil
+			return moves, nil
 		}
 
 		// Filter the candidate list for this instance using project restrictions.
-		var instanceCandidates []*ServerScore
-		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), inst.Project().Name)
-			if err != nil {
-				return fmt.Errorf("Failed to get project: %w", err)
-			}
-
-			apiProject, err := dbProject.ToAPI(ctx, tx.Tx())
-			if err != nil {
-				return fmt.Errorf("Failed to load project: %w", err)
-			}
-
-			for _, c := range lessLoadedCandidates {
-				_, _, err := project.CheckTarget(ctx, s.Authorizer, nil, tx, apiProject, c.NodeInfo.Name, []db.NodeInfo{c.NodeInfo})
-				if err != nil {
-					continue
-				}
-
-				instanceCandidates = append(instanceCandidates, c)
-			}
-
-			return nil
-		})
+		instanceCandidates, err := clusterRebalanceCandidates(ctx, s, inst, lessLoadedCandidates, moves)
 		if err != nil {
-			return -1, fmt.Errorf("Failed to filter candidates for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+			return nil, err
 		}
 
 		if len(instanceCandidates) == 0 {
@@ -266,7 +336,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		if placementScriptletEnabled {
 			archName, err := osarch.ArchitectureName(inst.Architecture())
 			if err != nil {
-				return -1, fmt.Errorf("Failed getting architecture for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+				return nil, fmt.Errorf("Failed getting architecture for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 			}
 
 			profileNames := make([]string, 0, len(inst.Profiles()))
@@ -299,7 +369,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			scriptTarget, err := scriptlet.InstancePlacementRun(scriptCtx, logger.Log, s, &placementReq, sortedCandidates, leaderAddress)
 			cancel()
 			if err != nil {
-				return -1, fmt.Errorf("Failed instance placement scriptlet for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+				return nil, fmt.Errorf("Failed instance placement scriptlet for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 			}
 
 			if scriptTarget != nil {
@@ -329,63 +399,163 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		// Calculate resource consumption.
 		cpuUsage, memUsage, _, err := instance.ResourceUsage(inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative(), api.InstanceType(inst.Type().String()))
 		if err != nil {
-			return -1, fmt.Errorf("Failed to establish instance resource usage: %w", err)
+			return nil, fmt.Errorf("Failed to establish instance resource usage: %w", err)
 		}
 
 		// Calculate impact of migration.
 		additionalUsage := &ServerUsage{
-			MemoryUsage: uint64(cpuUsage),
-			CPUUsage:    float64(memUsage),
+			MemoryUsage: uint64(memUsage),
+			CPUUsage:    float64(cpuUsage),
 		}
 
-		expectedScore := calculateScore(runningUsage[chosenTarget.Name], additionalUsage)
+		// Apply the impact estimated from the resource usage to the current score (which may come from the scriptlet).
+		usage := runningUsage[chosenTarget.Name]
+		impact := int(calculateScore(usage, additionalUsage)) - int(calculateScore(usage, nil))
+		expectedScore := uint8(min(max(int(runningScore[chosenTarget.Name])+impact, 0), 100))
 		if expectedScore >= targetScore {
 			// Skip the instance as it would have too big an impact.
 			continue
 		}
 
+		moves = append(moves, rebalanceMove{inst: inst, target: *chosenTarget})
+
+		// Update per-target running state.
+		runningScore[chosenTarget.Name] = expectedScore
+		runningUsage[chosenTarget.Name].MemoryUsage += additionalUsage.MemoryUsage
+		runningUsage[chosenTarget.Name].CPUUsage += additionalUsage.CPUUsage
+	}
+
+	return moves, nil
+}
+
+// clusterRebalanceSelect runs the select_instances function of the cluster re-balancing scriptlet.
+// It returns false if the scriptlet doesn't select the instances to move itself.
+func clusterRebalanceSelect(ctx context.Context, s *state.State, srcServer *ServerScore, candidates []*ServerScore, instances []instance.Instance, maxToMigrate int64) ([]rebalanceMove, bool, error) {
+	if s.GlobalConfig.ClusterRebalanceScriptlet() == "" {
+		return nil, false, nil
+	}
+
+	source := clusterRebalanceMember(srcServer)
+
+	scriptletCandidates := make([]apiScriptlet.ClusterRebalanceMember, 0, len(candidates))
+	for _, c := range slices.Backward(candidates) {
+		scriptletCandidates = append(scriptletCandidates, clusterRebalanceMember(c))
+	}
+
+	scriptletInstances := make([]apiScriptlet.ClusterRebalanceInstance, 0, len(instances))
+	for _, inst := range instances {
+		cpuUsage, memUsage, diskUsage, err := instance.ResourceUsage(inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative(), api.InstanceType(inst.Type().String()))
+		if err != nil {
+			return nil, false, fmt.Errorf("Failed to establish instance resource usage: %w", err)
+		}
+
+		scriptletInstances = append(scriptletInstances, apiScriptlet.ClusterRebalanceInstance{
+			Name:    inst.Name(),
+			Project: inst.Project().Name,
+			Type:    inst.Type().String(),
+			Config:  inst.ExpandedConfig(),
+			Resources: apiScriptlet.InstanceResources{
+				CPUCores:     uint64(cpuUsage),
+				MemorySize:   uint64(memUsage),
+				RootDiskSize: uint64(diskUsage),
+			},
+		})
+	}
+
+	migrations, ok, err := scriptlet.ClusterRebalanceSelect(ctx, logger.Log, &source, scriptletInstances, scriptletCandidates)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed cluster re-balancing scriptlet for member %q: %w", srcServer.NodeInfo.Name, err)
+	}
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	moves := []rebalanceMove{}
+	for _, migration := range migrations {
+		if int64(len(moves)) >= maxToMigrate {
+			break
+		}
+
+		// Look for the instance.
+		idx := slices.IndexFunc(instances, func(inst instance.Instance) bool {
+			return inst.Project().Name == migration.Project && inst.Name() == migration.Instance
+		})
+
+		if idx < 0 {
+			continue
+		}
+
+		inst := instances[idx]
+
+		// Check that the target is allowed for the instance.
+		instanceCandidates, err := clusterRebalanceCandidates(ctx, s, inst, candidates, moves)
+		if err != nil {
+			return nil, false, err
+		}
+
+		idx = slices.IndexFunc(instanceCandidates, func(c *ServerScore) bool { return c.NodeInfo.Name == migration.Target })
+		if idx < 0 {
+			logger.Warn("Cluster re-balancing scriptlet picked a target not allowed for the instance", logger.Ctx{"project": migration.Project, "instance": migration.Instance, "member": migration.Target})
+			continue
+		}
+
+		moves = append(moves, rebalanceMove{inst: inst, target: instanceCandidates[idx].NodeInfo})
+	}
+
+	return moves, true, nil
+}
+
+// clusterRebalanceServers is responsible for instances migration from the most busy server to less busy candidates.
+func clusterRebalanceServers(s *state.State, srcServer *ServerScore, moves []rebalanceMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	// Prepare the source API client.
+	srcClient, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to cluster member: %w", err)
+	}
+
+	for _, move := range moves {
 		// Prepare for live migration.
 		req := api.InstancePost{
 			Migration: true,
 			Live:      true,
 		}
 
-		targetClient := srcClient.UseTarget(chosenTarget.Name)
+		targetClient := srcClient.UseProject(move.inst.Project().Name).UseTarget(move.target.Name)
 
-		migrationOp, err := targetClient.MigrateInstance(inst.Name(), req)
+		migrationOp, err := targetClient.MigrateInstance(move.inst.Name(), req)
 		if err != nil {
-			return -1, fmt.Errorf("Migration API failure: %w", err)
+			return fmt.Errorf("Migration API failure: %w", err)
 		}
 
 		err = migrationOp.Wait()
 		if err != nil {
-			return -1, fmt.Errorf("Failed to wait for migration to finish: %w", err)
+			return fmt.Errorf("Failed to wait for migration to finish: %w", err)
 		}
 
 		// Record the migration in the instance volatile storage.
-		err = inst.VolatileSet(map[string]string{"volatile.rebalance.last_move": strconv.FormatInt(time.Now().Unix(), 10)})
+		err = move.inst.VolatileSet(map[string]string{"volatile.rebalance.last_move": strconv.FormatInt(time.Now().Unix(), 10)})
 		if err != nil {
-			return -1, err
+			return err
 		}
-
-		// Update counters and per-target running state.
-		numOfMigrated++
-		runningScore[chosenTarget.Name] = expectedScore
-		runningUsage[chosenTarget.Name].MemoryUsage += additionalUsage.MemoryUsage
-		runningUsage[chosenTarget.Name].CPUUsage += additionalUsage.CPUUsage
 	}
 
-	return numOfMigrated, nil
+	return nil
 }
 
 // clusterRebalance performs cluster re-balancing.
-func clusterRebalance(ctx context.Context, s *state.State, servers map[string][]*ServerScore, leaderAddress string) error {
+// When dryRun is set, the planned migrations are returned without moving anything.
+func clusterRebalance(ctx context.Context, s *state.State, servers map[string][]*ServerScore, leaderAddress string, dryRun bool) ([]api.ClusterRebalanceMigration, error) {
 	rebalanceThreshold := s.GlobalConfig.ClusterRebalanceThreshold()
 	rebalanceBatch := s.GlobalConfig.ClusterRebalanceBatch()
-	numOfMigrated := int64(0)
+	migrations := []api.ClusterRebalanceMigration{}
 
 	for archName, v := range servers {
-		if numOfMigrated >= rebalanceBatch {
+		if int64(len(migrations)) >= rebalanceBatch {
 			// Maximum number of instances already migrated in this run.
 			continue
 		}
@@ -408,39 +578,37 @@ func clusterRebalance(ctx context.Context, s *state.State, servers map[string][]
 			continue // Skip as threshold condition is not met.
 		}
 
-		n, err := clusterRebalanceServers(ctx, s, v[0], v[1:], leaderAddress, rebalanceBatch-numOfMigrated)
+		moves, err := clusterRebalancePlanServer(ctx, s, v[0], v[1:], leaderAddress, rebalanceBatch-int64(len(migrations)))
 		if err != nil {
-			return fmt.Errorf("Failed to rebalance cluster: %w", err)
+			return nil, fmt.Errorf("Failed to rebalance cluster: %w", err)
 		}
 
-		numOfMigrated += n
-	}
-
-	return nil
-}
-
-func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
-	s := d.State()
-
-	// Confirm we should run the rebalance.
-	leader, err := s.Cluster.LeaderAddress()
-	if err != nil {
-		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
-			// Not clustered.
-			return nil
+		if !dryRun {
+			err = clusterRebalanceServers(s, v[0], moves)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to rebalance cluster: %w", err)
+			}
 		}
 
-		return fmt.Errorf("Failed to get leader cluster member address: %w", err)
+		for _, move := range moves {
+			migrations = append(migrations, api.ClusterRebalanceMigration{
+				Project:  move.inst.Project().Name,
+				Instance: move.inst.Name(),
+				Source:   v[0].NodeInfo.Name,
+				Target:   move.target.Name,
+			})
+		}
 	}
 
-	if s.LocalConfig.ClusterAddress() != leader {
-		// Not the leader.
-		return nil
-	}
+	return migrations, nil
+}
 
+// clusterRebalancePlan scores the online cluster members and re-balances the cluster.
+// When dryRun is set, the planned migrations are returned without moving anything.
+func clusterRebalancePlan(ctx context.Context, s *state.State, leaderAddress string, dryRun bool) (*api.ClusterRebalancePlan, error) {
 	// Get all online members
 	var onlineMembers []db.NodeInfo
-	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		members, err := tx.GetNodes(ctx)
 		if err != nil {
 			return fmt.Errorf("Failed getting cluster members: %w", err)
@@ -454,17 +622,51 @@ func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("Failed getting cluster members: %w", err)
+		return nil, fmt.Errorf("Failed getting cluster members: %w", err)
 	}
 
-	servers, err := calculateServersScore(s, onlineMembers)
+	servers, err := calculateServersScore(ctx, s, onlineMembers)
 	if err != nil {
-		return fmt.Errorf("Failed calculating servers score: %w", err)
+		return nil, fmt.Errorf("Failed calculating servers score: %w", err)
+	}
+
+	plan := &api.ClusterRebalancePlan{Scores: map[string]uint8{}}
+	for _, archServers := range servers {
+		for _, server := range archServers {
+			plan.Scores[server.NodeInfo.Name] = server.Score
+		}
 	}
 
-	err = clusterRebalance(ctx, s, servers, leader)
+	plan.Migrations, err = clusterRebalance(ctx, s, servers, leaderAddress, dryRun)
 	if err != nil {
-		return fmt.Errorf("Failed rebalancing cluster: %w", err)
+		return nil, fmt.Errorf("Failed rebalancing cluster: %w", err)
+	}
+
+	return plan, nil
+}
+
+func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
+	s := d.State()
+
+	// Confirm we should run the rebalance.
+	leader, err := s.Cluster.LeaderAddress()
+	if err != nil {
+		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			// Not clustered.
+			return nil
+		}
+
+		return fmt.Errorf("Failed to get leader cluster member address: %w", err)
+	}
+
+	if s.LocalConfig.ClusterAddress() != leader {
+		// Not the leader.
+		return nil
+	}
+
+	_, err = clusterRebalancePlan(ctx, s, leader, false)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -497,3 +699,113 @@ func autoRebalanceClusterTask(d *Daemon) (task.Func, task.Schedule) {
 
 	return f, task.Every(time.Minute, task.SkipFirst)
 }
+
+var clusterRebalanceCmd = APIEndpoint{
+	Path: "cluster/rebalance",
+
+	Get:  APIEndpointAction{Handler: clusterRebalanceGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: clusterRebalancePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/cluster/rebalance cluster cluster_rebalance_get
+//
+//	Get the cluster re-balancing plan
+//
+//	Scores the cluster members and returns the instance migrations that re-balancing would perform, without moving anything.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Re-balancing plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalancePlan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	leader, err := s.Cluster.LeaderAddress()
+	if err != nil {
+		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			return response.BadRequest(errors.New("This server is not clustered"))
+		}
+
+		return response.SmartError(err)
+	}
+
+	plan, err := clusterRebalancePlan(r.Context(), s, leader, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, plan)
+}
+
+// swagger:operation POST /1.0/cluster/rebalance cluster cluster_rebalance_post
+//
+//	Re-balance the cluster
+//
+//	Re-balances the cluster now, regardless of the configured interval.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	leader, err := s.Cluster.LeaderAddress()
+	if err != nil {
+		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			return response.BadRequest(errors.New("This server is not clustered"))
+		}
+
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		plan, err := clusterRebalancePlan(context.Background(), s, leader, false)
+		if err != nil {
+			return err
+		}
+
+		return op.UpdateMetadata(map[string]any{"migrations": plan.Migrations})
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/cluster"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+var clusterUpgradeCmd = APIEndpoint{
+	Path: "cluster/upgrade",
+
+	Post: APIEndpointAction{Handler: clusterUpgradePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var internalClusterUpgradeCmd = APIEndpoint{
+	Path: "cluster/upgrade",
+
+	Post: APIEndpointAction{Handler: internalClusterPostUpgrade, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation POST /1.0/cluster/upgrade cluster cluster_upgrade_post
+//
+//	Upgrade the cluster
+//
+//	Performs a rolling upgrade of the cluster, one member at a time.
+//	Each member is evacuated, upgraded, waited for and then restored.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: upgrade
+//	    description: Upgrade request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterUpgradePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterUpgradePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	req := api.ClusterUpgradePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Timeout < 0 {
+		return response.BadRequest(errors.New("Timeout can't be negative"))
+	}
+
+	timeout := 30 * time.Minute
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	var members []db.NodeInfo
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Upgrade all members by default, the local member last as it has to hand over the upgrade.
+	memberNames := req.Members
+	if len(memberNames) == 0 {
+		for _, member := range members {
+			memberNames = append(memberNames, member.Name)
+		}
+
+		sort.SliceStable(memberNames, func(i, j int) bool {
+			if memberNames[i] == s.ServerName || memberNames[j] == s.ServerName {
+				return memberNames[j] == s.ServerName
+			}
+
+			return memberNames[i] < memberNames[j]
+		})
+	}
+
+	// Offline members can't be upgraded.
+	for i, name := range memberNames {
+		if slices.Contains(memberNames[:i], name) {
+			return response.BadRequest(fmt.Errorf("Cluster member %q is listed more than once", name))
+		}
+
+		idx := slices.IndexFunc(members, func(member db.NodeInfo) bool { return member.Name == name })
+		if idx < 0 {
+			return response.BadRequest(fmt.Errorf("Cluster member %q doesn't exist", name))
+		}
+
+		if members[idx].IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return response.BadRequest(fmt.Errorf("Cluster member %q is offline", name))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func(op *operations.Operation) error {
+		defer cancel()
+
+		return clusterUpgradeMembers(ctx, s, op, memberNames, req.Version, timeout)
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterUpgrade, nil, nil, run, onCancel, nil, r)
+	if err != nil {
+		cancel()
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// clusterUpgradeMembers upgrades the cluster members one at a time.
+// When reaching the local member, the rest of the upgrade is handed over to another member.
+func clusterUpgradeMembers(ctx context.Context, s *state.State, op *operations.Operation, memberNames []string, version string, timeout time.Duration) error {
+	for i, name := range memberNames {
+		if name == s.ServerName {
+			return clusterUpgradeHandover(ctx, s, op, memberNames[:i], memberNames[i:], version, timeout)
+		}
+
+		err := clusterUpgradeMember(ctx, s, op, fmt.Sprintf("%d/%d", i+1, len(memberNames)), name, version, timeout)
+		if err != nil {
+			return fmt.Errorf("Failed upgrading cluster member %q: %w", name, err)
+		}
+	}
+
+	_ = op.UpdateMetadata(map[string]any{"upgrade_progress": "All cluster members upgraded"})
+
+	return nil
+}
+
+// clusterUpgradeMember evacuates a cluster member, triggers its upgrade, waits for it to come back and restores it.
+func clusterUpgradeMember(ctx context.Context, s *state.State, op *operations.Operation, step string, name string, version string, timeout time.Duration) error {
+	progress := func(format string, args ...any) {
+		msg := fmt.Sprintf("[%s] %s", step, fmt.Sprintf(format, args...))
+		logger.Info("Rolling cluster upgrade", logger.Ctx{"member": name, "progress": msg})
+		_ = op.UpdateMetadata(map[string]any{"upgrade_progress": msg})
+	}
+
+	var member db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = tx.GetNodeByName(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	server, _, err := client.GetServer()
+	if err != nil {
+		return err
+	}
+
+	currentVersion := server.Environment.ServerVersion
+	if version != "" && currentVersion == version {
+		progress("Member %q already runs version %s", name, version)
+		return nil
+	}
+
+	// Drain the member.
+	if member.State != db.ClusterMemberStateEvacuated {
+		progress("Evacuating member %q", name)
+
+		evacuateOp, err := client.UpdateClusterMemberState(name, api.ClusterMemberStatePost{Action: "evacuate"})
+		if err != nil {
+			return err
+		}
+
+		err = evacuateOp.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Trigger the upgrade.
+	progress("Upgrading member %q from version %s", name, currentVersion)
+
+	_, _, err = client.RawQuery("POST", "/internal/cluster/upgrade", nil, "")
+	if err != nil {
+		return err
+	}
+
+	// Wait for the member to come back online at the new version.
+	progress("Waiting for member %q to come back", name)
+
+	triggered := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("Member didn't come back with a new version within %s (members with database or API changes wait for the rest of the cluster to be upgraded)", timeout)
+		case <-time.After(10 * time.Second):
+		}
+
+		client, err = cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			continue
+		}
+
+		server, _, err := client.GetServer()
+		if err != nil {
+			continue
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			member, err = tx.GetNodeByName(ctx, name)
+
+			return err
+		})
+		if err != nil {
+			continue
+		}
+
+		newVersion := server.Environment.ServerVersion
+		if clusterUpgradeMemberBack(member, s.GlobalConfig.OfflineThreshold(), triggered, currentVersion, version, newVersion) {
+			progress("Member %q is back with version %s", name, newVersion)
+			break
+		}
+	}
+
+	// Put the member back into service.
+	progress("Restoring member %q", name)
+
+	restoreOp, err := client.UpdateClusterMemberState(name, api.ClusterMemberStatePost{Action: "restore"})
+	if err != nil {
+		return err
+	}
+
+	return restoreOp.WaitContext(ctx)
+}
+
+// clusterUpgradeMemberBack returns whether a member whose upgrade was triggered is back online with the
+// expected version, or with any new version if none is expected.
+func clusterUpgradeMemberBack(member db.NodeInfo, offlineThreshold time.Duration, triggered time.Time, currentVersion string, version string, newVersion string) bool {
+	if version != "" && newVersion != version {
+		return false
+	}
+
+	if version == "" && newVersion == currentVersion {
+		return false
+	}
+
+	// Only trust heartbeats received since the upgrade was triggered.
+	return member.Heartbeat.After(triggered) && !member.IsOffline(offlineThreshold)
+}
+
+// clusterUpgradeHandover hands the upgrade of the remaining members (starting with the local member)
+// over to another cluster member, preferably one which was already upgraded.
+func clusterUpgradeHandover(ctx context.Context, s *state.State, op *operations.Operation, upgraded []string, remaining []string, version string, timeout time.Duration) error {
+	var members []db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Prefer upgraded members, most recent first.
+	candidates := []db.NodeInfo{}
+	for _, name := range slices.Backward(upgraded) {
+		idx := slices.IndexFunc(members, func(member db.NodeInfo) bool { return member.Name == name })
+		if idx >= 0 {
+			candidates = append(candidates, members[idx])
+		}
+	}
+
+	for _, member := range members {
+		if !slices.Contains(upgraded, member.Name) && !slices.Contains(remaining, member.Name) {
+			candidates = append(candidates, member)
+		}
+	}
+
+	var client incus.InstanceServer
+	var target db.NodeInfo
+	for _, candidate := range candidates {
+		if candidate.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			continue
+		}
+
+		client, err = cluster.Connect(candidate.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err == nil {
+			target = candidate
+			break
+		}
+	}
+
+	if client == nil {
+		return fmt.Errorf("No other cluster member available to upgrade %q", s.ServerName)
+	}
+
+	handoverOp, err := client.UpgradeCluster(api.ClusterUpgradePost{
+		Members: remaining,
+		Version: version,
+		Timeout: int64(timeout / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed handing over the upgrade to %q: %w", target.Name, err)
+	}
+
+	handover := handoverOp.Get()
+	_ = op.UpdateMetadata(map[string]any{
+		"upgrade_progress":   fmt.Sprintf("Upgrade of %q handed over to %q", s.ServerName, target.Name),
+		"handover_member":    target.Name,
+		"handover_operation": handover.ID,
+	})
+
+	// The local member gets upgraded by the other member, so this only returns once the upgrade is
+	// over or this member shuts down for its upgrade. Clients should follow the handover operation.
+	err = handoverOp.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Upgrade handed over to %q failed: %w", target.Name, err)
+	}
+
+	return nil
+}
+
+func internalClusterPostUpgrade(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	err := cluster.TriggerUpgrade(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+)
+
+func TestClusterUpgradeMemberBack(t *testing.T) {
+	threshold := 20 * time.Second
+	triggered := time.Now().Add(-time.Minute)
+
+	online := db.NodeInfo{Heartbeat: time.Now()}
+	offline := db.NodeInfo{Heartbeat: time.Now().Add(-time.Hour)}
+	stale := db.NodeInfo{Heartbeat: triggered.Add(-time.Second)}
+
+	tests := []struct {
+		name       string
+		member     db.NodeInfo
+		version    string
+		newVersion string
+		back       bool
+	}{
+		{name: "new version", member: online, newVersion: "7.1", back: true},
+		{name: "same version", member: online, newVersion: "7.0"},
+		{name: "expected version", member: online, version: "7.2", newVersion: "7.2", back: true},
+		{name: "unexpected version", member: online, version: "7.2", newVersion: "7.1"},
+		{name: "offline", member: offline, newVersion: "7.1"},
+		{name: "heartbeat before the upgrade", member: stale, newVersion: "7.1"},
+	}
+
+	for _, test := range tests {
+		back := clusterUpgradeMemberBack(test.member, threshold, triggered, "7.0", test.version, test.newVersion)
+		assert.Equal(t, test.back, back, test.name)
+	}
+}
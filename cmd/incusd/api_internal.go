@@ -57,11 +57,14 @@ var apiInternal = []APIEndpoint{
 	internalClusterHandoverCmd,
 	internalClusterRaftNodeCmd,
 	internalClusterRebalanceCmd,
+	internalClusterUpgradeCmd,
 	internalContainerOnStartCmd,
 	internalContainerOnStopCmd,
 	internalContainerOnStopNSCmd,
 	internalVirtualMachineOnResizeCmd,
 	internalGarbageCollectorCmd,
+	internalImageChunkCmd,
+	internalImageChunksCmd,
 	internalImageOptimizeCmd,
 	internalImageRefreshCmd,
 	internalRAFTSnapshotCmd,
@@ -99,6 +102,18 @@ var internalImageOptimizeCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: internalOptimizeImage, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalImageChunksCmd = APIEndpoint{
+	Path: "image-chunks/{fingerprint}",
+
+	Get: APIEndpointAction{Handler: internalImageChunksGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var internalImageChunkCmd = APIEndpoint{
+	Path: "image-chunks/{fingerprint}/{file}/{index}",
+
+	Get: APIEndpointAction{Handler: internalImageChunkGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 var internalRebalanceLoadCmd = APIEndpoint{
 	Path: "rebalance",
 
@@ -277,7 +292,7 @@ func internalOptimizeImage(d *Daemon, r *http.Request) response.Response {
 func internalRefreshImage(d *Daemon, _ *http.Request) response.Response {
 	s := d.State()
 
-	err := autoUpdateImages(s.ShutdownCtx, s)
+	err := autoUpdateImages(s.ShutdownCtx, s, nil)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v7/internal/server/locking"
 	"github.com/lxc/incus/v7/internal/server/metrics"
+	"github.com/lxc/incus/v7/internal/server/network"
 	projecthelpers "github.com/lxc/incus/v7/internal/server/project"
 	"github.com/lxc/incus/v7/internal/server/request"
 	"github.com/lxc/incus/v7/internal/server/response"
@@ -132,6 +133,14 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Add flow accounting metrics.
+	flowMetrics, err := network.FlowMetrics(s, projectNames)
+	if err != nil {
+		logger.Warn("Failed getting network flow metrics", logger.Ctx{"err": err})
+	} else {
+		intMetrics.Merge(flowMetrics)
+	}
+
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
 	invalidProjectFilters := func(projectNames []string) []dbCluster.InstanceFilter {
 		metricsCacheLock.Lock()
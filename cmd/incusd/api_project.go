@@ -17,6 +17,7 @@ import (
 	"github.com/lxc/incus/v7/internal/filter"
 	"github.com/lxc/incus/v7/internal/jmap"
 	"github.com/lxc/incus/v7/internal/server/auth"
+	serverCluster "github.com/lxc/incus/v7/internal/server/cluster"
 	"github.com/lxc/incus/v7/internal/server/db"
 	"github.com/lxc/incus/v7/internal/server/db/cluster"
 	"github.com/lxc/incus/v7/internal/server/db/operationtype"
@@ -343,23 +344,15 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 	// Parse the request.
 	project := api.ProjectsPost{}
 
-	// Set default features.
-	if project.Config == nil {
-		project.Config = map[string]string{}
-	}
-
-	for featureName, featureInfo := range cluster.ProjectFeatures {
-		_, ok := project.Config[featureName]
-		if !ok && featureInfo.DefaultEnabled {
-			project.Config[featureName] = "true"
-		}
-	}
-
 	err := json.NewDecoder(r.Body).Decode(&project)
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
+	if project.Config == nil {
+		project.Config = map[string]string{}
+	}
+
 	// Quick checks.
 	err = validate.IsAPIName(project.Name, false)
 	if err != nil {
@@ -371,14 +364,97 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Identities which don't administer the server can only create projects from self-service templates.
+	selfService := projectTemplateSelfService(s, r)
+	if selfService && project.Template == "" {
+		return response.Forbidden(errors.New("Projects can only be created from a self-service project template"))
+	}
+
+	var template *api.ProjectTemplate
+	if project.Template != "" {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			template, err = tx.GetProjectTemplate(ctx, project.Template)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if selfService && !template.SelfService {
+			return response.NotFound(errors.New("Project template not found"))
+		}
+
+		// Apply the template configuration, which only administrators can override.
+		for key, value := range template.Config {
+			requested, ok := project.Config[key]
+			if ok && requested != value && selfService {
+				return response.Forbidden(fmt.Errorf("Project template %q doesn't allow changing %q", template.Name, key))
+			}
+
+			if !ok || selfService {
+				project.Config[key] = value
+			}
+		}
+
+		if project.Description == "" {
+			project.Description = template.Description
+		}
+	}
+
+	// Restrictions and limits are set by administrators.
+	if selfService {
+		keys := []string{}
+		for key := range project.Config {
+			_, fromTemplate := template.Config[key]
+			if !fromTemplate {
+				keys = append(keys, key)
+			}
+		}
+
+		err = projectCheckAdminKeys(keys)
+		if err != nil {
+			return response.Forbidden(err)
+		}
+	}
+
+	// Set default features.
+	for featureName, featureInfo := range cluster.ProjectFeatures {
+		_, ok := project.Config[featureName]
+		if !ok && featureInfo.DefaultEnabled {
+			project.Config[featureName] = "true"
+		}
+	}
+
 	// Validate the configuration.
 	err = projectValidateConfig(s, project.Config)
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
+	if template != nil {
+		err = projectTemplateCheckFeatures(project.Config, template)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	requestor := request.CreateRequestor(r)
+
 	var id int64
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check the number of projects the identity already created.
+		if selfService {
+			owned, err := tx.GetOwnedProjects(ctx, requestor.Protocol, requestor.Username)
+			if err != nil {
+				return err
+			}
+
+			maxProjects := s.GlobalConfig.AuthorizationSelfServiceMaxProjects()
+			if int64(len(owned)) >= maxProjects {
+				return api.StatusErrorf(http.StatusForbidden, "Identity reached its limit of %d self-service projects", maxProjects)
+			}
+		}
+
 		id, err = cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Description: project.Description, Name: project.Name})
 		if err != nil {
 			return fmt.Errorf("Failed adding database record: %w", err)
@@ -403,6 +479,14 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		// The identity administers the projects it creates through self-service.
+		if selfService {
+			err = tx.CreateProjectOwner(ctx, project.Name, requestor.Protocol, requestor.Username)
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -414,7 +498,15 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to add project to authorizer", logger.Ctx{"name": project.Name, "error": err})
 	}
 
-	requestor := request.CreateRequestor(r)
+	// Create the resources of the template.
+	if template != nil {
+		err = projectTemplateApply(s, project.Name, template)
+		if err != nil {
+			projectDeleteAfterFailedTemplate(s, project.Name)
+			return response.SmartError(fmt.Errorf("Failed applying project template %q: %w", template.Name, err))
+		}
+	}
+
 	lc := lifecycle.ProjectCreated.Event(project.Name, requestor, nil)
 	s.Events.SendLifecycle(project.Name, lc)
 
@@ -598,10 +690,25 @@ func projectPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// The project has already been updated by the notifying member, only apply the local changes.
+	if isClusterNotification(r) {
+		err = network.UpdateBridgeLimits(s, "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	confined, err := projectSelfServiceConfined(r.Context(), s, r, project.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
-	return projectChange(r.Context(), s, project, req)
+	return projectChange(r.Context(), s, project, req, confined)
 }
 
 // swagger:operation PATCH /1.0/projects/{name} projects project_patch
@@ -719,14 +826,58 @@ func projectPatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	confined, err := projectSelfServiceConfined(r.Context(), s, r, project.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
-	return projectChange(r.Context(), s, project, req)
+	return projectChange(r.Context(), s, project, req, confined)
+}
+
+// projectAdminOnlyKey returns whether a project configuration key can only be set by server administrators.
+func projectAdminOnlyKey(key string) bool {
+	return key == "restricted" || strings.HasPrefix(key, "restricted.") || strings.HasPrefix(key, "limits.")
+}
+
+// projectCheckAdminKeys returns an error if any of the given project configuration keys can only be set by
+// server administrators. It is used for identities which don't administer the server, such as the owners
+// of projects created through self-service.
+func projectCheckAdminKeys(keys []string) error {
+	for _, key := range keys {
+		if projectAdminOnlyKey(key) {
+			return fmt.Errorf("Only administrators can set %q", key)
+		}
+	}
+
+	return nil
+}
+
+// projectSelfServiceConfined returns whether the request can't change the restrictions and limits of the project.
+// This is the case for projects created through self-service when changed by identities not administering the server.
+func projectSelfServiceConfined(ctx context.Context, s *state.State, r *http.Request, projectName string) (bool, error) {
+	if !projectTemplateSelfService(s, r) {
+		return false, nil
+	}
+
+	var owned bool
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		owned, err = tx.ProjectHasOwner(ctx, projectName)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return owned, nil
 }
 
 // Common logic between PUT and PATCH.
-func projectChange(ctx context.Context, s *state.State, project *api.Project, req api.ProjectPut) response.Response {
+func projectChange(ctx context.Context, s *state.State, project *api.Project, req api.ProjectPut, confined bool) response.Response {
 	// Make a list of config keys that have changed.
 	configChanged := []string{}
 	for key := range project.Config {
@@ -742,6 +893,14 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		}
 	}
 
+	// Restrictions and limits confine self-service projects, only server administrators can change them.
+	if confined {
+		err := projectCheckAdminKeys(configChanged)
+		if err != nil {
+			return response.Forbidden(err)
+		}
+	}
+
 	// Record which features have been changed.
 	var featuresChanged []string
 	for _, configKeyChanged := range configChanged {
@@ -824,6 +983,31 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.SmartError(err)
 	}
 
+	// Apply the new network limits to the OVN networks of the project and to the bridges of all members.
+	if slices.Contains(configChanged, "limits.network.ingress") || slices.Contains(configChanged, "limits.network.egress") {
+		err = network.UpdateOVNLimits(s, project.Name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = network.UpdateBridgeLimits(s, "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		notifier, err := serverCluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), serverCluster.NotifyAlive)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UpdateProject(project.Name, req, "")
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.EmptySyncResponse
 }
 
@@ -1496,6 +1680,14 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: When an unused cached remote image is flushed in the project
 		"images.remote_cache_expiry": validate.Optional(validate.IsInt64),
 
+		// gendoc:generate(entity=project, group=specific, key=images.verify.required)
+		// When enabled, images downloaded into the project must be signed by one of the keys in {config:option}`server-images:images.verify.keys`.
+		// This can only add to the server-wide {config:option}`server-images:images.verify.required` policy, not relax it.
+		// ---
+		//  type: bool
+		//  shortdesc: Whether images downloaded into the project must be signed
+		"images.verify.required": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.instances)
 		//
 		// ---
@@ -1545,6 +1737,29 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.snapshots)
+		// This value is the maximum number of instance and custom volume snapshots in the project, including scheduled snapshots.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of snapshots in the project
+		"limits.snapshots": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.backups.disk)
+		// This value is the maximum value of the aggregate size of the instance, custom volume and bucket backup tarballs stored for the project.
+		// Backup creation fails once the limit is reached.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum disk space used by the backups of the project
+		"limits.backups.disk": validate.Optional(validate.IsSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.images.disk)
+		// This value is the maximum value of the aggregate size of the images of the project, including cached images.
+		// It only applies if {config:option}`project-features:features.images` is enabled.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum disk space used by the images of the project
+		"limits.images.disk": validate.Optional(validate.IsSize),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -1552,6 +1767,22 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum number of networks that the project can have
 		"limits.networks": validate.Optional(validate.IsUint32),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.network.ingress)
+		// This limit applies to the aggregate traffic going to all the instances of the project on a network.
+		// On bridge networks, it is enforced separately on each cluster member.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum bandwidth in bit/s for incoming traffic of the project's instances on each network
+		"limits.network.ingress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network.egress)
+		// This limit applies to the aggregate traffic coming from all the instances of the project on a network.
+		// On bridge networks, it is enforced separately on each cluster member.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum bandwidth in bit/s for outgoing traffic of the project's instances on each network
+		"limits.network.egress": validate.Optional(validate.IsBitSize),
+
 		// gendoc:generate(entity=project, group=specific, key=network.hwaddr_pattern)
 		// Specify a MAC address template, e.g. `10:66:6a:xx:xx:xx`, to use within the cluster.
 		// Every `x` in the template will be replaced by a random character in `0`–`f`.
@@ -0,0 +1,623 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+var projectTemplatesCmd = APIEndpoint{
+	Path: "project-templates",
+
+	Get:  APIEndpointAction{Handler: projectTemplatesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateProjects)},
+	Post: APIEndpointAction{Handler: projectTemplatesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var projectTemplateCmd = APIEndpoint{
+	Path: "project-templates/{name}",
+
+	Delete: APIEndpointAction{Handler: projectTemplateDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: projectTemplateGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateProjects)},
+	Put:    APIEndpointAction{Handler: projectTemplatePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: projectTemplatePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: projectTemplatePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// projectTemplateValidate validates the content of a project template.
+func projectTemplateValidate(s *state.State, template api.ProjectTemplatePut) error {
+	err := projectValidateConfig(s, template.Config)
+	if err != nil {
+		return err
+	}
+
+	profiles := []string{}
+	for _, profile := range template.Profiles {
+		if profile.Name == "" || slices.Contains(profiles, profile.Name) {
+			return fmt.Errorf("Invalid or duplicate profile name %q", profile.Name)
+		}
+
+		profiles = append(profiles, profile.Name)
+	}
+
+	networks := []string{}
+	for _, network := range template.Networks {
+		if network.Name == "" || slices.Contains(networks, network.Name) {
+			return fmt.Errorf("Invalid or duplicate network name %q", network.Name)
+		}
+
+		networks = append(networks, network.Name)
+	}
+
+	acls := []string{}
+	for _, acl := range template.NetworkACLs {
+		if acl.Name == "" || slices.Contains(acls, acl.Name) {
+			return fmt.Errorf("Invalid or duplicate network ACL name %q", acl.Name)
+		}
+
+		acls = append(acls, acl.Name)
+	}
+
+	return nil
+}
+
+// projectTemplateCheckFeatures checks that a project configuration isolates the resources created by a template.
+func projectTemplateCheckFeatures(config map[string]string, template *api.ProjectTemplate) error {
+	if len(template.Profiles) > 0 && util.IsFalseOrEmpty(config["features.profiles"]) {
+		return fmt.Errorf("Project template %q creates profiles and requires %q", template.Name, "features.profiles")
+	}
+
+	if (len(template.Networks) > 0 || len(template.NetworkACLs) > 0) && util.IsFalseOrEmpty(config["features.networks"]) {
+		return fmt.Errorf("Project template %q creates networks and requires %q", template.Name, "features.networks")
+	}
+
+	return nil
+}
+
+// projectTemplateApply creates the network ACLs, networks and profiles of a template in a new project.
+func projectTemplateApply(s *state.State, projectName string, template *api.ProjectTemplate) error {
+	client, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), nil)
+	if err != nil {
+		return err
+	}
+
+	client = client.UseProject(projectName)
+
+	for _, acl := range template.NetworkACLs {
+		err := client.CreateNetworkACL(acl)
+		if err != nil {
+			return fmt.Errorf("Failed creating network ACL %q: %w", acl.Name, err)
+		}
+	}
+
+	for _, network := range template.Networks {
+		err := client.CreateNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Failed creating network %q: %w", network.Name, err)
+		}
+	}
+
+	for _, profile := range template.Profiles {
+		if profile.Name == api.ProjectDefaultName {
+			err = client.UpdateProfile(profile.Name, profile.ProfilePut, "")
+		} else {
+			err = client.CreateProfile(profile)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed creating profile %q: %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// projectDeleteAfterFailedTemplate deletes a project whose template couldn't be applied, along with the resources already created.
+func projectDeleteAfterFailedTemplate(s *state.State, projectName string) {
+	client, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), nil)
+	if err == nil {
+		err = client.DeleteProjectForce(projectName)
+	}
+
+	if err != nil {
+		logger.Error("Failed deleting project after failing to apply its template", logger.Ctx{"project": projectName, "err": err})
+	}
+}
+
+// projectTemplateSelfService returns whether the requestor can only use self-service project templates.
+func projectTemplateSelfService(s *state.State, r *http.Request) bool {
+	return s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanEdit) != nil
+}
+
+// swagger:operation GET /1.0/project-templates project-templates project_templates_get
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (URLs).
+//	Identities which don't administer the server only get the self-service templates.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/project-templates/tenant",
+//	              "/1.0/project-templates/lab"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/project-templates?recursion=1 project-templates project_templates_get_recursion1
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (structs).
+//	Identities which don't administer the server only get the self-service templates.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of project templates
+//	          items:
+//	            $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var templates []api.ProjectTemplate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		templates, err = tx.GetProjectTemplates(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if projectTemplateSelfService(s, r) {
+		templates = slices.DeleteFunc(templates, func(template api.ProjectTemplate) bool { return !template.SelfService })
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, templates)
+	}
+
+	urls := make([]string, 0, len(templates))
+	for _, template := range templates {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "project-templates", template.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/project-templates project-templates project_templates_post
+//
+//	Add a project template
+//
+//	Creates a new project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ProjectTemplatesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = validate.IsAPIName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid project template name: %w", err))
+	}
+
+	err = projectTemplateValidate(s, req.ProjectTemplatePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Create the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateProjectTemplate(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.ProjectTemplateCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/project-templates/{name} project-templates project_template_get
+//
+//	Get the project template
+//
+//	Gets a specific project template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project template name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    description: Project template
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var template *api.ProjectTemplate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err = tx.GetProjectTemplate(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !template.SelfService && projectTemplateSelfService(s, r) {
+		return response.NotFound(errors.New("Project template not found"))
+	}
+
+	return response.SyncResponseETag(true, template, template.Writable())
+}
+
+// swagger:operation PUT /1.0/project-templates/{name} project-templates project_template_put
+//
+//	Update the project template
+//
+//	Updates the entire project template.
+//	Existing projects created from the template are left unchanged.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project template name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/project-templates/{name} project-templates project_template_patch
+//
+//	Partially update the project template
+//
+//	Updates a subset of the project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project template name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var template *api.ProjectTemplate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err = tx.GetProjectTemplate(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, template.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	// Start from the current template on PATCH so that omitted fields are kept.
+	req := api.ProjectTemplatePut{}
+	if r.Method == http.MethodPatch {
+		req = template.Writable()
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Update the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateProjectTemplate(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/project-templates/{name} project-templates project_template_post
+//
+//	Rename the project template
+//
+//	Renames an existing project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project template name
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: template
+//	    description: Project template rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectTemplatePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = validate.IsAPIName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid project template name: %w", err))
+	}
+
+	// Rename the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameProjectTemplate(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.ProjectTemplateRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/project-templates/{name} project-templates project_template_delete
+//
+//	Delete the project template
+//
+//	Removes the project template.
+//	Projects created from the template are left unchanged.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project template name
+//	    type: string
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Delete the DB record.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteProjectTemplate(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Emit the lifecycle event.
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
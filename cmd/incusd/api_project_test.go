@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectCheckAdminKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		allowed bool
+	}{
+		{name: "user keys", keys: []string{"user.foo", "features.images"}, allowed: true},
+		{name: "no changes", keys: []string{}, allowed: true},
+		{name: "restricted", keys: []string{"restricted"}},
+		{name: "restricted sub-key", keys: []string{"user.foo", "restricted.containers.nesting"}},
+		{name: "limits", keys: []string{"limits.instances"}},
+		{name: "storage limits", keys: []string{"limits.snapshots"}},
+		{name: "similar prefix", keys: []string{"user.limits.foo", "user.restricted"}, allowed: true},
+	}
+
+	for _, test := range tests {
+		// Owners of self-service projects don't administer the server.
+		err := projectCheckAdminKeys(test.keys)
+		if test.allowed {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
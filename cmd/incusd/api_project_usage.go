@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/auth"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/internal/server/task"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// projectUsageInterval is how often the resource usage of the local instances is sampled.
+const projectUsageInterval = 5 * time.Minute
+
+// projectUsageRetention is how long the usage periods of each resolution are kept.
+var projectUsageRetention = map[time.Duration]time.Duration{
+	time.Hour:      31 * 24 * time.Hour,
+	24 * time.Hour: 400 * 24 * time.Hour,
+}
+
+var projectUsageCmd = APIEndpoint{
+	Path: "projects/{name}/usage",
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
+// projectUsageCounters holds the last values of the cumulative counters of an instance.
+type projectUsageCounters struct {
+	cpu       int64
+	received  int64
+	sent      int64
+	startedAt time.Time
+}
+
+// projectUsageTask samples the resource usage of the instances running on this member
+// and adds it to the hourly and daily usage periods of their projects.
+func projectUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	last := map[int]projectUsageCounters{}
+	var lastSample time.Time
+
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		now := time.Now()
+		elapsed := now.Sub(lastSample)
+
+		// Don't account for the time the daemon wasn't running.
+		if lastSample.IsZero() || elapsed > 2*projectUsageInterval {
+			elapsed = 0
+		}
+
+		usage, counters, err := projectUsageSample(s, last, lastSample, elapsed)
+		if err != nil {
+			logger.Warn("Failed sampling project usage", logger.Ctx{"err": err})
+			return
+		}
+
+		last = counters
+		lastSample = now
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			for projectName, entry := range usage {
+				err := tx.AddProjectUsage(ctx, projectName, now, *entry)
+				if err != nil {
+					return err
+				}
+			}
+
+			for resolution, retention := range projectUsageRetention {
+				err := tx.DeleteProjectUsageBefore(ctx, resolution, now.Add(-retention))
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed recording project usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(projectUsageInterval)
+}
+
+// projectUsageSample returns the resource usage of the local instances per project since the last sample,
+// along with the new values of the cumulative counters.
+func projectUsageSample(s *state.State, last map[int]projectUsageCounters, lastSample time.Time, elapsed time.Duration) (map[string]*api.ProjectUsageEntry, map[int]projectUsageCounters, error) {
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hostInterfaces, _ := net.Interfaces()
+	hours := elapsed.Hours()
+
+	usage := map[string]*api.ProjectUsageEntry{}
+	counters := map[int]projectUsageCounters{}
+
+	for _, inst := range instances {
+		instState, err := inst.RenderState(hostInterfaces)
+		if err != nil {
+			logger.Debug("Failed getting instance state for project usage", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			continue
+		}
+
+		entry, ok := usage[inst.Project().Name]
+		if !ok {
+			entry = &api.ProjectUsageEntry{}
+			usage[inst.Project().Name] = entry
+		}
+
+		for _, disk := range instState.Disk {
+			entry.DiskByteHours += float64(disk.Usage) * hours
+		}
+
+		if !inst.IsRunning() {
+			continue
+		}
+
+		current := projectUsageCounters{
+			cpu:       instState.CPU.Usage,
+			startedAt: instState.StartedAt,
+		}
+
+		for name, network := range instState.Network {
+			if name == "lo" {
+				continue
+			}
+
+			current.received += network.Counters.BytesReceived
+			current.sent += network.Counters.BytesSent
+		}
+
+		counters[inst.ID()] = current
+		entry.InstanceHours += hours
+		entry.MemoryByteHours += float64(instState.Memory.Usage) * hours
+
+		// Counters are relative to the previous sample, or to the start of instances (re)started since.
+		previous, ok := last[inst.ID()]
+		if !ok || !previous.startedAt.Equal(current.startedAt) {
+			if lastSample.IsZero() || current.startedAt.Before(lastSample) {
+				continue
+			}
+
+			previous = projectUsageCounters{}
+		}
+
+		entry.CPUSeconds += float64(max(current.cpu-previous.cpu, 0)) / float64(time.Second)
+		entry.NetworkBytesReceived += max(current.received-previous.received, 0)
+		entry.NetworkBytesSent += max(current.sent-previous.sent, 0)
+	}
+
+	return usage, counters, nil
+}
+
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project usage
+//
+//	Gets the historical resource usage of the project, aggregated hourly or daily.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: path
+//	    name: name
+//	    description: Project name
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: since
+//	    description: Start of the time range (RFC3339 or date, defaults to 24 hours ago)
+//	    type: string
+//	    example: 2026-10-01
+//	  - in: query
+//	    name: until
+//	    description: End of the time range (RFC3339 or date, defaults to now)
+//	    type: string
+//	    example: 2026-11-01
+//	  - in: query
+//	    name: resolution
+//	    description: Aggregation period (hourly or daily, defaults to hourly)
+//	    type: string
+//	    example: daily
+//	responses:
+//	  "200":
+//	    description: Project usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := pathVar(r, "name")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resolutionName := request.QueryParam(r, "resolution")
+	if resolutionName == "" {
+		resolutionName = "hourly"
+	}
+
+	resolution, ok := db.ProjectUsageResolutions[resolutionName]
+	if !ok {
+		return response.BadRequest(fmt.Errorf("Invalid resolution %q", resolutionName))
+	}
+
+	until := time.Now()
+	if request.QueryParam(r, "until") != "" {
+		until, err = projectUsageParseTime(request.QueryParam(r, "until"))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	since := until.Add(-24 * time.Hour)
+	if request.QueryParam(r, "since") != "" {
+		since, err = projectUsageParseTime(request.QueryParam(r, "since"))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	if !since.Before(until) {
+		return response.BadRequest(errors.New("The start of the time range must be before its end"))
+	}
+
+	usage := api.ProjectUsage{Resolution: resolutionName}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the project exists.
+		_, err := cluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		usage.Entries, err = tx.GetProjectUsage(ctx, name, resolution, since, until)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	usage.Total.Start = since.UTC().Truncate(resolution)
+	usage.Total.End = until.UTC()
+	for _, entry := range usage.Entries {
+		usage.Total.CPUSeconds += entry.CPUSeconds
+		usage.Total.MemoryByteHours += entry.MemoryByteHours
+		usage.Total.DiskByteHours += entry.DiskByteHours
+		usage.Total.NetworkBytesReceived += entry.NetworkBytesReceived
+		usage.Total.NetworkBytesSent += entry.NetworkBytesSent
+		usage.Total.InstanceHours += entry.InstanceHours
+	}
+
+	return response.SyncResponse(true, &usage)
+}
+
+// projectUsageParseTime parses a RFC3339 timestamp or a date.
+func projectUsageParseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid time %q, expected a RFC3339 timestamp or a date", value)
+	}
+
+	return t, nil
+}
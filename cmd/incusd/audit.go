@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/audit"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// auditRequest tracks a mutating API request until its result is recorded in the audit log.
+type auditRequest struct {
+	record api.EventAudit
+	body   *audit.BodyDigester
+}
+
+// auditBegin starts tracking the request for the audit log.
+// It returns nil if the request doesn't need to be recorded.
+func (d *Daemon) auditBegin(r *http.Request, username string, protocol string) *auditRequest {
+	if !d.auditLog.Enabled() {
+		return nil
+	}
+
+	// Only record requests which may modify state.
+	if !slices.Contains([]string{"PUT", "POST", "DELETE", "PATCH"}, r.Method) {
+		return nil
+	}
+
+	requestor := &api.EventLifecycleRequestor{
+		Username: username,
+		Protocol: protocol,
+		Address:  r.RemoteAddr,
+	}
+
+	if protocol == "cluster" {
+		// Requests between cluster members without an original requestor are internal and aren't recorded.
+		// Forwarded requests are recorded with their original requestor, both by the member which received
+		// them from the client and by the member handling them.
+		if r.Header.Get(request.HeaderForwardedUsername) == "" {
+			return nil
+		}
+
+		requestor.Username = r.Header.Get(request.HeaderForwardedUsername)
+		requestor.Protocol = r.Header.Get(request.HeaderForwardedProtocol)
+		requestor.Address = r.Header.Get(request.HeaderForwardedAddress)
+	}
+
+	// Strip port from address.
+	host, _, err := net.SplitHostPort(requestor.Address)
+	if err == nil {
+		requestor.Address = host
+	}
+
+	a := &auditRequest{
+		record: api.EventAudit{
+			Timestamp: time.Now(),
+			Requestor: requestor,
+			Project:   request.ProjectParam(r),
+			Method:    r.Method,
+			URL:       r.URL.RequestURI(),
+		},
+	}
+
+	if r.Body != nil {
+		a.body = audit.NewBodyDigester(r.Body)
+		r.Body = a.body
+	}
+
+	return a
+}
+
+// finishResponse records the response to the request in the audit log once it got rendered.
+// Requests running in the background are recorded again once their operation is done, with its final status.
+func (a *auditRequest) finishResponse(d *Daemon, resp response.Response, renderErr error) {
+	if a == nil {
+		return
+	}
+
+	a.record.Operation = operations.ResponseOperationID(resp)
+	if renderErr != nil {
+		a.finish(d, response.SmartError(renderErr).Code(), renderErr.Error())
+	} else {
+		a.finish(d, resp.Code(), resp.String())
+	}
+
+	// Operations are started when rendering the response, there's nothing to wait for if that failed.
+	op := operations.ResponseOperation(resp)
+	if op == nil || op.Status() == api.Pending {
+		return
+	}
+
+	go func() {
+		err := op.Wait(d.shutdownCtx)
+		if err != nil && d.shutdownCtx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			a.finish(d, response.SmartError(err).Code(), err.Error())
+		} else {
+			a.finish(d, http.StatusOK, "")
+		}
+	}()
+}
+
+// finish records the result of the request in the audit log and forwards it to the event listeners.
+func (a *auditRequest) finish(d *Daemon, statusCode int, message string) {
+	if a == nil {
+		return
+	}
+
+	if a.body != nil {
+		a.record.RequestSize, a.record.RequestDigest, a.record.RequestPartial = a.body.Digest()
+		a.body = nil
+	}
+
+	a.record.StatusCode = statusCode
+	a.record.Error = ""
+	if statusCode >= http.StatusBadRequest {
+		a.record.Error = message
+	}
+
+	record, err := d.auditLog.Append(a.record)
+	if err != nil {
+		logger.Warn("Failed recording API request in audit log", logger.Ctx{"method": a.record.Method, "url": a.record.URL, "err": err})
+		return
+	}
+
+	_ = d.events.Send(record.Project, api.EventTypeAudit, record)
+}
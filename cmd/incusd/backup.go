@@ -35,6 +35,51 @@ import (
 	"github.com/lxc/incus/v7/shared/util"
 )
 
+// backupFileWriter wraps the file a backup is written to, tracking its size and enforcing
+// the backups disk limit of the project.
+type backupFileWriter struct {
+	io.WriteCloser
+
+	projectName string
+	size        int64
+	budget      int64
+}
+
+// newBackupFileWriter opens the file a backup of the given project is written to.
+func newBackupFileWriter(s *state.State, projectName string, target string) (*backupFileWriter, error) {
+	var budget int64
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		budget, err = project.GetBackupSpaceBudget(tx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backupFileWriter{WriteCloser: f, projectName: projectName, budget: budget}, nil
+}
+
+// Write writes to the backup file unless this would exceed the backups disk limit of the project.
+func (w *backupFileWriter) Write(p []byte) (int, error) {
+	if w.budget >= 0 && w.size+int64(len(p)) > w.budget {
+		return 0, fmt.Errorf("Backup exceeds the backups disk limit of project %q", w.projectName)
+	}
+
+	n, err := w.WriteCloser.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
 // Create a new backup.
 func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, op *operations.Operation, writer *io.PipeWriter) error {
 	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": args.Name})
@@ -115,6 +160,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Setup the tarball writer.
 	var tarFileWriter io.WriteCloser
+	var backupFile *backupFileWriter
 
 	if writer == nil {
 		// Create the target path if needed.
@@ -131,11 +177,12 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		target := internalUtil.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, b.Name()))
 
 		l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-		tarFileWriter, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+		backupFile, err = newBackupFileWriter(s, sourceInst.Project().Name, target)
 		if err != nil {
 			return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
 		}
 
+		tarFileWriter = backupFile
 		reverter.Add(func() { _ = os.Remove(target) })
 	} else {
 		tarFileWriter = writer
@@ -234,6 +281,16 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Record the size of the tarball for the project limits.
+	if backupFile != nil {
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateInstanceBackupSize(ctx, b.Name(), backupFile.size)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed recording backup size: %w", err)
+		}
+	}
+
 	reverter.Success()
 	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
 
@@ -480,6 +537,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 
 	// Setup the writer.
 	var fileWriter io.WriteCloser
+	var backupFile *backupFileWriter
 
 	if writer == nil {
 		// Create the target path if needed.
@@ -496,11 +554,12 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		target := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
 
 		l.Debug("Opening backup file for writing", logger.Ctx{"path": target})
-		fileWriter, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+		backupFile, err = newBackupFileWriter(s, projectName, target)
 		if err != nil {
 			return fmt.Errorf("Error opening backup file for writing %q: %w", target, err)
 		}
 
+		fileWriter = backupFile
 		reverter.Add(func() { _ = os.Remove(target) })
 	} else {
 		fileWriter = writer
@@ -584,6 +643,16 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error closing backup file: %w", err)
 	}
 
+	// Record the size of the backup file for the project limits.
+	if backupFile != nil {
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolVolumeBackupSize(ctx, backupRow.Name, backupFile.size)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed recording backup size: %w", err)
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -747,6 +816,7 @@ func bucketBackupCreate(s *state.State, args db.StoragePoolBucketBackup, project
 
 	// Setup the tarball writer.
 	var tarFileWriter io.WriteCloser
+	var backupFile *backupFileWriter
 
 	if writer == nil {
 		// Create the target path if needed.
@@ -763,11 +833,12 @@ func bucketBackupCreate(s *state.State, args db.StoragePoolBucketBackup, project
 		target := internalUtil.VarPath("backups", "buckets", pool.Name(), project.StorageBucket(projectName, backupRow.Name))
 
 		l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-		tarFileWriter, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+		backupFile, err = newBackupFileWriter(s, projectName, target)
 		if err != nil {
 			return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
 		}
 
+		tarFileWriter = backupFile
 		reverter.Add(func() { _ = os.Remove(target) })
 	} else {
 		tarFileWriter = writer
@@ -842,6 +913,16 @@ func bucketBackupCreate(s *state.State, args db.StoragePoolBucketBackup, project
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Record the size of the tarball for the project limits.
+	if backupFile != nil {
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolBucketBackupSize(ctx, backupRow.Name, backupFile.size)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed recording backup size: %w", err)
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
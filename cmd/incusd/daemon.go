@@ -29,6 +29,7 @@ import (
 	"github.com/lxc/incus/v7/internal/linux"
 	"github.com/lxc/incus/v7/internal/rsync"
 	"github.com/lxc/incus/v7/internal/server/apparmor"
+	"github.com/lxc/incus/v7/internal/server/audit"
 	"github.com/lxc/incus/v7/internal/server/auth"
 	"github.com/lxc/incus/v7/internal/server/auth/oidc"
 	"github.com/lxc/incus/v7/internal/server/bgp"
@@ -154,6 +155,9 @@ type Daemon struct {
 
 	loggingController *logging.Controller
 
+	// Audit log.
+	auditLog *audit.Log
+
 	// Authorization.
 	authorizer auth.Authorizer
 
@@ -204,6 +208,7 @@ func newDaemon(config *DaemonConfig, osInfo *sys.OS) *Daemon {
 		shutdownCancel: shutdownCancel,
 		shutdownDoneCh: make(chan error),
 		apiExtensions:  len(version.APIExtensions),
+		auditLog:       audit.NewLog(internalUtil.LogPath("audit.log")),
 	}
 
 	d.serverCert = func() *localtls.CertInfo { return d.serverCertInt }
@@ -492,9 +497,15 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
 // client that has been authenticated (cluster, unix, or tls).
 func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+	trusted, username, protocol, _, err := d.authenticate(w, r)
+	return trusted, username, protocol, err
+}
+
+// authenticate is Authenticate also returning the API token used for the request, if any.
+func (d *Daemon) authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, *api.AuthToken, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
-		return false, "", "", err
+		return false, "", "", nil, err
 	}
 
 	// Allow internal cluster traffic by checking against the trusted certfificates.
@@ -502,7 +513,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, fingerprint := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeServer], d.endpoints.NetworkCert(), false)
 			if trusted {
-				return true, fingerprint, "cluster", nil
+				return true, fingerprint, "cluster", nil, nil
 			}
 		}
 	}
@@ -512,38 +523,38 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		if w != nil {
 			cred, err := ucred.GetCredFromContext(r.Context())
 			if err != nil {
-				return false, "", "", err
+				return false, "", "", nil, err
 			}
 
 			u, err := user.LookupId(fmt.Sprintf("%d", cred.Uid))
 			if err != nil {
-				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil
+				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil, nil
 			}
 
-			return true, u.Username, "unix", nil
+			return true, u.Username, "unix", nil, nil
 		}
 
-		return true, "", "unix", nil
+		return true, "", "unix", nil, nil
 	}
 
 	// DevIncus unix socket credentials on main API.
 	if r.RemoteAddr == "@dev_incus" {
-		return false, "", "", errors.New("Main API query can't come from /dev/incus socket")
+		return false, "", "", nil, errors.New("Main API query can't come from /dev/incus socket")
 	}
 
 	// Cluster notification with wrong certificate.
 	if isClusterNotification(r) {
-		return false, "", "", errors.New("Cluster notification isn't using trusted server certificate")
+		return false, "", "", nil, errors.New("Cluster notification isn't using trusted server certificate")
 	}
 
 	// Cluster internal client with wrong certificate.
 	if isClusterInternal(r) {
-		return false, "", "", errors.New("Cluster internal client isn't using trusted server certificate")
+		return false, "", "", nil, errors.New("Cluster internal client isn't using trusted server certificate")
 	}
 
 	// Bad query, no TLS found.
 	if r.TLS == nil {
-		return false, "", "", errors.New("Bad/missing TLS on network query")
+		return false, "", "", nil, errors.New("Bad/missing TLS on network query")
 	}
 
 	// Load the certificates.
@@ -554,18 +565,29 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
+		}
+	}
+
+	// Check for an API token.
+	secret := authTokenSecret(r)
+	if secret != "" {
+		token, err := d.authTokenLookup(r.Context(), secret)
+		if err != nil {
+			return false, "", "", nil, err
 		}
+
+		return true, token.Name, api.AuthenticationMethodToken, token, nil
 	}
 
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
 		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
 		if err != nil {
-			return false, "", "", err
+			return false, "", "", nil, err
 		}
 
-		return true, userName, api.AuthenticationMethodOIDC, nil
+		return true, userName, api.AuthenticationMethodOIDC, nil, nil
 	}
 
 	// Validate metrics TLS certificates.
@@ -573,7 +595,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
 			if trusted {
-				return true, username, api.AuthenticationMethodTLS, nil
+				return true, username, api.AuthenticationMethodTLS, nil, nil
 			}
 		}
 	}
@@ -582,12 +604,12 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Reject unauthorized.
-	return false, "", "", nil
+	return false, "", "", nil, nil
 }
 
 // State creates a new State instance linked to our internal db and os.
@@ -609,7 +631,7 @@ func (d *Daemon) State() *state.State {
 	d.globalConfigMu.Unlock()
 
 	return &state.State{
-		Authorizer:             d.authorizer,
+		Authorizer:             auth.WithTokenRestrictions(d.authorizer),
 		BGP:                    d.bgp,
 		Cluster:                d.gateway,
 		DB:                     d.db,
@@ -677,7 +699,11 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 		}
 
 		// Authentication
-		trusted, username, protocol, err := d.Authenticate(w, r)
+		trusted, username, protocol, token, err := d.authenticate(w, r)
+
+		// Track mutating requests for the audit log.
+		auditReq := d.auditBegin(r, username, protocol)
+
 		if err != nil {
 			var authError *oidc.AuthError
 			if errors.As(err, &authError) {
@@ -686,6 +712,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 					_ = d.oidcVerifier.WriteHeaders(w)
 				}
 
+				auditReq.finish(d, http.StatusUnauthorized, err.Error())
 				_ = response.Unauthorized(err).Render(w)
 				return
 			}
@@ -715,6 +742,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			// Except for the initial cluster accept request (done over trusted TLS)
 			if !internalAllowed {
 				logger.Warn("Rejecting remote internal API request", logger.Ctx{"ip": r.RemoteAddr})
+				auditReq.finish(d, http.StatusForbidden, "Remote internal API request")
 				_ = response.Forbidden(nil).Render(w)
 				return
 			}
@@ -734,6 +762,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			// Add authentication/authorization context data.
 			ctx := context.WithValue(r.Context(), request.CtxUsername, username)
 			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+			ctx = auth.WithPermissionCache(ctx)
 
 			// Add forwarded requestor data.
 			if protocol == "cluster" {
@@ -743,6 +772,21 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 				ctx = context.WithValue(ctx, request.CtxForwardedProtocol, r.Header.Get(request.HeaderForwardedProtocol))
 			}
 
+			// Add the scope of the API token used for the request.
+			if protocol == "cluster" {
+				token, err = d.authTokenForwarded(r)
+				if err != nil {
+					logger.Warn("Rejecting request with invalid API token", logger.Ctx{"ip": r.RemoteAddr, "err": err})
+					auditReq.finish(d, http.StatusForbidden, "Invalid API token")
+					_ = response.Forbidden(nil).Render(w)
+					return
+				}
+			}
+
+			if token != nil {
+				ctx = context.WithValue(ctx, request.CtxAuthToken, token)
+			}
+
 			r = r.WithContext(ctx)
 		} else if untrustedOk && r.Header.Get("X-Incus-authenticated") == "" {
 			logger.Debug(fmt.Sprintf("Allowing untrusted %s", r.Method), logger.Ctx{"url": r.URL.RequestURI(), "ip": r.RemoteAddr})
@@ -752,6 +796,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			}
 
 			logger.Warn("Rejecting request from untrusted client", logger.Ctx{"ip": r.RemoteAddr})
+			auditReq.finish(d, http.StatusForbidden, "Untrusted client")
 			_ = response.Forbidden(nil).Render(w)
 			return
 		}
@@ -763,6 +808,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 			multiW := io.MultiWriter(newBody, captured)
 			_, err := util.SafeCopy(multiW, r.Body)
 			if err != nil {
+				auditReq.finish(d, http.StatusInternalServerError, err.Error())
 				_ = response.InternalError(err).Render(w)
 				return
 			}
@@ -798,6 +844,7 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 		}
 
 		if errors.Is(d.shutdownCtx.Err(), context.Canceled) && !allowedDuringShutdown() {
+			auditReq.finish(d, http.StatusServiceUnavailable, "Incus is shutting down")
 			_ = response.Unavailable(errors.New("Incus is shutting down")).Render(w)
 			return
 		}
@@ -869,6 +916,8 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, apiVersion string, c APIEndpo
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
 		}
+
+		auditReq.finishResponse(d, resp, err)
 	}
 
 	restAPI.HandleFunc(uri, handler)
@@ -1372,7 +1421,11 @@ func (d *Daemon) init() error {
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
+	clusterRebalanceScriptlet := d.globalConfig.ClusterRebalanceScriptlet()
+	clusterFencingScriptlet := d.globalConfig.ClusterHealingFencingScriptlet()
 	authorizationScriptlet := d.globalConfig.AuthorizationScriptlet()
+	authorizationBuiltin := d.globalConfig.AuthorizationBuiltin()
+	auditEnabled, auditMaxSize, auditMaxFiles := d.globalConfig.Audit()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
 	ws.SetTrustedOrigins(d.globalConfig.HTTPSAllowedWebsocketOrigin())
@@ -1384,6 +1437,12 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Setup the audit log.
+	err = d.auditLog.Configure(auditEnabled, auditMaxSize, auditMaxFiles)
+	if err != nil {
+		return fmt.Errorf("Failed to configure audit log: %w", err)
+	}
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1416,6 +1475,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the built-in authorization driver.
+	if authorizationBuiltin {
+		err = d.setupAuthorizationBuiltin(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Setup BGP listener.
 	d.bgp = bgp.NewServer()
 	if bgpAddress != "" && bgpASN != 0 && bgpRouterID != "" {
@@ -1507,6 +1574,22 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Load cluster re-balancing scriptlet.
+	if clusterRebalanceScriptlet != "" {
+		err = scriptletLoad.ClusterRebalanceSet(clusterRebalanceScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading cluster re-balancing scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
+	// Load cluster fencing scriptlet.
+	if clusterFencingScriptlet != "" {
+		err = scriptletLoad.ClusterFencingSet(clusterFencingScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading cluster fencing scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialized.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -1631,6 +1714,9 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Record project resource usage (every 5 minutes)
+		d.tasks.Add(projectUsageTask(d))
 	}
 
 	// Start all background tasks
@@ -1856,6 +1942,8 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		trackError(d.endpoints.Down(), "Shutdown endpoints")
 	}
 
+	trackError(d.auditLog.Close(), "Close audit log")
+
 	if shouldUnmount {
 		logger.Info("Unmounting temporary filesystems")
 
@@ -2211,6 +2299,38 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	return nil
 }
 
+// setupAuthorizationBuiltin enables or disables the built-in authorization driver.
+func (d *Daemon) setupAuthorizationBuiltin(enabled bool) error {
+	var err error
+
+	if !enabled {
+		// Reset to default authorizer.
+		_, ok := d.authorizer.(*auth.Builtin)
+		if ok {
+			d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Fail if not using the default tls or built-in authorizer.
+	switch d.authorizer.(type) {
+	case *auth.TLS, *auth.Builtin:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverBuiltin, logger.Log, d.clientCerts, auth.WithPermissionStore(&authPermissionStore{db: d.db.Cluster}))
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("Attempting to setup built-in authorization while another authorizer is already set")
+	}
+
+	return nil
+}
+
 // Syslog listener.
 func (d *Daemon) setupSyslogSocket(enable bool) error {
 	// Always cancel the context to ensure that no goroutines leak.
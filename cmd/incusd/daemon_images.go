@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -69,12 +70,20 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 	var info *api.Image
 
 	// Check if the project allows retrieving the image.
+	verifyRequired := s.GlobalConfig.ImagesVerifyRequired()
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		err := project.AllowImageDownload(tx, args.ProjectName, args.Server)
 		if err != nil {
 			return err
 		}
 
+		if !verifyRequired {
+			verifyRequired, err = project.ImageVerifyRequired(ctx, tx, args.ProjectName)
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -87,6 +96,19 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		protocol = "incus"
 	}
 
+	// Only simplestreams and OCI images can be signed.
+	var verifyKeys []crypto.PublicKey
+	if verifyRequired && args.Server != "" {
+		if !slices.Contains([]string{"oci", "simplestreams"}, protocol) {
+			return nil, false, fmt.Errorf("Images must be signed, which isn't possible with the %q protocol", protocol)
+		}
+
+		verifyKeys = s.GlobalConfig.ImagesVerifyKeys()
+		if len(verifyKeys) == 0 {
+			return nil, false, errors.New("Images must be signed but no trusted keys are configured in \"images.verify.keys\"")
+		}
+	}
+
 	// Copy so that local modifications aren't propagated to args.
 	alias := args.Alias
 
@@ -104,6 +126,7 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			SkipGetEvents: true,
 			SkipGetServer: true,
 			TempPath:      internalUtil.VarPath("images"),
+			VerifyKeys:    verifyKeys,
 		}
 
 		if slices.Contains([]string{"incus", "lxd"}, protocol) {
@@ -119,6 +142,8 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			}
 		} else if protocol == "oci" {
 			// Setup OCI client
+			clientArgs.Mirrors = s.GlobalConfig.ImagesRegistryMirrors(args.Server)
+
 			remote, err = incus.ConnectOCI(args.Server, clientArgs)
 			if err != nil {
 				return nil, false, fmt.Errorf("Failed to connect to oci server %q: %w", args.Server, err)
@@ -216,7 +241,7 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 
 		if nodeAddress != "" {
 			// The image is available from another node, let's try to import it.
-			err = instanceImageTransfer(s, r, args.ProjectName, imgInfo.Fingerprint, nodeAddress)
+			err = instanceImageTransfer(ctx, s, r, op, args.ProjectName, imgInfo.Fingerprint, nodeAddress)
 			if err != nil {
 				return nil, false, fmt.Errorf("Failed transferring image %q from %q: %w", imgInfo.Fingerprint, nodeAddress, err)
 			}
@@ -277,7 +302,7 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			// Transfer image if needed (after database record has been created above).
 			if nodeAddress != "" {
 				// The image is available from another node, let's try to import it.
-				err = instanceImageTransfer(s, r, args.ProjectName, info.Fingerprint, nodeAddress)
+				err = instanceImageTransfer(ctx, s, r, op, args.ProjectName, info.Fingerprint, nodeAddress)
 				if err != nil {
 					return nil, false, fmt.Errorf("Failed transferring image: %w", err)
 				}
@@ -459,6 +484,22 @@ func imageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			return nil, false, err
 		}
 
+		// Report the amount of data transferred.
+		downloadSize := resp.MetaSize + resp.RootfsSize
+		if resp.DeltaSource != "" {
+			downloadSize = resp.MetaSize + resp.DeltaSize
+			logger.Info("Image downloaded using a delta", logger.Ctx{"fingerprint": fp, "source": resp.DeltaSource, "deltaSize": resp.DeltaSize, "rootfsSize": resp.RootfsSize})
+		}
+
+		if op != nil {
+			metadata := map[string]any{"download_size": downloadSize}
+			if resp.DeltaSource != "" {
+				metadata["download_delta_source"] = resp.DeltaSource
+			}
+
+			_ = op.ExtendMetadata(metadata)
+		}
+
 		// Truncate down to size
 		if resp.RootfsSize > 0 {
 			err = destRootfs.Truncate(resp.RootfsSize)
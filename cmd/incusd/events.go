@@ -21,8 +21,8 @@ import (
 )
 
 var (
-	eventTypes           = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL}
-	privilegedEventTypes = []string{api.EventTypeLogging}
+	eventTypes           = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL, api.EventTypeAudit}
+	privilegedEventTypes = []string{api.EventTypeLogging, api.EventTypeAudit}
 )
 
 var eventsCmd = APIEndpoint{
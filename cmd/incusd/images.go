@@ -1897,7 +1897,7 @@ func autoUpdateImagesTask(d *Daemon) (task.Func, task.Schedule) {
 		s := d.State()
 
 		opRun := func(op *operations.Operation) error {
-			return autoUpdateImages(ctx, s)
+			return autoUpdateImages(ctx, s, op)
 		}
 
 		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ImagesUpdate, nil, nil, opRun, nil, nil, nil)
@@ -1930,7 +1930,7 @@ func autoUpdateImagesTask(d *Daemon) (task.Func, task.Schedule) {
 	return f, task.Hourly()
 }
 
-func autoUpdateImages(ctx context.Context, s *state.State) error {
+func autoUpdateImages(ctx context.Context, s *state.State, op *operations.Operation) error {
 	imageMap := make(map[string][]dbCluster.Image)
 
 	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -2031,7 +2031,7 @@ func autoUpdateImages(ctx context.Context, s *state.State) error {
 				continue
 			}
 
-			newInfo, err := autoUpdateImage(ctx, s, nil, image.ID, imageInfo, image.Project, false)
+			newInfo, err := autoUpdateImage(ctx, s, op, image.ID, imageInfo, image.Project, false)
 			if err != nil {
 				logger.Error("Failed to update image", logger.Ctx{"err": err, "project": image.Project, "fingerprint": image.Fingerprint})
 
@@ -2556,6 +2556,11 @@ func pruneLeftoverImages(s *state.State) {
 
 		// Check and delete leftovers
 		for _, entry := range entries {
+			// Image root disks still backing lazily loaded instance volumes.
+			if entry.Name() == "lazy" {
+				continue
+			}
+
 			fp, _, _ := strings.Cut(entry.Name(), ".")
 			if !slices.Contains(images, fp) {
 				err = os.RemoveAll(internalUtil.VarPath("images", entry.Name()))
@@ -4377,6 +4382,7 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 //	Make the server push the image to a remote server
 //
 //	Gets the server to connect to a remote server and push the image to it.
+//	With the `oci` protocol, container images are pushed to an OCI registry under the names listed as aliases.
 //
 //	---
 //	produces:
@@ -4431,6 +4437,14 @@ func imageExportPost(d *Daemon, r *http.Request) response.Response {
 		response.SmartError(err)
 	}
 
+	switch req.Protocol {
+	case "", "incus":
+	case "oci":
+		return imageExportOCI(d, r, projectName, fingerprint, req)
+	default:
+		return response.BadRequest(fmt.Errorf("Unsupported protocol %q", req.Protocol))
+	}
+
 	// Connect to the target and push the image
 	args := &incus.ConnectionArgs{
 		TLSServerCert: req.Certificate,
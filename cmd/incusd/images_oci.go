@@ -0,0 +1,329 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/operationtype"
+	"github.com/lxc/incus/v7/internal/server/lifecycle"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/response"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/archive"
+	"github.com/lxc/incus/v7/shared/ioprogress"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// imageExportOCI pushes a container image to an OCI registry.
+func imageExportOCI(d *Daemon, r *http.Request, projectName string, fingerprint string, req api.ImageExportPost) response.Response {
+	s := d.State()
+
+	var imgInfo *api.Image
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		_, imgInfo, err = tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if imgInfo.Type != string(api.InstanceTypeContainer) {
+		return response.BadRequest(errors.New("Only container images can be pushed to OCI registries"))
+	}
+
+	if len(req.Aliases) == 0 {
+		return response.BadRequest(errors.New("At least one image name is required to push to an OCI registry"))
+	}
+
+	names := make([]string, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		names = append(names, alias.Name)
+	}
+
+	// Don't leak the registry credentials in events.
+	target, err := url.Parse(req.Target)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid registry URL: %w", err))
+	}
+
+	target.User = nil
+
+	// Connect to the registry.
+	remote, err := incus.ConnectOCI(req.Target, &incus.ConnectionArgs{
+		TLSServerCert: req.Certificate,
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+		TempPath:      internalUtil.VarPath("images"),
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	registry, ok := remote.(incus.OCIServer)
+	if !ok {
+		return response.InternalError(errors.New("Registry doesn't support pushing images"))
+	}
+
+	run := func(op *operations.Operation) error {
+		imageMetaPath := internalUtil.VarPath("images", fingerprint)
+		imageRootfsPath := internalUtil.VarPath("images", fingerprint+".rootfs")
+
+		pushArgs := &incus.OCIImagePushArgs{
+			Architecture: imgInfo.Architecture,
+			ProgressHandler: func(progress ioprogress.ProgressData) {
+				_ = op.ExtendMetadata(map[string]any{"push_progress": progress.Text})
+			},
+		}
+
+		// Get the process configuration, from the image and then the instance configuration.
+		spec, err := imageOCISpec(imageMetaPath)
+		if err != nil {
+			return err
+		}
+
+		err = imageOCIProcess(pushArgs, spec, req.Config)
+		if err != nil {
+			return err
+		}
+
+		// Stream the root filesystem to the registry.
+		pipeReader, pipeWriter := io.Pipe()
+		defer logger.WarnOnError(pipeReader.Close, "Failed to close pipe reader")
+
+		go func() {
+			if util.PathExists(imageRootfsPath) {
+				_ = pipeWriter.CloseWithError(imageOCIRootfs(imageRootfsPath, "", pipeWriter))
+			} else {
+				_ = pipeWriter.CloseWithError(imageOCIRootfs(imageMetaPath, "rootfs", pipeWriter))
+			}
+		}()
+
+		pushArgs.Rootfs = pipeReader
+
+		err = registry.PushImage(names, pushArgs)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.ImageRetrieved.Event(fingerprint, projectName, op.Requestor(), logger.Ctx{"target": target.String(), "names": names}))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.ImageDownload, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// imageOCITarReader returns a tar reader for an image file, whatever its compression.
+func imageOCITarReader(imagePath string) (*tar.Reader, func(), error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, extension, unpacker, err := archive.DetectCompressionFile(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+
+	// sqfs2tar can only read from a file.
+	if extension == ".squashfs" {
+		unpacker = append(unpacker, imagePath)
+	}
+
+	tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), f, unpacker, "")
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+
+	return tr, func() {
+		cancelFunc()
+		_ = f.Close()
+	}, nil
+}
+
+// imageOCISpec returns the OCI runtime configuration stored in an image, if any.
+func imageOCISpec(imagePath string) (*ociSpecs.Spec, error) {
+	tr, cleanup, err := imageOCITarReader(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanup()
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean("/" + hdr.Name)
+
+		// The metadata files come before the root filesystem.
+		if name == "/rootfs" || strings.HasPrefix(name, "/rootfs/") {
+			return nil, nil
+		}
+
+		if name != "/config.json" {
+			continue
+		}
+
+		spec := ociSpecs.Spec{}
+		err = json.NewDecoder(tr).Decode(&spec)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing the image OCI configuration: %w", err)
+		}
+
+		return &spec, nil
+	}
+}
+
+// imageOCIProcess fills in the process configuration of an OCI image.
+// The instance configuration keys take precedence over the image OCI configuration.
+func imageOCIProcess(args *incus.OCIImagePushArgs, spec *ociSpecs.Spec, config map[string]string) error {
+	uid := "0"
+	gid := "0"
+	env := map[string]string{}
+
+	if spec != nil && spec.Process != nil {
+		args.Entrypoint = spec.Process.Args
+		args.WorkingDir = spec.Process.Cwd
+		uid = fmt.Sprintf("%d", spec.Process.User.UID)
+		gid = fmt.Sprintf("%d", spec.Process.User.GID)
+
+		for _, entry := range spec.Process.Env {
+			key, value, _ := strings.Cut(entry, "=")
+			env[key] = value
+		}
+	}
+
+	for key, value := range config {
+		switch {
+		case key == "oci.entrypoint" && value != "":
+			entrypoint, err := shellquote.Split(value)
+			if err != nil {
+				return fmt.Errorf("Invalid %q: %w", key, err)
+			}
+
+			args.Entrypoint = entrypoint
+		case key == "oci.cwd" && value != "":
+			args.WorkingDir = value
+		case key == "oci.uid" && value != "":
+			uid = value
+		case key == "oci.gid" && value != "":
+			gid = value
+		case strings.HasPrefix(key, "environment."):
+			env[strings.TrimPrefix(key, "environment.")] = value
+		}
+	}
+
+	args.User = uid + ":" + gid
+
+	args.Env = make([]string, 0, len(env))
+	for key, value := range env {
+		args.Env = append(args.Env, key+"="+value)
+	}
+
+	slices.Sort(args.Env)
+
+	return nil
+}
+
+// imageOCIRootfs writes the root filesystem of an image as an uncompressed tarball.
+// When prefix is set, only the entries below it are included, relative to it.
+func imageOCIRootfs(imagePath string, prefix string, w io.Writer) error {
+	tr, cleanup, err := imageOCITarReader(imagePath)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	relative := func(name string) (string, bool) {
+		name = path.Clean("/" + name)
+		if prefix != "" {
+			if name != "/"+prefix && !strings.HasPrefix(name, "/"+prefix+"/") {
+				return "", false
+			}
+
+			name = strings.TrimPrefix(name, "/"+prefix)
+		}
+
+		name = strings.TrimPrefix(name, "/")
+
+		return name, name != ""
+	}
+
+	tw := tar.NewWriter(w)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name, ok := relative(hdr.Name)
+		if !ok {
+			continue
+		}
+
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname, ok = relative(hdr.Linkname)
+			if !ok {
+				return fmt.Errorf("Invalid hard link target for %q", name)
+			}
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	incus "github.com/lxc/incus/v7/client"
+)
+
+func TestImageOCIProcess(t *testing.T) {
+	spec := &ociSpecs.Spec{
+		Process: &ociSpecs.Process{
+			Args: []string{"/bin/app"},
+			Cwd:  "/srv",
+			User: ociSpecs.User{UID: 1000, GID: 100},
+			Env:  []string{"PATH=/bin", "HOME=/home/app"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		spec       *ociSpecs.Spec
+		config     map[string]string
+		entrypoint []string
+		cwd        string
+		user       string
+		env        []string
+		err        bool
+	}{
+		{
+			name: "system container",
+			user: "0:0",
+			env:  []string{},
+		},
+		{
+			name:       "image configuration",
+			spec:       spec,
+			entrypoint: []string{"/bin/app"},
+			cwd:        "/srv",
+			user:       "1000:100",
+			env:        []string{"HOME=/home/app", "PATH=/bin"},
+		},
+		{
+			name:       "instance configuration",
+			spec:       spec,
+			config:     map[string]string{"oci.entrypoint": "/bin/app --name 'my app'", "oci.cwd": "/", "oci.uid": "0", "environment.HOME": "/root", "environment.LANG": "C.UTF-8", "user.foo": "bar"},
+			entrypoint: []string{"/bin/app", "--name", "my app"},
+			cwd:        "/",
+			user:       "0:100",
+			env:        []string{"HOME=/root", "LANG=C.UTF-8", "PATH=/bin"},
+		},
+		{
+			name:       "empty keys",
+			spec:       spec,
+			config:     map[string]string{"oci.entrypoint": "", "oci.gid": ""},
+			entrypoint: []string{"/bin/app"},
+			cwd:        "/srv",
+			user:       "1000:100",
+			env:        []string{"HOME=/home/app", "PATH=/bin"},
+		},
+		{
+			name:   "invalid entrypoint",
+			config: map[string]string{"oci.entrypoint": "/bin/app 'unterminated"},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		args := &incus.OCIImagePushArgs{}
+
+		err := imageOCIProcess(args, test.spec, test.config)
+		if test.err {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.entrypoint, args.Entrypoint, test.name)
+		assert.Equal(t, test.cwd, args.WorkingDir, test.name)
+		assert.Equal(t, test.user, args.User, test.name)
+		assert.Equal(t, test.env, args.Env, test.name)
+	}
+}
@@ -0,0 +1,878 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/internal/server/cluster"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/operations"
+	"github.com/lxc/incus/v7/internal/server/response"
+	"github.com/lxc/incus/v7/internal/server/state"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/units"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// Chunked image transfers between cluster members.
+//
+// Each image file is split into fixed size chunks, identified by their SHA256 hash. A member
+// needing an image fetches a manifest from all other online members and then downloads chunks in
+// parallel from any member holding them, including members which are themselves still
+// downloading the same image. Every chunk is checked against the manifest of a member holding
+// the complete image before being written, and the assembled image against its fingerprint.
+const (
+	imageChunkSize         = 8 * 1024 * 1024
+	imageChunkWorkers      = 8
+	imageChunkSourceLimit  = 4
+	imageChunkSourceErrors = 3
+)
+
+var errImageTransferUnavailable = errors.New("No cluster member holds the complete image")
+
+// imageChunkFile describes the chunks of one of the image files ("meta" or "rootfs").
+type imageChunkFile struct {
+	Name   string   `json:"name"`
+	Size   int64    `json:"size"`
+	Hashes []string `json:"hashes"`
+
+	// Chunks held by a member which is still transferring the image, nil if complete.
+	Available []bool `json:"available,omitempty"`
+}
+
+// imageChunkManifest describes the image chunks held by a cluster member.
+type imageChunkManifest struct {
+	ChunkSize int64            `json:"chunk_size"`
+	Complete  bool             `json:"complete"`
+	Files     []imageChunkFile `json:"files"`
+}
+
+// has returns whether the manifest's member holds the given chunk.
+func (m *imageChunkManifest) has(file int, index int) bool {
+	if m.Complete {
+		return true
+	}
+
+	return file < len(m.Files) && index < len(m.Files[file].Available) && m.Files[file].Available[index]
+}
+
+// matches returns whether the manifest describes the same chunks as the reference one.
+func (m *imageChunkManifest) matches(ref *imageChunkManifest) bool {
+	if m.ChunkSize != ref.ChunkSize || len(m.Files) != len(ref.Files) {
+		return false
+	}
+
+	for i, file := range m.Files {
+		refFile := ref.Files[i]
+		if file.Name != refFile.Name || file.Size != refFile.Size || len(file.Hashes) != len(refFile.Hashes) {
+			return false
+		}
+
+		for j := range file.Hashes {
+			if file.Hashes[j] != refFile.Hashes[j] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// imageTransfer is an in-progress chunked transfer whose chunks can be served to other members.
+type imageTransfer struct {
+	mu       sync.Mutex
+	manifest *imageChunkManifest
+	files    map[string]*os.File
+}
+
+var (
+	imageTransfers   = map[string]*imageTransfer{}
+	imageTransfersMu sync.Mutex
+)
+
+// imageChunkManifestCacheEntry is the manifest of a complete image file, computed only once.
+type imageChunkManifestCacheEntry struct {
+	once    sync.Once
+	size    int64
+	modTime time.Time
+	file    *imageChunkFile
+	err     error
+}
+
+var (
+	imageChunkManifestCache   = map[string]*imageChunkManifestCacheEntry{}
+	imageChunkManifestCacheMu sync.Mutex
+)
+
+// imageChunkFilePaths returns the image file names and their paths in the images directory.
+func imageChunkFilePaths(s *state.State, fingerprint string) ([]string, []string) {
+	imagePath := filepath.Join(s.OS.VarDir, "images", fingerprint)
+
+	names := []string{"meta"}
+	paths := []string{imagePath}
+
+	if util.PathExists(imagePath + ".rootfs") {
+		names = append(names, "rootfs")
+		paths = append(paths, imagePath+".rootfs")
+	}
+
+	return names, paths
+}
+
+// imageChunkHashFile returns the chunk hashes of a complete image file, using cached values when possible.
+func imageChunkHashFile(name string, path string) (*imageChunkFile, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	imageChunkManifestCacheMu.Lock()
+	entry, ok := imageChunkManifestCache[path]
+	if !ok || entry.size != fi.Size() || !entry.modTime.Equal(fi.ModTime()) {
+		entry = &imageChunkManifestCacheEntry{size: fi.Size(), modTime: fi.ModTime()}
+		imageChunkManifestCache[path] = entry
+	}
+
+	imageChunkManifestCacheMu.Unlock()
+
+	entry.once.Do(func() {
+		f, err := os.Open(path)
+		if err != nil {
+			entry.err = err
+			return
+		}
+
+		defer func() { _ = f.Close() }()
+
+		file := &imageChunkFile{Name: name, Size: fi.Size()}
+		for offset := int64(0); offset < fi.Size(); offset += imageChunkSize {
+			hash := sha256.New()
+
+			_, err = io.Copy(hash, io.NewSectionReader(f, offset, imageChunkSize))
+			if err != nil {
+				entry.err = err
+				return
+			}
+
+			file.Hashes = append(file.Hashes, hex.EncodeToString(hash.Sum(nil)))
+		}
+
+		entry.file = file
+	})
+
+	if entry.err != nil {
+		imageChunkManifestCacheMu.Lock()
+		delete(imageChunkManifestCache, path)
+		imageChunkManifestCacheMu.Unlock()
+	}
+
+	return entry.file, entry.err
+}
+
+// imageHeldLocally returns whether the local member has a complete copy of the image.
+func imageHeldLocally(ctx context.Context, s *state.State, fingerprint string) (bool, error) {
+	if !util.PathExists(filepath.Join(s.OS.VarDir, "images", fingerprint)) {
+		return false, nil
+	}
+
+	// Images downloaded from remote servers are written in place, only trust files once the
+	// database records them as available on this member.
+	var held bool
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		localAddress, err := tx.GetLocalNodeAddress(ctx)
+		if err != nil {
+			return err
+		}
+
+		addresses, err := tx.GetNodesWithImage(ctx, fingerprint)
+		if err != nil {
+			return err
+		}
+
+		for _, address := range addresses {
+			if address == localAddress {
+				held = true
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return held, nil
+}
+
+// imageChunkFingerprint returns the validated fingerprint path variable.
+func imageChunkFingerprint(r *http.Request) (string, error) {
+	fingerprint, err := pathVar(r, "fingerprint")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = hex.DecodeString(fingerprint)
+	if err != nil || len(fingerprint) != 64 {
+		return "", fmt.Errorf("Invalid image fingerprint %q", fingerprint)
+	}
+
+	return fingerprint, nil
+}
+
+func internalImageChunksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	fingerprint, err := imageChunkFingerprint(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Serve the chunks of an in-progress transfer.
+	imageTransfersMu.Lock()
+	transfer := imageTransfers[fingerprint]
+	imageTransfersMu.Unlock()
+
+	if transfer != nil {
+		// Copy the manifest as it's only rendered once the lock is released.
+		transfer.mu.Lock()
+		manifest := imageChunkManifest{ChunkSize: transfer.manifest.ChunkSize}
+		for _, file := range transfer.manifest.Files {
+			file.Available = slices.Clone(file.Available)
+			manifest.Files = append(manifest.Files, file)
+		}
+
+		transfer.mu.Unlock()
+
+		return response.SyncResponse(true, manifest)
+	}
+
+	held, err := imageHeldLocally(r.Context(), s, fingerprint)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !held {
+		return response.NotFound(fmt.Errorf("Image %q not available on this member", fingerprint))
+	}
+
+	manifest := imageChunkManifest{ChunkSize: imageChunkSize, Complete: true}
+
+	names, paths := imageChunkFilePaths(s, fingerprint)
+	for i := range names {
+		file, err := imageChunkHashFile(names[i], paths[i])
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		manifest.Files = append(manifest.Files, *file)
+	}
+
+	return response.SyncResponse(true, manifest)
+}
+
+func internalImageChunkGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	fingerprint, err := imageChunkFingerprint(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	name, err := pathVar(r, "file")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 {
+		return response.BadRequest(errors.New("Invalid chunk index"))
+	}
+
+	filename := fmt.Sprintf("%s.%s.%d", fingerprint, name, index)
+	offset := int64(index) * imageChunkSize
+
+	// Serve the chunk from an in-progress transfer.
+	imageTransfersMu.Lock()
+	transfer := imageTransfers[fingerprint]
+	imageTransfersMu.Unlock()
+
+	if transfer != nil {
+		transfer.mu.Lock()
+		defer transfer.mu.Unlock()
+
+		for i, file := range transfer.manifest.Files {
+			if file.Name != name {
+				continue
+			}
+
+			if !transfer.manifest.has(i, index) {
+				break
+			}
+
+			buf := make([]byte, min(imageChunkSize, file.Size-offset))
+
+			_, err = transfer.files[name].ReadAt(buf, offset)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			return response.FileResponse(r, []response.FileResponseEntry{{Identifier: name, Filename: filename, File: bytes.NewReader(buf), FileSize: int64(len(buf))}}, nil)
+		}
+
+		return response.NotFound(fmt.Errorf("Chunk %d of image file %q not available on this member", index, name))
+	}
+
+	held, err := imageHeldLocally(r.Context(), s, fingerprint)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !held {
+		return response.NotFound(fmt.Errorf("Image %q not available on this member", fingerprint))
+	}
+
+	names, paths := imageChunkFilePaths(s, fingerprint)
+	for i := range names {
+		if names[i] != name {
+			continue
+		}
+
+		f, err := os.Open(paths[i])
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		fi, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return response.SmartError(err)
+		}
+
+		if offset >= fi.Size() {
+			_ = f.Close()
+			break
+		}
+
+		size := min(imageChunkSize, fi.Size()-offset)
+
+		return response.FileResponse(r, []response.FileResponseEntry{{
+			Identifier: name,
+			Filename:   filename,
+			File:       io.NewSectionReader(f, offset, size),
+			FileSize:   size,
+			Cleanup:    func() { _ = f.Close() },
+		}}, nil)
+	}
+
+	return response.NotFound(fmt.Errorf("Chunk %d of image file %q not available on this member", index, name))
+}
+
+// imageChunkSource is a cluster member chunks can be fetched from.
+type imageChunkSource struct {
+	name     string
+	url      string
+	client   incus.InstanceServer
+	manifest *imageChunkManifest
+	inflight int
+	errors   int
+	received int64
+}
+
+// imageChunkRef identifies a chunk of one of the image files.
+type imageChunkRef struct {
+	file  int
+	index int
+}
+
+// imageChunkFetchManifest retrieves the chunk manifest of an image from another member.
+func imageChunkFetchManifest(client incus.InstanceServer, fingerprint string) (*imageChunkManifest, error) {
+	resp, _, err := client.RawQuery("GET", "/internal/image-chunks/"+fingerprint, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &imageChunkManifest{}
+	err = json.Unmarshal(resp.Metadata, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chunk offsets are computed with the local chunk size.
+	if manifest.ChunkSize != imageChunkSize {
+		return nil, fmt.Errorf("Unsupported chunk size %d in chunk manifest", manifest.ChunkSize)
+	}
+
+	for _, file := range manifest.Files {
+		if file.Name != "meta" && file.Name != "rootfs" {
+			return nil, fmt.Errorf("Invalid image file %q in chunk manifest", file.Name)
+		}
+
+		if file.Size < 0 || int64(len(file.Hashes)) != (file.Size+imageChunkSize-1)/imageChunkSize {
+			return nil, errors.New("Invalid chunk manifest")
+		}
+
+		if !manifest.Complete && len(file.Available) != len(file.Hashes) {
+			return nil, errors.New("Invalid chunk manifest")
+		}
+	}
+
+	return manifest, nil
+}
+
+// imageChunkFetch downloads a single chunk, checks it against the reference manifest and writes it out.
+func imageChunkFetch(ctx context.Context, source *imageChunkSource, fingerprint string, file imageChunkFile, index int, target *os.File) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/internal/image-chunks/%s/%s/%d", source.url, fingerprint, file.Name, index), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := source.client.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	offset := int64(index) * imageChunkSize
+	size := min(imageChunkSize, file.Size-offset)
+
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, size+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(buf)) != size {
+		return fmt.Errorf("Chunk size mismatch (got %d, expected %d)", len(buf), size)
+	}
+
+	hash := sha256.Sum256(buf)
+	if hex.EncodeToString(hash[:]) != file.Hashes[index] {
+		return errors.New("Chunk hash mismatch")
+	}
+
+	_, err = target.WriteAt(buf, offset)
+
+	return err
+}
+
+// imageTransferChunked transfers an image from all cluster members holding it, in verified chunks.
+func imageTransferChunked(ctx context.Context, s *state.State, op *operations.Operation, fingerprint string) error {
+	// List the other online members.
+	var members []db.NodeInfo
+	var localAddress string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		offlineThreshold, err := tx.GetNodeOfflineThreshold(ctx)
+		if err != nil {
+			return err
+		}
+
+		localAddress, err = tx.GetLocalNodeAddress(ctx)
+		if err != nil {
+			return err
+		}
+
+		nodes, err := tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, node := range nodes {
+			if node.Address != localAddress && !node.IsOffline(offlineThreshold) {
+				members = append(members, node)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the chunk manifests.
+	var sources []*imageChunkSource
+	var sourcesMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, member := range members {
+		wg.Add(1)
+		go func(member db.NodeInfo) {
+			defer wg.Done()
+
+			client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, false)
+			if err != nil {
+				return
+			}
+
+			manifest, err := imageChunkFetchManifest(client, fingerprint)
+			if err != nil {
+				if !api.StatusErrorCheck(err, http.StatusNotFound) {
+					logger.Debug("Failed retrieving image chunk manifest", logger.Ctx{"fingerprint": fingerprint, "member": member.Name, "err": err})
+				}
+
+				return
+			}
+
+			info, err := client.GetConnectionInfo()
+			if err != nil {
+				return
+			}
+
+			sourcesMu.Lock()
+			sources = append(sources, &imageChunkSource{name: member.Name, url: info.URL, client: client, manifest: manifest})
+			sourcesMu.Unlock()
+		}(member)
+	}
+
+	wg.Wait()
+
+	// Pick a reference manifest from a member holding the complete image and drop disagreeing members.
+	var ref *imageChunkManifest
+	for _, source := range sources {
+		if source.manifest.Complete {
+			ref = source.manifest
+			break
+		}
+	}
+
+	if ref == nil {
+		return errImageTransferUnavailable
+	}
+
+	for i := 0; i < len(sources); i++ {
+		if !sources[i].manifest.matches(ref) {
+			logger.Warn("Ignoring cluster member with mismatching image chunks", logger.Ctx{"fingerprint": fingerprint, "member": sources[i].name})
+			sources = append(sources[:i], sources[i+1:]...)
+			i--
+		}
+	}
+
+	// Prepare the target files.
+	imagesDir := filepath.Join(s.OS.VarDir, "images")
+
+	buildDir, err := os.MkdirTemp(imagesDir, "incus_build_")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory for download: %w", err)
+	}
+
+	defer logger.WarnOnError(func() error { return os.RemoveAll(buildDir) }, "Failed to remove build directory")
+
+	transfer := &imageTransfer{
+		manifest: &imageChunkManifest{ChunkSize: ref.ChunkSize},
+		files:    map[string]*os.File{},
+	}
+
+	var chunks []imageChunkRef
+	var totalSize int64
+
+	for i, refFile := range ref.Files {
+		f, err := os.Create(filepath.Join(buildDir, refFile.Name))
+		if err != nil {
+			return err
+		}
+
+		defer logger.WarnOnError(f.Close, "Failed to close image file")
+
+		err = f.Truncate(refFile.Size)
+		if err != nil {
+			return err
+		}
+
+		transfer.files[refFile.Name] = f
+		transfer.manifest.Files = append(transfer.manifest.Files, imageChunkFile{
+			Name:      refFile.Name,
+			Size:      refFile.Size,
+			Hashes:    refFile.Hashes,
+			Available: make([]bool, len(refFile.Hashes)),
+		})
+
+		for j := range refFile.Hashes {
+			chunks = append(chunks, imageChunkRef{file: i, index: j})
+		}
+
+		totalSize += refFile.Size
+	}
+
+	// Let other members fetch the chunks we already have.
+	imageTransfersMu.Lock()
+	if imageTransfers[fingerprint] != nil {
+		imageTransfersMu.Unlock()
+		return fmt.Errorf("Image %q is already being transferred", fingerprint)
+	}
+
+	imageTransfers[fingerprint] = transfer
+	imageTransfersMu.Unlock()
+
+	defer func() {
+		imageTransfersMu.Lock()
+		delete(imageTransfers, fingerprint)
+		imageTransfersMu.Unlock()
+	}()
+
+	// Spread the members downloading the same image over different chunks.
+	rand.Shuffle(len(chunks), func(i, j int) { chunks[i], chunks[j] = chunks[j], chunks[i] })
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var received int64
+	var transferErr error
+	pending := chunks
+	inflight := 0
+
+	// next picks a pending chunk and the least busy member holding it.
+	next := func() (imageChunkRef, *imageChunkSource, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for i, chunk := range pending {
+			var best *imageChunkSource
+			for _, source := range sources {
+				if source.errors >= imageChunkSourceErrors || source.inflight >= imageChunkSourceLimit || !source.manifest.has(chunk.file, chunk.index) {
+					continue
+				}
+
+				// Prefer members which are still transferring, leaving capacity on the complete ones.
+				if best == nil || source.inflight < best.inflight || (source.inflight == best.inflight && best.manifest.Complete && !source.manifest.Complete) {
+					best = source
+				}
+			}
+
+			if best != nil {
+				pending = append(pending[:i:i], pending[i+1:]...)
+				best.inflight++
+				inflight++
+				return chunk, best, true
+			}
+		}
+
+		return imageChunkRef{}, nil, false
+	}
+
+	// done records the result of a chunk download.
+	done := func(chunk imageChunkRef, source *imageChunkSource, size int64, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		source.inflight--
+		inflight--
+
+		if err == nil {
+			source.received += size
+			received += size
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Debug("Failed fetching image chunk", logger.Ctx{"fingerprint": fingerprint, "member": source.name, "chunk": chunk.index, "err": err})
+		pending = append(pending, chunk)
+		source.errors++
+
+		if source.errors < imageChunkSourceErrors {
+			return
+		}
+
+		logger.Warn("Stopped fetching image chunks from cluster member", logger.Ctx{"fingerprint": fingerprint, "member": source.name, "err": err})
+		for _, source := range sources {
+			if source.errors < imageChunkSourceErrors && source.manifest.Complete {
+				return
+			}
+		}
+
+		transferErr = errImageTransferUnavailable
+		cancel()
+	}
+
+	// Periodically refresh the manifests of members which are still transferring and report progress.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		start := time.Now()
+		for tick := 1; ; tick++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if tick%5 == 0 {
+				mu.Lock()
+				partial := []*imageChunkSource{}
+				for _, source := range sources {
+					if !source.manifest.Complete && source.errors < imageChunkSourceErrors {
+						partial = append(partial, source)
+					}
+				}
+
+				mu.Unlock()
+
+				for _, source := range partial {
+					manifest, err := imageChunkFetchManifest(source.client, fingerprint)
+					if err != nil || !manifest.matches(ref) {
+						continue
+					}
+
+					mu.Lock()
+					source.manifest = manifest
+					mu.Unlock()
+				}
+			}
+
+			if op == nil {
+				continue
+			}
+
+			mu.Lock()
+			active := 0
+			perSource := map[string]int64{}
+			for _, source := range sources {
+				if source.received > 0 {
+					active++
+					perSource[source.name] = source.received
+				}
+			}
+
+			progress := received
+			mu.Unlock()
+
+			percent := int64(100)
+			if totalSize > 0 {
+				percent = progress * 100 / totalSize
+			}
+
+			speed := int64(float64(progress) / time.Since(start).Seconds())
+
+			_ = op.ExtendMetadata(map[string]any{
+				"download_progress": fmt.Sprintf("%d%% (%s/s from %d members)", percent, units.GetByteSizeString(speed, 2), active),
+				"download_sources":  perSource,
+			})
+		}
+	}()
+
+	// Fetch the chunks.
+	var workers sync.WaitGroup
+	for range imageChunkWorkers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+
+			for ctx.Err() == nil {
+				chunk, source, ok := next()
+				if !ok {
+					mu.Lock()
+					finished := len(pending) == 0 && inflight == 0
+					mu.Unlock()
+
+					if finished {
+						return
+					}
+
+					select {
+					case <-ctx.Done():
+					case <-time.After(100 * time.Millisecond):
+					}
+
+					continue
+				}
+
+				file := ref.Files[chunk.file]
+				err := imageChunkFetch(ctx, source, fingerprint, file, chunk.index, transfer.files[file.Name])
+				if err == nil {
+					transfer.mu.Lock()
+					transfer.manifest.Files[chunk.file].Available[chunk.index] = true
+					transfer.mu.Unlock()
+				}
+
+				done(chunk, source, min(imageChunkSize, file.Size-int64(chunk.index)*imageChunkSize), err)
+			}
+		}()
+	}
+
+	workers.Wait()
+
+	err = transferErr
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	cancel()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	// Check the assembled image against its fingerprint.
+	hash := sha256.New()
+	for _, file := range ref.Files {
+		_, err = io.Copy(hash, io.NewSectionReader(transfer.files[file.Name], 0, file.Size))
+		if err != nil {
+			return err
+		}
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != fingerprint {
+		return fmt.Errorf("Transferred image doesn't match fingerprint %q", fingerprint)
+	}
+
+	// Move the files into place.
+	for _, file := range ref.Files {
+		targetPath := filepath.Join(imagesDir, fingerprint)
+		if file.Name == "rootfs" {
+			targetPath += ".rootfs"
+		}
+
+		err = internalUtil.FileMove(transfer.files[file.Name].Name(), targetPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if op != nil {
+		mu.Lock()
+		perSource := map[string]int64{}
+		for _, source := range sources {
+			if source.received > 0 {
+				perSource[source.name] = source.received
+			}
+		}
+
+		mu.Unlock()
+
+		_ = op.ExtendMetadata(map[string]any{"download_progress": "100%", "download_sources": perSource})
+	}
+
+	logger.Info("Transferred image from cluster members", logger.Ctx{"fingerprint": fingerprint, "members": len(sources), "size": totalSize})
+
+	return nil
+}
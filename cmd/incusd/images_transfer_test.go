@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	incus "github.com/lxc/incus/v7/client"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// imageChunkTestTransport serves the requests of a client from a handler, without any network.
+type imageChunkTestTransport http.HandlerFunc
+
+func (t imageChunkTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t(rec, req)
+
+	return rec.Result(), nil
+}
+
+// imageChunkTestClient returns a client whose requests are served by the handler.
+func imageChunkTestClient(t *testing.T, handler http.HandlerFunc) incus.InstanceServer {
+	client, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, &http.Client{Transport: imageChunkTestTransport(handler)})
+	require.NoError(t, err)
+
+	return client
+}
+
+func imageChunkTestHash(data []byte) string {
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func TestImageChunkManifestHas(t *testing.T) {
+	complete := &imageChunkManifest{Complete: true}
+	assert.True(t, complete.has(0, 0))
+	assert.True(t, complete.has(1, 5))
+
+	partial := &imageChunkManifest{Files: []imageChunkFile{{Name: "meta", Available: []bool{true, false}}}}
+	assert.True(t, partial.has(0, 0))
+	assert.False(t, partial.has(0, 1))
+	assert.False(t, partial.has(0, 2))
+	assert.False(t, partial.has(1, 0))
+}
+
+func TestImageChunkManifestMatches(t *testing.T) {
+	newManifest := func() *imageChunkManifest {
+		return &imageChunkManifest{
+			ChunkSize: imageChunkSize,
+			Complete:  true,
+			Files: []imageChunkFile{
+				{Name: "meta", Size: 10, Hashes: []string{"a"}},
+				{Name: "rootfs", Size: imageChunkSize + 1, Hashes: []string{"b", "c"}},
+			},
+		}
+	}
+
+	ref := newManifest()
+
+	tests := []struct {
+		name    string
+		change  func(m *imageChunkManifest)
+		matches bool
+	}{
+		{name: "identical", change: func(m *imageChunkManifest) {}, matches: true},
+		{name: "incomplete", change: func(m *imageChunkManifest) { m.Complete = false; m.Files[0].Available = []bool{false} }, matches: true},
+		{name: "chunk size", change: func(m *imageChunkManifest) { m.ChunkSize = 1024 }},
+		{name: "missing file", change: func(m *imageChunkManifest) { m.Files = m.Files[:1] }},
+		{name: "file name", change: func(m *imageChunkManifest) { m.Files[1].Name = "meta" }},
+		{name: "file size", change: func(m *imageChunkManifest) { m.Files[0].Size = 11 }},
+		{name: "hash count", change: func(m *imageChunkManifest) { m.Files[1].Hashes = m.Files[1].Hashes[:1] }},
+		{name: "hash", change: func(m *imageChunkManifest) { m.Files[1].Hashes[1] = "d" }},
+	}
+
+	for _, test := range tests {
+		m := newManifest()
+		test.change(m)
+		assert.Equal(t, test.matches, m.matches(ref), test.name)
+	}
+}
+
+func TestImageChunkFetchManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest imageChunkManifest
+		valid    bool
+	}{
+		{
+			name:     "complete",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Complete: true, Files: []imageChunkFile{{Name: "meta", Size: imageChunkSize + 1, Hashes: []string{"a", "b"}}}},
+			valid:    true,
+		},
+		{
+			name:     "in progress",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Files: []imageChunkFile{{Name: "rootfs", Size: 1, Hashes: []string{"a"}, Available: []bool{false}}}},
+			valid:    true,
+		},
+		{
+			name:     "other chunk size",
+			manifest: imageChunkManifest{ChunkSize: 1024, Complete: true, Files: []imageChunkFile{{Name: "meta", Size: 1024, Hashes: []string{"a"}}}},
+		},
+		{
+			name:     "unknown file",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Complete: true, Files: []imageChunkFile{{Name: "../meta", Size: 1, Hashes: []string{"a"}}}},
+		},
+		{
+			name:     "missing hashes",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Complete: true, Files: []imageChunkFile{{Name: "meta", Size: imageChunkSize + 1, Hashes: []string{"a"}}}},
+		},
+		{
+			name:     "negative size",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Complete: true, Files: []imageChunkFile{{Name: "meta", Size: -1, Hashes: []string{}}}},
+		},
+		{
+			name:     "missing availability",
+			manifest: imageChunkManifest{ChunkSize: imageChunkSize, Files: []imageChunkFile{{Name: "meta", Size: 1, Hashes: []string{"a"}}}},
+		},
+	}
+
+	for _, test := range tests {
+		client := imageChunkTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/internal/image-chunks/abcd", r.URL.Path)
+
+			_ = json.NewEncoder(w).Encode(api.ResponseRaw{Type: api.SyncResponse, Status: "Success", StatusCode: http.StatusOK, Metadata: test.manifest})
+		})
+
+		manifest, err := imageChunkFetchManifest(client, "abcd")
+		if test.valid {
+			require.NoError(t, err, test.name)
+			assert.Equal(t, test.manifest, *manifest, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestImageChunkFetch(t *testing.T) {
+	chunk := []byte("last chunk")
+	file := imageChunkFile{Name: "rootfs", Size: imageChunkSize + int64(len(chunk)), Hashes: []string{"first", imageChunkTestHash(chunk)}}
+
+	target, err := os.Create(filepath.Join(t.TempDir(), "rootfs"))
+	require.NoError(t, err)
+	defer func() { _ = target.Close() }()
+
+	fetch := func(data []byte, status int) error {
+		client := imageChunkTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/internal/image-chunks/abcd/rootfs/1", r.URL.Path)
+
+			w.WriteHeader(status)
+			_, _ = w.Write(data)
+		})
+
+		source := &imageChunkSource{name: "member", url: "https://custom.socket", client: client}
+
+		return imageChunkFetch(context.Background(), source, "abcd", file, 1, target)
+	}
+
+	// Chunks with a bad hash or size aren't written.
+	err = fetch([]byte("last chunK"), http.StatusOK)
+	assert.ErrorContains(t, err, "Chunk hash mismatch")
+
+	err = fetch([]byte("last chunk and more"), http.StatusOK)
+	assert.ErrorContains(t, err, "Chunk size mismatch")
+
+	err = fetch(nil, http.StatusNotFound)
+	assert.Error(t, err)
+
+	info, err := target.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), info.Size())
+
+	// Valid chunks are written at their offset.
+	err = fetch(chunk, http.StatusOK)
+	require.NoError(t, err)
+
+	buf := make([]byte, len(chunk))
+	_, err = target.ReadAt(buf, imageChunkSize)
+	require.NoError(t, err)
+	assert.Equal(t, chunk, buf)
+}
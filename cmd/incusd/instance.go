@@ -72,8 +72,20 @@ func instanceCreateAsEmpty(s *state.State, args db.InstanceArgs, op *operations.
 	return inst, nil
 }
 
-// instanceImageTransfer transfers an image from another cluster node.
-func instanceImageTransfer(s *state.State, r *http.Request, projectName string, hash string, nodeAddress string) error {
+// instanceImageTransfer transfers an image from other cluster nodes.
+// The image is fetched in chunks from all members holding it, falling back to a direct download from nodeAddress.
+func instanceImageTransfer(ctx context.Context, s *state.State, r *http.Request, op *operations.Operation, projectName string, hash string, nodeAddress string) error {
+	err := imageTransferChunked(ctx, s, op, hash)
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	logger.Warn("Failed chunked image transfer, falling back to direct transfer", logger.Ctx{"fingerprint": hash, "member": nodeAddress, "err": err})
+
 	logger.Debugf("Transferring image %q from node %q", hash, nodeAddress)
 	client, err := cluster.Connect(nodeAddress, s.Endpoints.NetworkCert(), s.ServerCert(), r, false)
 	if err != nil {
@@ -90,7 +102,7 @@ func instanceImageTransfer(s *state.State, r *http.Request, projectName string,
 	return nil
 }
 
-func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, r *http.Request, img *api.Image, projectName string) error {
+func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, r *http.Request, op *operations.Operation, img *api.Image, projectName string) error {
 	// Check if the image is available locally or it's on another member.
 	// Ensure we are the only ones operating on this image. Otherwise another instance created at the same
 	// time may also arrive at the conclusion that the image doesn't exist on this cluster member and then
@@ -116,7 +128,7 @@ func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, r *http.
 
 	if memberAddress != "" {
 		// The image is available from another node, let's try to import it.
-		err = instanceImageTransfer(s, r, projectName, img.Fingerprint, memberAddress)
+		err = instanceImageTransfer(ctx, s, r, op, projectName, img.Fingerprint, memberAddress)
 		if err != nil {
 			return fmt.Errorf("Failed transferring image %q from %q: %w", img.Fingerprint, memberAddress, err)
 		}
@@ -290,7 +302,7 @@ func instanceRebuildFromImage(ctx context.Context, s *state.State, r *http.Reque
 		return fmt.Errorf("Requested image's type %q doesn't match instance type %q", imgType, inst.Type())
 	}
 
-	err = ensureImageIsLocallyAvailable(ctx, s, r, img, inst.Project().Name)
+	err = ensureImageIsLocallyAvailable(ctx, s, r, op, img, inst.Project().Name)
 	if err != nil {
 		return err
 	}
@@ -424,6 +436,11 @@ func instanceCreateAsCopy(s *state.State, opts instanceCreateAsCopyOpts, op *ope
 			}
 		}
 
+		err = snapshotsCheckLimit(context.TODO(), s, inst.Project().Name, len(snapshots))
+		if err != nil {
+			return nil, err
+		}
+
 		var snapInstOps []*operationlock.InstanceOperation
 		defer func() {
 			for _, snapInstOp := range snapInstOps {
@@ -572,6 +589,16 @@ func autoCreateInstanceSnapshots(ctx context.Context, s *state.State, instances
 
 		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			p := inst.Project()
+
+			return project.CheckSnapshotsLimit(tx, &p, 1)
+		})
+		if err != nil {
+			l.Warn("Skipping scheduled snapshot", logger.Ctx{"err": err})
+			continue
+		}
+
 		snapshotName, err := instance.NextSnapshotName(s, inst, "snap%d")
 		if err != nil {
 			l.Error("Error retrieving next snapshot name", logger.Ctx{"err": err})
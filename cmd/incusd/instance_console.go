@@ -71,6 +71,9 @@ type consoleWs struct {
 
 	// channel type (either console or vga)
 	protocol string
+
+	// serial device to attach to instead of the main console
+	device string
 }
 
 func (s *consoleWs) metadata() any {
@@ -83,7 +86,12 @@ func (s *consoleWs) metadata() any {
 		}
 	}
 
-	return jmap.Map{"fds": fds}
+	metadata := jmap.Map{"fds": fds}
+	if s.device != "" {
+		metadata["device"] = s.device
+	}
+
+	return metadata
 }
 
 func (s *consoleWs) connect(op *operations.Operation, r *http.Request, w http.ResponseWriter) error {
@@ -229,7 +237,16 @@ func (s *consoleWs) doConsole() error {
 	<-s.allConnected
 
 	// Get console from instance.
-	console, consoleDisconnectCh, err := s.instance.Console(s.protocol)
+	var console *os.File
+	var consoleDisconnectCh chan error
+	var err error
+
+	if s.device != "" {
+		console, consoleDisconnectCh, err = s.instance.SerialConsole(s.device)
+	} else {
+		console, consoleDisconnectCh, err = s.instance.Console(s.protocol)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -353,7 +370,10 @@ func (s *consoleWs) doConsole() error {
 	// Write a reset escape sequence to the console to cancel any ongoing reads to the handle
 	// and then close it. This ordering is important, close the console before closing the
 	// websocket to ensure console doesn't get stuck reading.
-	_, _ = console.Write([]byte("\x1bc"))
+	// Serial devices are left alone as the escape sequence would reach the connected appliance.
+	if s.device == "" {
+		_, _ = console.Write([]byte("\x1bc"))
+	}
 
 	err = console.Close()
 	if err != nil && !errors.Is(err, os.ErrClosed) {
@@ -533,6 +553,16 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("VGA console is only supported by virtual machines"))
 	}
 
+	if post.Device != "" {
+		if post.Type != instance.ConsoleTypeConsole {
+			return response.BadRequest(errors.New("Serial devices can only be attached to with the console type"))
+		}
+
+		if inst.ExpandedDevices()[post.Device]["type"] != "serial" {
+			return response.BadRequest(fmt.Errorf("Serial device %q not found", post.Device))
+		}
+	}
+
 	if !inst.IsRunning() {
 		return response.BadRequest(errors.New("Instance is not running"))
 	}
@@ -565,6 +595,12 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 			continue
 		}
 
+		// Each serial device has its own session.
+		opDevice, _ := op.Metadata()["device"].(string)
+		if opDevice != post.Device {
+			continue
+		}
+
 		if !post.Force {
 			return response.SmartError(errors.New("This console is already connected. Force is required to take it over."))
 		}
@@ -595,6 +631,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	consoleWS.width = post.Width
 	consoleWS.height = post.Height
 	consoleWS.protocol = post.Type
+	consoleWS.device = post.Device
 
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", consoleWS.instance.Name())}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/warningtype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+// instancePlacementFilter loads the placement rules of an instance and restricts the candidate cluster
+// members to those respecting them. All candidates are kept if none respects them.
+func instancePlacementFilter(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, config map[string]string, candidates []db.NodeInfo) (*instance.PlacementRules, []db.NodeInfo, error) {
+	rules, err := instance.LoadPlacementRules(ctx, tx, projectName, instanceName, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rules.Empty() {
+		return rules, candidates, nil
+	}
+
+	filtered := rules.Filter(candidates)
+	if len(filtered) == 0 {
+		return rules, candidates, nil
+	}
+
+	return rules, filtered, nil
+}
+
+// instancePlacementWarn records a warning if the instance violates its placement rules on the given
+// cluster member and resolves any such previous warning otherwise.
+func instancePlacementWarn(s *state.State, rules *instance.PlacementRules, projectName string, instanceID int, memberName string) {
+	if rules == nil || rules.Empty() {
+		return
+	}
+
+	entityTypeCode := dbCluster.TypeInstance
+	violation := rules.Check(memberName)
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if violation != nil {
+			return tx.UpsertWarning(ctx, memberName, projectName, entityTypeCode, instanceID, warningtype.InstancePlacementRulesViolated, violation.Error())
+		}
+
+		// Resolve warnings for the instance, wherever it was previously running.
+		typeCode := warningtype.InstancePlacementRulesViolated
+		warnings, err := dbCluster.GetWarnings(ctx, tx.Tx(), dbCluster.WarningFilter{
+			TypeCode:       &typeCode,
+			Project:        &projectName,
+			EntityTypeCode: &entityTypeCode,
+			EntityID:       &instanceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, w := range warnings {
+			err = tx.UpdateWarningStatus(w.UUID, warningtype.StatusResolved)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed to record instance placement rules warning", logger.Ctx{"project": projectName, "member": memberName, "err": err})
+	}
+
+	if violation != nil {
+		logger.Warn("Instance placed against its placement rules", logger.Ctx{"project": projectName, "member": memberName, "err": violation})
+	}
+}
+
+// instancePlacementWarnPlaced checks the placement rules of an instance once it has been created or
+// moved, at which point its ID and cluster member are known.
+func instancePlacementWarnPlaced(s *state.State, projectName string, instanceName string) {
+	if !s.ServerClustered {
+		return
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, instanceName)
+	if err != nil {
+		logger.Warn("Failed to load instance for placement rules check", logger.Ctx{"project": projectName, "instance": instanceName, "err": err})
+		return
+	}
+
+	var rules *instance.PlacementRules
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		rules, err = instance.LoadPlacementRules(ctx, tx, projectName, instanceName, inst.ExpandedConfig())
+		return err
+	})
+	if err != nil {
+		logger.Warn("Failed to load instance placement rules", logger.Ctx{"project": projectName, "instance": instanceName, "err": err})
+		return
+	}
+
+	instancePlacementWarn(s, rules, projectName, inst.ID(), inst.Location())
+}
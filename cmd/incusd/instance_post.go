@@ -280,6 +280,7 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	// If clustered, consider a new location for the instance.
 	var targetMemberInfo *db.NodeInfo
 	var targetCandidates []db.NodeInfo
+	var placementRules *instance.PlacementRules
 	if s.ServerClustered && (target != "" || req.Project != "") {
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 			var targetGroupName string
@@ -323,6 +324,12 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				}
 			}
 
+			// Restrict the candidates to the cluster members respecting the placement rules.
+			placementRules, targetCandidates, err = instancePlacementFilter(ctx, tx, instProject, name, inst.ExpandedConfig(), targetCandidates)
+			if err != nil {
+				return err
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -451,6 +458,11 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		if targetMemberInfo.IsOffline(s.GlobalConfig.OfflineThreshold()) {
 			return response.BadRequest(errors.New("Target cluster member is offline"))
 		}
+
+		// Moving to another project gives the instance a new ID, so check the rules once it's moved.
+		if instProject == inst.Project().Name {
+			instancePlacementWarn(s, placementRules, instProject, inst.ID(), targetMemberInfo.Name)
+		}
 	}
 
 	// If the user requested a specific server group, make sure we can have it recorded.
@@ -493,7 +505,21 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		// Setup the instance move operation.
 		run := func(op *operations.Operation) error {
 			inst.SetOperation(op)
-			return migrateInstance(context.TODO(), s, inst, req, sourceMemberInfo, targetMemberInfo, targetGroupName, op, nil)
+			err := migrateInstance(context.TODO(), s, inst, req, sourceMemberInfo, targetMemberInfo, targetGroupName, op, nil)
+			if err != nil {
+				return err
+			}
+
+			if placementRules != nil && instProject != inst.Project().Name {
+				instanceName := name
+				if req.Name != "" {
+					instanceName = req.Name
+				}
+
+				instancePlacementWarnPlaced(s, instProject, instanceName)
+			}
+
+			return nil
 		}
 
 		resources := map[string][]api.URL{}
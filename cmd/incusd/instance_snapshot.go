@@ -209,6 +209,28 @@ func instanceSnapshotsGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, resultMap)
 }
 
+// snapshotsCheckLimit returns an error if adding the given number of snapshots to the project would exceed
+// its snapshots limit.
+func snapshotsCheckLimit(ctx context.Context, s *state.State, projectName string, count int) error {
+	if count == 0 {
+		return nil
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return project.CheckSnapshotsLimit(tx, p, count)
+	})
+}
+
 // swagger:operation POST /1.0/instances/{name}/snapshots instances instance_snapshots_post
 //
 //	Create a snapshot
@@ -275,6 +297,11 @@ func instanceSnapshotsPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = project.CheckSnapshotsLimit(tx, p, 1)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
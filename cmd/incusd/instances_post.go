@@ -125,7 +125,7 @@ func createFromImage(s *state.State, r *http.Request, p api.Project, profiles []
 				return err
 			}
 		} else if img != nil {
-			err := ensureImageIsLocallyAvailable(context.TODO(), s, r, img, args.Project)
+			err := ensureImageIsLocallyAvailable(context.TODO(), s, r, op, img, args.Project)
 			if err != nil {
 				return err
 			}
@@ -874,6 +874,11 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.SmartError(err)
 	}
 
+	err = snapshotsCheckLimit(r.Context(), s, projectName, len(bInfo.Snapshots))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	bInfo.Project = projectName
 
 	// Override pool.
@@ -1434,6 +1439,14 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		if s.ServerClustered && !clusterNotification {
+			// Restrict the candidates to the cluster members respecting the placement rules.
+			_, candidateMembers, err = instancePlacementFilter(ctx, tx, targetProjectName, req.Name, db.ExpandInstanceConfig(req.Config, profiles), candidateMembers)
+			if err != nil {
+				return err
+			}
+		}
+
 		if !clusterNotification {
 			// Check that the project's limits are not violated. Note this check is performed after
 			// automatically generated config values (such as ones from an InstanceType) have been set.
@@ -1696,6 +1709,9 @@ func clusterCopyContainerInternal(ctx context.Context, s *state.State, r *http.R
 }
 
 func instanceCreateFinish(s *state.State, req *api.InstancesPost, args db.InstanceArgs, op *operations.Operation) error {
+	// Now that the instance exists, record whether it respects its placement rules.
+	instancePlacementWarnPlaced(s, args.Project, args.Name)
+
 	if req == nil || !req.Start {
 		return nil
 	}
@@ -110,6 +110,10 @@ func main() {
 	activateifneededCmd := cmdActivateifneeded{global: &globalCmd}
 	app.AddCommand(activateifneededCmd.command())
 
+	// audit sub-command
+	auditCmd := cmdAudit{global: &globalCmd}
+	app.AddCommand(auditCmd.command())
+
 	// callhook sub-command
 	callhookCmd := cmdCallhook{global: &globalCmd}
 	app.AddCommand(callhookCmd.command())
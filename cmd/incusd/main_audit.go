@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lxc/incus/v7/internal/server/audit"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/api"
+	cli "github.com/lxc/incus/v7/shared/cmd"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+type cmdAudit struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAudit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "audit"
+	cmd.Short = "Audit log commands"
+	cmd.Long = cli.FormatSection("Description:",
+		`Audit log commands`)
+
+	// Verify
+	verifyCmd := cmdAuditVerify{global: c.global}
+	cmd.AddCommand(verifyCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdAuditVerify struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuditVerify) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "verify [<path>]"
+	cmd.Short = "Verify the integrity of the audit log"
+	cmd.Long = cli.FormatSection("Description:",
+		`Verify the integrity of the audit log
+
+  This checks the hash chain of the audit log, including its rotated files,
+  reporting any record which was modified or removed.`)
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuditVerify) run(_ *cobra.Command, args []string) error {
+	path := internalUtil.LogPath("audit.log")
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// Find the rotated files, oldest first.
+	paths := []string{}
+	for i := 1; ; i++ {
+		rotatedPath := fmt.Sprintf("%s.%d", path, i)
+		if !util.PathExists(rotatedPath) {
+			break
+		}
+
+		paths = append([]string{rotatedPath}, paths...)
+	}
+
+	paths = append(paths, path)
+
+	var last *api.EventAudit
+
+	for _, p := range paths {
+		file, err := os.Open(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return err
+		}
+
+		previousHash := ""
+		if last != nil {
+			previousHash = last.Hash
+		}
+
+		record, err := audit.Verify(file, previousHash)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("Failed verifying %q: %w", p, err)
+		}
+
+		if record == nil {
+			continue
+		}
+
+		last = record
+	}
+
+	if last == nil {
+		fmt.Println("The audit log is empty")
+		return nil
+	}
+
+	fmt.Printf("Verified audit log up to record %d (hash %s)\n", last.Sequence, last.Hash)
+
+	return nil
+}
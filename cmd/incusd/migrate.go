@@ -218,6 +218,7 @@ type migrationSink struct {
 	clusterMoveSourceName string
 	refresh               bool
 	refreshExcludeOlder   bool
+	snapshotsLimit        bool
 }
 
 // MigrationSinkArgs arguments to configure migration sink.
@@ -239,9 +240,10 @@ type migrationSinkArgs struct {
 	Snapshots             []*migration.Snapshot
 
 	// Storage specific fields
-	StoragePool string
-	VolumeOnly  bool
-	VolumeSize  int64
+	StoragePool    string
+	VolumeOnly     bool
+	VolumeSize     int64
+	SnapshotsLimit bool // Enforce the snapshots limit of the target project.
 
 	// Transport specific fields
 	RsyncFeatures []string
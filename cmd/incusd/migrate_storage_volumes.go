@@ -256,6 +256,7 @@ func newStorageMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 		push:                args.Push,
 		refresh:             args.Refresh,
 		refreshExcludeOlder: args.RefreshExcludeOlder,
+		snapshotsLimit:      args.SnapshotsLimit,
 	}
 
 	secretNames := []string{api.SecretNameControl, api.SecretNameFilesystem}
@@ -388,6 +389,13 @@ func (c *migrationSink) DoStorage(st *state.State, projectName string, poolName
 			}
 		}
 
+		if c.snapshotsLimit {
+			err := snapshotsCheckLimit(context.TODO(), st, projectName, len(volTargetArgs.Snapshots))
+			if err != nil {
+				return err
+			}
+		}
+
 		return pool.CreateCustomVolumeFromMigration(projectName, conn, volTargetArgs, op)
 	}
 
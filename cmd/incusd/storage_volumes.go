@@ -1166,6 +1166,9 @@ func doVolumeMigration(s *state.State, r *http.Request, requestProjectName strin
 		VolumeOnly:          req.Source.VolumeOnly,
 		Refresh:             req.Source.Refresh,
 		RefreshExcludeOlder: req.Source.RefreshExcludeOlder,
+
+		// Moves between cluster members don't add snapshots to the project.
+		SnapshotsLimit: !isClusterNotification(r),
 	}
 
 	sink, err := newStorageMigrationSink(&migrationArgs)
@@ -2857,6 +2860,11 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		return response.BadRequest(err)
 	}
 
+	err = snapshotsCheckLimit(r.Context(), s, projectName, len(bInfo.Snapshots))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	bInfo.Project = projectName
 
 	// Override pool.
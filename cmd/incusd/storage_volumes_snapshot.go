@@ -159,6 +159,11 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 			return err
 		}
 
+		err = project.CheckSnapshotsLimit(tx, p, 1)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1628,6 +1633,12 @@ func autoCreateCustomVolumeSnapshots(ctx context.Context, s *state.State, volume
 			return err // Stop if context is cancelled.
 		}
 
+		err = snapshotsCheckLimit(ctx, s, v.ProjectName, 1)
+		if err != nil {
+			logger.Warn("Skipping scheduled custom volume snapshot", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+			continue
+		}
+
 		snapshotName, err := volumeDetermineNextSnapshotName(ctx, s, v, "snap%d")
 		if err != nil {
 			return fmt.Errorf("Error retrieving next snapshot name for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
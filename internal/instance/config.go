@@ -178,6 +178,37 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=cluster.healing_priority)
+	// When an offline cluster member is healed, instances with a higher priority are restarted first.
+	//
+	// See {ref}`cluster-automatic-evacuation` for more information.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0`
+	//  liveupdate: yes
+	//  shortdesc: Restart priority when healing an offline cluster member
+	"cluster.healing_priority": validate.Optional(validate.IsInRange(0, 100)),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.affinity)
+	// Comma-separated list of instances of the same project that this instance should run alongside.
+	//
+	// See {ref}`cluster-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to place on the same cluster member
+	"placement.affinity": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.anti-affinity)
+	// Instances of the same project sharing this tag are spread across different cluster members.
+	//
+	// See {ref}`cluster-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Tag of the instances to place on different cluster members
+	"placement.anti-affinity": validate.Optional(func(value string) error { return validate.IsAPIName(value, false) }),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	// For virtual machines, a CPU topology of the form `sockets=2,cores=4,threads=2` may also be provided.
@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/lxc/incus/v7/internal/server/state"
@@ -20,6 +22,9 @@ import (
 // certificate at a later stage.
 const ClusterCertFilename = "cluster.crt.new"
 
+// challengeMu serializes the ACME challenges, as the HTTP-01 challenges all bind acme.http.port.
+var challengeMu sync.Mutex
+
 // CertKeyPair describes a certificate and its private key.
 type CertKeyPair struct {
 	Certificate []byte `json:"-"`
@@ -102,7 +107,45 @@ func UpdateCertificate(s *state.State, challengeType string, clustered bool, dom
 		}
 	}()
 
+	challengeMu.Lock()
+	certBytes, keyBytes, err := incustls.RunACMEChallenge(context.TODO(), tmpDir, caURL, domain, email, challengeType, provider, port, proxy, resolvers, environment)
+	challengeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertKeyPair{
+		Certificate: certBytes,
+		PrivateKey:  keyBytes,
+	}, nil
+}
+
+// IssueCertificate issues a new certificate for the domain using the server's ACME configuration.
+func IssueCertificate(s *state.State, domain string) (*CertKeyPair, error) {
+	_, email, caURL, agreeToS, challengeType := s.GlobalConfig.ACME()
+	if email == "" || !agreeToS || challengeType == "" {
+		return nil, errors.New("ACME requires acme.email, acme.agree_tos and acme.challenge to be configured")
+	}
+
+	port := s.GlobalConfig.ACMEHTTP()
+	provider, environment, resolvers := s.GlobalConfig.ACMEDNS()
+	proxy := s.GlobalConfig.ProxyHTTPS()
+
+	tmpDir, err := os.MkdirTemp("", "lego")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temporary directory: %w", err)
+	}
+
+	defer func() {
+		err := os.RemoveAll(tmpDir)
+		if err != nil {
+			logger.Warn("Failed to remove temporary directory", logger.Ctx{"err": err})
+		}
+	}()
+
+	challengeMu.Lock()
 	certBytes, keyBytes, err := incustls.RunACMEChallenge(context.TODO(), tmpDir, caURL, domain, email, challengeType, provider, port, proxy, resolvers, environment)
+	challengeMu.Unlock()
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
 	"github.com/lxc/incus/v7/internal/server/instance/drivers/edk2"
 	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v7/internal/server/project"
@@ -26,6 +27,7 @@ type instance interface {
 	Name() string
 	ID() int
 	ExpandedConfig() map[string]string
+	ExpandedDevices() deviceConfig.Devices
 	Type() instancetype.Type
 	LogPath() string
 	RunPath() string
@@ -267,6 +269,14 @@ func instanceProfile(sysOS *sys.OS, inst instance, extraBinaries []string) (stri
 			}
 		}
 
+		// Host devices wired to serial ports.
+		serialPaths := []string{}
+		for _, devConfig := range inst.ExpandedDevices() {
+			if devConfig["type"] == "serial" && strings.HasPrefix(devConfig["source"], "/") {
+				serialPaths = append(serialPaths, devConfig["source"])
+			}
+		}
+
 		err = qemuProfileTpl.Execute(sb, map[string]any{
 			"devicesPath":    inst.DevicesPath(),
 			"exePath":        execPath,
@@ -280,6 +290,7 @@ func instanceProfile(sysOS *sys.OS, inst instance, extraBinaries []string) (stri
 			"path":           path,
 			"raw":            rawContent.String(),
 			"edk2Paths":      edk2Paths,
+			"serialPaths":    serialPaths,
 			"agentPath":      agentPath,
 		})
 		if err != nil {
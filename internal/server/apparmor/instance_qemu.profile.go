@@ -74,6 +74,11 @@ profile "{{ .name }}" flags=(attach_disconnected,mediate_deleted) {
   {{ .devicesPath }}/** rwk,
   /tmp/incus_screenshot_{{ .id }} rwk,
 
+  # Host serial devices
+{{- range $index, $element := .serialPaths }}
+  {{ $element }} rw,
+{{- end }}
+
   # Needed for the fork sub-commands
   {{ .exePath }} mr,
   @{PROC}/@{pid}/cmdline r,
@@ -28,8 +28,10 @@ profile "{{ .name }}" flags=(attach_disconnected,mediate_deleted) {
 
   # Network-specific paths
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.hosts/{,*} r,
+  {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.opts/{,*} r,
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.leases rw,
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.raw r,
+  {{ .varPath }}/networks/{{ .networkName }}/tftp/{,**} r,
 
   # Allow to restart dnsmasq
   signal (receive) set=("hup","kill"),
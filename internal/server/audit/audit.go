@@ -0,0 +1,315 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// maxRecordSize is the maximum size of a single record in the audit log.
+const maxRecordSize = 1024 * 1024
+
+// Log represents an append-only, hash-chained audit log stored in a local file.
+type Log struct {
+	mu sync.Mutex
+
+	path     string
+	file     *os.File
+	size     int64
+	maxSize  int64
+	maxFiles int
+
+	sequence uint64
+	lastHash string
+}
+
+// NewLog returns a new (disabled) audit log stored at the given path.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Configure enables or disables the audit log and sets its rotation settings.
+// Rotation happens once the log reaches maxSize bytes, keeping maxFiles rotated files.
+// At least one rotated file is kept as the chain is restored from it after a rotation.
+func (l *Log) Configure(enabled bool, maxSize int64, maxFiles int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxSize = maxSize
+	l.maxFiles = max(maxFiles, 1)
+
+	if !enabled {
+		return l.close()
+	}
+
+	if l.file != nil {
+		return nil
+	}
+
+	// Restore the chain from the last record written.
+	last, err := lastRecord(l.path)
+	if err != nil {
+		return err
+	}
+
+	if last == nil {
+		last, err = lastRecord(l.rotatedPath(1))
+		if err != nil {
+			return err
+		}
+	}
+
+	if last != nil {
+		l.sequence = last.Sequence
+		l.lastHash = last.Hash
+	}
+
+	return l.open()
+}
+
+// Enabled returns whether the audit log is currently enabled.
+func (l *Log) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file != nil
+}
+
+// Append adds a record to the audit log, filling in its sequence number and hashes.
+func (l *Log) Append(record api.EventAudit) (*api.EventAudit, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil, errors.New("Audit log isn't enabled")
+	}
+
+	record.Sequence = l.sequence + 1
+	record.Timestamp = record.Timestamp.UTC()
+	record.PreviousHash = l.lastHash
+
+	hash, err := Hash(record)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	line = append(line, '\n')
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		err = l.rotate()
+		if err != nil {
+			return nil, fmt.Errorf("Failed rotating audit log: %w", err)
+		}
+	}
+
+	_, err = l.file.Write(line)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return nil, err
+	}
+
+	l.size += int64(len(line))
+	l.sequence = record.Sequence
+	l.lastHash = record.Hash
+
+	return &record, nil
+}
+
+// Close closes the audit log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.close()
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed opening audit log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+func (l *Log) close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+func (l *Log) rotatedPath(index int) string {
+	return fmt.Sprintf("%s.%d", l.path, index)
+}
+
+// rotate moves the current file out of the way, dropping the oldest rotated file if needed.
+func (l *Log) rotate() error {
+	err := l.close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(l.rotatedPath(l.maxFiles))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		err = os.Rename(l.rotatedPath(i), l.rotatedPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	err = os.Rename(l.path, l.rotatedPath(1))
+	if err != nil {
+		return err
+	}
+
+	return l.open()
+}
+
+// Hash computes the hash of a record, covering all its fields but the hash itself.
+func Hash(record api.EventAudit) (string, error) {
+	record.Hash = ""
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify checks the hash chain of the records read from r.
+// The first record must reference previousHash, unless it's empty in which case the first record
+// is used as the starting point of the chain. The last record is returned on success.
+func Verify(r io.Reader, previousHash string) (*api.EventAudit, error) {
+	var last *api.EventAudit
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordSize)
+
+	for scanner.Scan() {
+		record := api.EventAudit{}
+
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing record after sequence %d: %w", sequenceOf(last), err)
+		}
+
+		hash, err := Hash(record)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash != record.Hash {
+			return nil, fmt.Errorf("Record %d has been modified", record.Sequence)
+		}
+
+		if last != nil {
+			if record.Sequence != last.Sequence+1 {
+				return nil, fmt.Errorf("Record %d is followed by record %d", last.Sequence, record.Sequence)
+			}
+
+			if record.PreviousHash != last.Hash {
+				return nil, fmt.Errorf("Record %d doesn't chain to record %d", record.Sequence, last.Sequence)
+			}
+		} else if previousHash != "" && record.PreviousHash != previousHash {
+			return nil, fmt.Errorf("Record %d doesn't chain to the previous file", record.Sequence)
+		}
+
+		last = &record
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return last, nil
+}
+
+// lastRecord returns the last record stored in the file at path, if any.
+func lastRecord(path string) (*api.EventAudit, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var line []byte
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxRecordSize)
+
+	for scanner.Scan() {
+		line = append(line[:0], scanner.Bytes()...)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	record := api.EventAudit{}
+
+	err = json.Unmarshal(line, &record)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing last record of %q: %w", path, err)
+	}
+
+	return &record, nil
+}
+
+func sequenceOf(record *api.EventAudit) uint64 {
+	if record == nil {
+		return 0
+	}
+
+	return record.Sequence
+}
@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l := NewLog(path)
+	require.NoError(t, l.Configure(true, 600, 2))
+
+	var records []*api.EventAudit
+	for _, method := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+		record, err := l.Append(api.EventAudit{Method: method, URL: "/1.0/instances/c1", StatusCode: 200})
+		require.NoError(t, err)
+
+		records = append(records, record)
+	}
+
+	require.NoError(t, l.Close())
+
+	// The records are chained together.
+	for i, record := range records {
+		assert.Equal(t, uint64(i+1), record.Sequence)
+		if i > 0 {
+			assert.Equal(t, records[i-1].Hash, record.PreviousHash)
+		}
+	}
+
+	// The log got rotated.
+	_, err := os.Stat(path + ".1")
+	require.NoError(t, err)
+
+	// The chain is restored when re-opening the log.
+	require.NoError(t, l.Configure(true, 600, 2))
+	record, err := l.Append(api.EventAudit{Method: "POST", URL: "/1.0/networks", StatusCode: 403})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), record.Sequence)
+	assert.Equal(t, records[3].Hash, record.PreviousHash)
+	require.NoError(t, l.Close())
+
+	// The current file verifies.
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	last, err := Verify(file, "")
+	require.NoError(t, err)
+	assert.Equal(t, record.Hash, last.Hash)
+}
+
+func TestLogKeepsLastRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l := NewLog(path)
+	require.NoError(t, l.Configure(true, 200, 0))
+
+	var last *api.EventAudit
+	for range 3 {
+		record, err := l.Append(api.EventAudit{Method: "POST", URL: "/1.0/instances", StatusCode: 200})
+		require.NoError(t, err)
+
+		last = record
+	}
+
+	require.NoError(t, l.Close())
+
+	// The last rotated file is kept.
+	_, err := os.Stat(path + ".1")
+	require.NoError(t, err)
+
+	_, err = os.Stat(path + ".2")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// The chain carries on after re-opening the log.
+	require.NoError(t, l.Configure(true, 200, 0))
+	record, err := l.Append(api.EventAudit{Method: "POST", URL: "/1.0/instances", StatusCode: 200})
+	require.NoError(t, err)
+	assert.Equal(t, last.Sequence+1, record.Sequence)
+	assert.Equal(t, last.Hash, record.PreviousHash)
+	require.NoError(t, l.Close())
+}
+
+func TestVerify(t *testing.T) {
+	l := NewLog(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, l.Configure(true, 0, 0))
+
+	first, err := l.Append(api.EventAudit{Method: "POST", URL: "/1.0/instances", StatusCode: 202})
+	require.NoError(t, err)
+
+	second, err := l.Append(api.EventAudit{Method: "DELETE", URL: "/1.0/instances/c1", StatusCode: 202})
+	require.NoError(t, err)
+
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(l.path)
+	require.NoError(t, err)
+
+	_, err = Verify(strings.NewReader(string(content)), "")
+	assert.NoError(t, err)
+
+	// Modified record.
+	_, err = Verify(strings.NewReader(strings.Replace(string(content), "DELETE", "PATCH", 1)), "")
+	assert.ErrorContains(t, err, "modified")
+
+	// Removed record.
+	lines := strings.SplitAfter(string(content), "\n")
+	_, err = Verify(strings.NewReader(lines[1]), first.PreviousHash+"0")
+	assert.ErrorContains(t, err, "previous file")
+
+	_, err = Verify(strings.NewReader(lines[1]), first.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Hash, second.PreviousHash)
+}
+
+func TestBodyDigester(t *testing.T) {
+	// Unread content is drained to complete the digest.
+	body := NewBodyDigester(io.NopCloser(strings.NewReader("hello")))
+	size, digest, partial := body.Digest()
+	assert.Equal(t, int64(5), size)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", digest)
+	assert.False(t, partial)
+
+	// Bodies too large to be drained are flagged as such.
+	body = NewBodyDigester(io.NopCloser(strings.NewReader(strings.Repeat("a", maxDrainSize+1))))
+	size, _, partial = body.Digest()
+	assert.Equal(t, int64(maxDrainSize), size)
+	assert.True(t, partial)
+}
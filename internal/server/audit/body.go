@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// maxDrainSize is how much of a request body not consumed by the handler gets read to complete its digest.
+const maxDrainSize = 1024 * 1024
+
+// BodyDigester wraps a request body, hashing its content as it's being read.
+type BodyDigester struct {
+	body io.ReadCloser
+	hash hash.Hash
+	size int64
+	eof  bool
+}
+
+// NewBodyDigester returns a BodyDigester for the provided request body.
+func NewBodyDigester(body io.ReadCloser) *BodyDigester {
+	return &BodyDigester{
+		body: body,
+		hash: sha256.New(),
+	}
+}
+
+// Read reads from the request body while updating the digest.
+func (b *BodyDigester) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		_, _ = b.hash.Write(p[:n])
+		b.size += int64(n)
+	}
+
+	if err == io.EOF {
+		b.eof = true
+	}
+
+	return n, err
+}
+
+// Close closes the request body.
+func (b *BodyDigester) Close() error {
+	return b.body.Close()
+}
+
+// Digest returns the size and SHA-256 digest of the request body.
+// Any content not consumed by the handler is read first, up to a limit.
+// If the body couldn't be read entirely, the digest only covers the returned size and partial is true.
+func (b *BodyDigester) Digest() (size int64, digest string, partial bool) {
+	if !b.eof {
+		_, _ = io.Copy(io.Discard, io.LimitReader(b, maxDrainSize))
+	}
+
+	return b.size, hex.EncodeToString(b.hash.Sum(nil)), !b.eof
+}
@@ -20,6 +20,9 @@ const (
 
 	// DriverScriptlet provides scriptlet-based authorization. It is compatible with any authentication method.
 	DriverScriptlet string = "scriptlet"
+
+	// DriverBuiltin provides fine-grained authorization backed by the cluster database. It is compatible with any authentication method.
+	DriverBuiltin string = "builtin"
 )
 
 // ErrUnknownDriver is the "Unknown driver" error.
@@ -29,6 +32,7 @@ var authorizers = map[string]func() authorizer{
 	DriverTLS:       func() authorizer { return &TLS{} },
 	DriverOpenFGA:   func() authorizer { return &FGA{} },
 	DriverScriptlet: func() authorizer { return &Scriptlet{} },
+	DriverBuiltin:   func() authorizer { return &Builtin{} },
 }
 
 type authorizer interface {
@@ -112,6 +116,7 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+	permissionStore PermissionStore
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
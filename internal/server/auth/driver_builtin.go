@@ -0,0 +1,546 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/lxc/incus/v7/internal/server/certificate"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Relations used by the built-in authorization model to group entitlements.
+const (
+	relationAdmin         Entitlement = "admin"
+	relationOperator      Entitlement = "operator"
+	relationUser          Entitlement = "user"
+	relationViewer        Entitlement = "viewer"
+	relationAuthenticated Entitlement = "authenticated"
+)
+
+// Permission represents an entitlement granted on an authorization object.
+type Permission struct {
+	Entitlement Entitlement
+	Object      Object
+}
+
+// IdentityPermissions represents the permissions granted to an identity.
+type IdentityPermissions struct {
+	AuthenticationMethod string
+	Identifier           string
+	Permissions          []Permission
+}
+
+// PermissionStore is used by the built-in driver to retrieve and maintain the permissions granted to identities.
+type PermissionStore interface {
+	// GetIdentityPermissions returns the permissions of an identity and whether the identity is known.
+	GetIdentityPermissions(ctx context.Context, authenticationMethod string, identifier string) ([]Permission, bool, error)
+
+	// GetAllIdentityPermissions returns the permissions of all known identities.
+	GetAllIdentityPermissions(ctx context.Context) ([]IdentityPermissions, error)
+
+	// RenameObjects updates the permissions on objects, and any object below them, after a rename.
+	// The renames map the old objects to the new ones.
+	RenameObjects(ctx context.Context, renames map[Object]Object) error
+
+	// DeleteObjects removes the permissions on objects, and any object below them, after a deletion.
+	DeleteObjects(ctx context.Context, objects ...Object) error
+}
+
+// builtinRequestPermissions caches the permissions of the requestor for the duration of a request, so that
+// all the permission checks of the request share a single lookup.
+type builtinRequestPermissions struct {
+	mu       sync.Mutex
+	identity string
+	granted  map[Permission]bool
+	found    bool
+}
+
+// WithPermissionCache returns a context in which the built-in driver caches the permissions of the requestor.
+func WithPermissionCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, request.CtxPermissionCache, &builtinRequestPermissions{})
+}
+
+// ProjectOwnerPermission returns the permission granted to the identity owning a project.
+func ProjectOwnerPermission(projectName string) Permission {
+	return Permission{Entitlement: relationAdmin, Object: ObjectProject(projectName)}
+}
+
+// WithPermissionStore should be passed into LoadAuthorizer when DriverBuiltin is used.
+func WithPermissionStore(store PermissionStore) func(*Opts) {
+	return func(o *Opts) {
+		o.permissionStore = store
+	}
+}
+
+// builtinRule describes how an entitlement on an object type can be obtained.
+type builtinRule struct {
+	// direct indicates that the entitlement can be granted on the object.
+	direct bool
+
+	// implied lists the entitlements on the same object which imply this entitlement.
+	implied []Entitlement
+
+	// parent lists the entitlements on the parent project or server which imply this entitlement.
+	parent []Entitlement
+
+	// authenticated indicates that any authenticated user has this entitlement.
+	authenticated bool
+}
+
+// builtinProjectResourceRules are the rules shared by most project level resources.
+var builtinProjectResourceRules = map[Entitlement]builtinRule{
+	EntitlementCanEdit: {direct: true, parent: []Entitlement{relationOperator}},
+	EntitlementCanView: {direct: true, implied: []Entitlement{EntitlementCanEdit}, parent: []Entitlement{relationViewer}},
+}
+
+// builtinModel mirrors the OpenFGA authorization model.
+var builtinModel = map[ObjectType]map[Entitlement]builtinRule{
+	ObjectTypeServer: {
+		relationAdmin:                                  {direct: true},
+		relationOperator:                               {direct: true, implied: []Entitlement{relationAdmin}},
+		relationUser:                                   {direct: true, implied: []Entitlement{relationOperator}},
+		relationViewer:                                 {direct: true, implied: []Entitlement{relationUser}},
+		relationAuthenticated:                          {authenticated: true},
+		EntitlementCanCreateCertificates:               {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanCreateNetworkIntegrations:        {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanCreateProjects:                   {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanCreateStoragePools:               {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanEdit:                             {implied: []Entitlement{relationAdmin}},
+		EntitlementCanOverrideClusterTargetRestriction: {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanViewPrivilegedEvents:             {direct: true, implied: []Entitlement{relationAdmin}},
+		EntitlementCanViewMetrics:                      {implied: []Entitlement{relationAuthenticated}},
+		EntitlementCanViewResources:                    {implied: []Entitlement{relationAuthenticated}},
+		EntitlementCanViewSensitive:                    {direct: true, implied: []Entitlement{relationViewer}},
+		EntitlementCanView:                             {implied: []Entitlement{relationAuthenticated}},
+	},
+	ObjectTypeCertificate: {
+		EntitlementCanEdit: {direct: true, parent: []Entitlement{relationAdmin}},
+		EntitlementCanView: {parent: []Entitlement{relationViewer}},
+	},
+	ObjectTypeNetworkIntegration: {
+		EntitlementCanEdit: {direct: true, parent: []Entitlement{relationAdmin}},
+		EntitlementCanView: {parent: []Entitlement{relationViewer}},
+	},
+	ObjectTypeStoragePool: {
+		EntitlementCanEdit: {direct: true, parent: []Entitlement{relationAdmin}},
+		EntitlementCanView: {parent: []Entitlement{relationAuthenticated}},
+	},
+	ObjectTypeProject: {
+		relationAdmin:                          {direct: true, parent: []Entitlement{relationAdmin}},
+		relationOperator:                       {direct: true, implied: []Entitlement{relationAdmin}, parent: []Entitlement{relationOperator}},
+		relationUser:                           {direct: true, implied: []Entitlement{relationOperator}, parent: []Entitlement{relationUser}},
+		relationViewer:                         {direct: true, implied: []Entitlement{relationUser}, parent: []Entitlement{relationViewer}},
+		EntitlementCanCreateImageAliases:       {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateImages:             {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateInstances:          {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateNetworkACLs:        {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateNetworkAddressSets: {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateNetworks:           {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateNetworkZones:       {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateProfiles:           {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateStorageBuckets:     {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanCreateStorageVolumes:     {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanEdit:                     {implied: []Entitlement{relationAdmin}},
+		EntitlementCanViewEvents:               {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanViewOperations:           {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanView:                     {implied: []Entitlement{relationViewer}},
+	},
+	ObjectTypeInstance: {
+		relationAdmin:                 {direct: true, parent: []Entitlement{relationAdmin}},
+		relationOperator:              {direct: true, implied: []Entitlement{relationAdmin}, parent: []Entitlement{relationOperator}},
+		relationUser:                  {direct: true, implied: []Entitlement{relationOperator}, parent: []Entitlement{relationUser}},
+		relationViewer:                {direct: true, implied: []Entitlement{relationUser}, parent: []Entitlement{relationViewer}},
+		EntitlementCanAccessConsole:   {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanAccessFiles:     {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanConnectNBD:      {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanConnectSFTP:     {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanEdit:            {implied: []Entitlement{relationOperator}},
+		EntitlementCanExec:            {direct: true, implied: []Entitlement{relationUser}},
+		EntitlementCanManageBackups:   {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanManageSnapshots: {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanUpdateState:     {direct: true, implied: []Entitlement{relationOperator}},
+		EntitlementCanView:            {implied: []Entitlement{relationViewer}},
+	},
+	ObjectTypeImage:             builtinProjectResourceRules,
+	ObjectTypeImageAlias:        builtinProjectResourceRules,
+	ObjectTypeNetwork:           builtinProjectResourceRules,
+	ObjectTypeNetworkACL:        builtinProjectResourceRules,
+	ObjectTypeNetworkAddressSet: builtinProjectResourceRules,
+	ObjectTypeNetworkZone:       builtinProjectResourceRules,
+	ObjectTypeProfile:           builtinProjectResourceRules,
+	ObjectTypeStorageBucket:     builtinProjectResourceRules,
+	ObjectTypeStorageVolume: {
+		EntitlementCanEdit:            {direct: true, parent: []Entitlement{relationOperator}},
+		EntitlementCanManageBackups:   {direct: true, implied: []Entitlement{EntitlementCanEdit}},
+		EntitlementCanManageSnapshots: {direct: true, implied: []Entitlement{EntitlementCanEdit}},
+		EntitlementCanView:            {direct: true, implied: []Entitlement{EntitlementCanEdit}, parent: []Entitlement{relationViewer}},
+		EntitlementCanAccessFiles:     {direct: true, implied: []Entitlement{EntitlementCanEdit}},
+		EntitlementCanConnectNBD:      {direct: true, implied: []Entitlement{EntitlementCanEdit}},
+		EntitlementCanConnectSFTP:     {direct: true, implied: []Entitlement{EntitlementCanEdit}},
+	},
+}
+
+// ValidatePermission checks that an entitlement can be granted on an object with the built-in driver.
+func ValidatePermission(entitlement string, object string) error {
+	o, err := ObjectFromString(object)
+	if err != nil {
+		return fmt.Errorf("Invalid object %q: %w", object, err)
+	}
+
+	rule, ok := builtinModel[o.Type()][Entitlement(entitlement)]
+	if !ok || !rule.direct {
+		return fmt.Errorf("Entitlement %q cannot be granted on objects of type %q", entitlement, o.Type())
+	}
+
+	return nil
+}
+
+// Builtin represents the built-in authorizer backed by the cluster database.
+type Builtin struct {
+	commonAuthorizer
+	tls   *TLS
+	store PermissionStore
+}
+
+func (b *Builtin) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	if opts.permissionStore == nil {
+		return errors.New("No permission store provided")
+	}
+
+	b.store = opts.permissionStore
+
+	b.tls = &TLS{}
+	err := b.tls.load(ctx, certificateCache, opts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// builtinParent returns the object from which an object inherits relations.
+func builtinParent(object Object) (Object, bool) {
+	switch object.Type() {
+	case ObjectTypeServer:
+		return "", false
+	case ObjectTypeProject:
+		return ObjectServer(), true
+	}
+
+	if objectValidators[object.Type()].requireProject {
+		return ObjectProject(object.Project()), true
+	}
+
+	return ObjectServer(), true
+}
+
+// builtinAllowed evaluates the built-in model for a set of granted permissions.
+func builtinAllowed(granted map[Permission]bool, object Object, entitlement Entitlement) bool {
+	rule, ok := builtinModel[object.Type()][entitlement]
+	if !ok {
+		return false
+	}
+
+	if rule.authenticated {
+		return true
+	}
+
+	if rule.direct && granted[Permission{Entitlement: entitlement, Object: object}] {
+		return true
+	}
+
+	for _, implied := range rule.implied {
+		if builtinAllowed(granted, object, implied) {
+			return true
+		}
+	}
+
+	if len(rule.parent) > 0 {
+		parent, ok := builtinParent(object)
+		if ok {
+			for _, entitlement := range rule.parent {
+				if builtinAllowed(granted, parent, entitlement) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// grantedPermissions returns the permissions of the requestor and whether they're known to the driver.
+// The permissions are retrieved once per request when the request context has a permission cache.
+func (b *Builtin) grantedPermissions(ctx context.Context, r *http.Request, details *requestDetails) (map[Permission]bool, bool, error) {
+	identity := details.authenticationProtocol() + "/" + details.username()
+
+	cache, _ := r.Context().Value(request.CtxPermissionCache).(*builtinRequestPermissions)
+	if cache != nil {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+
+		if cache.granted != nil && cache.identity == identity {
+			return cache.granted, cache.found, nil
+		}
+	}
+
+	permissions, found, err := b.store.GetIdentityPermissions(ctx, details.authenticationProtocol(), details.username())
+	if err != nil {
+		return nil, false, err
+	}
+
+	granted := make(map[Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	if cache != nil {
+		cache.identity = identity
+		cache.granted = granted
+		cache.found = found
+	}
+
+	return granted, found, nil
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (b *Builtin) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	details, err := b.requestDetails(r)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return nil
+	}
+
+	granted, found, err := b.grantedPermissions(ctx, r, details)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve permissions: %w", err)
+	}
+
+	// Use the TLS driver if the user authenticated with TLS and isn't a known identity.
+	if !found && details.authenticationProtocol() == api.AuthenticationMethodTLS {
+		return b.tls.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	if !builtinAllowed(granted, object, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "User does not have entitlement %q on object %q", entitlement, object)
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (b *Builtin) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	allowFunc := func(b bool) func(Object) bool {
+		return func(Object) bool {
+			return b
+		}
+	}
+
+	details, err := b.requestDetails(r)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return allowFunc(true), nil
+	}
+
+	granted, found, err := b.grantedPermissions(ctx, r, details)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve permissions: %w", err)
+	}
+
+	// Use the TLS driver if the user authenticated with TLS and isn't a known identity.
+	if !found && details.authenticationProtocol() == api.AuthenticationMethodTLS {
+		return b.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
+	return func(object Object) bool {
+		if object.Type() != objectType {
+			return false
+		}
+
+		return builtinAllowed(granted, object, entitlement)
+	}, nil
+}
+
+// getAccess returns the identities having one of the main relations on an object.
+func (b *Builtin) getAccess(ctx context.Context, object Object) (*api.Access, error) {
+	identities, err := b.store.GetAllIdentityPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve permissions: %w", err)
+	}
+
+	access := api.Access{}
+	for _, identity := range identities {
+		granted := make(map[Permission]bool, len(identity.Permissions))
+		for _, permission := range identity.Permissions {
+			granted[permission] = true
+		}
+
+		for _, relation := range []Entitlement{relationAdmin, relationOperator, relationUser, relationViewer} {
+			if builtinAllowed(granted, object, relation) {
+				access = append(access, api.AccessEntry{
+					Identifier: identity.Identifier,
+					Role:       string(relation),
+					Provider:   b.driverName,
+				})
+
+				break
+			}
+		}
+	}
+
+	return &access, nil
+}
+
+// GetInstanceAccess returns the list of entities who have access to the instance.
+func (b *Builtin) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
+	return b.getAccess(ctx, ObjectInstance(projectName, instanceName))
+}
+
+// GetProjectAccess returns the list of entities who have access to the project.
+func (b *Builtin) GetProjectAccess(ctx context.Context, projectName string) (*api.Access, error) {
+	return b.getAccess(ctx, ObjectProject(projectName))
+}
+
+// DeleteProject removes the permissions on the project and its resources.
+func (b *Builtin) DeleteProject(ctx context.Context, projectID int64, projectName string) error {
+	objects := []Object{}
+	for objectType, validator := range objectValidators {
+		if !validator.requireProject {
+			continue
+		}
+
+		objects = append(objects, Object(fmt.Sprintf("%s%s%s", objectType, objectTypeDelimiter, escape(projectName))))
+	}
+
+	return b.store.DeleteObjects(ctx, objects...)
+}
+
+// RenameProject updates the permissions on the project and its resources.
+func (b *Builtin) RenameProject(ctx context.Context, projectID int64, oldName string, newName string) error {
+	renames := map[Object]Object{}
+	for objectType, validator := range objectValidators {
+		if !validator.requireProject {
+			continue
+		}
+
+		oldObject := Object(fmt.Sprintf("%s%s%s", objectType, objectTypeDelimiter, escape(oldName)))
+		renames[oldObject] = Object(fmt.Sprintf("%s%s%s", objectType, objectTypeDelimiter, escape(newName)))
+	}
+
+	return b.store.RenameObjects(ctx, renames)
+}
+
+// DeleteCertificate removes the permissions on the certificate.
+func (b *Builtin) DeleteCertificate(ctx context.Context, fingerprint string) error {
+	return b.store.DeleteObjects(ctx, ObjectCertificate(fingerprint))
+}
+
+// DeleteStoragePool removes the permissions on the storage pool.
+func (b *Builtin) DeleteStoragePool(ctx context.Context, storagePoolName string) error {
+	return b.store.DeleteObjects(ctx, ObjectStoragePool(storagePoolName))
+}
+
+// DeleteImage removes the permissions on the image.
+func (b *Builtin) DeleteImage(ctx context.Context, projectName string, fingerprint string) error {
+	return b.store.DeleteObjects(ctx, ObjectImage(projectName, fingerprint))
+}
+
+// DeleteImageAlias removes the permissions on the image alias.
+func (b *Builtin) DeleteImageAlias(ctx context.Context, projectName string, imageAliasName string) error {
+	return b.store.DeleteObjects(ctx, ObjectImageAlias(projectName, imageAliasName))
+}
+
+// RenameImageAlias updates the permissions on the image alias.
+func (b *Builtin) RenameImageAlias(ctx context.Context, projectName string, oldAliasName string, newAliasName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectImageAlias(projectName, oldAliasName): ObjectImageAlias(projectName, newAliasName)})
+}
+
+// DeleteInstance removes the permissions on the instance.
+func (b *Builtin) DeleteInstance(ctx context.Context, projectName string, instanceName string) error {
+	return b.store.DeleteObjects(ctx, ObjectInstance(projectName, instanceName))
+}
+
+// RenameInstance updates the permissions on the instance.
+func (b *Builtin) RenameInstance(ctx context.Context, projectName string, oldInstanceName string, newInstanceName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectInstance(projectName, oldInstanceName): ObjectInstance(projectName, newInstanceName)})
+}
+
+// DeleteNetwork removes the permissions on the network.
+func (b *Builtin) DeleteNetwork(ctx context.Context, projectName string, networkName string) error {
+	return b.store.DeleteObjects(ctx, ObjectNetwork(projectName, networkName))
+}
+
+// RenameNetwork updates the permissions on the network.
+func (b *Builtin) RenameNetwork(ctx context.Context, projectName string, oldNetworkName string, newNetworkName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectNetwork(projectName, oldNetworkName): ObjectNetwork(projectName, newNetworkName)})
+}
+
+// DeleteNetworkZone removes the permissions on the network zone.
+func (b *Builtin) DeleteNetworkZone(ctx context.Context, projectName string, networkZoneName string) error {
+	return b.store.DeleteObjects(ctx, ObjectNetworkZone(projectName, networkZoneName))
+}
+
+// DeleteNetworkIntegration removes the permissions on the network integration.
+func (b *Builtin) DeleteNetworkIntegration(ctx context.Context, networkIntegrationName string) error {
+	return b.store.DeleteObjects(ctx, ObjectNetworkIntegration(networkIntegrationName))
+}
+
+// RenameNetworkIntegration updates the permissions on the network integration.
+func (b *Builtin) RenameNetworkIntegration(ctx context.Context, oldNetworkIntegrationName string, newNetworkIntegrationName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectNetworkIntegration(oldNetworkIntegrationName): ObjectNetworkIntegration(newNetworkIntegrationName)})
+}
+
+// DeleteNetworkACL removes the permissions on the network ACL.
+func (b *Builtin) DeleteNetworkACL(ctx context.Context, projectName string, networkACLName string) error {
+	return b.store.DeleteObjects(ctx, ObjectNetworkACL(projectName, networkACLName))
+}
+
+// RenameNetworkACL updates the permissions on the network ACL.
+func (b *Builtin) RenameNetworkACL(ctx context.Context, projectName string, oldNetworkACLName string, newNetworkACLName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectNetworkACL(projectName, oldNetworkACLName): ObjectNetworkACL(projectName, newNetworkACLName)})
+}
+
+// DeleteNetworkAddressSet removes the permissions on the network address set.
+func (b *Builtin) DeleteNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	return b.store.DeleteObjects(ctx, ObjectNetworkAddressSet(projectName, networkAddressSetName))
+}
+
+// RenameNetworkAddressSet updates the permissions on the network address set.
+func (b *Builtin) RenameNetworkAddressSet(ctx context.Context, projectName string, oldNetworkAddressSetName string, newNetworkAddressSetName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectNetworkAddressSet(projectName, oldNetworkAddressSetName): ObjectNetworkAddressSet(projectName, newNetworkAddressSetName)})
+}
+
+// DeleteProfile removes the permissions on the profile.
+func (b *Builtin) DeleteProfile(ctx context.Context, projectName string, profileName string) error {
+	return b.store.DeleteObjects(ctx, ObjectProfile(projectName, profileName))
+}
+
+// RenameProfile updates the permissions on the profile.
+func (b *Builtin) RenameProfile(ctx context.Context, projectName string, oldProfileName string, newProfileName string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectProfile(projectName, oldProfileName): ObjectProfile(projectName, newProfileName)})
+}
+
+// DeleteStoragePoolVolume removes the permissions on the storage volume.
+func (b *Builtin) DeleteStoragePoolVolume(ctx context.Context, projectName string, storagePoolName string, storageVolumeType string, storageVolumeName string, storageVolumeLocation string) error {
+	return b.store.DeleteObjects(ctx, ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, storageVolumeName, storageVolumeLocation))
+}
+
+// RenameStoragePoolVolume updates the permissions on the storage volume.
+func (b *Builtin) RenameStoragePoolVolume(ctx context.Context, projectName string, storagePoolName string, storageVolumeType string, oldStorageVolumeName string, newStorageVolumeName string, storageVolumeLocation string) error {
+	return b.store.RenameObjects(ctx, map[Object]Object{ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, oldStorageVolumeName, storageVolumeLocation): ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, newStorageVolumeName, storageVolumeLocation)})
+}
+
+// DeleteStorageBucket removes the permissions on the storage bucket.
+func (b *Builtin) DeleteStorageBucket(ctx context.Context, projectName string, storagePoolName string, storageBucketName string, storageBucketLocation string) error {
+	return b.store.DeleteObjects(ctx, ObjectStorageBucket(projectName, storagePoolName, storageBucketName, storageBucketLocation))
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// countingPermissionStore is a permission store counting the permission lookups.
+type countingPermissionStore struct {
+	permissions []Permission
+	lookups     int
+}
+
+func (s *countingPermissionStore) GetIdentityPermissions(ctx context.Context, authenticationMethod string, identifier string) ([]Permission, bool, error) {
+	s.lookups++
+	return s.permissions, true, nil
+}
+
+func (s *countingPermissionStore) GetAllIdentityPermissions(ctx context.Context) ([]IdentityPermissions, error) {
+	return nil, nil
+}
+
+func (s *countingPermissionStore) RenameObjects(ctx context.Context, renames map[Object]Object) error {
+	return nil
+}
+
+func (s *countingPermissionStore) DeleteObjects(ctx context.Context, objects ...Object) error {
+	return nil
+}
+
+func TestBuiltinAllowed(t *testing.T) {
+	granted := map[Permission]bool{
+		{Entitlement: relationOperator, Object: ObjectProject("foo")}:          true,
+		{Entitlement: EntitlementCanExec, Object: ObjectInstance("bar", "c1")}: true,
+		{Entitlement: EntitlementCanEdit, Object: ObjectNetwork("bar", "net")}: true,
+	}
+
+	tests := []struct {
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		{ObjectInstance("foo", "c1"), EntitlementCanEdit, true},
+		{ObjectInstance("foo", "c1"), EntitlementCanExec, true},
+		{ObjectProject("foo"), EntitlementCanCreateInstances, true},
+		{ObjectProject("foo"), EntitlementCanEdit, false},
+		{ObjectInstance("bar", "c1"), EntitlementCanExec, true},
+		{ObjectInstance("bar", "c1"), EntitlementCanView, false},
+		{ObjectInstance("bar", "c2"), EntitlementCanExec, false},
+		{ObjectNetwork("bar", "net"), EntitlementCanView, true},
+		{ObjectNetwork("bar", "other"), EntitlementCanView, false},
+		{ObjectStoragePool("default"), EntitlementCanView, true},
+		{ObjectStoragePool("default"), EntitlementCanEdit, false},
+		{ObjectServer(), EntitlementCanViewSensitive, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, builtinAllowed(granted, test.object, test.entitlement), "%s on %s", test.entitlement, test.object)
+	}
+}
+
+func TestValidatePermission(t *testing.T) {
+	assert.NoError(t, ValidatePermission("can_exec", "instance:default/c1"))
+	assert.NoError(t, ValidatePermission("admin", "server:incus"))
+	assert.Error(t, ValidatePermission("can_view", "instance:default/c1"))
+	assert.Error(t, ValidatePermission("can_exec", "network:default/net"))
+	assert.Error(t, ValidatePermission("can_exec", "instance"))
+}
+
+func TestBuiltinPermissionCache(t *testing.T) {
+	store := &countingPermissionStore{permissions: []Permission{{Entitlement: relationOperator, Object: ObjectProject("foo")}}}
+	b := &Builtin{store: store}
+
+	newRequest := func(cache bool) *http.Request {
+		ctx := context.WithValue(context.Background(), request.CtxUsername, "alice")
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodOIDC)
+		if cache {
+			ctx = WithPermissionCache(ctx)
+		}
+
+		return httptest.NewRequest(http.MethodGet, "/1.0/instances", nil).WithContext(ctx)
+	}
+
+	// All the checks of a request share a single lookup.
+	r := newRequest(true)
+	assert.NoError(t, b.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.Error(t, b.CheckPermission(r.Context(), r, ObjectInstance("bar", "c1"), EntitlementCanExec))
+
+	checker, err := b.GetPermissionChecker(r.Context(), r, EntitlementCanView, ObjectTypeInstance)
+	assert.NoError(t, err)
+	assert.True(t, checker(ObjectInstance("foo", "c1")))
+	assert.Equal(t, 1, store.lookups)
+
+	// Each request gets its own lookup.
+	r = newRequest(true)
+	assert.NoError(t, b.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.Equal(t, 2, store.lookups)
+
+	// Without a cache, each check does its own lookup.
+	r = newRequest(false)
+	assert.NoError(t, b.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.NoError(t, b.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.Equal(t, 4, store.lookups)
+}
@@ -7,6 +7,7 @@ import (
 	"slices"
 
 	"github.com/lxc/incus/v7/internal/server/certificate"
+	"github.com/lxc/incus/v7/internal/server/request"
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/util"
@@ -38,14 +39,18 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
-	authenticationProtocol := details.authenticationProtocol()
+	authenticationProtocol, username, err := t.identity(r, details)
+	if err != nil {
+		return err
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		// Return nil. If the server has been configured with an authentication method but no associated authorization driver,
 		// the default is to give these authenticated users admin privileges.
 		return nil
 	}
 
-	certType, isNotRestricted, projectNames, err := t.certificateDetails(details.username())
+	certType, isNotRestricted, projectNames, err := t.certificateDetails(username)
 	if err != nil {
 		return err
 	}
@@ -54,44 +59,7 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
-	if details.IsAllProjectsRequest {
-		// Only admins (users with non-restricted certs) can use the all-projects parameter.
-		return api.StatusErrorf(http.StatusForbidden, "Certificate is restricted")
-	}
-
-	// Check server level object types
-	switch object.Type() {
-	case ObjectTypeServer:
-		if entitlement == EntitlementCanView || entitlement == EntitlementCanViewResources || entitlement == EntitlementCanViewMetrics {
-			return nil
-		}
-
-		return api.StatusErrorf(http.StatusForbidden, "Certificate is restricted")
-	case ObjectTypeStoragePool, ObjectTypeCertificate:
-		if entitlement == EntitlementCanView {
-			return nil
-		}
-
-		return api.StatusErrorf(http.StatusForbidden, "Certificate is restricted")
-	}
-
-	// Don't allow project modifications.
-	if object.Type() == ObjectTypeProject && entitlement == EntitlementCanEdit {
-		return api.StatusErrorf(http.StatusForbidden, "Certificate is restricted")
-	}
-
-	// Check project level permissions against the certificates project list.
-	projectName := object.Project()
-	if slices.Contains(projectNames, projectName) {
-		return nil
-	}
-
-	// Also allow read-only access to inherited resources.
-	if object.Project() == api.ProjectDefaultName && entitlement == EntitlementCanView && slices.Contains([]ObjectType{ObjectTypeImage, ObjectTypeProfile, ObjectTypeStorageVolume, ObjectTypeStorageBucket, ObjectTypeNetwork, ObjectTypeNetworkZone}, object.Type()) {
-		return nil
-	}
-
-	return api.StatusErrorf(http.StatusForbidden, "User does not have permission for project %q", projectName)
+	return checkRestrictedPermission("Certificate", projectNames, details, object, entitlement)
 }
 
 // GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
@@ -111,14 +79,18 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
-	authenticationProtocol := details.authenticationProtocol()
+	authenticationProtocol, username, err := t.identity(r, details)
+	if err != nil {
+		return nil, err
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		// Allow all. If the server has been configured with an authentication method but no associated authorization driver,
 		// the default is to give these authenticated users admin privileges.
 		return allowFunc(true), nil
 	}
 
-	certType, isNotRestricted, projectNames, err := t.certificateDetails(details.username())
+	certType, isNotRestricted, projectNames, err := t.certificateDetails(username)
 	if err != nil {
 		return nil, err
 	}
@@ -134,49 +106,24 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		}, nil
 	}
 
-	// Check server level object types
-	switch objectType {
-	case ObjectTypeServer:
-		if entitlement == EntitlementCanView || entitlement == EntitlementCanViewResources || entitlement == EntitlementCanViewMetrics {
-			return allowFunc(true), nil
-		}
-
-		return allowFunc(false), nil
-	case ObjectTypeStoragePool, ObjectTypeCertificate:
-		if entitlement == EntitlementCanView {
-			return allowFunc(true), nil
-		}
+	return restrictedPermissionChecker(projectNames, details, entitlement, objectType), nil
+}
 
-		return allowFunc(false), nil
+// identity returns the authentication method and identifier to authorize the request as.
+// API tokens have no certificate of their own, so requests using one are authorized as the identity which
+// created the token. This keeps a token from exceeding the rights of its creator.
+func (t *TLS) identity(r *http.Request, details *requestDetails) (string, string, error) {
+	authenticationProtocol := details.authenticationProtocol()
+	if authenticationProtocol != api.AuthenticationMethodToken {
+		return authenticationProtocol, details.username(), nil
 	}
 
-	// Error if user does not have access to the project (unless we're getting projects, where we want to filter the results).
-	if !details.IsAllProjectsRequest && !slices.Contains(projectNames, details.ProjectName) && objectType != ObjectTypeProject {
-		return allowFunc(false), nil
+	token, ok := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+	if !ok || token == nil || token.CreatorAuthenticationMethod == "" {
+		return "", "", api.StatusErrorf(http.StatusForbidden, "Creator of API token %q is unknown", details.username())
 	}
 
-	// Filter objects by project.
-	return func(object Object) bool {
-		// Allow if the project is in the allowed set.
-		if slices.Contains(projectNames, object.Project()) {
-			return true
-		}
-
-		// Also allow read-only access to inherited resources.
-		if object.Project() != api.ProjectDefaultName {
-			return false
-		}
-
-		if entitlement != EntitlementCanView {
-			return false
-		}
-
-		if !slices.Contains([]ObjectType{ObjectTypeImage, ObjectTypeProfile, ObjectTypeStorageVolume, ObjectTypeStorageBucket, ObjectTypeNetwork, ObjectTypeNetworkZone}, objectType) {
-			return false
-		}
-
-		return true
-	}, nil
+	return token.CreatorAuthenticationMethod, token.CreatorIdentifier, nil
 }
 
 // certificateDetails returns the certificate type, a boolean indicating if the certificate is *not* restricted, a slice of
@@ -326,3 +273,99 @@ func (t *TLS) GetProjectAccess(ctx context.Context, projectName string) (*api.Ac
 
 	return &access, nil
 }
+
+// checkRestrictedPermission returns an error if a user restricted to the given projects doesn't have the entitlement on the object.
+// The subject is used to describe the restricted credential in error messages.
+func checkRestrictedPermission(subject string, projectNames []string, details *requestDetails, object Object, entitlement Entitlement) error {
+	if details.IsAllProjectsRequest {
+		// Only admins (users with non-restricted credentials) can use the all-projects parameter.
+		return api.StatusErrorf(http.StatusForbidden, "%s is restricted", subject)
+	}
+
+	// Check server level object types
+	switch object.Type() {
+	case ObjectTypeServer:
+		if entitlement == EntitlementCanView || entitlement == EntitlementCanViewResources || entitlement == EntitlementCanViewMetrics {
+			return nil
+		}
+
+		return api.StatusErrorf(http.StatusForbidden, "%s is restricted", subject)
+	case ObjectTypeStoragePool, ObjectTypeCertificate:
+		if entitlement == EntitlementCanView {
+			return nil
+		}
+
+		return api.StatusErrorf(http.StatusForbidden, "%s is restricted", subject)
+	}
+
+	// Don't allow project modifications.
+	if object.Type() == ObjectTypeProject && entitlement == EntitlementCanEdit {
+		return api.StatusErrorf(http.StatusForbidden, "%s is restricted", subject)
+	}
+
+	// Check project level permissions against the project list.
+	projectName := object.Project()
+	if slices.Contains(projectNames, projectName) {
+		return nil
+	}
+
+	// Also allow read-only access to inherited resources.
+	if object.Project() == api.ProjectDefaultName && entitlement == EntitlementCanView && slices.Contains([]ObjectType{ObjectTypeImage, ObjectTypeProfile, ObjectTypeStorageVolume, ObjectTypeStorageBucket, ObjectTypeNetwork, ObjectTypeNetworkZone}, object.Type()) {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "User does not have permission for project %q", projectName)
+}
+
+// restrictedPermissionChecker returns a permission checker for a user restricted to the given projects.
+func restrictedPermissionChecker(projectNames []string, details *requestDetails, entitlement Entitlement, objectType ObjectType) PermissionChecker {
+	allowFunc := func(b bool) func(Object) bool {
+		return func(Object) bool {
+			return b
+		}
+	}
+
+	// Check server level object types
+	switch objectType {
+	case ObjectTypeServer:
+		if entitlement == EntitlementCanView || entitlement == EntitlementCanViewResources || entitlement == EntitlementCanViewMetrics {
+			return allowFunc(true)
+		}
+
+		return allowFunc(false)
+	case ObjectTypeStoragePool, ObjectTypeCertificate:
+		if entitlement == EntitlementCanView {
+			return allowFunc(true)
+		}
+
+		return allowFunc(false)
+	}
+
+	// Error if user does not have access to the project (unless we're getting projects, where we want to filter the results).
+	if !details.IsAllProjectsRequest && !slices.Contains(projectNames, details.ProjectName) && objectType != ObjectTypeProject {
+		return allowFunc(false)
+	}
+
+	// Filter objects by project.
+	return func(object Object) bool {
+		// Allow if the project is in the allowed set.
+		if slices.Contains(projectNames, object.Project()) {
+			return true
+		}
+
+		// Also allow read-only access to inherited resources.
+		if object.Project() != api.ProjectDefaultName {
+			return false
+		}
+
+		if entitlement != EntitlementCanView {
+			return false
+		}
+
+		if !slices.Contains([]ObjectType{ObjectTypeImage, ObjectTypeProfile, ObjectTypeStorageVolume, ObjectTypeStorageBucket, ObjectTypeNetwork, ObjectTypeNetworkZone}, objectType) {
+			return false
+		}
+
+		return true
+	}
+}
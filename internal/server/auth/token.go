@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// tokenAuthorizer restricts the permissions of requests authenticated with an API token
+// before handing them over to the configured authorization driver.
+type tokenAuthorizer struct {
+	Authorizer
+}
+
+// WithTokenRestrictions wraps the authorizer so that the scope of API tokens gets enforced.
+func WithTokenRestrictions(authorizer Authorizer) Authorizer {
+	if authorizer == nil {
+		return nil
+	}
+
+	return &tokenAuthorizer{Authorizer: authorizer}
+}
+
+// token returns the API token used for the request, if any.
+func (t *tokenAuthorizer) token(r *http.Request) (*api.AuthToken, *requestDetails, error) {
+	if r == nil {
+		return nil, nil, nil
+	}
+
+	token, ok := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+	if !ok || token == nil {
+		return nil, nil, nil
+	}
+
+	details, err := (&commonAuthorizer{}).requestDetails(r)
+	if err != nil {
+		return nil, nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.authenticationProtocol() != api.AuthenticationMethodToken {
+		return nil, nil, nil
+	}
+
+	return token, details, nil
+}
+
+// CheckPermission returns an error if the API token used for the request doesn't grant the entitlement,
+// otherwise defers to the wrapped authorizer.
+func (t *tokenAuthorizer) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	token, details, err := t.token(r)
+	if err != nil {
+		return err
+	}
+
+	if token != nil {
+		if len(token.Entitlements) > 0 && !slices.Contains(token.Entitlements, string(entitlement)) {
+			return api.StatusErrorf(http.StatusForbidden, "Token doesn't grant %q", entitlement)
+		}
+
+		if token.Restricted {
+			err := checkRestrictedPermission("Token", token.Projects, details, object, entitlement)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return t.Authorizer.CheckPermission(ctx, r, object, entitlement)
+}
+
+// GetPermissionChecker returns a function that checks both the scope of the API token used for the request
+// and the wrapped authorizer.
+func (t *tokenAuthorizer) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	token, details, err := t.token(r)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := t.Authorizer.GetPermissionChecker(ctx, r, entitlement, objectType)
+	if err != nil || token == nil {
+		return checker, err
+	}
+
+	if len(token.Entitlements) > 0 && !slices.Contains(token.Entitlements, string(entitlement)) {
+		return func(Object) bool { return false }, nil
+	}
+
+	if !token.Restricted {
+		return checker, nil
+	}
+
+	restrictedChecker := restrictedPermissionChecker(token.Projects, details, entitlement, objectType)
+
+	return func(object Object) bool {
+		return restrictedChecker(object) && checker(object)
+	}, nil
+}
+
+// CheckTokenScope returns an error if the token would grant more than the caller's API token.
+// The token must be limited to the caller's entitlements and projects and can't outlive it.
+func CheckTokenScope(caller *api.AuthToken, token api.AuthTokenPut) error {
+	if len(caller.Entitlements) > 0 {
+		if len(token.Entitlements) == 0 {
+			return fmt.Errorf("Token must be limited to the entitlements of the token used for the request (%s)", strings.Join(caller.Entitlements, ", "))
+		}
+
+		for _, entitlement := range token.Entitlements {
+			if !slices.Contains(caller.Entitlements, entitlement) {
+				return fmt.Errorf("Entitlement %q isn't granted to the token used for the request", entitlement)
+			}
+		}
+	}
+
+	if caller.Restricted {
+		if !token.Restricted {
+			return errors.New("Token must be restricted as the token used for the request is")
+		}
+
+		for _, projectName := range token.Projects {
+			if !slices.Contains(caller.Projects, projectName) {
+				return fmt.Errorf("Project %q isn't allowed for the token used for the request", projectName)
+			}
+		}
+	}
+
+	if !caller.ExpiresAt.IsZero() && (token.ExpiresAt.IsZero() || token.ExpiresAt.After(caller.ExpiresAt)) {
+		return errors.New("Token can't expire after the token used for the request")
+	}
+
+	return nil
+}
+
+// ValidateEntitlement checks that the entitlement exists on at least one object type and can be granted to a token.
+// Relations such as "admin" or "viewer" are rejected as tokens are only checked against the entitlements they list.
+func ValidateEntitlement(entitlement string) error {
+	if slices.Contains([]Entitlement{relationAdmin, relationOperator, relationUser, relationViewer, relationAuthenticated}, Entitlement(entitlement)) {
+		return fmt.Errorf("%q is a relation rather than an entitlement, list the entitlements it should grant instead (for example can_view or can_edit)", entitlement)
+	}
+
+	for _, rules := range builtinModel {
+		_, ok := rules[Entitlement(entitlement)]
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown entitlement %q", entitlement)
+}
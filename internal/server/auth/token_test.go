@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/internal/server/certificate"
+	"github.com/lxc/incus/v7/internal/server/request"
+	"github.com/lxc/incus/v7/shared/api"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+)
+
+func TestCheckTokenScope(t *testing.T) {
+	expiry := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	unrestricted := &api.AuthToken{}
+	scoped := &api.AuthToken{AuthTokenPut: api.AuthTokenPut{
+		ExpiresAt:    expiry,
+		Restricted:   true,
+		Projects:     []string{"foo", "bar"},
+		Entitlements: []string{"can_view", "can_exec"},
+	}}
+
+	tests := []struct {
+		name   string
+		caller *api.AuthToken
+		token  api.AuthTokenPut
+		ok     bool
+	}{
+		{name: "unrestricted caller", caller: unrestricted, token: api.AuthTokenPut{}, ok: true},
+		{name: "same scope", caller: scoped, token: scoped.AuthTokenPut, ok: true},
+		{name: "narrower scope", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry.Add(-time.Hour), Restricted: true, Projects: []string{"foo"}, Entitlements: []string{"can_view"}}, ok: true},
+		{name: "no entitlements", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry, Restricted: true, Projects: []string{"foo"}}, ok: false},
+		{name: "other entitlement", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry, Restricted: true, Projects: []string{"foo"}, Entitlements: []string{"can_edit"}}, ok: false},
+		{name: "unrestricted", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry, Entitlements: []string{"can_view"}}, ok: false},
+		{name: "other project", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry, Restricted: true, Projects: []string{"baz"}, Entitlements: []string{"can_view"}}, ok: false},
+		{name: "no expiry", caller: scoped, token: api.AuthTokenPut{Restricted: true, Projects: []string{"foo"}, Entitlements: []string{"can_view"}}, ok: false},
+		{name: "later expiry", caller: scoped, token: api.AuthTokenPut{ExpiresAt: expiry.Add(time.Hour), Restricted: true, Projects: []string{"foo"}, Entitlements: []string{"can_view"}}, ok: false},
+	}
+
+	for _, test := range tests {
+		err := CheckTokenScope(test.caller, test.token)
+		if test.ok {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestValidateEntitlement(t *testing.T) {
+	assert.NoError(t, ValidateEntitlement("can_view"))
+	assert.NoError(t, ValidateEntitlement("can_exec"))
+	assert.Error(t, ValidateEntitlement("can_fly"))
+
+	// Relations would silently grant nothing.
+	for _, relation := range []string{"admin", "operator", "user", "viewer", "authenticated"} {
+		assert.Error(t, ValidateEntitlement(relation), relation)
+	}
+}
+
+func TestTokenAuthorizer(t *testing.T) {
+	store := &countingPermissionStore{permissions: []Permission{{Entitlement: relationAdmin, Object: ObjectServer()}}}
+	authorizer := WithTokenRestrictions(&Builtin{store: store})
+
+	newRequest := func(token *api.AuthToken) *http.Request {
+		ctx := context.WithValue(context.Background(), request.CtxUsername, "ci")
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodToken)
+		ctx = context.WithValue(ctx, request.CtxAuthToken, token)
+
+		return httptest.NewRequest(http.MethodGet, "/1.0/instances?project=foo", nil).WithContext(ctx)
+	}
+
+	// Unrestricted tokens defer to the authorizer.
+	r := newRequest(&api.AuthToken{})
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanEdit))
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectServer(), EntitlementCanEdit))
+
+	// Scoped tokens are limited to their entitlements and projects.
+	r = newRequest(&api.AuthToken{AuthTokenPut: api.AuthTokenPut{Restricted: true, Projects: []string{"foo"}, Entitlements: []string{"can_exec", "can_view"}}})
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanEdit))
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("bar", "c1"), EntitlementCanExec))
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectServer(), EntitlementCanEdit))
+
+	checker, err := authorizer.GetPermissionChecker(r.Context(), r, EntitlementCanView, ObjectTypeInstance)
+	assert.NoError(t, err)
+	assert.True(t, checker(ObjectInstance("foo", "c1")))
+	assert.False(t, checker(ObjectInstance("bar", "c1")))
+}
+
+func TestTokenAuthorizerTLS(t *testing.T) {
+	cert, _, err := localtls.GenerateMemCert(true, false)
+	require.NoError(t, err)
+
+	certificates := &certificate.Cache{}
+	certificates.SetCertificates([]*api.Certificate{
+		{Fingerprint: "admin", CertificatePut: api.CertificatePut{Type: api.CertificateTypeClient, Certificate: string(cert)}},
+		{Fingerprint: "restricted", CertificatePut: api.CertificatePut{Type: api.CertificateTypeClient, Certificate: string(cert), Restricted: true, Projects: []string{"foo"}}},
+	})
+
+	authorizer := WithTokenRestrictions(&TLS{certificates: certificates})
+
+	newRequest := func(token *api.AuthToken) *http.Request {
+		ctx := context.WithValue(context.Background(), request.CtxUsername, token.Name)
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodToken)
+		ctx = context.WithValue(ctx, request.CtxAuthToken, token)
+
+		return httptest.NewRequest(http.MethodGet, "/1.0/instances?project=foo", nil).WithContext(ctx)
+	}
+
+	// Unrestricted tokens get the rights of the certificate which created them.
+	r := newRequest(&api.AuthToken{Name: "admin", CreatorAuthenticationMethod: api.AuthenticationMethodTLS, CreatorIdentifier: "admin"})
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectServer(), EntitlementCanEdit))
+
+	r = newRequest(&api.AuthToken{Name: "ci", CreatorAuthenticationMethod: api.AuthenticationMethodTLS, CreatorIdentifier: "restricted"})
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanEdit))
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("bar", "c1"), EntitlementCanEdit))
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectServer(), EntitlementCanEdit))
+
+	checker, err := authorizer.GetPermissionChecker(r.Context(), r, EntitlementCanView, ObjectTypeInstance)
+	assert.NoError(t, err)
+	assert.True(t, checker(ObjectInstance("foo", "c1")))
+	assert.False(t, checker(ObjectInstance("bar", "c1")))
+
+	// Tokens lose their rights along with the certificate which created them.
+	r = newRequest(&api.AuthToken{Name: "ci", CreatorAuthenticationMethod: api.AuthenticationMethodTLS, CreatorIdentifier: "removed"})
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanView))
+
+	// Tokens without a known creator are rejected rather than treated as administrators.
+	r = newRequest(&api.AuthToken{Name: "ci"})
+	assert.Error(t, authorizer.CheckPermission(r.Context(), r, ObjectInstance("foo", "c1"), EntitlementCanView))
+
+	// Tokens created locally keep full access.
+	r = newRequest(&api.AuthToken{Name: "local", CreatorAuthenticationMethod: "unix", CreatorIdentifier: "root"})
+	assert.NoError(t, authorizer.CheckPermission(r.Context(), r, ObjectServer(), EntitlementCanEdit))
+}
@@ -2,9 +2,11 @@ package config
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"maps"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +17,9 @@ import (
 	"github.com/lxc/incus/v7/internal/server/config"
 	"github.com/lxc/incus/v7/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+	"github.com/lxc/incus/v7/shared/units"
+	"github.com/lxc/incus/v7/shared/util"
 	"github.com/lxc/incus/v7/shared/validate"
 )
 
@@ -157,6 +162,11 @@ func (c *Config) ClusterRebalanceInterval() int64 {
 	return c.m.GetInt64("cluster.rebalance.interval")
 }
 
+// ClusterRebalanceScriptlet returns the cluster re-balancing scriptlet source code.
+func (c *Config) ClusterRebalanceScriptlet() string {
+	return c.m.GetString("cluster.rebalance.scriptlet")
+}
+
 // ClusterRebalanceThreshold returns load difference between most and least busy server
 // needed to trigger a migration.
 func (c *Config) ClusterRebalanceThreshold() int64 {
@@ -225,6 +235,43 @@ func (c *Config) ImagesRemoteCacheExpiryDays() int64 {
 	return c.m.GetInt64("images.remote_cache_expiry")
 }
 
+// ImagesRegistryMirrors returns the mirrors to use for the given OCI registry.
+func (c *Config) ImagesRegistryMirrors(server string) []string {
+	registry := server
+	uri, err := url.Parse(server)
+	if err == nil && uri.Host != "" {
+		registry = uri.Host
+	}
+
+	mirrors := []string{}
+	for _, entry := range util.SplitNTrimSpace(c.m.GetString("images.registry_mirrors"), ",", -1, true) {
+		host, mirror, _ := strings.Cut(entry, "=")
+		if host == registry {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+
+	return mirrors
+}
+
+// ImagesVerifyKeys returns the public keys trusted to sign images.
+func (c *Config) ImagesVerifyKeys() []crypto.PublicKey {
+	value := c.m.GetString("images.verify.keys")
+	if value == "" {
+		return nil
+	}
+
+	// The value was validated when set.
+	keys, _ := localtls.ParsePublicKeys([]byte(value))
+
+	return keys
+}
+
+// ImagesVerifyRequired returns whether all downloaded images must be signed.
+func (c *Config) ImagesVerifyRequired() bool {
+	return c.m.GetBool("images.verify.required")
+}
+
 // InstancesNICHostname returns hostname mode to use for instance NICs.
 func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
@@ -235,11 +282,29 @@ func (c *Config) InstancesPlacementScriptlet() string {
 	return c.m.GetString("instances.placement.scriptlet")
 }
 
+// Audit returns whether the audit log is enabled along with its maximum size and number of rotated files.
+func (c *Config) Audit() (bool, int64, int) {
+	// The error can be ignored as the value is validated elsewhere.
+	maxSize, _ := units.ParseByteSizeString(c.m.GetString("audit.max_size"))
+
+	return c.m.GetBool("audit.enabled"), maxSize, int(c.m.GetInt64("audit.max_files"))
+}
+
+// AuthorizationBuiltin returns whether the built-in authorization driver is enabled.
+func (c *Config) AuthorizationBuiltin() bool {
+	return c.m.GetBool("authorization.builtin")
+}
+
 // AuthorizationScriptlet returns the authorization scriptlet source code.
 func (c *Config) AuthorizationScriptlet() string {
 	return c.m.GetString("authorization.scriptlet")
 }
 
+// AuthorizationSelfServiceMaxProjects returns the maximum number of projects each identity can create through self-service.
+func (c *Config) AuthorizationSelfServiceMaxProjects() int64 {
+	return c.m.GetInt64("authorization.self_service.max_projects")
+}
+
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
 	return c.m.GetBool("instances.lxcfs.per_instance")
@@ -326,6 +391,16 @@ func (c *Config) OIDCServer() (string, string, string, string, string) {
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
 }
 
+// ClusterHealingFencing returns the fencing method used for offline members and the fencing command.
+func (c *Config) ClusterHealingFencing() (string, string) {
+	return c.m.GetString("cluster.healing_fencing"), c.m.GetString("cluster.healing_fencing.command")
+}
+
+// ClusterHealingFencingScriptlet returns the cluster fencing scriptlet source code.
+func (c *Config) ClusterHealingFencingScriptlet() string {
+	return c.m.GetString("cluster.healing_fencing.scriptlet")
+}
+
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
 // number of seconds after which an offline node will be evacuated automatically. If the config key
 // is set but its value is lower than cluster.offline_threshold it returns
@@ -407,6 +482,20 @@ func (c *Config) LoggingCommonConfig(loggerName string) (string, string, string,
 	return c.m.GetString(lifecycleProjectsKey), c.m.GetString(lifecycleTypesKey), c.m.GetString(loggingLevelKey), c.m.GetString(typesKey)
 }
 
+// LoggingFilterConfigured returns whether any of the event filters of the logger is set to a non-default value.
+func (c *Config) LoggingFilterConfigured(loggerName string) bool {
+	values := c.m.Dump()
+
+	for _, key := range []string{"lifecycle.projects", "lifecycle.types", "logging.level", "types"} {
+		_, ok := values[fmt.Sprintf("logging.%s.%s", loggerName, key)]
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 // LoggingConfigForSyslog returns the logging configuration for the syslog logger type.
 func (c *Config) LoggingConfigForSyslog(loggerName string) (string, string) {
 	prefix := fmt.Sprintf("logging.%s", loggerName)
@@ -564,6 +653,42 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Port and interface for HTTP server (used by HTTP-01)
 	"acme.http.port": {Default: ":80", Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+	// gendoc:generate(entity=server, group=audit, key=audit.enabled)
+	// When enabled, every mutating API request is recorded in a hash-chained audit log stored locally on each server.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to record API requests in the audit log
+	"audit.enabled": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.max_files)
+	// Number of rotated audit log files to keep. At least one is kept so the hash chain can be restored on restart.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `10`
+	//  shortdesc: Number of rotated audit log files to keep
+	"audit.max_files": {Type: config.Int64, Default: "10", Validator: validate.Optional(validate.IsInRange(1, 4294967295))},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.max_size)
+	// The audit log is rotated once it reaches this size. When set to `0`, the audit log is never rotated.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `100MiB`
+	//  shortdesc: Size at which to rotate the audit log
+	"audit.max_size": {Default: "100MiB", Validator: validate.Optional(validate.IsSize)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.builtin)
+	// When enabled, identities, groups and permissions stored in the cluster database are used to authorize requests.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use the built-in authorization driver
+	"authorization.builtin": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.scriptlet)
 	// When using scriptlet-based authorization, this option stores the scriptlet.
 	// ---
@@ -572,6 +697,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Authorization scriptlet
 	"authorization.scriptlet": {Validator: validate.Optional(scriptletLoad.AuthorizationValidate)},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.self_service.max_projects)
+	// Identities allowed to create projects but not to administer the server can only create projects from self-service project templates.
+	// See {ref}`projects-templates` for more information.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Maximum number of projects each identity can create through self-service
+	"authorization.self_service.max_projects": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsUint32)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=backups.compression_algorithm)
 	// Possible values are `bzip2`, `gzip`, `lz4`, `lzma`, `xz`, `zstd` or `none`.
 	// ---
@@ -600,6 +735,36 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Number of cluster members that replicate an image
 	"cluster.images_minimal_replica": {Type: config.Int64, Default: "3", Validator: imageMinimalReplicaValidator},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.healing_fencing)
+	// Possible values are `none`, `command` or `scriptlet`.
+	// A member is only considered dead if it also fails to respond to ICMP packets.
+	// Unless set to `none`, the leader then fences the member before restarting its instances elsewhere.
+	// See {ref}`cluster-automatic-evacuation-fencing` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `none`
+	//  shortdesc: How to fence an offline cluster member before healing
+	"cluster.healing_fencing": {Default: "none", Validator: validate.Optional(validate.IsOneOf("none", "command", "scriptlet"))},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.healing_fencing.command)
+	// The command is run through `sh -c` on the leader, with the `INCUS_MEMBER_NAME` and
+	// `INCUS_MEMBER_ADDRESS` environment variables set. It must exit successfully once the member is powered off.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Command fencing an offline cluster member
+	"cluster.healing_fencing.command": {},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.healing_fencing.scriptlet)
+	// When using scriptlet-based fencing, this option stores the scriptlet.
+	// See {ref}`cluster-automatic-evacuation-fencing` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Scriptlet deciding whether an offline cluster member is fenced
+	"cluster.healing_fencing.scriptlet": {Validator: validate.Optional(scriptletLoad.ClusterFencingValidate)},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.healing_threshold)
 	// Specify the number of seconds after which an offline cluster member is to be evacuated.
 	// To disable evacuating offline members, set this option to `0`.
@@ -666,6 +831,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How often (in minutes) to consider re-balancing things. 0 to disable (default)
 	"cluster.rebalance.interval": {Type: config.Int64, Default: "0"},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.scriptlet)
+	// When using custom re-balancing logic, this option stores the scriptlet.
+	// See {ref}`cluster-automatic-balancing-scriptlet` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Scriptlet scoring cluster members and selecting the instances to move during re-balancing
+	"cluster.rebalance.scriptlet": {Validator: validate.Optional(scriptletLoad.ClusterRebalanceValidate)},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.threshold)
 	//
 	// ---
@@ -848,6 +1022,34 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: When an unused cached remote image is flushed
 	"images.remote_cache_expiry": {Type: config.Int64, Default: "10"},
 
+	// gendoc:generate(entity=server, group=images, key=images.registry_mirrors)
+	// Specify a comma-separated list of `REGISTRY=URL` entries, for example `docker.io=https://mirror.example.net`.
+	// When retrieving OCI images, the mirrors of the image's registry are tried in order before the registry itself.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Mirrors to use for OCI registries
+	"images.registry_mirrors": {Validator: validate.Optional(registryMirrorsValidator)},
+
+	// gendoc:generate(entity=server, group=images, key=images.verify.keys)
+	// Specify one or more PEM encoded ECDSA, Ed25519 or RSA public keys (or certificates).
+	// Images from simplestreams and OCI remotes must be signed by one of them when signature verification is required.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Public keys trusted to sign images
+	"images.verify.keys": {Validator: validate.Optional(verifyKeysValidator)},
+
+	// gendoc:generate(entity=server, group=images, key=images.verify.required)
+	// When enabled, images downloaded from simplestreams and OCI remotes are rejected unless signed by one of the keys in {config:option}`server-images:images.verify.keys`.
+	// Images can't be downloaded from other remotes or URLs.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether downloaded images must be signed
+	"images.verify.required": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=instances.lxcfs.per_instance)
 	// LXCFS is used to provide overlays for common `/proc` and `/sys`
 	// files which reflect the resource limits applied to the container.
@@ -1138,6 +1340,28 @@ func expiryValidator(value string) error {
 	return nil
 }
 
+func registryMirrorsValidator(value string) error {
+	for _, entry := range util.SplitNTrimSpace(value, ",", -1, true) {
+		host, mirror, ok := strings.Cut(entry, "=")
+		if !ok || host == "" {
+			return fmt.Errorf("Invalid mirror %q, must be REGISTRY=URL", entry)
+		}
+
+		err := validate.IsRequestURL(mirror)
+		if err != nil {
+			return fmt.Errorf("Invalid mirror URL for %q: %w", host, err)
+		}
+	}
+
+	return nil
+}
+
+func verifyKeysValidator(value string) error {
+	_, err := localtls.ParsePublicKeys([]byte(value))
+
+	return err
+}
+
 func offlineThresholdDefault() string {
 	return strconv.Itoa(db.DefaultOfflineThreshold)
 }
@@ -77,6 +77,18 @@ func TestConfigLoad_MaxVotersValidator(t *testing.T) {
 	require.EqualError(t, err, "cannot set 'cluster.max_voters' to '4': Value must be an odd number equal to or higher than 3")
 }
 
+// At least one rotated audit log file must be kept.
+func TestConfigLoad_AuditMaxFilesValidator(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	_, err = config.Patch(map[string]string{"audit.max_files": "0"})
+	require.EqualError(t, err, "cannot set 'audit.max_files' to '0': Value isn't within valid range. Must be between 1 and 4294967295")
+}
+
 // If some previously set values are missing from the ones passed to Replace(),
 // they are deleted from the configuration.
 func TestConfig_ReplaceDeleteValues(t *testing.T) {
@@ -121,3 +133,24 @@ func TestConfig_PatchKeepsValues(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, map[string]string{"core.proxy_http": "foo.bar"}, values)
 }
+
+// A logger only has event filters once one of them is set to a non-default value.
+func TestConfig_LoggingFilterConfigured(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	_, err = config.Patch(map[string]string{
+		"logging.hook01.target.type":    "webhook",
+		"logging.hook01.target.address": "https://hook01.int.example.net",
+		"logging.hook02.target.type":    "webhook",
+		"logging.hook02.target.address": "https://hook02.int.example.net",
+		"logging.hook02.types":          "lifecycle",
+	})
+	require.NoError(t, err)
+
+	assert.False(t, config.LoggingFilterConfigured("hook01"))
+	assert.True(t, config.LoggingFilterConfigured("hook02"))
+}
@@ -137,6 +137,30 @@ func triggerUpdate(s *state.State) error {
 	return nil
 }
 
+// TriggerUpgrade starts upgrading this member as part of a rolling cluster upgrade, either through IncusOS
+// or by running the INCUS_CLUSTER_UPDATE executable. The executable runs in the background as it's
+// expected to restart the daemon.
+func TriggerUpgrade(s *state.State) error {
+	if s.OS.IncusOS != nil {
+		return s.OS.IncusOS.TriggerSystemUpdateCheck()
+	}
+
+	updateExecutable := os.Getenv("INCUS_CLUSTER_UPDATE")
+	if updateExecutable == "" {
+		return errors.New("Member isn't running IncusOS and has no INCUS_CLUSTER_UPDATE variable set")
+	}
+
+	go func() {
+		logger.Info("Triggering cluster upgrade", logger.Ctx{"updateExecutable": updateExecutable})
+		_, err := subprocess.RunCommand(updateExecutable)
+		if err != nil {
+			logger.Error("Triggering cluster upgrade failed", logger.Ctx{"err": err})
+		}
+	}()
+
+	return nil
+}
+
 // UpgradeMembersWithoutRole assigns the Spare raft role to all cluster members that are not currently part of the
 // raft configuration. It's used for upgrading a cluster from a version without roles support.
 func UpgradeMembersWithoutRole(gateway *Gateway, members []db.NodeInfo) error {
@@ -0,0 +1,442 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v7/internal/server/db/query"
+	"github.com/lxc/incus/v7/internal/version"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// GetAuthGroups returns all the authorization groups along with their permissions.
+func (c *ClusterTx) GetAuthGroups(ctx context.Context) ([]api.AuthGroup, error) {
+	groups := []api.AuthGroup{}
+	groupIndex := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, name, description FROM auth_groups ORDER BY name", func(scan func(dest ...any) error) error {
+		var id int64
+		group := api.AuthGroup{}
+		group.Permissions = []api.Permission{}
+		group.UsedBy = []string{}
+
+		err := scan(&id, &group.Name, &group.Description)
+		if err != nil {
+			return err
+		}
+
+		groupIndex[id] = len(groups)
+		groups = append(groups, group)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Scan(ctx, c.tx, "SELECT auth_group_id, entitlement, object FROM auth_groups_permissions ORDER BY object, entitlement", func(scan func(dest ...any) error) error {
+		var groupID int64
+		permission := api.Permission{}
+
+		err := scan(&groupID, &permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndex[groupID]
+		if ok {
+			groups[i].Permissions = append(groups[i].Permissions, permission)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `
+SELECT identities_auth_groups.auth_group_id, identities.authentication_method, identities.identifier
+FROM identities_auth_groups
+JOIN identities ON identities.id = identities_auth_groups.identity_id
+ORDER BY identities.authentication_method, identities.identifier`
+
+	err = query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		var groupID int64
+		var authenticationMethod, identifier string
+
+		err := scan(&groupID, &authenticationMethod, &identifier)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndex[groupID]
+		if ok {
+			groups[i].UsedBy = append(groups[i].UsedBy, api.NewURL().Path(version.APIVersion, "auth", "identities", authenticationMethod, identifier).String())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns the authorization group with the given name.
+func (c *ClusterTx) GetAuthGroup(ctx context.Context, name string) (*api.AuthGroup, error) {
+	groups, err := c.GetAuthGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.Name == name {
+			return &group, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Authorization group not found")
+}
+
+// getAuthGroupID returns the ID of the authorization group with the given name.
+func (c *ClusterTx) getAuthGroupID(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM auth_groups WHERE name = ?", name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Authorization group %q not found", name)
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (c *ClusterTx) CreateAuthGroup(ctx context.Context, group api.AuthGroupsPost) error {
+	_, err := c.getAuthGroupID(ctx, group.Name)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", group.Name)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_groups (name, description) VALUES (?, ?)", group.Name, group.Description)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return c.updateAuthGroupPermissions(ctx, id, group.Permissions)
+}
+
+// UpdateAuthGroup updates the description and permissions of an authorization group.
+func (c *ClusterTx) UpdateAuthGroup(ctx context.Context, name string, group api.AuthGroupPut) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET description = ? WHERE id = ?", group.Description, id)
+	if err != nil {
+		return err
+	}
+
+	return c.updateAuthGroupPermissions(ctx, id, group.Permissions)
+}
+
+// updateAuthGroupPermissions replaces the permissions of an authorization group.
+func (c *ClusterTx) updateAuthGroupPermissions(ctx context.Context, id int64, permissions []api.Permission) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM auth_groups_permissions WHERE auth_group_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		_, err = c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO auth_groups_permissions (auth_group_id, entitlement, object) VALUES (?, ?, ?)", id, permission.Entitlement, permission.Object)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames an authorization group.
+func (c *ClusterTx) RenameAuthGroup(ctx context.Context, name string, newName string) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.getAuthGroupID(ctx, newName)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", newName)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET name = ? WHERE id = ?", newName, id)
+	return err
+}
+
+// DeleteAuthGroup deletes an authorization group.
+func (c *ClusterTx) DeleteAuthGroup(ctx context.Context, name string) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.DeleteObject(c.tx, "auth_groups", id)
+	return err
+}
+
+// GetIdentities returns all the identities along with their groups.
+func (c *ClusterTx) GetIdentities(ctx context.Context) ([]api.Identity, error) {
+	identities := []api.Identity{}
+	identityIndex := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, authentication_method, identifier, description FROM identities ORDER BY authentication_method, identifier", func(scan func(dest ...any) error) error {
+		var id int64
+		identity := api.Identity{}
+		identity.Groups = []string{}
+
+		err := scan(&id, &identity.AuthenticationMethod, &identity.Identifier, &identity.Description)
+		if err != nil {
+			return err
+		}
+
+		identityIndex[id] = len(identities)
+		identities = append(identities, identity)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `
+SELECT identities_auth_groups.identity_id, auth_groups.name
+FROM identities_auth_groups
+JOIN auth_groups ON auth_groups.id = identities_auth_groups.auth_group_id
+ORDER BY auth_groups.name`
+
+	err = query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		var identityID int64
+		var groupName string
+
+		err := scan(&identityID, &groupName)
+		if err != nil {
+			return err
+		}
+
+		i, ok := identityIndex[identityID]
+		if ok {
+			identities[i].Groups = append(identities[i].Groups, groupName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetIdentity returns the identity with the given authentication method and identifier.
+func (c *ClusterTx) GetIdentity(ctx context.Context, authenticationMethod string, identifier string) (*api.Identity, error) {
+	identities, err := c.GetIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range identities {
+		if identity.AuthenticationMethod == authenticationMethod && identity.Identifier == identifier {
+			return &identity, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+}
+
+// getIdentityID returns the ID of the identity with the given authentication method and identifier.
+func (c *ClusterTx) getIdentityID(ctx context.Context, authenticationMethod string, identifier string) (int64, error) {
+	var id int64
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM identities WHERE authentication_method = ? AND identifier = ?", authenticationMethod, identifier).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Identity %q not found", fmt.Sprintf("%s/%s", authenticationMethod, identifier))
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// CreateIdentity creates a new identity.
+func (c *ClusterTx) CreateIdentity(ctx context.Context, identity api.IdentitiesPost) error {
+	_, err := c.getIdentityID(ctx, identity.AuthenticationMethod, identity.Identifier)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Identity %q already exists", fmt.Sprintf("%s/%s", identity.AuthenticationMethod, identity.Identifier))
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO identities (authentication_method, identifier, description) VALUES (?, ?, ?)", identity.AuthenticationMethod, identity.Identifier, identity.Description)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return c.updateIdentityGroups(ctx, id, identity.Groups)
+}
+
+// UpdateIdentity updates the description and groups of an identity.
+func (c *ClusterTx) UpdateIdentity(ctx context.Context, authenticationMethod string, identifier string, identity api.IdentityPut) error {
+	id, err := c.getIdentityID(ctx, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE identities SET description = ? WHERE id = ?", identity.Description, id)
+	if err != nil {
+		return err
+	}
+
+	return c.updateIdentityGroups(ctx, id, identity.Groups)
+}
+
+// updateIdentityGroups replaces the groups of an identity.
+func (c *ClusterTx) updateIdentityGroups(ctx context.Context, id int64, groups []string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM identities_auth_groups WHERE identity_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		groupID, err := c.getAuthGroupID(ctx, group)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO identities_auth_groups (identity_id, auth_group_id) VALUES (?, ?)", id, groupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteIdentity deletes an identity.
+func (c *ClusterTx) DeleteIdentity(ctx context.Context, authenticationMethod string, identifier string) error {
+	id, err := c.getIdentityID(ctx, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.DeleteObject(c.tx, "identities", id)
+	return err
+}
+
+// GetIdentityPermissions returns the permissions granted to an identity through its groups.
+// The returned boolean indicates whether the identity exists.
+func (c *ClusterTx) GetIdentityPermissions(ctx context.Context, authenticationMethod string, identifier string) ([]api.Permission, bool, error) {
+	_, err := c.getIdentityID(ctx, authenticationMethod, identifier)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	stmt := `
+SELECT DISTINCT auth_groups_permissions.entitlement, auth_groups_permissions.object
+FROM auth_groups_permissions
+JOIN identities_auth_groups ON identities_auth_groups.auth_group_id = auth_groups_permissions.auth_group_id
+JOIN identities ON identities.id = identities_auth_groups.identity_id
+WHERE identities.authentication_method = ? AND identities.identifier = ?`
+
+	permissions := []api.Permission{}
+	err = query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		permission := api.Permission{}
+
+		err := scan(&permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, authenticationMethod, identifier)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return permissions, true, nil
+}
+
+// RenameAuthObjects updates the permissions referring to an object, or any object below it, after it was renamed.
+func (c *ClusterTx) RenameAuthObjects(ctx context.Context, oldObject string, newObject string) error {
+	objects, err := query.SelectStrings(ctx, c.tx, "SELECT DISTINCT object FROM auth_groups_permissions WHERE object = ? OR object LIKE ? ESCAPE '\\'", oldObject, likeEscape(oldObject)+"/%")
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		renamed := newObject + object[len(oldObject):]
+
+		// Drop the permissions which would now be duplicates.
+		_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups_permissions WHERE object = ? AND EXISTS (SELECT 1 FROM auth_groups_permissions AS p WHERE p.auth_group_id = auth_groups_permissions.auth_group_id AND p.entitlement = auth_groups_permissions.entitlement AND p.object = ?)", object, renamed)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups_permissions SET object = ? WHERE object = ?", renamed, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAuthObjects deletes the permissions referring to an object, or any object below it, after it was deleted.
+func (c *ClusterTx) DeleteAuthObjects(ctx context.Context, object string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM auth_groups_permissions WHERE object = ? OR object LIKE ? ESCAPE '\\'", object, likeEscape(object)+"/%")
+	return err
+}
+
+// likeEscape escapes the wildcard characters of a LIKE pattern.
+func likeEscape(s string) string {
+	escaped := make([]rune, 0, len(s))
+	for _, r := range s {
+		if slices.Contains([]rune{'\\', '%', '_'}, r) {
+			escaped = append(escaped, '\\')
+		}
+
+		escaped = append(escaped, r)
+	}
+
+	return string(escaped)
+}
@@ -0,0 +1,213 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db/query"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// GetAuthTokens returns all the API tokens.
+func (c *ClusterTx) GetAuthTokens(ctx context.Context) ([]api.AuthToken, error) {
+	return c.getAuthTokens(ctx, "", nil)
+}
+
+// GetAuthToken returns the API token with the given name.
+func (c *ClusterTx) GetAuthToken(ctx context.Context, name string) (*api.AuthToken, error) {
+	tokens, err := c.getAuthTokens(ctx, "WHERE auth_tokens.name = ?", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) != 1 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "API token not found")
+	}
+
+	return &tokens[0], nil
+}
+
+// GetAuthTokenByHash returns the API token with the given secret hash.
+func (c *ClusterTx) GetAuthTokenByHash(ctx context.Context, hash string) (*api.AuthToken, error) {
+	tokens, err := c.getAuthTokens(ctx, "WHERE auth_tokens.hash = ?", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) != 1 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "API token not found")
+	}
+
+	return &tokens[0], nil
+}
+
+func (c *ClusterTx) getAuthTokens(ctx context.Context, where string, args ...any) ([]api.AuthToken, error) {
+	tokens := []api.AuthToken{}
+	tokenIndex := map[int64]int{}
+
+	stmt := "SELECT id, name, description, creation_date, expiry_date, restricted, entitlements, creator_authentication_method, creator_identifier FROM auth_tokens " + where + " ORDER BY name"
+
+	err := query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		var id int64
+		var expiryDate sql.NullTime
+		var entitlements string
+		token := api.AuthToken{}
+		token.Projects = []string{}
+		token.Entitlements = []string{}
+
+		err := scan(&id, &token.Name, &token.Description, &token.CreatedAt, &expiryDate, &token.Restricted, &entitlements, &token.CreatorAuthenticationMethod, &token.CreatorIdentifier)
+		if err != nil {
+			return err
+		}
+
+		token.ExpiresAt = expiryDate.Time // Convert nulls to zero.
+
+		if entitlements != "" {
+			token.Entitlements = strings.Split(entitlements, ",")
+		}
+
+		tokenIndex[id] = len(tokens)
+		tokens = append(tokens, token)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt = `
+SELECT auth_tokens_projects.auth_token_id, projects.name
+FROM auth_tokens_projects
+JOIN projects ON projects.id = auth_tokens_projects.project_id
+ORDER BY projects.name`
+
+	err = query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		var tokenID int64
+		var projectName string
+
+		err := scan(&tokenID, &projectName)
+		if err != nil {
+			return err
+		}
+
+		i, ok := tokenIndex[tokenID]
+		if ok {
+			tokens[i].Projects = append(tokens[i].Projects, projectName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// getAuthTokenID returns the ID of the API token with the given name.
+func (c *ClusterTx) getAuthTokenID(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM auth_tokens WHERE name = ?", name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "API token %q not found", name)
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// CreateAuthToken creates a new API token, only storing the hash of its secret.
+// The identity creating the token is recorded as some authorization drivers grant the token its rights.
+func (c *ClusterTx) CreateAuthToken(ctx context.Context, token api.AuthTokensPost, hash string, creatorAuthenticationMethod string, creatorIdentifier string) error {
+	_, err := c.getAuthTokenID(ctx, token.Name)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "API token %q already exists", token.Name)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_tokens (name, description, hash, creation_date, expiry_date, restricted, entitlements, creator_authentication_method, creator_identifier) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		token.Name, token.Description, hash, time.Now().UTC(), authTokenExpiry(token.ExpiresAt), token.Restricted, strings.Join(token.Entitlements, ","), creatorAuthenticationMethod, creatorIdentifier)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return c.updateAuthTokenProjects(ctx, id, token.Projects)
+}
+
+// UpdateAuthToken updates the modifiable fields of an API token.
+func (c *ClusterTx) UpdateAuthToken(ctx context.Context, name string, token api.AuthTokenPut) error {
+	id, err := c.getAuthTokenID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_tokens SET description = ?, expiry_date = ?, restricted = ?, entitlements = ? WHERE id = ?",
+		token.Description, authTokenExpiry(token.ExpiresAt), token.Restricted, strings.Join(token.Entitlements, ","), id)
+	if err != nil {
+		return err
+	}
+
+	return c.updateAuthTokenProjects(ctx, id, token.Projects)
+}
+
+// updateAuthTokenProjects replaces the projects of an API token.
+func (c *ClusterTx) updateAuthTokenProjects(ctx context.Context, id int64, projects []string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM auth_tokens_projects WHERE auth_token_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	for _, projectName := range projects {
+		var projectID int64
+		err = c.tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE name = ?", projectName).Scan(&projectID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return api.StatusErrorf(http.StatusBadRequest, "Project %q not found", projectName)
+			}
+
+			return err
+		}
+
+		_, err = c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO auth_tokens_projects (auth_token_id, project_id) VALUES (?, ?)", id, projectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAuthToken deletes (revokes) an API token.
+func (c *ClusterTx) DeleteAuthToken(ctx context.Context, name string) error {
+	id, err := c.getAuthTokenID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_tokens WHERE id = ?", id)
+	return err
+}
+
+// authTokenExpiry converts a zero expiry time to NULL.
+func authTokenExpiry(expiresAt time.Time) any {
+	if expiresAt.IsZero() {
+		return nil
+	}
+
+	return expiresAt.UTC()
+}
@@ -246,6 +246,16 @@ func (c *ClusterTx) DeleteInstanceBackup(ctx context.Context, name string) error
 	return nil
 }
 
+// UpdateInstanceBackupSize records the size of the tarball of the instance backup with the given name.
+func (c *ClusterTx) UpdateInstanceBackupSize(ctx context.Context, name string, size int64) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE instances_backups SET size = ? WHERE name = ?", size, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RenameInstanceBackup renames an instance backup from the given current name
 // to the new one.
 func (c *ClusterTx) RenameInstanceBackup(ctx context.Context, oldName, newName string) error {
@@ -554,6 +564,16 @@ WHERE backups.id=?
 	return args, nil
 }
 
+// UpdateStoragePoolVolumeBackupSize records the size of the tarball of the volume backup with the given name.
+func (c *ClusterTx) UpdateStoragePoolVolumeBackupSize(ctx context.Context, name string, size int64) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE storage_volumes_backups SET size = ? WHERE name = ?", size, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RenameVolumeBackup renames a volume backup from the given current name
 // to the new one.
 func (c *ClusterTx) RenameVolumeBackup(ctx context.Context, oldName, newName string) error {
@@ -777,6 +797,16 @@ FROM storage_buckets_backups`
 	return backups, nil
 }
 
+// UpdateStoragePoolBucketBackupSize records the size of the tarball of the bucket backup with the given name.
+func (c *ClusterTx) UpdateStoragePoolBucketBackupSize(ctx context.Context, name string, size int64) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE storage_buckets_backups SET size = ? WHERE name = ?", size, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RenameBucketBackup renames a bucket backup from the given current name to the new one.
 func (c *ClusterTx) RenameBucketBackup(ctx context.Context, oldName, newName string) error {
 	str := "UPDATE storage_buckets_backups SET name = ? WHERE name = ?"
@@ -6,6 +6,41 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE "auth_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+CREATE TABLE "auth_groups_permissions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+CREATE TABLE "auth_tokens" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    hash TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME,
+    restricted INTEGER NOT NULL DEFAULT 0,
+    entitlements TEXT NOT NULL DEFAULT "",
+    creator_authentication_method TEXT NOT NULL DEFAULT "",
+    creator_identifier TEXT NOT NULL DEFAULT "",
+    UNIQUE (name),
+    UNIQUE (hash)
+);
+CREATE TABLE "auth_tokens_projects" (
+    auth_token_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_token_id) REFERENCES "auth_tokens" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (auth_token_id, project_id)
+);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -43,6 +78,20 @@ CREATE TABLE config (
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE "identities" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (authentication_method, identifier)
+);
+CREATE TABLE "identities_auth_groups" (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (identity_id, auth_group_id),
+    FOREIGN KEY (identity_id) REFERENCES "identities" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
 CREATE TABLE "images" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -131,6 +180,7 @@ CREATE TABLE "instances_backups" (
     container_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
     root_only INTEGER NOT NULL DEFAULT 0,
+    size INTEGER NOT NULL DEFAULT 0,
     FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
     UNIQUE (instance_id, name)
 );
@@ -485,6 +535,36 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE "projects_owners" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    UNIQUE (project_id),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE TABLE "projects_templates" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    self_service INTEGER NOT NULL DEFAULT 0,
+    blueprint TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+CREATE TABLE "projects_usage" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    resolution INTEGER NOT NULL,
+    start DATETIME NOT NULL,
+    cpu_seconds REAL NOT NULL DEFAULT 0,
+    memory_byte_hours REAL NOT NULL DEFAULT 0,
+    disk_byte_hours REAL NOT NULL DEFAULT 0,
+    network_bytes_received INTEGER NOT NULL DEFAULT 0,
+    network_bytes_sent INTEGER NOT NULL DEFAULT 0,
+    instance_hours REAL NOT NULL DEFAULT 0,
+    UNIQUE (project_id, resolution, start),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
 CREATE TABLE "storage_buckets" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -503,6 +583,7 @@ CREATE TABLE "storage_buckets_backups" (
     name VARCHAR(255) NOT NULL,
     creation_date DATETIME,
     expiry_date DATETIME,
+    size INTEGER NOT NULL DEFAULT 0,
     FOREIGN KEY (storage_bucket_id) REFERENCES "storage_buckets" (id) ON DELETE CASCADE,
     UNIQUE (storage_bucket_id, name)
 );
@@ -610,6 +691,7 @@ CREATE TABLE "storage_volumes_backups" (
     expiry_date DATETIME,
     volume_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    size INTEGER NOT NULL DEFAULT 0,
     FOREIGN KEY (storage_volume_id) REFERENCES "storage_volumes" (id) ON DELETE CASCADE,
     UNIQUE (storage_volume_id, name)
 );
@@ -675,5 +757,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (77, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (83, strftime("%s"))
 `
@@ -114,6 +114,141 @@ var updates = map[int]schema.Update{
 	75: updateFromV74,
 	76: updateFromV75,
 	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+}
+
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+ALTER TABLE auth_tokens ADD COLUMN creator_authentication_method TEXT NOT NULL DEFAULT "";
+ALTER TABLE auth_tokens ADD COLUMN creator_identifier TEXT NOT NULL DEFAULT "";
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "projects_owners" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    UNIQUE (project_id),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+
+CREATE TABLE "projects_templates" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    self_service INTEGER NOT NULL DEFAULT 0,
+    blueprint TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+ALTER TABLE instances_backups ADD COLUMN size INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE storage_volumes_backups ADD COLUMN size INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE storage_buckets_backups ADD COLUMN size INTEGER NOT NULL DEFAULT 0;
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "projects_usage" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    resolution INTEGER NOT NULL,
+    start DATETIME NOT NULL,
+    cpu_seconds REAL NOT NULL DEFAULT 0,
+    memory_byte_hours REAL NOT NULL DEFAULT 0,
+    disk_byte_hours REAL NOT NULL DEFAULT 0,
+    network_bytes_received INTEGER NOT NULL DEFAULT 0,
+    network_bytes_sent INTEGER NOT NULL DEFAULT 0,
+    instance_hours REAL NOT NULL DEFAULT 0,
+    UNIQUE (project_id, resolution, start),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "auth_tokens" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    hash TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME,
+    restricted INTEGER NOT NULL DEFAULT 0,
+    entitlements TEXT NOT NULL DEFAULT "",
+    UNIQUE (name),
+    UNIQUE (hash)
+);
+
+CREATE TABLE "auth_tokens_projects" (
+    auth_token_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_token_id) REFERENCES "auth_tokens" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (auth_token_id, project_id)
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
+}
+
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	stmts := `
+CREATE TABLE "auth_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+
+CREATE TABLE "auth_groups_permissions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+
+CREATE TABLE "identities" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (authentication_method, identifier)
+);
+
+CREATE TABLE "identities_auth_groups" (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (identity_id, auth_group_id),
+    FOREIGN KEY (identity_id) REFERENCES "identities" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(stmts)
+	return err
 }
 
 func updateFromV76(ctx context.Context, tx *sql.Tx) error {
@@ -80,6 +80,8 @@ const (
 	BucketBackupRename
 	BucketBackupRestore
 	VolumeRebuild
+	ClusterRebalance
+	ClusterUpgrade
 )
 
 // Description return a human-readable description of the operation type.
@@ -205,6 +207,10 @@ func (t Type) Description() string {
 		return "Remove expired tokens"
 	case ClusterHeal:
 		return "Healing cluster"
+	case ClusterRebalance:
+		return "Re-balancing cluster"
+	case ClusterUpgrade:
+		return "Upgrading cluster"
 	case BucketBackupCreate:
 		return "Creating bucket backup"
 	case BucketBackupRemove:
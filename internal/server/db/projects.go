@@ -26,3 +26,67 @@ func (db *DB) GetProject(ctx context.Context, projectName string) (*cluster.Proj
 
 	return p, nil
 }
+
+// GetProjectSnapshotsCount returns the number of instance and custom volume snapshots in the given project.
+func (c *ClusterTx) GetProjectSnapshotsCount(ctx context.Context, projectName string) (int, error) {
+	stmt := `
+SELECT
+  (SELECT count(*) FROM instances_snapshots
+    JOIN instances ON instances.id = instances_snapshots.instance_id
+    JOIN projects ON projects.id = instances.project_id
+    WHERE projects.name = ?) +
+  (SELECT count(*) FROM storage_volumes_snapshots
+    JOIN storage_volumes ON storage_volumes.id = storage_volumes_snapshots.storage_volume_id
+    JOIN projects ON projects.id = storage_volumes.project_id
+    WHERE projects.name = ? AND storage_volumes.type = ?)`
+
+	var count int
+	err := c.tx.QueryRowContext(ctx, stmt, projectName, projectName, StoragePoolVolumeTypeCustom).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+// GetProjectBackupsSize returns the total size of the instance, custom volume and bucket backup tarballs in the given project.
+func (c *ClusterTx) GetProjectBackupsSize(ctx context.Context, projectName string) (int64, error) {
+	stmt := `
+SELECT
+  (SELECT coalesce(sum(instances_backups.size), 0) FROM instances_backups
+    JOIN instances ON instances.id = instances_backups.instance_id
+    JOIN projects ON projects.id = instances.project_id
+    WHERE projects.name = ?) +
+  (SELECT coalesce(sum(storage_volumes_backups.size), 0) FROM storage_volumes_backups
+    JOIN storage_volumes ON storage_volumes.id = storage_volumes_backups.storage_volume_id
+    JOIN projects ON projects.id = storage_volumes.project_id
+    WHERE projects.name = ?) +
+  (SELECT coalesce(sum(storage_buckets_backups.size), 0) FROM storage_buckets_backups
+    JOIN storage_buckets ON storage_buckets.id = storage_buckets_backups.storage_bucket_id
+    JOIN projects ON projects.id = storage_buckets.project_id
+    WHERE projects.name = ?)`
+
+	var size int64
+	err := c.tx.QueryRowContext(ctx, stmt, projectName, projectName, projectName).Scan(&size)
+	if err != nil {
+		return -1, err
+	}
+
+	return size, nil
+}
+
+// GetProjectImagesSize returns the total size of the images in the given project.
+func (c *ClusterTx) GetProjectImagesSize(ctx context.Context, projectName string) (int64, error) {
+	stmt := `
+SELECT coalesce(sum(images.size), 0) FROM images
+  JOIN projects ON projects.id = images.project_id
+  WHERE projects.name = ?`
+
+	var size int64
+	err := c.tx.QueryRowContext(ctx, stmt, projectName).Scan(&size)
+	if err != nil {
+		return -1, err
+	}
+
+	return size, nil
+}
@@ -0,0 +1,227 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/query"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// projectTemplateBlueprint is the part of a project template stored as YAML.
+type projectTemplateBlueprint struct {
+	Config      map[string]string     `yaml:"config,omitempty"`
+	Profiles    []api.ProfilesPost    `yaml:"profiles,omitempty"`
+	Networks    []api.NetworksPost    `yaml:"networks,omitempty"`
+	NetworkACLs []api.NetworkACLsPost `yaml:"network_acls,omitempty"`
+}
+
+// GetProjectTemplates returns all the project templates.
+func (c *ClusterTx) GetProjectTemplates(ctx context.Context) ([]api.ProjectTemplate, error) {
+	templates := []api.ProjectTemplate{}
+
+	err := query.Scan(ctx, c.tx, "SELECT name, description, self_service, blueprint FROM projects_templates ORDER BY name", func(scan func(dest ...any) error) error {
+		template := api.ProjectTemplate{}
+		var blueprint string
+
+		err := scan(&template.Name, &template.Description, &template.SelfService, &blueprint)
+		if err != nil {
+			return err
+		}
+
+		content := projectTemplateBlueprint{}
+		err = yaml.Unmarshal([]byte(blueprint), &content)
+		if err != nil {
+			return fmt.Errorf("Failed parsing blueprint of project template %q: %w", template.Name, err)
+		}
+
+		template.Config = content.Config
+		if template.Config == nil {
+			template.Config = map[string]string{}
+		}
+
+		template.Profiles = content.Profiles
+		if template.Profiles == nil {
+			template.Profiles = []api.ProfilesPost{}
+		}
+
+		template.Networks = content.Networks
+		if template.Networks == nil {
+			template.Networks = []api.NetworksPost{}
+		}
+
+		template.NetworkACLs = content.NetworkACLs
+		if template.NetworkACLs == nil {
+			template.NetworkACLs = []api.NetworkACLsPost{}
+		}
+
+		templates = append(templates, template)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetProjectTemplate returns the project template with the given name.
+func (c *ClusterTx) GetProjectTemplate(ctx context.Context, name string) (*api.ProjectTemplate, error) {
+	templates, err := c.GetProjectTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		if template.Name == name {
+			return &template, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Project template not found")
+}
+
+// getProjectTemplateID returns the ID of the project template with the given name.
+func (c *ClusterTx) getProjectTemplateID(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM projects_templates WHERE name = ?", name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Project template %q not found", name)
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// projectTemplateBlueprintYAML returns the YAML blueprint of a project template.
+func projectTemplateBlueprintYAML(template api.ProjectTemplatePut) (string, error) {
+	blueprint, err := yaml.Marshal(projectTemplateBlueprint{
+		Config:      template.Config,
+		Profiles:    template.Profiles,
+		Networks:    template.Networks,
+		NetworkACLs: template.NetworkACLs,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(blueprint), nil
+}
+
+// CreateProjectTemplate creates a new project template.
+func (c *ClusterTx) CreateProjectTemplate(ctx context.Context, template api.ProjectTemplatesPost) error {
+	_, err := c.getProjectTemplateID(ctx, template.Name)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Project template %q already exists", template.Name)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	blueprint, err := projectTemplateBlueprintYAML(template.ProjectTemplatePut)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO projects_templates (name, description, self_service, blueprint) VALUES (?, ?, ?, ?)", template.Name, template.Description, template.SelfService, blueprint)
+	return err
+}
+
+// UpdateProjectTemplate updates a project template.
+func (c *ClusterTx) UpdateProjectTemplate(ctx context.Context, name string, template api.ProjectTemplatePut) error {
+	id, err := c.getProjectTemplateID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	blueprint, err := projectTemplateBlueprintYAML(template)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE projects_templates SET description = ?, self_service = ?, blueprint = ? WHERE id = ?", template.Description, template.SelfService, blueprint, id)
+	return err
+}
+
+// RenameProjectTemplate renames a project template.
+func (c *ClusterTx) RenameProjectTemplate(ctx context.Context, name string, newName string) error {
+	id, err := c.getProjectTemplateID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.getProjectTemplateID(ctx, newName)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Project template %q already exists", newName)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE projects_templates SET name = ? WHERE id = ?", newName, id)
+	return err
+}
+
+// DeleteProjectTemplate deletes a project template.
+func (c *ClusterTx) DeleteProjectTemplate(ctx context.Context, name string) error {
+	id, err := c.getProjectTemplateID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.DeleteObject(c.tx, "projects_templates", id)
+	return err
+}
+
+// CreateProjectOwner records the identity owning a project created through self-service.
+func (c *ClusterTx) CreateProjectOwner(ctx context.Context, projectName string, authenticationMethod string, identifier string) error {
+	projectID, err := cluster.GetProjectID(ctx, c.tx, projectName)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO projects_owners (project_id, authentication_method, identifier) VALUES (?, ?, ?)", projectID, authenticationMethod, identifier)
+	if err != nil {
+		return fmt.Errorf("Failed recording owner of project %q: %w", projectName, err)
+	}
+
+	return nil
+}
+
+// ProjectHasOwner returns whether the project was created through self-service.
+func (c *ClusterTx) ProjectHasOwner(ctx context.Context, projectName string) (bool, error) {
+	stmt := `
+SELECT projects_owners.identifier
+FROM projects_owners
+JOIN projects ON projects.id = projects_owners.project_id
+WHERE projects.name = ?`
+
+	owners, err := query.SelectStrings(ctx, c.tx, stmt, projectName)
+	if err != nil {
+		return false, err
+	}
+
+	return len(owners) > 0, nil
+}
+
+// GetOwnedProjects returns the names of the projects owned by an identity.
+func (c *ClusterTx) GetOwnedProjects(ctx context.Context, authenticationMethod string, identifier string) ([]string, error) {
+	stmt := `
+SELECT projects.name
+FROM projects_owners
+JOIN projects ON projects.id = projects_owners.project_id
+WHERE projects_owners.authentication_method = ? AND projects_owners.identifier = ?
+ORDER BY projects.name`
+
+	return query.SelectStrings(ctx, c.tx, stmt, authenticationMethod, identifier)
+}
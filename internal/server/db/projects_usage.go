@@ -0,0 +1,112 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/query"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// ProjectUsageResolutions are the periods over which project resource usage is aggregated.
+var ProjectUsageResolutions = map[string]time.Duration{
+	"hourly": time.Hour,
+	"daily":  24 * time.Hour,
+}
+
+// AddProjectUsage adds the given resource usage to the hourly and daily periods containing the given time.
+// Usage of a project deleted since it was sampled is dropped.
+func (c *ClusterTx) AddProjectUsage(ctx context.Context, projectName string, at time.Time, usage api.ProjectUsageEntry) error {
+	projectID, err := cluster.GetProjectID(ctx, c.tx, projectName)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, resolution := range ProjectUsageResolutions {
+		start := at.UTC().Truncate(resolution)
+		args := []any{usage.CPUSeconds, usage.MemoryByteHours, usage.DiskByteHours, usage.NetworkBytesReceived, usage.NetworkBytesSent, usage.InstanceHours, projectID, int64(resolution / time.Second), start}
+
+		result, err := c.tx.ExecContext(ctx, `
+UPDATE projects_usage SET
+    cpu_seconds = cpu_seconds + ?,
+    memory_byte_hours = memory_byte_hours + ?,
+    disk_byte_hours = disk_byte_hours + ?,
+    network_bytes_received = network_bytes_received + ?,
+    network_bytes_sent = network_bytes_sent + ?,
+    instance_hours = instance_hours + ?
+  WHERE project_id = ? AND resolution = ? AND start = ?`, args...)
+		if err != nil {
+			return fmt.Errorf("Failed updating project usage: %w", err)
+		}
+
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n > 0 {
+			continue
+		}
+
+		_, err = c.tx.ExecContext(ctx, `
+INSERT INTO projects_usage (cpu_seconds, memory_byte_hours, disk_byte_hours, network_bytes_received, network_bytes_sent, instance_hours, project_id, resolution, start)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...)
+		if err != nil {
+			return fmt.Errorf("Failed adding project usage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetProjectUsage returns the resource usage of a project for the periods starting within the given time range.
+func (c *ClusterTx) GetProjectUsage(ctx context.Context, projectName string, resolution time.Duration, since time.Time, until time.Time) ([]api.ProjectUsageEntry, error) {
+	entries := []api.ProjectUsageEntry{}
+
+	stmt := `
+SELECT projects_usage.start, projects_usage.cpu_seconds, projects_usage.memory_byte_hours, projects_usage.disk_byte_hours,
+       projects_usage.network_bytes_received, projects_usage.network_bytes_sent, projects_usage.instance_hours
+  FROM projects_usage
+  JOIN projects ON projects.id = projects_usage.project_id
+  WHERE projects.name = ? AND projects_usage.resolution = ? AND projects_usage.start >= ? AND projects_usage.start < ?
+  ORDER BY projects_usage.start`
+
+	err := query.Scan(ctx, c.tx, stmt, func(scan func(dest ...any) error) error {
+		entry := api.ProjectUsageEntry{}
+
+		err := scan(&entry.Start, &entry.CPUSeconds, &entry.MemoryByteHours, &entry.DiskByteHours, &entry.NetworkBytesReceived, &entry.NetworkBytesSent, &entry.InstanceHours)
+		if err != nil {
+			return err
+		}
+
+		entry.Start = entry.Start.UTC()
+		entry.End = entry.Start.Add(resolution)
+		entries = append(entries, entry)
+
+		return nil
+	}, projectName, int64(resolution/time.Second), since.UTC().Truncate(resolution), until.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching project usage: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteProjectUsageBefore deletes the project resource usage periods of the given resolution starting before the given time.
+func (c *ClusterTx) DeleteProjectUsageBefore(ctx context.Context, resolution time.Duration, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM projects_usage WHERE resolution = ? AND start < ?", int64(resolution/time.Second), before.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed deleting project usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// Usage is aggregated per period and dropped for deleted projects.
+func TestProjectUsage(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	at := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	err := tx.AddProjectUsage(ctx, "default", at, api.ProjectUsageEntry{CPUSeconds: 10, InstanceHours: 1})
+	require.NoError(t, err)
+
+	err = tx.AddProjectUsage(ctx, "default", at.Add(10*time.Minute), api.ProjectUsageEntry{CPUSeconds: 5, NetworkBytesSent: 100})
+	require.NoError(t, err)
+
+	err = tx.AddProjectUsage(ctx, "default", at.Add(time.Hour), api.ProjectUsageEntry{CPUSeconds: 1})
+	require.NoError(t, err)
+
+	// A project deleted since sampling doesn't fail the batch.
+	err = tx.AddProjectUsage(ctx, "deleted", at, api.ProjectUsageEntry{CPUSeconds: 10})
+	assert.NoError(t, err)
+
+	hourly, err := tx.GetProjectUsage(ctx, "default", time.Hour, at.Add(-24*time.Hour), at.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hourly, 2)
+	assert.Equal(t, at.Truncate(time.Hour), hourly[0].Start)
+	assert.Equal(t, at.Truncate(time.Hour).Add(time.Hour), hourly[0].End)
+	assert.Equal(t, float64(15), hourly[0].CPUSeconds)
+	assert.Equal(t, int64(100), hourly[0].NetworkBytesSent)
+	assert.Equal(t, float64(1), hourly[1].CPUSeconds)
+
+	daily, err := tx.GetProjectUsage(ctx, "default", 24*time.Hour, at.Add(-24*time.Hour), at.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, float64(16), daily[0].CPUSeconds)
+
+	err = tx.DeleteProjectUsageBefore(ctx, time.Hour, at.Add(time.Hour))
+	require.NoError(t, err)
+
+	hourly, err = tx.GetProjectUsage(ctx, "default", time.Hour, at.Add(-24*time.Hour), at.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, hourly, 1)
+}
@@ -58,6 +58,10 @@ const (
 	UnableToUpdateClusterCertificate
 	// SELinuxNotAvailable represents the SELinux not available warning.
 	SELinuxNotAvailable
+	// InstancePlacementRulesViolated represents an instance running against its affinity or anti-affinity rules.
+	InstancePlacementRulesViolated
+	// InstanceWatchdogTriggered represents an instance whose watchdog timer expired.
+	InstanceWatchdogTriggered
 )
 
 // TypeNames associates a warning code to its name.
@@ -87,6 +91,8 @@ var TypeNames = map[Type]string{
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
 	SELinuxNotAvailable:               "SELinux support has been disabled",
+	InstancePlacementRulesViolated:    "Instance placement rules violated",
+	InstanceWatchdogTriggered:         "Instance watchdog triggered",
 }
 
 // Severity returns the severity of the warning type.
@@ -142,6 +148,10 @@ func (t Type) Severity() Severity {
 		return SeverityLow
 	case SELinuxNotAvailable:
 		return SeverityLow
+	case InstancePlacementRulesViolated:
+		return SeverityModerate
+	case InstanceWatchdogTriggered:
+		return SeverityModerate
 	}
 
 	return SeverityLow
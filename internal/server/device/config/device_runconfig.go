@@ -21,17 +21,18 @@ type RunConfigItem struct {
 
 // MountEntryItem represents a single mount entry item.
 type MountEntryItem struct {
-	DevName     string      // The internal name for the device.
-	DevPath     string      // Describes the block special device or remote filesystem to be mounted.
-	BackingPath []string    // Describes the block special device to be mounted as backing drive for qcow2.
-	TargetPath  string      // Describes the mount point (target) for the filesystem.
-	FSType      string      // Describes the type of the filesystem.
-	Opts        []string    // Describes the mount options associated with the filesystem.
-	Freq        int         // Used by dump(8) to determine which filesystems need to be dumped. Defaults to zero (don't dump) if not present.
-	PassNo      int         // Used by fsck(8) to determine the order in which filesystem checks are done at boot time. Defaults to zero (don't fsck) if not present.
-	OwnerShift  string      // Ownership shifting mode, use constants MountOwnerShiftNone, MountOwnerShiftStatic or MountOwnerShiftDynamic.
-	Limits      *DiskLimits // Disk limits.
-	Size        int64       // Expected disk size in bytes.
+	DevName      string      // The internal name for the device.
+	DevPath      string      // Describes the block special device or remote filesystem to be mounted.
+	BackingPath  []string    // Describes the block special device to be mounted as backing drive for qcow2.
+	BackingImage string      // Describes the read-only image file backing a lazily loaded qcow2 drive.
+	TargetPath   string      // Describes the mount point (target) for the filesystem.
+	FSType       string      // Describes the type of the filesystem.
+	Opts         []string    // Describes the mount options associated with the filesystem.
+	Freq         int         // Used by dump(8) to determine which filesystems need to be dumped. Defaults to zero (don't dump) if not present.
+	PassNo       int         // Used by fsck(8) to determine the order in which filesystem checks are done at boot time. Defaults to zero (don't fsck) if not present.
+	OwnerShift   string      // Ownership shifting mode, use constants MountOwnerShiftNone, MountOwnerShiftStatic or MountOwnerShiftDynamic.
+	Limits       *DiskLimits // Disk limits.
+	Size         int64       // Expected disk size in bytes.
 }
 
 // RootFSEntryItem represents the root filesystem options for an Instance.
@@ -66,6 +67,8 @@ type RunConfig struct {
 	USBDevice        []USBDeviceItem  // USB device configuration settings.
 	TPMDevice        []RunConfigItem  // TPM device configuration settings.
 	PCIDevice        []RunConfigItem  // PCI device configuration settings.
+	WatchdogDevice   []RunConfigItem  // Watchdog device configuration settings.
+	SerialDevice     []RunConfigItem  // Serial device configuration settings.
 	Revert           revert.Hook      // Revert setup of device on post-setup error.
 	UseUSBBus        bool             // Whether to use a USB bus for the device.
 }
@@ -88,6 +88,10 @@ func newByType(s *state.State, projectName string, conf deviceConfig.Device) (de
 		dev = &tpm{}
 	case "pci":
 		dev = &pci{}
+	case "watchdog":
+		dev = &watchdog{}
+	case "serial":
+		dev = &serial{}
 	}
 
 	// Check a valid device type has been found.
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"math/rand"
 	"net"
 	"net/http"
@@ -527,6 +528,11 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader, partialValid
 		}
 	}
 
+	// Check that the parent network can serve the iPXE script.
+	if d.config["ipv4.dhcp.ipxe_script"] != "" && (d.network == nil || util.IsFalseOrEmpty(d.network.Config()["ipv4.dhcp.tftp"]) && util.IsFalseOrEmpty(d.network.Config()["ipv4.dhcp.http"])) {
+		return errors.New(`"ipv4.dhcp.ipxe_script" requires a managed parent network with "ipv4.dhcp.tftp" or "ipv4.dhcp.http" enabled`)
+	}
+
 	// Check that IP filtering isn't being used with VLAN filtering.
 	if util.IsTrue(d.config["security.ipv4_filtering"]) || util.IsTrue(d.config["security.ipv6_filtering"]) {
 		if d.config["vlan"] != "" || d.config["vlan.tagged"] != "" {
@@ -628,6 +634,60 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader, partialValid
 	rules["ipv4.gateway"] = networkValidGatewayV4
 	rules["ipv6.gateway"] = networkValidGatewayV6
 
+	// Add bridge specific network boot validation rules.
+
+	// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.dhcp.next_server)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Address of the server the instance should boot from over the network (overrides the network's `ipv4.dhcp.next_server`)
+	rules["ipv4.dhcp.next_server"] = validate.Optional(validate.IsNetworkAddressV4)
+
+	// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.dhcp.filename)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Boot file name to provide to the instance when it boots using PXE (overrides the network's `ipv4.dhcp.filename`)
+	rules["ipv4.dhcp.filename"] = validate.IsAny
+
+	// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.dhcp.ipxe_filename)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Boot file name or URL to provide to the instance when it boots using iPXE (overrides the network's `ipv4.dhcp.ipxe_filename`)
+	rules["ipv4.dhcp.ipxe_filename"] = validate.IsAny
+
+	// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.dhcp.ipxe_script)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: iPXE script to serve to the instance when it boots using iPXE (requires `ipv4.dhcp.tftp` or `ipv4.dhcp.http` on the network)
+	rules["ipv4.dhcp.ipxe_script"] = validate.IsAny
+
+	for k := range d.config {
+		code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if !found {
+			continue
+		}
+
+		err := validate.IsInRange(1, 254)(code)
+		if err != nil {
+			return fmt.Errorf("Invalid DHCP option code in %q: %w", k, err)
+		}
+
+		// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.dhcp.option.NUMBER)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Value of an arbitrary DHCP option to provide to the instance (same syntax as dnsmasq)
+		rules[k] = validate.IsAny
+	}
+
 	// Now run normal validation.
 	err := d.config.Validate(rules)
 	if err != nil {
@@ -730,12 +790,23 @@ func (d *nicBridged) validateEnvironment() error {
 // UpdatableFields returns a list of fields that can be updated without triggering a device remove & add.
 func (d *nicBridged) UpdatableFields(oldDevice Type) []string {
 	// Check old and new device types match.
-	_, match := oldDevice.(*nicBridged)
+	oldNIC, match := oldDevice.(*nicBridged)
 	if !match {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged", "connected"}
+	fields := []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged", "connected", "ipv4.dhcp.next_server", "ipv4.dhcp.filename", "ipv4.dhcp.ipxe_filename", "ipv4.dhcp.ipxe_script"}
+
+	// DHCP options are applied by rebuilding the dnsmasq entry.
+	for _, config := range []deviceConfig.Device{d.config, oldNIC.config} {
+		for k := range config {
+			if strings.HasPrefix(k, "ipv4.dhcp.option.") && !slices.Contains(fields, k) {
+				fields = append(fields, k)
+			}
+		}
+	}
+
+	return fields
 }
 
 // Add is run when a device is added to a non-snapshot instance whether or not the instance is running.
@@ -895,6 +966,22 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	// Add the NIC to its project's traffic class if the project has network limits.
+	instProject := d.inst.Project()
+	if d.network != nil && (instProject.Config["limits.network.ingress"] != "" || instProject.Config["limits.network.egress"] != "") {
+		hwaddr, err := net.ParseMAC(d.config["hwaddr"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MAC address %q: %w", d.config["hwaddr"], err)
+		}
+
+		err = network.AddBridgeLimitsNIC(d.network.Name(), instProject.Name, instProject.Config, d.config, hwaddr)
+		if err != nil {
+			return nil, fmt.Errorf("Failed updating network bandwidth limits: %w", err)
+		}
+
+		reverter.Add(func() { _ = network.RemoveBridgeLimitsNIC(d.network.Name(), hwaddr) })
+	}
+
 	// Check if hairpin mode needs to be enabled.
 	// IncusOS doesn't load br_netfilter as it breaks routed proxy traffic, so skip the hairpin handling there.
 	if nativeBridge && d.network != nil && d.state.OS.IncusOS == nil {
@@ -1166,6 +1253,17 @@ func (d *nicBridged) postStop() error {
 		}
 	}
 
+	// Remove the NIC from its project's traffic class.
+	if d.network != nil && d.config["hwaddr"] != "" {
+		hwaddr, err := net.ParseMAC(d.config["hwaddr"])
+		if err == nil {
+			err = network.RemoveBridgeLimitsNIC(bridgeName, hwaddr)
+			if err != nil {
+				return fmt.Errorf("Failed updating network bandwidth limits: %w", err)
+			}
+		}
+	}
+
 	// Remove host-side routes from bridge interface.
 	routes := []string{}
 	routes = append(routes, util.SplitNTrimSpace(d.config["ipv4.routes"], ",", -1, true)...)
@@ -1206,6 +1304,16 @@ func (d *nicBridged) Remove(cleanupDependencies bool) error {
 			return err
 		}
 
+		err = dnsmasq.RemoveOptionsEntry(bridgeName, d.inst.Project().Name, d.inst.Name(), d.Name())
+		if err != nil {
+			return err
+		}
+
+		err = d.removeIPXEScript(bridgeName)
+		if err != nil {
+			return err
+		}
+
 		// Reload dnsmasq to apply new settings if dnsmasq is running.
 		err = dnsmasq.Kill(bridgeName, true)
 		if err != nil {
@@ -1266,7 +1374,18 @@ func (d *nicBridged) rebuildDnsmasqEntry() error {
 		}
 	}
 
-	err := dnsmasq.UpdateStaticEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), d.network.Config(), d.config["hwaddr"], ipv4Address, ipv6Address)
+	// Write the DHCP options first so that the host entry gets tagged accordingly.
+	options, err := d.dhcpOptions()
+	if err != nil {
+		return err
+	}
+
+	err = dnsmasq.UpdateOptionsEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), options)
+	if err != nil {
+		return err
+	}
+
+	err = dnsmasq.UpdateStaticEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), d.network.Config(), d.config["hwaddr"], ipv4Address, ipv6Address)
 	if err != nil {
 		return err
 	}
@@ -1280,6 +1399,96 @@ func (d *nicBridged) rebuildDnsmasqEntry() error {
 	return nil
 }
 
+// dhcpOptions returns the per-device DHCP options in dnsmasq's dhcp-option format.
+// If an iPXE script is configured, it is written to the network's TFTP directory.
+// When the device overrides any network boot setting, the device's boot settings replace the network's entirely,
+// so the network's settings that the device doesn't override are included too.
+func (d *nicBridged) dhcpOptions() ([]string, error) {
+	var options []string
+
+	if d.config["ipv4.dhcp.ipxe_script"] != "" {
+		err := os.WriteFile(dnsmasq.TFTPPath(d.config["parent"], d.ipxeScriptFileName()), []byte(d.config["ipv4.dhcp.ipxe_script"]+"\n"), 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("Failed writing iPXE script: %w", err)
+		}
+	} else {
+		err := d.removeIPXEScript(d.config["parent"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	overridesBoot := slices.ContainsFunc([]string{"ipv4.dhcp.next_server", "ipv4.dhcp.filename", "ipv4.dhcp.ipxe_filename", "ipv4.dhcp.ipxe_script"}, func(k string) bool {
+		return d.config[k] != ""
+	})
+
+	if overridesBoot {
+		netConfig := d.network.Config()
+
+		nextServer := d.config["ipv4.dhcp.next_server"]
+		if nextServer == "" {
+			nextServer = netConfig["ipv4.dhcp.next_server"]
+		}
+
+		filename := d.config["ipv4.dhcp.filename"]
+		if filename == "" {
+			filename = netConfig["ipv4.dhcp.filename"]
+		}
+
+		var ipxeFilename string
+		if d.config["ipv4.dhcp.ipxe_script"] != "" {
+			ipxeFilename = network.BridgeBootFileName(netConfig, d.ipxeScriptFileName())
+		} else if d.config["ipv4.dhcp.ipxe_filename"] != "" {
+			ipxeFilename = d.config["ipv4.dhcp.ipxe_filename"]
+		} else if netConfig["ipv4.dhcp.ipxe_script"] != "" {
+			ipxeFilename = network.BridgeBootFileName(netConfig, "boot.ipxe")
+		} else {
+			ipxeFilename = netConfig["ipv4.dhcp.ipxe_filename"]
+		}
+
+		// The server-ip-address option sets the next server address (siaddr) of the reply.
+		if nextServer != "" {
+			options = append(options, fmt.Sprintf("option:server-ip-address,%s", nextServer))
+		}
+
+		if ipxeFilename != "" {
+			options = append(options, fmt.Sprintf("tag:ipxe,option:bootfile-name,%s", ipxeFilename))
+
+			if filename != "" {
+				options = append(options, fmt.Sprintf("tag:!ipxe,option:bootfile-name,%s", filename))
+			}
+		} else if filename != "" {
+			options = append(options, fmt.Sprintf("option:bootfile-name,%s", filename))
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(d.config)) {
+		code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if found {
+			options = append(options, fmt.Sprintf("%s,%s", code, d.config[k]))
+		}
+	}
+
+	return options, nil
+}
+
+// ipxeScriptFileName returns the name of the device's iPXE script in the network's TFTP directory.
+func (d *nicBridged) ipxeScriptFileName() string {
+	deviceStaticFileName := dnsmasq.StaticAllocationFileName(d.inst.Project().Name, d.inst.Name(), d.Name())
+
+	return fmt.Sprintf("%s.ipxe", dnsmasq.DHCPOptionsTag(deviceStaticFileName))
+}
+
+// removeIPXEScript removes the device's iPXE script from the network's TFTP directory (if present).
+func (d *nicBridged) removeIPXEScript(networkName string) error {
+	err := os.Remove(dnsmasq.TFTPPath(networkName, d.ipxeScriptFileName()))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed removing iPXE script: %w", err)
+	}
+
+	return nil
+}
+
 // setupHostFilters applies any host side network filters.
 // Returns a revert fail function that can be used to undo this function if a subsequent step fails.
 func (d *nicBridged) setupHostFilters(oldConfig deviceConfig.Device) (revert.Hook, error) {
@@ -550,6 +550,48 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader, partialValidatio
 	rules["ipv4.address.external"] = validate.Optional(validate.And(validate.IsNetworkAddressV4, isNetworkForward))
 	rules["ipv6.address.external"] = validate.Optional(validate.And(validate.IsNetworkAddressV6, isNetworkForward))
 
+	// gendoc:generate(entity=devices, group=nic_ovn, key=ipv4.dhcp.next_server)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Address of the server the instance should boot from over the network (overrides the network's `ipv4.dhcp.next_server`)
+	rules["ipv4.dhcp.next_server"] = validate.Optional(validate.IsNetworkAddressV4)
+
+	// gendoc:generate(entity=devices, group=nic_ovn, key=ipv4.dhcp.filename)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Boot file name to provide to the instance when it boots using PXE (overrides the network's `ipv4.dhcp.filename`)
+	rules["ipv4.dhcp.filename"] = validate.IsAny
+
+	// gendoc:generate(entity=devices, group=nic_ovn, key=ipv4.dhcp.ipxe_filename)
+	//
+	// ---
+	//  type: string
+	//  managed: no
+	//  shortdesc: Boot file name or URL to provide to the instance when it boots using iPXE (overrides the network's `ipv4.dhcp.ipxe_filename`)
+	rules["ipv4.dhcp.ipxe_filename"] = validate.IsAny
+
+	for k := range d.config {
+		code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if !found {
+			continue
+		}
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=ipv4.dhcp.option.NUMBER)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Value of an additional DHCP option supported by OVN to provide to the instance (overrides the network's `ipv4.dhcp.option.NUMBER`)
+		rules[k] = func(value string) error {
+			_, _, err := ovn.DHCPv4Option(code, value)
+			return err
+		}
+	}
+
 	// Now run normal validation.
 	err = d.config.Validate(rules)
 	if err != nil {
@@ -1018,6 +1060,15 @@ func (d *nicOVN) Start() (*deviceConfig.RunConfig, error) {
 		})
 	})
 
+	// Include the NIC in its project's network limits.
+	projectConfig := d.inst.Project().Config
+	if projectConfig["limits.network.ingress"] != "" || projectConfig["limits.network.egress"] != "" {
+		err = network.UpdateOVNLimits(d.state, d.inst.Project().Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed updating network bandwidth limits: %w", err)
+		}
+	}
+
 	// Associated host side interface to OVN logical switch port (if not nested).
 	if integrationBridgeNICName != "" {
 		cleanup, err := d.setupHostNIC(integrationBridgeNICName, logicalPortName)
@@ -0,0 +1,568 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v7/internal/linux"
+	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/shared/idmap"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/revert"
+	"github.com/lxc/incus/v7/shared/termios"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+// Serial device source types.
+const (
+	SerialSourceUnix = "unix"
+	SerialSourceTCP  = "tcp"
+	SerialSourceHost = "host"
+)
+
+type serial struct {
+	deviceCommon
+}
+
+// serialRelay forwards the pty backing a container serial port to the client of the port's socket.
+type serialRelay struct {
+	ptx      *os.File
+	pty      *os.File
+	listener net.Listener
+	socket   string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// serialRelays holds the relays of the running container serial ports.
+var (
+	serialRelays   = map[string]*serialRelay{}
+	serialRelaysMu sync.Mutex
+)
+
+// SerialSource returns the source type and address of a serial device.
+// Serial devices without a source listen on a unix socket in the instance's devices directory.
+func SerialSource(inst instance.Instance, devName string, devConfig deviceConfig.Device) (string, string) {
+	source := devConfig["source"]
+	if source == "" {
+		return SerialSourceUnix, filepath.Join(inst.DevicesPath(), fmt.Sprintf("serial.%s.sock", linux.PathNameEncode(devName)))
+	}
+
+	path, ok := strings.CutPrefix(source, "unix:")
+	if ok {
+		return SerialSourceUnix, path
+	}
+
+	address, ok := strings.CutPrefix(source, "tcp:")
+	if ok {
+		return SerialSourceTCP, address
+	}
+
+	return SerialSourceHost, source
+}
+
+// SerialConnect connects to the socket a serial device listens on.
+func SerialConnect(inst instance.Instance, devName string) (*os.File, error) {
+	devConfig, ok := inst.ExpandedDevices()[devName]
+	if !ok || devConfig["type"] != "serial" {
+		return nil, fmt.Errorf("Serial device %q not found", devName)
+	}
+
+	var conn net.Conn
+	var err error
+
+	sourceType, source := SerialSource(inst, devName, devConfig)
+	switch sourceType {
+	case SerialSourceUnix:
+		conn, err = linux.DialUnix(source)
+	case SerialSourceTCP:
+		conn, err = net.Dial("tcp", source)
+	default:
+		return nil, fmt.Errorf("Serial device %q is connected to a host device", devName)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Connect to serial device %q: %w", devName, err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	fileConn, ok := conn.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.New("Unexpected serial connection type")
+	}
+
+	file, err := fileConn.File()
+	if err != nil {
+		return nil, fmt.Errorf("Get socket file: %w", err)
+	}
+
+	return file, nil
+}
+
+// validateSerialSource checks the source of a serial device.
+func validateSerialSource(value string) error {
+	path, ok := strings.CutPrefix(value, "unix:")
+	if ok {
+		return validate.IsAbsFilePath(path)
+	}
+
+	address, ok := strings.CutPrefix(value, "tcp:")
+	if ok {
+		return validate.IsListenAddress(false, true, true)(address)
+	}
+
+	err := validate.IsAbsFilePath(value)
+	if err != nil {
+		return errors.New("Must be a host device path, or start with \"unix:\" or \"tcp:\"")
+	}
+
+	return nil
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *serial) CanMigrate() bool {
+	return !strings.HasPrefix(d.config["source"], "/")
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *serial) validateConfig(instConf instance.ConfigReader, partialValidation bool) error {
+	if !instanceSupported(instConf.Type(), instancetype.Container, instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// gendoc:generate(entity=devices, group=serial, key=source)
+		//
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Host endpoint of the port: a host device path, `unix:<path>` or `tcp:<address>:<port>` (see {ref}`devices-serial-source`)
+		"source": validate.Optional(validateSerialSource),
+	}
+
+	if instConf.Type() == instancetype.Container {
+		// gendoc:generate(entity=devices, group=serial, key=path)
+		//
+		// ---
+		//  type: string
+		//  default: value of `source` for host devices
+		//  required: for containers not using a host device
+		//  shortdesc: Only for containers: path inside the instance (for example, `/dev/ttyS1`)
+		rules["path"] = validate.Optional(validate.IsAbsFilePath)
+	} else {
+		// gendoc:generate(entity=devices, group=serial, key=model)
+		//
+		// ---
+		//  type: string
+		//  default: `virtio`
+		//  required: no
+		//  shortdesc: Only for VMs: emulated port (`virtio` or `16550`)
+		rules["model"] = validate.Optional(validate.IsOneOf("virtio", "16550"))
+
+		// gendoc:generate(entity=devices, group=serial, key=name)
+		//
+		// ---
+		//  type: string
+		//  default: device name
+		//  required: no
+		//  shortdesc: Only for VMs with the `virtio` model: port name inside the instance (under `/dev/virtio-ports/`)
+		rules["name"] = validate.Optional(validate.IsNotEmpty)
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	if instConf.Type() == instancetype.Container && !strings.HasPrefix(d.config["source"], "/") && d.config["path"] == "" {
+		return errors.New("The path property is required for container serial devices not using a host device")
+	}
+
+	if d.config["name"] != "" && d.config["model"] == "16550" {
+		return errors.New("The name property is only supported by the virtio model")
+	}
+
+	return nil
+}
+
+// validateEnvironment checks the host device used by the serial device is available.
+func (d *serial) validateEnvironment() error {
+	sourceType, source := SerialSource(d.inst, d.name, d.config)
+	if sourceType != SerialSourceHost {
+		return nil
+	}
+
+	dType, _, _, err := unixDeviceAttributes(source)
+	if err != nil {
+		return fmt.Errorf("Failed to get device attributes for %q: %w", source, err)
+	}
+
+	if dType != "c" {
+		return fmt.Errorf("Path %q is not a character device", source)
+	}
+
+	return nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *serial) Start() (*deviceConfig.RunConfig, error) {
+	err := d.validateEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to validate environment: %w", err)
+	}
+
+	if d.inst.Type() == instancetype.VM {
+		return d.startVM()
+	}
+
+	return d.startContainer()
+}
+
+// startContainer passes the host device or a pty relayed to the port's socket into the container.
+func (d *serial) startContainer() (*deviceConfig.RunConfig, error) {
+	sourceType, _ := SerialSource(d.inst, d.name, d.config)
+	if sourceType != SerialSourceHost {
+		return d.startContainerPty()
+	}
+
+	runConf := deviceConfig.RunConfig{}
+
+	_, major, minor, err := unixDeviceAttributes(d.config["source"])
+	if err != nil {
+		return nil, err
+	}
+
+	err = unixDeviceSetupCharNum(d.state, d.inst.DevicesPath(), "unix", d.name, d.config, major, minor, unixDeviceDestPath(d.config), false, &runConf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runConf, nil
+}
+
+// startContainerPty creates the pty backing a container serial port, mounts it into the container
+// and relays it to the port's socket.
+func (d *serial) startContainerPty() (*deviceConfig.RunConfig, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Stop any leftover relay.
+	d.stopRelay()
+
+	// The pty is owned by the root user of the container.
+	c, ok := d.inst.(instance.Container)
+	if !ok {
+		return nil, errors.New("Serial ports backed by a pty are only supported by containers")
+	}
+
+	var idmapSet *idmap.Set
+	var err error
+
+	if c.IsRunning() {
+		idmapSet, err = c.CurrentIdmap()
+	} else {
+		idmapSet, err = c.NextIdmap()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rootUID, rootGID int64
+	if idmapSet != nil {
+		rootUID, rootGID = idmapSet.ShiftIntoNS(0, 0)
+	}
+
+	ptx, pty, err := serialOpenPty(rootUID, rootGID)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() {
+		_ = ptx.Close()
+		_ = pty.Close()
+	})
+
+	// Listen on the port's socket.
+	err = os.MkdirAll(d.inst.DevicesPath(), 0o711)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create devices path: %w", err)
+	}
+
+	var listener net.Listener
+	var socket string
+
+	sourceType, source := SerialSource(d.inst, d.name, d.config)
+	if sourceType == SerialSourceUnix {
+		// Remove any leftover socket.
+		_ = os.Remove(source)
+
+		listener, err = linux.ListenUnix(source)
+		socket = source
+	} else {
+		listener, err = net.Listen("tcp", source)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed listening on %q: %w", source, err)
+	}
+
+	relay := &serialRelay{ptx: ptx, pty: pty, listener: listener, socket: socket}
+	reverter.Add(relay.stop)
+
+	// Mount the pty itself into the container, as new device nodes for a pty can't be opened.
+	relativeDestPath := strings.TrimPrefix(unixDeviceDestPath(d.config), "/")
+	devPath := filepath.Join(d.inst.DevicesPath(), linux.PathNameEncode(deviceJoinPath("unix", d.name, relativeDestPath)))
+
+	f, err := os.Create(devPath)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = f.Close()
+	reverter.Add(func() { _ = os.Remove(devPath) })
+
+	err = DiskMount(pty.Name(), devPath, false, "", nil, "none")
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() { _ = unix.Unmount(devPath, unix.MNT_DETACH) })
+
+	dType, major, minor, err := unixDeviceAttributes(devPath)
+	if err != nil {
+		return nil, err
+	}
+
+	runConf := deviceConfig.RunConfig{}
+	runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
+		DevPath:    devPath,
+		TargetPath: relativeDestPath,
+		FSType:     "none",
+		Opts:       []string{"bind", "create=file"},
+	})
+
+	runConf.CGroups = append(runConf.CGroups, deviceConfig.RunConfigItem{
+		Key:   "devices.allow",
+		Value: fmt.Sprintf("%s %d:%d rwm", dType, major, minor),
+	})
+
+	serialRelaysMu.Lock()
+	serialRelays[d.relayKey()] = relay
+	serialRelaysMu.Unlock()
+
+	go relay.run()
+
+	reverter.Success()
+
+	return &runConf, nil
+}
+
+// serialOpenPty creates a raw pty pair whose ptx side can be read from the relay.
+func serialOpenPty(uid int64, gid int64) (*os.File, *os.File, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	ptx, pty, err := linux.OpenPty(uid, gid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed creating pty: %w", err)
+	}
+
+	reverter.Add(func() {
+		_ = ptx.Close()
+		_ = pty.Close()
+	})
+
+	// Serial ports don't process their input or output.
+	_, err = termios.MakeRaw(int(ptx.Fd()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Make the reads of the relay interruptible.
+	ptxFd, err := unix.FcntlInt(ptx.Fd(), unix.F_DUPFD_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = unix.SetNonblock(ptxFd, true)
+	if err != nil {
+		_ = unix.Close(ptxFd)
+		return nil, nil, err
+	}
+
+	_ = ptx.Close()
+
+	reverter.Success()
+
+	return os.NewFile(uintptr(ptxFd), ptx.Name()), pty, nil
+}
+
+// relayKey returns the key of the relay of the device.
+func (d *serial) relayKey() string {
+	return project.Instance(d.inst.Project().Name, d.inst.Name()) + "/" + d.name
+}
+
+// stopRelay stops the relay of a container serial port, if any.
+func (d *serial) stopRelay() {
+	serialRelaysMu.Lock()
+	relay := serialRelays[d.relayKey()]
+	delete(serialRelays, d.relayKey())
+	serialRelaysMu.Unlock()
+
+	if relay != nil {
+		relay.stop()
+	}
+}
+
+// run forwards the output of the port to the connected client and serves one client at a time.
+// The relay holds the pty side open so that reads don't fail while nothing in the container uses the port.
+func (r *serialRelay) run() {
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.ptx.Read(buf)
+			if err != nil {
+				return
+			}
+
+			// Output is dropped while no client is connected.
+			r.mu.Lock()
+			conn := r.conn
+			r.mu.Unlock()
+
+			if conn != nil {
+				_, _ = conn.Write(buf[:n])
+			}
+		}
+	}()
+
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Warn("Failed accepting serial port client", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		r.mu.Lock()
+		r.conn = conn
+		r.mu.Unlock()
+
+		_, _ = io.Copy(r.ptx, conn)
+
+		r.mu.Lock()
+		r.conn = nil
+		r.mu.Unlock()
+
+		_ = conn.Close()
+	}
+}
+
+// stop closes the socket, the connected client and the pty of the relay.
+func (r *serialRelay) stop() {
+	_ = r.listener.Close()
+
+	r.mu.Lock()
+	if r.conn != nil {
+		_ = r.conn.Close()
+	}
+
+	r.mu.Unlock()
+
+	_ = r.ptx.Close()
+	_ = r.pty.Close()
+
+	if r.socket != "" {
+		_ = os.Remove(r.socket)
+	}
+}
+
+// startVM returns the port configuration for QEMU.
+func (d *serial) startVM() (*deviceConfig.RunConfig, error) {
+	sourceType, source := SerialSource(d.inst, d.name, d.config)
+
+	model := d.config["model"]
+	if model == "" {
+		model = "virtio"
+	}
+
+	name := d.config["name"]
+	if name == "" {
+		name = d.name
+	}
+
+	runConf := deviceConfig.RunConfig{
+		SerialDevice: []deviceConfig.RunConfigItem{
+			{Key: "devName", Value: d.name},
+			{Key: "model", Value: model},
+			{Key: "name", Value: name},
+			{Key: "sourceType", Value: sourceType},
+			{Key: "source", Value: source},
+		},
+	}
+
+	return &runConf, nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *serial) Stop() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	if d.inst.Type() == instancetype.VM {
+		// Remove the listening socket created for the port.
+		sourceType, source := SerialSource(d.inst, d.name, d.config)
+		if sourceType == SerialSourceUnix {
+			err := os.Remove(source)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("Failed to remove serial socket %q: %w", source, err)
+			}
+		}
+
+		return &runConf, nil
+	}
+
+	runConf.PostHooks = []func() error{d.postStop}
+
+	err := unixDeviceRemove(d.inst.DevicesPath(), "unix", d.name, "", &runConf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runConf, nil
+}
+
+// postStop is run after the device is removed from the instance.
+func (d *serial) postStop() error {
+	sourceType, _ := SerialSource(d.inst, d.name, d.config)
+	if sourceType != SerialSourceHost {
+		d.stopRelay()
+
+		// Release the mount of the pty.
+		relativeDestPath := strings.TrimPrefix(unixDeviceDestPath(d.config), "/")
+		devPath := filepath.Join(d.inst.DevicesPath(), linux.PathNameEncode(deviceJoinPath("unix", d.name, relativeDestPath)))
+		_ = unix.Unmount(devPath, unix.MNT_DETACH)
+	}
+
+	err := unixDeviceDeleteFiles(d.state, d.inst.DevicesPath(), "unix", d.name, "")
+	if err != nil {
+		return fmt.Errorf("Failed to delete files for device %q: %w", d.name, err)
+	}
+
+	return nil
+}
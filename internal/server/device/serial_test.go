@@ -0,0 +1,141 @@
+package device
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v7/internal/linux"
+	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+)
+
+// serialTestInstance is an instance only providing its type and devices path.
+type serialTestInstance struct {
+	instance.Instance
+
+	instType    instancetype.Type
+	devicesPath string
+}
+
+func (inst *serialTestInstance) Type() instancetype.Type {
+	return inst.instType
+}
+
+func (inst *serialTestInstance) DevicesPath() string {
+	return inst.devicesPath
+}
+
+func TestSerialSource(t *testing.T) {
+	inst := &serialTestInstance{instType: instancetype.Container, devicesPath: "/var/lib/incus/devices/c1"}
+
+	tests := []struct {
+		name       string
+		source     string
+		sourceType string
+		address    string
+	}{
+		{name: "default", source: "", sourceType: SerialSourceUnix, address: "/var/lib/incus/devices/c1/serial.ttyS1.sock"},
+		{name: "unix", source: "unix:/run/serial.sock", sourceType: SerialSourceUnix, address: "/run/serial.sock"},
+		{name: "tcp", source: "tcp:127.0.0.1:4000", sourceType: SerialSourceTCP, address: "127.0.0.1:4000"},
+		{name: "host", source: "/dev/ttyUSB0", sourceType: SerialSourceHost, address: "/dev/ttyUSB0"},
+	}
+
+	for _, test := range tests {
+		sourceType, address := SerialSource(inst, "ttyS1", deviceConfig.Device{"type": "serial", "source": test.source})
+		assert.Equal(t, test.sourceType, sourceType, test.name)
+		assert.Equal(t, test.address, address, test.name)
+	}
+}
+
+func TestSerialValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		instType instancetype.Type
+		config   deviceConfig.Device
+		valid    bool
+	}{
+		{name: "container socket", instType: instancetype.Container, config: deviceConfig.Device{"path": "/dev/ttyS1"}, valid: true},
+		{name: "container unix", instType: instancetype.Container, config: deviceConfig.Device{"source": "unix:/run/serial.sock", "path": "/dev/ttyS1"}, valid: true},
+		{name: "container tcp", instType: instancetype.Container, config: deviceConfig.Device{"source": "tcp:127.0.0.1:4000", "path": "/dev/ttyS1"}, valid: true},
+		{name: "container host device", instType: instancetype.Container, config: deviceConfig.Device{"source": "/dev/ttyUSB0"}, valid: true},
+		{name: "container without path", instType: instancetype.Container, config: deviceConfig.Device{"source": "unix:/run/serial.sock"}},
+		{name: "container with model", instType: instancetype.Container, config: deviceConfig.Device{"path": "/dev/ttyS1", "model": "virtio"}},
+		{name: "relative unix path", instType: instancetype.Container, config: deviceConfig.Device{"source": "unix:serial.sock", "path": "/dev/ttyS1"}},
+		{name: "tcp without port", instType: instancetype.Container, config: deviceConfig.Device{"source": "tcp:127.0.0.1", "path": "/dev/ttyS1"}},
+		{name: "unknown source", instType: instancetype.Container, config: deviceConfig.Device{"source": "udp:127.0.0.1:4000", "path": "/dev/ttyS1"}},
+		{name: "vm socket", instType: instancetype.VM, config: deviceConfig.Device{}, valid: true},
+		{name: "vm virtio name", instType: instancetype.VM, config: deviceConfig.Device{"model": "virtio", "name": "console"}, valid: true},
+		{name: "vm 16550", instType: instancetype.VM, config: deviceConfig.Device{"source": "tcp:127.0.0.1:4000", "model": "16550"}, valid: true},
+		{name: "vm 16550 name", instType: instancetype.VM, config: deviceConfig.Device{"model": "16550", "name": "console"}},
+		{name: "vm unknown model", instType: instancetype.VM, config: deviceConfig.Device{"model": "pl011"}},
+		{name: "vm with path", instType: instancetype.VM, config: deviceConfig.Device{"path": "/dev/ttyS1"}},
+	}
+
+	for _, test := range tests {
+		inst := &serialTestInstance{instType: test.instType}
+
+		d := &serial{}
+		d.name = "port"
+		d.config = test.config
+		d.config["type"] = "serial"
+
+		err := d.validateConfig(inst, false)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestSerialRelay(t *testing.T) {
+	ptx, pty, err := serialOpenPty(int64(os.Getuid()), int64(os.Getgid()))
+	if err != nil {
+		t.Skipf("Failed creating pty: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "serial.sock")
+	listener, err := linux.ListenUnix(socket)
+	require.NoError(t, err)
+
+	relay := &serialRelay{ptx: ptx, pty: pty, listener: listener, socket: socket}
+	go relay.run()
+
+	conn, err := linux.DialUnix(socket)
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// Input of the client is written to the port.
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(pty, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	// Output of the port is sent to the client.
+	_, err = pty.Write([]byte("pong"))
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(buf))
+
+	// Stopping the relay disconnects the client and removes the socket.
+	relay.stop()
+
+	_, err = conn.Read(buf)
+	assert.Error(t, err)
+	assert.NoFileExists(t, socket)
+}
@@ -0,0 +1,82 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+
+	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+type watchdog struct {
+	deviceCommon
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *watchdog) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *watchdog) validateConfig(instConf instance.ConfigReader, partialValidation bool) error {
+	if !instanceSupported(instConf.Type(), instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// gendoc:generate(entity=devices, group=watchdog, key=model)
+		//
+		// ---
+		//  type: string
+		//  required: no
+		//  default: `i6300esb`
+		//  shortdesc: Emulated watchdog model (`i6300esb` or `ib700`)
+		"model": validate.Optional(validate.IsOneOf("i6300esb", "ib700")),
+		// gendoc:generate(entity=devices, group=watchdog, key=action)
+		//
+		// ---
+		//  type: string
+		//  required: no
+		//  default: `reset`
+		//  shortdesc: Action taken when the watchdog fires (`reset`, `poweroff`, `pause`, `dump` or `none`)
+		"action": validate.Optional(validate.IsOneOf("reset", "poweroff", "pause", "dump", "none")),
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	// QEMU only has a single watchdog action per instance.
+	for name, devConfig := range instConf.ExpandedDevices() {
+		if name != d.name && devConfig["type"] == "watchdog" {
+			return errors.New("Only one watchdog device can be added to an instance")
+		}
+	}
+
+	return nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *watchdog) Start() (*deviceConfig.RunConfig, error) {
+	model := d.config["model"]
+	if model == "" {
+		model = "i6300esb"
+	}
+
+	runConf := deviceConfig.RunConfig{
+		WatchdogDevice: []deviceConfig.RunConfigItem{
+			{Key: "devName", Value: d.name},
+			{Key: "model", Value: model},
+		},
+	}
+
+	return &runConf, nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *watchdog) Stop() (*deviceConfig.RunConfig, error) {
+	return &deviceConfig.RunConfig{}, nil
+}
@@ -2,6 +2,8 @@ package dnsmasq
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -21,6 +23,9 @@ import (
 
 const staticAllocationDeviceSeparator = "."
 
+// DHCPBootTag is the dnsmasq tag set on the hosts whose DHCP options override the network boot settings.
+const DHCPBootTag = "incus-boot"
+
 // DHCPAllocation represents an IP allocation from dnsmasq.
 type DHCPAllocation struct {
 	IP             net.IP
@@ -36,6 +41,23 @@ func UpdateStaticEntry(network string, projectName string, instanceName string,
 	hwaddr = strings.ToLower(hwaddr)
 	line := hwaddr
 
+	// Tag the host so that any per-device DHCP options apply to it.
+	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
+	optionsPath := DHCPOptionsPath(network, deviceStaticFileName)
+	if util.PathExists(optionsPath) {
+		line += fmt.Sprintf(",set:%s", DHCPOptionsTag(deviceStaticFileName))
+
+		// Also tag the host if it overrides the network boot settings, so that the network's don't apply.
+		boot, err := dhcpOptionsOverrideBoot(optionsPath)
+		if err != nil {
+			return err
+		}
+
+		if boot {
+			line += fmt.Sprintf(",set:%s", DHCPBootTag)
+		}
+	}
+
 	// Generate the dhcp-host line
 	if ipv4Address != "" {
 		line += fmt.Sprintf(",%s", ipv4Address)
@@ -53,7 +75,6 @@ func UpdateStaticEntry(network string, projectName string, instanceName string,
 		return nil
 	}
 
-	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	err := os.WriteFile(internalUtil.VarPath("networks", network, "dnsmasq.hosts", deviceStaticFileName), []byte(line+"\n"), 0o644)
 	if err != nil {
 		return err
@@ -73,6 +94,56 @@ func RemoveStaticEntry(network string, projectName string, instanceName string,
 	return nil
 }
 
+// UpdateOptionsEntry writes the DHCP options for a network/instance combination.
+// Each option is in dnsmasq's dhcp-option format without any tag, the device tag is prepended automatically.
+func UpdateOptionsEntry(network string, projectName string, instanceName string, deviceName string, options []string) error {
+	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
+	if len(options) == 0 {
+		return RemoveOptionsEntry(network, projectName, instanceName, deviceName)
+	}
+
+	tag := DHCPOptionsTag(deviceStaticFileName)
+
+	var sb strings.Builder
+	for _, option := range options {
+		fmt.Fprintf(&sb, "tag:%s,%s\n", tag, option)
+	}
+
+	err := os.WriteFile(DHCPOptionsPath(network, deviceStaticFileName), []byte(sb.String()), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// dhcpOptionsOverrideBoot returns whether a DHCP options file contains network boot settings.
+func dhcpOptionsOverrideBoot(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.Contains(line, ",option:bootfile-name,") || strings.Contains(line, ",option:server-ip-address,") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// RemoveOptionsEntry removes the DHCP options for a network/instance combination.
+func RemoveOptionsEntry(network string, projectName string, instanceName string, deviceName string) error {
+	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
+	err := os.Remove(DHCPOptionsPath(network, deviceStaticFileName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
 // Kill kills dnsmasq for a particular network (or optionally reloads it).
 func Kill(name string, reload bool) error {
 	pidPath := internalUtil.VarPath("networks", name, "dnsmasq.pid")
@@ -112,6 +183,25 @@ func DHCPStaticAllocationPath(network string, deviceStaticFileName string) strin
 	return internalUtil.VarPath("networks", network, "dnsmasq.hosts", deviceStaticFileName)
 }
 
+// DHCPOptionsPath returns the path to the DHCP options file.
+func DHCPOptionsPath(network string, deviceStaticFileName string) string {
+	return internalUtil.VarPath("networks", network, "dnsmasq.opts", deviceStaticFileName)
+}
+
+// DHCPOptionsTag returns the dnsmasq tag used to match DHCP options to an instance device.
+// The static allocation file name can't be used directly as it may contain characters that dnsmasq
+// would interpret, so a short hash of it is used instead.
+func DHCPOptionsTag(deviceStaticFileName string) string {
+	hash := sha256.Sum256([]byte(deviceStaticFileName))
+
+	return fmt.Sprintf("incus-%s", hex.EncodeToString(hash[:8]))
+}
+
+// TFTPPath returns the path to a file in the network's TFTP root.
+func TFTPPath(network string, fileName string) string {
+	return internalUtil.VarPath("networks", network, "tftp", fileName)
+}
+
 // DHCPStaticAllocation retrieves the dnsmasq statically allocated MAC and IPs for an instance device static file.
 // Returns MAC, IPv4 and IPv6 DHCPAllocation structs respectively.
 func DHCPStaticAllocation(network string, deviceStaticFileName string) (net.HardwareAddr, DHCPAllocation, DHCPAllocation, error) {
@@ -1,9 +1,15 @@
 package dnsmasq
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalUtil "github.com/lxc/incus/v7/internal/util"
 )
 
 func Test_staticAllocationFileName(t *testing.T) {
@@ -13,3 +19,64 @@ func Test_staticAllocationFileName(t *testing.T) {
 	fileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	assert.Equal(t, "test.project_test-instance.test-.--_----.device", fileName)
 }
+
+func Test_UpdateStaticEntryTags(t *testing.T) {
+	t.Setenv("INCUS_DIR", t.TempDir())
+
+	for _, dir := range []string{"dnsmasq.hosts", "dnsmasq.opts"} {
+		err := os.MkdirAll(internalUtil.VarPath("networks", "incusbr0", dir), 0o755)
+		require.NoError(t, err)
+	}
+
+	fileName := StaticAllocationFileName("default", "c1", "eth0")
+	tag := DHCPOptionsTag(fileName)
+	hostsPath := DHCPStaticAllocationPath("incusbr0", fileName)
+
+	readHost := func() string {
+		content, err := os.ReadFile(hostsPath)
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(content))
+	}
+
+	// No options, no tags.
+	err := UpdateStaticEntry("incusbr0", "default", "c1", "eth0", nil, "00:16:3E:00:00:01", "10.0.0.2", "")
+	require.NoError(t, err)
+	assert.Equal(t, "00:16:3e:00:00:01,10.0.0.2,c1", readHost())
+
+	// Plain options only tag the host with the device tag.
+	err = UpdateOptionsEntry("incusbr0", "default", "c1", "eth0", []string{"42,10.0.0.1"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(DHCPOptionsPath("incusbr0", fileName))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("tag:%s,42,10.0.0.1\n", tag), string(content))
+
+	err = UpdateStaticEntry("incusbr0", "default", "c1", "eth0", nil, "00:16:3E:00:00:01", "10.0.0.2", "")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("00:16:3e:00:00:01,set:%s,10.0.0.2,c1", tag), readHost())
+
+	// Boot options also tag the host so that the network boot settings don't apply.
+	err = UpdateOptionsEntry("incusbr0", "default", "c1", "eth0", []string{"option:server-ip-address,10.0.0.5", "option:bootfile-name,pxelinux.0"})
+	require.NoError(t, err)
+
+	err = UpdateStaticEntry("incusbr0", "default", "c1", "eth0", nil, "00:16:3E:00:00:01", "10.0.0.2", "")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("00:16:3e:00:00:01,set:%s,set:%s,10.0.0.2,c1", tag, DHCPBootTag), readHost())
+
+	// Removing the options removes the tags.
+	err = UpdateOptionsEntry("incusbr0", "default", "c1", "eth0", nil)
+	require.NoError(t, err)
+	assert.NoFileExists(t, DHCPOptionsPath("incusbr0", fileName))
+
+	err = UpdateStaticEntry("incusbr0", "default", "c1", "eth0", nil, "00:16:3E:00:00:01", "10.0.0.2", "")
+	require.NoError(t, err)
+	assert.Equal(t, "00:16:3e:00:00:01,10.0.0.2,c1", readHost())
+}
+
+func Test_DHCPOptionsTag(t *testing.T) {
+	tag := DHCPOptionsTag(StaticAllocationFileName("default", "c1", "eth0"))
+	assert.Regexp(t, "^incus-[0-9a-f]{16}$", tag)
+	assert.Equal(t, tag, DHCPOptionsTag(StaticAllocationFileName("default", "c1", "eth0")))
+	assert.NotEqual(t, tag, DHCPOptionsTag(StaticAllocationFileName("default", "c1", "eth1")))
+}
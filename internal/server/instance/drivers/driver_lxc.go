@@ -7978,6 +7978,18 @@ func (d *lxc) Console(protocol string) (*os.File, chan error, error) {
 	return ptx, chDisconnect, nil
 }
 
+// SerialConsole gets access to one of the instance's serial devices.
+func (d *lxc) SerialConsole(devName string) (*os.File, chan error, error) {
+	file, err := device.SerialConnect(d, devName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceConsole.Event(d, logger.Ctx{"type": instance.ConsoleTypeConsole, "device": devName}))
+
+	return file, make(chan error, 1), nil
+}
+
 // ConsoleLog returns console log.
 func (d *lxc) ConsoleLog(opts liblxc.ConsoleLogOptions) (string, error) {
 	cc, err := d.initLXC(false)
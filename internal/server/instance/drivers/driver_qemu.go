@@ -53,6 +53,7 @@ import (
 	"github.com/lxc/incus/v7/internal/server/cgroup"
 	"github.com/lxc/incus/v7/internal/server/db"
 	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/db/warningtype"
 	"github.com/lxc/incus/v7/internal/server/device"
 	deviceConfig "github.com/lxc/incus/v7/internal/server/device/config"
 	"github.com/lxc/incus/v7/internal/server/device/nictype"
@@ -479,7 +480,7 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 	s := d.state
 
 	return func(event string, data map[string]any) {
-		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventVMReset, qmp.EventAgentStarted, qmp.EventAgentStopped, qmp.EventRTCChange, qmp.EventBlockJobCompleted, qmp.EventBlockJobError}, event) {
+		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventVMReset, qmp.EventAgentStarted, qmp.EventAgentStopped, qmp.EventRTCChange, qmp.EventBlockJobCompleted, qmp.EventBlockJobError, qmp.EventWatchdog}, event) {
 			return // Don't bother loading the instance from DB if we aren't going to handle the event.
 		}
 
@@ -593,10 +594,28 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				d.logger.Error("Failed to apply rtc change", logger.Ctx{"offset": val, "err": err})
 			}
 
+		case qmp.EventWatchdog:
+			d.onWatchdog()
+
 		case qmp.EventBlockJobCompleted, qmp.EventBlockJobError:
 			monitor, _ := d.qmpConnect()
 			monitor.PushEvent(event, data)
 			monitor.CleanupEventChannel(data["device"].(string))
+
+			// Release the image once a lazily loaded root disk has been fully loaded.
+			if event == qmp.EventBlockJobCompleted && data["type"] == "stream" {
+				if data["error"] != nil {
+					d.logger.Warn("Failed loading disk from its image", logger.Ctx{"device": data["device"], "err": data["error"]})
+					return
+				}
+
+				d.logger.Debug("Finished loading disk from its image", logger.Ctx{"device": data["device"]})
+
+				err = storagePools.LazyImageRelease(d.id)
+				if err != nil {
+					d.logger.Warn("Failed releasing image", logger.Ctx{"err": err})
+				}
+			}
 		}
 	}
 }
@@ -2236,6 +2255,16 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"panic":    "exit-failure",
 	}
 
+	// The memory dump for the watchdog "dump" action is taken by the event handler while the guest is paused.
+	watchdogAction := d.watchdogAction()
+	if watchdogAction == "dump" {
+		watchdogAction = "pause"
+	}
+
+	if watchdogAction != "" {
+		actions["watchdog"] = watchdogAction
+	}
+
 	err = monitor.SetAction(actions)
 	if err != nil {
 		op.Done(err)
@@ -3885,6 +3914,83 @@ func (d *qemu) onRTCChange(change int) error {
 	return nil
 }
 
+// watchdogAction returns the action configured on the instance's watchdog device, if any.
+func (d *qemu) watchdogAction() string {
+	for _, devConfig := range d.expandedDevices {
+		if devConfig["type"] != "watchdog" {
+			continue
+		}
+
+		if devConfig["action"] == "" {
+			return "reset"
+		}
+
+		return devConfig["action"]
+	}
+
+	return ""
+}
+
+// onWatchdog records the guest watchdog timer expiring and takes a memory dump if requested.
+func (d *qemu) onWatchdog() {
+	action := d.watchdogAction()
+	d.logger.Warn("Instance watchdog triggered", logger.Ctx{"action": action})
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceWatchdogTriggered.Event(d, map[string]any{"action": action}))
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, d.project.Name, dbCluster.TypeInstance, d.id, warningtype.InstanceWatchdogTriggered, fmt.Sprintf("Watchdog triggered with action %q", action))
+	})
+	if err != nil {
+		d.logger.Warn("Failed recording watchdog warning", logger.Ctx{"err": err})
+	}
+
+	if action != "dump" {
+		return
+	}
+
+	// QEMU was told to pause the guest, dump its memory and then reset it.
+	err = d.watchdogDump()
+	if err != nil {
+		d.logger.Error("Failed handling watchdog memory dump", logger.Ctx{"err": err})
+	}
+}
+
+// watchdogDump writes the memory of the paused guest to the instance log directory and resets it.
+func (d *qemu) watchdogDump() error {
+	monitor, err := d.qmpConnect()
+	if err != nil {
+		return err
+	}
+
+	dumpPath := filepath.Join(d.LogPath(), fmt.Sprintf("watchdog-%s.dump", time.Now().UTC().Format("20060102150405")))
+	f, err := os.OpenFile(dumpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed creating memory dump file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	err = monitor.SendFile("memory-dump", f)
+	if err != nil {
+		return err
+	}
+
+	err = monitor.DumpGuestMemory("memory-dump", "elf")
+	if err != nil {
+		return fmt.Errorf("Failed dumping guest memory: %w", err)
+	}
+
+	d.logger.Info("Saved watchdog memory dump", logger.Ctx{"path": dumpPath})
+
+	err = monitor.Reset()
+	if err != nil {
+		return err
+	}
+
+	return monitor.Start()
+}
+
 // generateQemuConfig generates the QEMU configuration.
 func (d *qemu) generateQemuConfig(bs *qemuBootState, mountInfo *storagePools.MountInfo, busName string, vsockFD int, devConfs []*deviceConfig.RunConfig, fdFiles *[]*os.File) ([]monitorHook, error) {
 	var monHooks []monitorHook
@@ -4345,6 +4451,22 @@ func (d *qemu) generateQemuConfig(bs *qemuBootState, mountInfo *storagePools.Mou
 				return nil, err
 			}
 		}
+
+		// Add watchdog device.
+		if len(runConf.WatchdogDevice) > 0 {
+			err = d.addWatchdogDeviceConfig(&conf, bus, runConf.WatchdogDevice)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Add serial device.
+		if len(runConf.SerialDevice) > 0 {
+			err = d.addSerialDeviceConfig(&conf, bus, runConf.SerialDevice, fdFiles)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// VM generation ID is only available on x86.
@@ -4547,12 +4669,13 @@ func (d *qemu) addRootDriveConfig(qemuDev map[string]any, mountInfo *storagePool
 
 	// Generate a new device config with the root device path expanded.
 	driveConf := deviceConfig.MountEntryItem{
-		DevName:     rootDriveConf.DevName,
-		DevPath:     mountInfo.DiskPath,
-		BackingPath: mountInfo.BackingPath,
-		Opts:        rootDriveConf.Opts,
-		TargetPath:  rootDriveConf.TargetPath,
-		Limits:      rootDriveConf.Limits,
+		DevName:      rootDriveConf.DevName,
+		DevPath:      mountInfo.DiskPath,
+		BackingPath:  mountInfo.BackingPath,
+		BackingImage: mountInfo.BackingImage,
+		Opts:         rootDriveConf.Opts,
+		TargetPath:   rootDriveConf.TargetPath,
+		Limits:       rootDriveConf.Limits,
 	}
 
 	if d.storagePool.Driver().Info().Remote {
@@ -5085,7 +5208,7 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 					"node-name": d.blockNodeName(escapedDeviceName),
 					"read-only": false,
 					"file": map[string]any{
-						"driver":   "host_device",
+						"driver":   blockDev["driver"],
 						"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
 						"aio":      aioMode,
 						"cache": map[string]any{
@@ -5102,6 +5225,13 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 						return err
 					}
 
+					blockDev["backing"] = backingBlockDev
+				} else if driveConf.BackingImage != "" {
+					backingBlockDev, err := d.lazyImageBlockDev(m, nodeName, aioMode, driveConf.BackingImage)
+					if err != nil {
+						return err
+					}
+
 					blockDev["backing"] = backingBlockDev
 				}
 			} else {
@@ -5114,6 +5244,14 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 			return fmt.Errorf("Failed adding block device for disk device %q: %w", driveConf.DevName, err)
 		}
 
+		// Start copying the image data into lazily loaded drives.
+		if driveConf.BackingImage != "" && len(driveConf.BackingPath) == 0 {
+			err = m.BlockStream(nodeName)
+			if err != nil {
+				return fmt.Errorf("Failed starting to load disk device %q from its image: %w", driveConf.DevName, err)
+			}
+		}
+
 		if driveConf.Limits != nil {
 			err = m.SetBlockThrottle(qemuDev["id"].(string), int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
 			if err != nil {
@@ -5707,6 +5845,146 @@ func (d *qemu) addTPMDeviceConfig(conf *[]cfg.Section, tpmConfig []deviceConfig.
 	return nil
 }
 
+// addWatchdogDeviceConfig adds the qemu config required for adding a watchdog device.
+func (d *qemu) addWatchdogDeviceConfig(conf *[]cfg.Section, bus *qemuBus, watchdogConfig []deviceConfig.RunConfigItem) error {
+	var devName, model string
+	for _, watchdogItem := range watchdogConfig {
+		switch watchdogItem.Key {
+		case "devName":
+			devName = watchdogItem.Value
+		case "model":
+			model = watchdogItem.Value
+		}
+	}
+
+	watchdogOpts := qemuWatchdogOpts{
+		devName: devName,
+		model:   model,
+	}
+
+	if model == "ib700" {
+		if d.architecture != osarch.ARCH_64BIT_INTEL_X86 {
+			return fmt.Errorf("Watchdog model %q is only supported on x86_64", model)
+		}
+	} else {
+		if !slices.Contains([]string{"pcie", "pci"}, bus.name) {
+			return fmt.Errorf("Watchdog model %q requires a PCI bus", model)
+		}
+
+		devBus, devAddr, multi := bus.allocate(busFunctionGroupNone)
+		watchdogOpts.dev = qemuDevOpts{
+			busName:       bus.name,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		}
+	}
+
+	*conf = append(*conf, qemuWatchdog(&watchdogOpts)...)
+
+	return nil
+}
+
+// addSerialDeviceConfig adds the qemu config required for adding a serial device.
+func (d *qemu) addSerialDeviceConfig(conf *[]cfg.Section, bus *qemuBus, serialConfig []deviceConfig.RunConfigItem, fdFiles *[]*os.File) error {
+	var devName, model, portName, sourceType, source string
+	for _, serialItem := range serialConfig {
+		switch serialItem.Key {
+		case "devName":
+			devName = serialItem.Value
+		case "model":
+			model = serialItem.Value
+		case "name":
+			portName = serialItem.Value
+		case "sourceType":
+			sourceType = serialItem.Value
+		case "source":
+			source = serialItem.Value
+		}
+	}
+
+	serialOpts := qemuSerialPortOpts{
+		devName:  devName,
+		model:    model,
+		portName: portName,
+	}
+
+	// The 16550 UART sits on the ISA bus on x86_64 and on the PCI bus elsewhere.
+	if model == "16550" {
+		if d.architecture == osarch.ARCH_64BIT_INTEL_X86 {
+			serialOpts.model = "isa-serial"
+		} else {
+			if !slices.Contains([]string{"pcie", "pci"}, bus.name) {
+				return fmt.Errorf("Serial model %q requires a PCI bus", model)
+			}
+
+			serialOpts.model = "pci-serial"
+
+			devBus, devAddr, multi := bus.allocate(busFunctionGroupNone)
+			serialOpts.dev = qemuDevOpts{
+				busName:       bus.name,
+				devBus:        devBus,
+				devAddr:       devAddr,
+				multifunction: multi,
+			}
+		}
+	}
+
+	switch sourceType {
+	case device.SerialSourceHost:
+		if d.state.OS.UnprivUser != "" {
+			err := os.Chown(source, int(d.state.OS.UnprivUID), -1)
+			if err != nil {
+				return fmt.Errorf("Failed to chown serial device %q: %w", source, err)
+			}
+		}
+
+		serialOpts.hostPath = source
+
+	case device.SerialSourceUnix:
+		// Remove any leftover socket.
+		_ = os.Remove(source)
+
+		listener, err := linux.ListenUnix(source)
+		if err != nil {
+			return fmt.Errorf("Failed listening on %q: %w", source, err)
+		}
+
+		defer func() { _ = listener.Close() }()
+
+		f, err := listener.File()
+		if err != nil {
+			return err
+		}
+
+		serialOpts.fd = d.addFileDescriptor(fdFiles, f)
+
+	case device.SerialSourceTCP:
+		listener, err := net.Listen("tcp", source)
+		if err != nil {
+			return fmt.Errorf("Failed listening on %q: %w", source, err)
+		}
+
+		defer func() { _ = listener.Close() }()
+
+		tcpListener, ok := listener.(*net.TCPListener)
+		if !ok {
+			return errors.New("Unexpected TCP listener type")
+		}
+
+		f, err := tcpListener.File()
+		if err != nil {
+			return err
+		}
+
+		serialOpts.fd = d.addFileDescriptor(fdFiles, f)
+	}
+
+	*conf = append(*conf, qemuSerialPort(&serialOpts)...)
+
+	return nil
+}
+
 func (d *qemu) addVmgenDeviceConfig(conf *[]cfg.Section, guid string) error {
 	vmgenIDOpts := qemuVmgenIDOpts{
 		guid: guid,
@@ -6677,6 +6955,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 		// Only certain keys can be changed on a running VM.
 		liveUpdateKeys := []string{
 			"cluster.evacuate",
+			"cluster.healing_priority",
 			"limits.memory",
 			"security.agent.metrics",
 			"security.csm",
@@ -6690,6 +6969,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			"cloud-init.",
 			"environment.",
 			"image.",
+			"placement.",
 			"snapshots.",
 			"user.",
 			"volatile.",
@@ -7619,10 +7899,33 @@ func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties m
 
 	fPath := fmt.Sprintf("%s/rootfs.img", tmpPath)
 
+	// Lazily loaded root disks are qcow2 and may still depend on their image.
+	srcFormat := "raw"
+	if mountInfo.BackingImage != "" {
+		err = storageDrivers.Qcow2Flatten(mountInfo.DiskPath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed flattening root disk: %w", err)
+		}
+
+		err = storagePools.LazyImageRelease(d.id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	isQcow2, err := d.isQCOW2(mountInfo.DiskPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed checking disk format: %w", err)
+	}
+
+	if isQcow2 {
+		srcFormat = storageDrivers.BlockVolumeTypeQcow2
+	}
+
 	// Convert to qcow2 image.
 	cmd := []string{
 		"nice", "-n19", // Run with low priority to reduce CPU impact on other processes.
-		"qemu-img", "convert", "-p", "-f", "raw", "-O", "qcow2",
+		"qemu-img", "convert", "-p", "-f", srcFormat, "-O", "qcow2",
 	}
 
 	if rootfsWriter != nil {
@@ -9230,6 +9533,18 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	return file, chDisconnect, nil
 }
 
+// SerialConsole gets access to one of the instance's serial devices.
+func (d *qemu) SerialConsole(devName string) (*os.File, chan error, error) {
+	file, err := device.SerialConnect(d, devName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceConsole.Event(d, logger.Ctx{"type": instance.ConsoleTypeConsole, "device": devName}))
+
+	return file, make(chan error, 1), nil
+}
+
 // Exec a command inside the instance.
 func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	reverter := revert.New()
@@ -9947,7 +10262,7 @@ func (d *qemu) statusCode() api.StatusCode {
 		}
 
 		return api.Running
-	case "inmigrate", "postmigrate", "finish-migrate", "save-vm", "suspended", "paused":
+	case "inmigrate", "postmigrate", "finish-migrate", "save-vm", "suspended", "paused", "watchdog":
 		return api.Frozen
 	default:
 		return api.Error
@@ -11298,6 +11613,43 @@ func (d *qemu) qcow2BlockDev(m *qmp.Monitor, nodeName string, aioMode string, di
 	return backingNodeName, nil
 }
 
+// lazyImageBlockDev adds the read-only image root disk backing a lazily loaded drive and returns its node name.
+func (d *qemu) lazyImageBlockDev(m *qmp.Monitor, nodeName string, aioMode string, imagePath string) (string, error) {
+	backingNodeName := fmt.Sprintf("%s_image", nodeName)
+
+	f, err := os.OpenFile(imagePath, unix.O_RDONLY, 0)
+	if err != nil {
+		return "", fmt.Errorf("Failed opening file descriptor for image %q: %w", imagePath, err)
+	}
+
+	defer logger.WarnOnError(f.Close, "Failed to close file")
+
+	info, err := m.SendFileWithFDSet(backingNodeName, f, true)
+	if err != nil {
+		return "", fmt.Errorf("Failed sending file descriptor of %q for image %q: %w", f.Name(), imagePath, err)
+	}
+
+	blockDev := map[string]any{
+		"driver":    "qcow2",
+		"node-name": backingNodeName,
+		"read-only": true,
+		"backing":   nil, // The image root disk never has a backing file of its own.
+		"file": map[string]any{
+			"driver":   "file",
+			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
+			"aio":      aioMode,
+			"locking":  "off",
+		},
+	}
+
+	err = m.AddBlockDevice(blockDev, nil, false)
+	if err != nil {
+		return "", err
+	}
+
+	return backingNodeName, nil
+}
+
 // currentQcow2OverlayIndex returns the current maximum overlay index.
 func currentQcow2OverlayIndex(names []string, prefix string) int {
 	re := regexp.MustCompile(fmt.Sprintf(`^%s_overlay(\d+)$`, prefix))
@@ -1137,6 +1137,95 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_watchdog", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuWatchdogOpts
+			expected string
+		}{{
+			qemuWatchdogOpts{
+				dev:     qemuDevOpts{"pcie", "qemu_pcie3", "00.0", false},
+				devName: "myWatchdog",
+				model:   "i6300esb",
+			},
+			`# Watchdog
+			[device "dev-incus_myWatchdog"]
+			addr = "00.0"
+			bus = "qemu_pcie3"
+			driver = "i6300esb"`,
+		}, {
+			qemuWatchdogOpts{
+				devName: "myWatchdog",
+				model:   "ib700",
+			},
+			`# Watchdog
+			[device "dev-incus_myWatchdog"]
+			driver = "ib700"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuWatchdog(&tc.opts))
+		}
+	})
+
+	t.Run("qemu_serial_port", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuSerialPortOpts
+			expected string
+		}{{
+			qemuSerialPortOpts{
+				devName:  "mySerial",
+				model:    "virtio",
+				portName: "appliance",
+				fd:       5,
+			},
+			`# Serial port
+			[chardev "qemu_serial-chardev_mySerial"]
+			backend = "socket"
+			fd = "5"
+			server = "on"
+			wait = "off"
+
+			[device "dev-incus_mySerial"]
+			bus = "dev-qemu_serial.0"
+			chardev = "qemu_serial-chardev_mySerial"
+			driver = "virtserialport"
+			name = "appliance"`,
+		}, {
+			qemuSerialPortOpts{
+				devName:  "mySerial",
+				model:    "isa-serial",
+				hostPath: "/dev/ttyUSB0",
+			},
+			`# Serial port
+			[chardev "qemu_serial-chardev_mySerial"]
+			backend = "serial"
+			path = "/dev/ttyUSB0"
+
+			[device "dev-incus_mySerial"]
+			chardev = "qemu_serial-chardev_mySerial"
+			driver = "isa-serial"`,
+		}, {
+			qemuSerialPortOpts{
+				dev:      qemuDevOpts{"pcie", "qemu_pcie4", "00.0", false},
+				devName:  "mySerial",
+				model:    "pci-serial",
+				hostPath: "/dev/ttyS4",
+			},
+			`# Serial port
+			[chardev "qemu_serial-chardev_mySerial"]
+			backend = "serial"
+			path = "/dev/ttyS4"
+
+			[device "dev-incus_mySerial"]
+			addr = "00.0"
+			bus = "qemu_pcie4"
+			chardev = "qemu_serial-chardev_mySerial"
+			driver = "pci-serial"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuSerialPort(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_raw_cfg_override", func(t *testing.T) {
 		conf := []cfg.Section{{
 			Name: "global",
@@ -1001,6 +1001,95 @@ func qemuTPM(opts *qemuTPMOpts) []cfg.Section {
 	}}
 }
 
+type qemuWatchdogOpts struct {
+	dev     qemuDevOpts
+	devName string
+	model   string
+}
+
+func qemuWatchdog(opts *qemuWatchdogOpts) []cfg.Section {
+	var entries map[string]string
+
+	// The ib700 is an ISA device and doesn't sit on the PCI bus.
+	if opts.model == "ib700" {
+		entries = map[string]string{
+			"driver": "ib700",
+		}
+	} else {
+		entries = qemuDeviceEntries(&qemuDevEntriesOpts{
+			dev:     opts.dev,
+			pciName: opts.model,
+		})
+	}
+
+	return []cfg.Section{{
+		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
+		Comment: "Watchdog",
+		Entries: entries,
+	}}
+}
+
+type qemuSerialPortOpts struct {
+	dev      qemuDevOpts
+	devName  string
+	model    string
+	portName string
+	hostPath string
+	fd       int
+}
+
+func qemuSerialPort(opts *qemuSerialPortOpts) []cfg.Section {
+	chardev := fmt.Sprintf("qemu_serial-chardev_%s", opts.devName)
+
+	// Ports are either wired to a host device or to a listening socket passed by Incus.
+	chardevEntries := map[string]string{
+		"backend": "socket",
+		"fd":      fmt.Sprintf("%d", opts.fd),
+		"server":  "on",
+		"wait":    "off",
+	}
+
+	if opts.hostPath != "" {
+		chardevEntries = map[string]string{
+			"backend": "serial",
+			"path":    opts.hostPath,
+		}
+	}
+
+	var entries map[string]string
+
+	switch opts.model {
+	case "virtio":
+		entries = map[string]string{
+			"driver": "virtserialport",
+			"name":   opts.portName,
+			"bus":    "dev-qemu_serial.0",
+		}
+
+	case "pci-serial":
+		entries = qemuDeviceEntries(&qemuDevEntriesOpts{
+			dev:     opts.dev,
+			pciName: "pci-serial",
+		})
+
+	default:
+		entries = map[string]string{
+			"driver": opts.model,
+		}
+	}
+
+	entries["chardev"] = chardev
+
+	return []cfg.Section{{
+		Name:    fmt.Sprintf(`chardev "%s"`, chardev),
+		Comment: "Serial port",
+		Entries: chardevEntries,
+	}, {
+		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
+		Entries: entries,
+	}}
+}
+
 type qemuVmgenIDOpts struct {
 	guid string
 }
@@ -1536,6 +1536,25 @@ func (m *Monitor) BlockCommit(deviceNodeName string, top string, base string) er
 	return nil
 }
 
+// BlockStream starts copying the data of the device's backing chain into the device itself.
+// The job runs in the background and the backing chain is dropped from the device once it completes.
+func (m *Monitor) BlockStream(deviceNodeName string) error {
+	var args struct {
+		Device string `json:"device"`
+		JobID  string `json:"job-id"`
+	}
+
+	args.Device = deviceNodeName
+	args.JobID = args.Device
+
+	err := m.Run("block-stream", args, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // BlockDevMirror mirrors the top device to the target device.
 func (m *Monitor) BlockDevMirror(deviceNodeName string, targetNodeName string) error {
 	var args struct {
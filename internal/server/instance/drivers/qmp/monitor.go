@@ -57,6 +57,9 @@ var EventBlockJobCompleted = "BLOCK_JOB_COMPLETED"
 // EventBlockJobError is emitted when a block job has errored.
 var EventBlockJobError = "BLOCK_JOB_ERROR"
 
+// EventWatchdog is emitted when the guest watchdog timer expires.
+var EventWatchdog = "WATCHDOG"
+
 // ExcludedCommands is used to filter verbose commands from the QMP logs.
 var ExcludedCommands = []string{"ringbuf-read"}
 
@@ -637,6 +637,7 @@ func SuitableArchitectures(ctx context.Context, s *state.State, tx *db.ClusterTx
 					Proxy:         s.Proxy,
 					CachePath:     s.OS.CacheDir,
 					CacheExpiry:   time.Hour,
+					Mirrors:       s.GlobalConfig.ImagesRegistryMirrors(req.Source.Server),
 				})
 				if err != nil {
 					return nil, err
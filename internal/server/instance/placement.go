@@ -0,0 +1,123 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// placementPeer represents another instance of the project referenced by a placement rule.
+type placementPeer struct {
+	name     string
+	location string
+}
+
+// PlacementRules represents the affinity and anti-affinity rules applying to an instance.
+type PlacementRules struct {
+	project string
+	name    string
+	tag     string
+
+	// Instances that should run on the same cluster member.
+	affinity []*placementPeer
+
+	// Instances that should run on a different cluster member.
+	antiAffinity []*placementPeer
+}
+
+// LoadPlacementRules loads the placement rules of the instance from its expanded configuration.
+//
+// Affinity is symmetric, instances referencing this instance in their own "placement.affinity" are
+// taken into account too. Instances sharing the "placement.anti-affinity" tag are kept apart.
+func LoadPlacementRules(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, config map[string]string) (*PlacementRules, error) {
+	rules := &PlacementRules{
+		project: projectName,
+		name:    instanceName,
+		tag:     config["placement.anti-affinity"],
+	}
+
+	affinity := util.SplitNTrimSpace(config["placement.affinity"], ",", -1, true)
+
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+		if inst.Name == instanceName {
+			return nil
+		}
+
+		peer := &placementPeer{name: inst.Name, location: inst.Node}
+		peerConfig := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+
+		if slices.Contains(affinity, inst.Name) || slices.Contains(util.SplitNTrimSpace(peerConfig["placement.affinity"], ",", -1, true), instanceName) {
+			rules.affinity = append(rules.affinity, peer)
+		}
+
+		if rules.tag != "" && peerConfig["placement.anti-affinity"] == rules.tag {
+			rules.antiAffinity = append(rules.antiAffinity, peer)
+		}
+
+		return nil
+	}, cluster.InstanceFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading placement rules for instance %q in project %q: %w", instanceName, projectName, err)
+	}
+
+	return rules, nil
+}
+
+// Empty returns whether the instance has no placement rules.
+func (p *PlacementRules) Empty() bool {
+	return len(p.affinity) == 0 && len(p.antiAffinity) == 0
+}
+
+// Relocate records that another instance of the project is (or will be) running on the given cluster member.
+func (p *PlacementRules) Relocate(projectName string, instanceName string, memberName string) {
+	if projectName != p.project {
+		return
+	}
+
+	for _, peer := range append(slices.Clone(p.affinity), p.antiAffinity...) {
+		if peer.name == instanceName {
+			peer.location = memberName
+		}
+	}
+}
+
+// Check returns an error describing the violated rules if the instance was to run on the given cluster member.
+func (p *PlacementRules) Check(memberName string) error {
+	var violations []string
+
+	for _, peer := range p.affinity {
+		if peer.location != memberName {
+			violations = append(violations, fmt.Sprintf("affinity with %q on %q", peer.name, peer.location))
+		}
+	}
+
+	for _, peer := range p.antiAffinity {
+		if peer.location == memberName {
+			violations = append(violations, fmt.Sprintf("anti-affinity %q with %q", p.tag, peer.name))
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("Instance %q in project %q on %q violates placement rules: %s", p.name, p.project, memberName, strings.Join(violations, ", "))
+	}
+
+	return nil
+}
+
+// Filter returns the candidate cluster members on which the instance would respect its placement rules.
+func (p *PlacementRules) Filter(candidates []db.NodeInfo) []db.NodeInfo {
+	filtered := make([]db.NodeInfo, 0, len(candidates))
+	for _, candidate := range candidates {
+		if p.Check(candidate.Name) == nil {
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,46 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+)
+
+func TestPlacementRules(t *testing.T) {
+	rules := &PlacementRules{project: "default", name: "c1"}
+	assert.True(t, rules.Empty())
+	assert.NoError(t, rules.Check("server01"))
+
+	rules = &PlacementRules{
+		project:      "default",
+		name:         "c1",
+		tag:          "web",
+		affinity:     []*placementPeer{{name: "db1", location: "server01"}},
+		antiAffinity: []*placementPeer{{name: "c2", location: "server02"}},
+	}
+
+	assert.False(t, rules.Empty())
+	assert.NoError(t, rules.Check("server01"))
+	assert.Error(t, rules.Check("server02"))
+	assert.Error(t, rules.Check("server03"))
+
+	candidates := []db.NodeInfo{{Name: "server01"}, {Name: "server02"}, {Name: "server03"}}
+	assert.Equal(t, []db.NodeInfo{{Name: "server01"}}, rules.Filter(candidates))
+
+	// Instances from other projects aren't peers.
+	rules.Relocate("foo", "db1", "server03")
+	assert.NoError(t, rules.Check("server01"))
+
+	// Moving the peers changes where the instance can go.
+	rules.Relocate("default", "db1", "server02")
+	rules.Relocate("default", "c2", "server01")
+	assert.Error(t, rules.Check("server01"))
+	assert.NoError(t, rules.Check("server02"))
+	assert.Equal(t, []db.NodeInfo{{Name: "server02"}}, rules.Filter(candidates))
+
+	// Conflicting rules leave no candidate.
+	rules.Relocate("default", "c2", "server02")
+	assert.Empty(t, rules.Filter(candidates))
+}
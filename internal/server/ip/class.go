@@ -16,9 +16,11 @@ type Class struct {
 }
 
 // ClassHTB represents htb qdisc class object.
+// If Ceil is empty, the class can't borrow bandwidth beyond its rate.
 type ClassHTB struct {
 	Class
 	Rate string
+	Ceil string
 }
 
 // Add adds class to a node.
@@ -59,6 +61,15 @@ func (class *ClassHTB) Add() error {
 		htbClassAttrs.Rate = uint64(rate)
 	}
 
+	if class.Ceil != "" {
+		ceil, err := units.ParseBitSizeString(class.Ceil)
+		if err != nil {
+			return fmt.Errorf("Invalid ceil %q: %w", class.Ceil, err)
+		}
+
+		htbClassAttrs.Ceil = uint64(ceil)
+	}
+
 	err = netlink.ClassAdd(netlink.NewHtbClass(classAttrs, htbClassAttrs))
 	if err != nil {
 		return fmt.Errorf("Failed to add htb class: %w", err)
@@ -66,3 +77,34 @@ func (class *ClassHTB) Add() error {
 
 	return nil
 }
+
+// Delete deletes class from a node.
+func (class *ClassHTB) Delete() error {
+	link, err := linkByName(class.Dev)
+	if err != nil {
+		return err
+	}
+
+	parent, err := parseHandle(class.Parent)
+	if err != nil {
+		return err
+	}
+
+	handle, err := parseHandle(class.Classid)
+	if err != nil {
+		return err
+	}
+
+	classAttrs := netlink.ClassAttrs{
+		LinkIndex: link.Attrs().Index,
+		Parent:    parent,
+		Handle:    handle,
+	}
+
+	err = netlink.ClassDel(netlink.NewHtbClass(classAttrs, netlink.HtbClassAttrs{}))
+	if err != nil {
+		return fmt.Errorf("Failed to delete htb class: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package ip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ConntrackTuple represents one direction of a connection tracking entry.
+type ConntrackTuple struct {
+	Src     net.IP
+	Dst     net.IP
+	SrcPort uint16
+	DstPort uint16
+	Bytes   uint64
+	Packets uint64
+}
+
+// ConntrackFlow represents a connection tracking entry.
+type ConntrackFlow struct {
+	Protocol uint8
+	Zone     uint16
+	Original ConntrackTuple
+	Reply    ConntrackTuple
+}
+
+// ConntrackList returns the entries of the connection tracking table for both IPv4 and IPv6.
+// The byte and packet counters are only populated if connection tracking accounting is enabled.
+func ConntrackList() ([]ConntrackFlow, error) {
+	var flows []ConntrackFlow
+
+	for _, family := range []netlink.InetFamily{unix.AF_INET, unix.AF_INET6} {
+		list, err := netlink.ConntrackTableList(netlink.ConntrackTable, family)
+		if err != nil && !errors.Is(err, netlink.ErrDumpInterrupted) {
+			return nil, fmt.Errorf("Failed to list connection tracking entries: %w", err)
+		}
+
+		for _, flow := range list {
+			flows = append(flows, ConntrackFlow{
+				Protocol: flow.Forward.Protocol,
+				Zone:     flow.Zone,
+				Original: ConntrackTuple{
+					Src:     flow.Forward.SrcIP,
+					Dst:     flow.Forward.DstIP,
+					SrcPort: flow.Forward.SrcPort,
+					DstPort: flow.Forward.DstPort,
+					Bytes:   flow.Forward.Bytes,
+					Packets: flow.Forward.Packets,
+				},
+				Reply: ConntrackTuple{
+					Src:     flow.Reverse.SrcIP,
+					Dst:     flow.Reverse.DstIP,
+					SrcPort: flow.Reverse.SrcPort,
+					DstPort: flow.Reverse.DstPort,
+					Bytes:   flow.Reverse.Bytes,
+					Packets: flow.Reverse.Packets,
+				},
+			})
+		}
+	}
+
+	return flows, nil
+}
@@ -1,7 +1,9 @@
 package ip
 
 import (
+	"encoding/binary"
 	"fmt"
+	"net"
 
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
@@ -36,6 +38,20 @@ func (a *ActionPolice) toNetlink() (netlink.Action, error) {
 	return action, nil
 }
 
+// ActionMirred represents an action of 'mirred' type redirecting packets to another device.
+type ActionMirred struct {
+	Dev string
+}
+
+func (a *ActionMirred) toNetlink() (netlink.Action, error) {
+	link, err := linkByName(a.Dev)
+	if err != nil {
+		return nil, err
+	}
+
+	return netlink.NewMirredAction(link.Attrs().Index), nil
+}
+
 // Filter represents filter object.
 type Filter struct {
 	Dev      string
@@ -44,12 +60,51 @@ type Filter struct {
 	Flowid   string
 }
 
+// U32Match represents a single 32bit match of a u32 filter.
+// The offset is relative to the network header and must be a multiple of 4.
+type U32Match struct {
+	Value  uint32
+	Mask   uint32
+	Offset int32
+}
+
 // U32Filter represents universal 32bit traffic control filter.
+// If Matches is empty, a single match using Value and Mask is used.
+// Handle and Priority are optional, they are needed to later delete a specific filter.
 type U32Filter struct {
 	Filter
-	Value   uint32
-	Mask    uint32
-	Actions []Action
+	Value    uint32
+	Mask     uint32
+	Matches  []U32Match
+	Actions  []Action
+	Handle   uint32
+	Priority uint16
+}
+
+// U32Handle returns the handle of the u32 filter node in the default hash table.
+func U32Handle(node uint16) uint32 {
+	return 0x800<<20 | uint32(node&0xfff)
+}
+
+// U32MACMatches returns the u32 matches for the source or destination MAC address of the Ethernet header.
+func U32MACMatches(hwaddr net.HardwareAddr, source bool) []U32Match {
+	if len(hwaddr) != 6 {
+		return nil
+	}
+
+	if source {
+		// The source address starts 8 bytes before the network header.
+		return []U32Match{
+			{Value: binary.BigEndian.Uint32(hwaddr[0:4]), Mask: 0xffffffff, Offset: -8},
+			{Value: uint32(binary.BigEndian.Uint16(hwaddr[4:6])) << 16, Mask: 0xffff0000, Offset: -4},
+		}
+	}
+
+	// The destination address starts 14 bytes before the network header.
+	return []U32Match{
+		{Value: uint32(binary.BigEndian.Uint16(hwaddr[0:2])), Mask: 0x0000ffff, Offset: -16},
+		{Value: binary.BigEndian.Uint32(hwaddr[2:6]), Mask: 0xffffffff, Offset: -12},
+	}
 }
 
 func parseProtocol(proto string) (uint16, error) {
@@ -77,20 +132,25 @@ func (u32 *U32Filter) Add() error {
 		FilterAttrs: netlink.FilterAttrs{
 			LinkIndex: link.Attrs().Index,
 			Protocol:  proto,
+			Handle:    u32.Handle,
+			Priority:  u32.Priority,
 			Chain:     nil,
 		},
 		Sel: &netlink.TcU32Sel{
 			Flags: netlink.TC_U32_TERMINAL,
-			Nkeys: 1,
-			Keys: []netlink.TcU32Key{
-				{
-					Mask: u32.Mask,
-					Val:  u32.Value,
-				},
-			},
 		},
 	}
 
+	if len(u32.Matches) > 0 {
+		for _, match := range u32.Matches {
+			filter.Sel.Keys = append(filter.Sel.Keys, netlink.TcU32Key{Mask: match.Mask, Val: match.Value, Off: match.Offset})
+		}
+	} else {
+		filter.Sel.Keys = []netlink.TcU32Key{{Mask: u32.Mask, Val: u32.Value}}
+	}
+
+	filter.Sel.Nkeys = uint8(len(filter.Sel.Keys))
+
 	for _, action := range u32.Actions {
 		netlinkAction, err := action.toNetlink()
 		if err != nil {
@@ -125,3 +185,41 @@ func (u32 *U32Filter) Add() error {
 
 	return nil
 }
+
+// Delete deletes universal 32bit traffic control filter from a node.
+func (u32 *U32Filter) Delete() error {
+	link, err := linkByName(u32.Dev)
+	if err != nil {
+		return err
+	}
+
+	proto, err := parseProtocol(u32.Protocol)
+	if err != nil {
+		return err
+	}
+
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Protocol:  proto,
+			Handle:    u32.Handle,
+			Priority:  u32.Priority,
+		},
+	}
+
+	if u32.Parent != "" {
+		parent, err := parseHandle(u32.Parent)
+		if err != nil {
+			return err
+		}
+
+		filter.Parent = parent
+	}
+
+	err = netlink.FilterDel(filter)
+	if err != nil {
+		return fmt.Errorf("Failed to delete filter %v: %w", filter, err)
+	}
+
+	return nil
+}
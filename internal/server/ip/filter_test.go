@@ -0,0 +1,47 @@
+package ip
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestU32MACMatches(t *testing.T) {
+	hwaddr, err := net.ParseMAC("00:16:3e:12:34:56")
+	require.NoError(t, err)
+
+	// The source address spans the two 32bit words before the network header.
+	assert.Equal(t, []U32Match{
+		{Value: 0x00163e12, Mask: 0xffffffff, Offset: -8},
+		{Value: 0x34560000, Mask: 0xffff0000, Offset: -4},
+	}, U32MACMatches(hwaddr, true))
+
+	// The destination address starts in the lower half of the word 16 bytes before the network header.
+	assert.Equal(t, []U32Match{
+		{Value: 0x00000016, Mask: 0x0000ffff, Offset: -16},
+		{Value: 0x3e123456, Mask: 0xffffffff, Offset: -12},
+	}, U32MACMatches(hwaddr, false))
+
+	// Offsets must be aligned on 32bit words.
+	for _, source := range []bool{true, false} {
+		for _, match := range U32MACMatches(hwaddr, source) {
+			assert.Zero(t, match.Offset%4)
+			assert.Equal(t, match.Value, match.Value&match.Mask)
+		}
+	}
+
+	// Only Ethernet addresses are supported.
+	infiniband, err := net.ParseMAC("00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01")
+	require.NoError(t, err)
+
+	assert.Nil(t, U32MACMatches(infiniband, true))
+	assert.Nil(t, U32MACMatches(nil, false))
+}
+
+func TestU32Handle(t *testing.T) {
+	assert.Equal(t, uint32(0x80000001), U32Handle(1))
+	assert.Equal(t, uint32(0x80000fff), U32Handle(0xfff))
+	assert.Equal(t, uint32(0x80000000), U32Handle(0x1000))
+}
@@ -10,13 +10,15 @@ type ClusterMemberAction string
 
 // All supported lifecycle events for cluster members.
 const (
-	ClusterMemberAdded     = ClusterMemberAction(api.EventLifecycleClusterMemberAdded)
-	ClusterMemberEvacuated = ClusterMemberAction(api.EventLifecycleClusterMemberEvacuated)
-	ClusterMemberHealed    = ClusterMemberAction(api.EventLifecycleClusterMemberHealed)
-	ClusterMemberRemoved   = ClusterMemberAction(api.EventLifecycleClusterMemberRemoved)
-	ClusterMemberRenamed   = ClusterMemberAction(api.EventLifecycleClusterMemberRenamed)
-	ClusterMemberRestored  = ClusterMemberAction(api.EventLifecycleClusterMemberRestored)
-	ClusterMemberUpdated   = ClusterMemberAction(api.EventLifecycleClusterMemberUpdated)
+	ClusterMemberAdded         = ClusterMemberAction(api.EventLifecycleClusterMemberAdded)
+	ClusterMemberEvacuated     = ClusterMemberAction(api.EventLifecycleClusterMemberEvacuated)
+	ClusterMemberFenced        = ClusterMemberAction(api.EventLifecycleClusterMemberFenced)
+	ClusterMemberFencingFailed = ClusterMemberAction(api.EventLifecycleClusterMemberFencingFailed)
+	ClusterMemberHealed        = ClusterMemberAction(api.EventLifecycleClusterMemberHealed)
+	ClusterMemberRemoved       = ClusterMemberAction(api.EventLifecycleClusterMemberRemoved)
+	ClusterMemberRenamed       = ClusterMemberAction(api.EventLifecycleClusterMemberRenamed)
+	ClusterMemberRestored      = ClusterMemberAction(api.EventLifecycleClusterMemberRestored)
+	ClusterMemberUpdated       = ClusterMemberAction(api.EventLifecycleClusterMemberUpdated)
 )
 
 // Event creates the lifecycle event for an action on a cluster member.
@@ -91,6 +91,8 @@ func (c *common) processEvent(event api.Event) bool {
 		}
 
 		return true
+	case api.EventTypeAudit:
+		return contains(c.types, "audit")
 	default:
 		return false
 	}
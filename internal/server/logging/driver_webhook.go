@@ -26,6 +26,7 @@ type WebhookLogger struct {
 	username string
 	password string
 	retry    int
+	filtered bool
 }
 
 // NewWebhookLogger instantiates a new webhook logger.
@@ -71,11 +72,21 @@ func NewWebhookLogger(s *state.State, name string) (*WebhookLogger, error) {
 		username: username,
 		password: password,
 		retry:    retry,
+		filtered: s.GlobalConfig.LoggingFilterConfigured(name),
 	}, nil
 }
 
 // HandleEvent handles the event received from the internal event listener.
 func (c *WebhookLogger) HandleEvent(event api.Event) {
+	// Without any filter configured, send all events but the audit ones.
+	if c.filtered {
+		if !c.processEvent(event) {
+			return
+		}
+	} else if event.Type == api.EventTypeAudit {
+		return
+	}
+
 	// JSON data.
 	data, err := json.Marshal(event)
 	if err != nil {
@@ -15,6 +15,11 @@ var metricTypeGauges = []MetricType{
 	CPUs,
 	GoGoroutines,
 	GoHeapObjects,
+	NetworkFlowConnections,
+	NetworkFlowReceiveBytes,
+	NetworkFlowTransmitBytes,
+	NetworkForwardConnections,
+	NetworkLoadBalancerConnections,
 	ProcsTotal,
 	ProjectLimit,
 	ProjectResourcesTotal,
@@ -56,3 +56,15 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestMetricSet_String_FlowGauges(t *testing.T) {
+	m := NewMetricSet(nil)
+	m.AddSamples(NetworkFlowReceiveBytes, Sample{Value: 10, Labels: map[string]string{"remote": "192.0.2.1"}})
+	m.AddSamples(NetworkFlowTransmitBytes, Sample{Value: 20, Labels: map[string]string{"remote": "192.0.2.1"}})
+
+	// The flow byte counters reflect the currently tracked connections so can decrease.
+	out := m.String()
+	require.Contains(t, out, "# TYPE incus_network_flow_receive_bytes gauge\n")
+	require.Contains(t, out, "# TYPE incus_network_flow_transmit_bytes gauge\n")
+	require.Contains(t, out, "incus_network_flow_receive_bytes{remote=\"192.0.2.1\"} 10\n")
+}
@@ -106,6 +106,11 @@ func (n *bridge) FillConfig(config map[string]string) error {
 		config["ipv4.nat"] = "true"
 	}
 
+	// The IPv6 address comes from the delegated prefix.
+	if config["ipv6.address"] == "" && config["ipv6.prefix_delegation.interface"] != "" {
+		config["ipv6.address"] = "none"
+	}
+
 	if config["ipv6.address"] == "" {
 		content, err := os.ReadFile("/proc/sys/net/ipv6/conf/default/disable_ipv6")
 		if err == nil && string(content) == "0\n" {
@@ -257,6 +262,15 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Whether to enable multicast snooping on the bridge
 		"bridge.multicast_snooping": validate.Optional(validate.IsBool),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=flows.accounting)
+		//
+		// ---
+		//  type: bool
+		//  condition: -
+		//  default: `false`
+		//  shortdesc: Whether to report flow accounting metrics for the instances on the network (see {ref}`network-flow-metrics`)
+		"flows.accounting": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.address)
 		//
 		// ---
@@ -359,6 +373,60 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Static routes to provide via DHCP option 121, as a comma-separated list of alternating subnets (CIDR) and gateway addresses (same syntax as dnsmasq)
 		"ipv4.dhcp.routes": validate.Optional(validate.IsDHCPRouteList),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.next_server)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Address of the server to boot from over the network (used with `ipv4.dhcp.filename` or `ipv4.dhcp.ipxe_filename`)
+		"ipv4.dhcp.next_server": validate.Optional(validate.IsNetworkAddressV4),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.filename)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Boot file name to provide to PXE clients
+		"ipv4.dhcp.filename": validate.IsAny,
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.ipxe_filename)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Boot file name or URL to provide to iPXE clients (for chain-loading)
+		"ipv4.dhcp.ipxe_filename": validate.IsAny,
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.ipxe_script)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 TFTP or HTTP
+		//  default: -
+		//  shortdesc: iPXE script to serve over TFTP or HTTP to iPXE clients (overrides `ipv4.dhcp.ipxe_filename`)
+		"ipv4.dhcp.ipxe_script": validate.IsAny,
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.http)
+		//
+		// ---
+		//  type: bool
+		//  condition: IPv4 DHCP
+		//  default: `false`
+		//  shortdesc: Whether to serve the network's TFTP directory over HTTP on port 80 of the bridge's IPv4 address
+		"ipv4.dhcp.http": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.tftp)
+		//
+		// ---
+		//  type: bool
+		//  condition: IPv4 DHCP
+		//  default: `false`
+		//  shortdesc: Whether to serve the network's TFTP directory (`networks/NAME/tftp` in the Incus directory)
+		"ipv4.dhcp.tftp": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.routes)
 		//
 		// ---
@@ -401,6 +469,24 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 			return validate.Or(validate.IsNetworkAddressCIDRV6, validate.IsNetworkV6)(value)
 		}),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.prefix_delegation.interface)
+		//
+		// ---
+		//  type: string
+		//  condition: standalone server
+		//  default: -
+		//  shortdesc: Host interface on which to request a delegated IPv6 prefix (DHCPv6-PD) to derive `ipv6.address` from
+		"ipv6.prefix_delegation.interface": validate.Optional(validate.IsInterfaceName),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.prefix_delegation.subnet)
+		//
+		// ---
+		//  type: integer
+		//  condition: IPv6 prefix delegation
+		//  default: derived from the network ID
+		//  shortdesc: Index of the `/64` subnet of the delegated prefix to use for the bridge
+		"ipv6.prefix_delegation.subnet": validate.Optional(validate.IsUint32),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.firewall)
 		//
 		// ---
@@ -500,6 +586,24 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Comma-separated list of IPv6 ranges to use for child OVN network routers (FIRST-LAST format)
 		"ipv6.ovn.ranges": validate.Optional(validate.IsListOf(validate.IsNetworkRangeV6)),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=limits.ingress)
+		//
+		// ---
+		//  type: string
+		//  condition: -
+		//  default: -
+		//  shortdesc: Total bandwidth limit in bit/s for traffic going to the instances on the bridge (various suffixes supported, see {ref}`instances-limit-units`)
+		"limits.ingress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=limits.egress)
+		//
+		// ---
+		//  type: string
+		//  condition: -
+		//  default: -
+		//  shortdesc: Total bandwidth limit in bit/s for traffic coming from the instances on the bridge (various suffixes supported, see {ref}`instances-limit-units`)
+		"limits.egress": validate.Optional(validate.IsBitSize),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=dns.nameservers)
 		//
 		// ---
@@ -709,6 +813,23 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 				rules[k] = validate.Optional(validate.IsUint8)
 			}
 		}
+
+		// DHCP option keys have the option code in their name.
+		if strings.HasPrefix(k, "ipv4.dhcp.option.") {
+			// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.option.NUMBER)
+			//
+			// ---
+			//  type: string
+			//  condition: IPv4 DHCP
+			//  default: -
+			//  shortdesc: Value of an arbitrary DHCP option to provide to clients (same syntax as dnsmasq)
+			err := validate.IsInRange(1, 254)(strings.TrimPrefix(k, "ipv4.dhcp.option."))
+			if err != nil {
+				return fmt.Errorf("Invalid DHCP option code in %q: %w", k, err)
+			}
+
+			rules[k] = validate.IsAny
+		}
 	}
 
 	// gendoc:generate(entity=network_bridge, group=bgp, key=bgp.peers.NAME.address)
@@ -773,6 +894,21 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		return err
 	}
 
+	// Prefix delegation relies on a DHCPv6 client running on this server only.
+	if config["ipv6.prefix_delegation.interface"] != "" && n.state != nil && n.state.ServerClustered {
+		return errors.New("IPv6 prefix delegation isn't supported on clustered servers")
+	}
+
+	// An iPXE script can only be served if TFTP or HTTP is enabled.
+	if config["ipv4.dhcp.ipxe_script"] != "" && util.IsFalseOrEmpty(config["ipv4.dhcp.tftp"]) && util.IsFalseOrEmpty(config["ipv4.dhcp.http"]) {
+		return errors.New(`"ipv4.dhcp.ipxe_script" requires "ipv4.dhcp.tftp" or "ipv4.dhcp.http" to be enabled`)
+	}
+
+	// The HTTP server listens on the bridge's IPv4 address.
+	if util.IsTrue(config["ipv4.dhcp.http"]) && util.IsNoneOrEmpty(config["ipv4.address"]) {
+		return errors.New(`"ipv4.dhcp.http" requires "ipv4.address" to be set`)
+	}
+
 	for k, v := range config {
 		key := k
 		// MTU checks
@@ -1467,6 +1603,25 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=121,%s", strings.ReplaceAll(n.config["ipv4.dhcp.routes"], " ", "")))
 			}
 
+			// Add any user provided DHCP options.
+			for _, k := range slices.Sorted(maps.Keys(n.config)) {
+				code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+				if found {
+					dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=%s,%s", code, n.config[k]))
+				}
+			}
+
+			// Tag iPXE clients and load any per-instance DHCP options.
+			dnsmasqCmd = append(dnsmasqCmd, "--dhcp-userclass=set:ipxe,iPXE", fmt.Sprintf("--dhcp-optsdir=%s", internalUtil.VarPath("networks", n.name, "dnsmasq.opts")))
+
+			// Configure network boot.
+			bootArgs, err := n.dhcpBootArgs()
+			if err != nil {
+				return err
+			}
+
+			dnsmasqCmd = append(dnsmasqCmd, bootArgs...)
+
 			expiry := "1h"
 			if n.config["ipv4.dhcp.expiry"] != "" {
 				expiry = n.config["ipv4.dhcp.expiry"]
@@ -1936,6 +2091,14 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			}
 		}
 
+		// Create DHCP options directory.
+		if !util.PathExists(internalUtil.VarPath("networks", n.name, "dnsmasq.opts")) {
+			err = os.MkdirAll(internalUtil.VarPath("networks", n.name, "dnsmasq.opts"), 0o755)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Check for dnsmasq.
 		_, err := exec.LookPath("dnsmasq")
 		if err != nil {
@@ -2022,6 +2185,21 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Serve the network's boot directory over HTTP.
+	if n.hasDHCPv4() && util.IsTrue(n.config["ipv4.dhcp.http"]) && !util.IsNoneOrEmpty(n.config["ipv4.address"]) {
+		ipAddress, _, err := net.ParseCIDR(n.config["ipv4.address"])
+		if err != nil {
+			return err
+		}
+
+		err = bootHTTPStart(n.name, ipAddress)
+		if err != nil {
+			return fmt.Errorf("Failed starting network boot HTTP server: %w", err)
+		}
+	} else {
+		bootHTTPStop(n.name)
+	}
+
 	// Setup firewall.
 	n.logger.Debug("Setting up firewall")
 
@@ -2073,6 +2251,26 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup bandwidth limits.
+	err = n.setupLimits()
+	if err != nil {
+		return fmt.Errorf("Failed to setup bandwidth limits: %w", err)
+	}
+
+	// Enable the connection tracking accounting used by the flow metrics.
+	if util.IsTrue(n.config["flows.accounting"]) {
+		flowAccountingSetup()
+	}
+
+	// Request a delegated IPv6 prefix.
+	if oldConfig != nil && oldConfig["ipv6.prefix_delegation.interface"] != "" && oldConfig["ipv6.prefix_delegation.interface"] != n.config["ipv6.prefix_delegation.interface"] {
+		prefixDelegationStop(oldConfig["ipv6.prefix_delegation.interface"], n.project, n.name)
+	}
+
+	if n.config["ipv6.prefix_delegation.interface"] != "" {
+		prefixDelegationStart(n.state, n.config["ipv6.prefix_delegation.interface"], n.project, n.name)
+	}
+
 	reverter.Success()
 
 	return nil
@@ -2097,6 +2295,26 @@ func (n *bridge) Stop() error {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
 	}
 
+	// Stop requesting a delegated IPv6 prefix.
+	if n.config["ipv6.prefix_delegation.interface"] != "" {
+		prefixDelegationStop(n.config["ipv6.prefix_delegation.interface"], n.project, n.name)
+	}
+
+	// Stop the HTTP address forwards.
+	err = forwardHTTPApply(n.state, n.name, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to stop HTTP address forwards: %w", err)
+	}
+
+	// Stop the network boot HTTP server.
+	bootHTTPStop(n.name)
+
+	// Remove the bandwidth limits (and the ifb device used for egress limits).
+	err = n.clearLimits()
+	if err != nil {
+		return fmt.Errorf("Failed to clear bandwidth limits: %w", err)
+	}
+
 	// Destroy the bridge interface
 	if n.config["bridge.driver"] == "openvswitch" {
 		vswitch, err := n.state.OVS()
@@ -2369,6 +2587,70 @@ func (n *bridge) hasDHCPv6() bool {
 	return util.IsTrueOrEmpty(n.config["ipv6.dhcp"])
 }
 
+// dhcpBootArgs returns the dnsmasq arguments needed for network boot and the built-in TFTP server.
+// PXE clients are handed ipv4.dhcp.filename, while iPXE clients are handed either the network's
+// iPXE script or ipv4.dhcp.ipxe_filename so that they can be chain-loaded.
+// The network settings don't apply to the instances that override them, those are tagged with dnsmasq.DHCPBootTag.
+func (n *bridge) dhcpBootArgs() ([]string, error) {
+	var args []string
+
+	tftpRoot := internalUtil.VarPath("networks", n.name, "tftp")
+	if util.IsTrue(n.config["ipv4.dhcp.tftp"]) || util.IsTrue(n.config["ipv4.dhcp.http"]) {
+		err := os.MkdirAll(tftpRoot, 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating TFTP directory: %w", err)
+		}
+	}
+
+	if util.IsTrue(n.config["ipv4.dhcp.tftp"]) {
+		args = append(args, "--enable-tftp", fmt.Sprintf("--tftp-root=%s", tftpRoot))
+	}
+
+	// Write or clear the network's iPXE script.
+	ipxeFilename := n.config["ipv4.dhcp.ipxe_filename"]
+	ipxeScriptPath := dnsmasq.TFTPPath(n.name, "boot.ipxe")
+	if n.config["ipv4.dhcp.ipxe_script"] != "" {
+		err := os.WriteFile(ipxeScriptPath, []byte(n.config["ipv4.dhcp.ipxe_script"]+"\n"), 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("Failed writing iPXE script: %w", err)
+		}
+
+		ipxeFilename = BridgeBootFileName(n.config, "boot.ipxe")
+	} else {
+		err := os.Remove(ipxeScriptPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("Failed removing iPXE script: %w", err)
+		}
+	}
+
+	bootArg := func(tag string, filename string) string {
+		arg := fmt.Sprintf("--dhcp-boot=tag:!%s,", dnsmasq.DHCPBootTag)
+		if tag != "" {
+			arg += fmt.Sprintf("tag:%s,", tag)
+		}
+
+		arg += filename
+
+		if n.config["ipv4.dhcp.next_server"] != "" {
+			arg += fmt.Sprintf(",,%s", n.config["ipv4.dhcp.next_server"])
+		}
+
+		return arg
+	}
+
+	if ipxeFilename != "" {
+		args = append(args, bootArg("ipxe", ipxeFilename))
+
+		if n.config["ipv4.dhcp.filename"] != "" {
+			args = append(args, bootArg("!ipxe", n.config["ipv4.dhcp.filename"]))
+		}
+	} else if n.config["ipv4.dhcp.filename"] != "" {
+		args = append(args, bootArg("", n.config["ipv4.dhcp.filename"]))
+	}
+
+	return args, nil
+}
+
 // DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
 func (n *bridge) DHCPv4Subnet() *net.IPNet {
 	// DHCP is disabled on this network.
@@ -2412,6 +2694,11 @@ func (n *bridge) forwardConvertToFirewallForwards(listenAddress net.IP, defaultT
 	}
 
 	for _, portMap := range portMaps {
+		// HTTP ports are handled by the forward HTTP proxies rather than the firewall.
+		if slices.Contains([]string{"http", "https"}, portMap.protocol) {
+			continue
+		}
+
 		vips = append(vips, firewallDrivers.AddressForward{
 			ListenAddress: listenAddress,
 			Protocol:      portMap.protocol,
@@ -3226,6 +3513,7 @@ func (n *bridge) forwardSetupFirewall() error {
 
 	var fwForwards []firewallDrivers.AddressForward
 	ipVersions := make(map[uint]struct{})
+	httpPortMaps := make(map[string][]*forwardPortMap)
 
 	for _, forward := range forwards {
 		// Convert listen address to subnet so we can check its valid and can be used.
@@ -3247,6 +3535,12 @@ func (n *bridge) forwardSetupFirewall() error {
 		}
 
 		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
+
+		for _, portMap := range portMaps {
+			if slices.Contains([]string{"http", "https"}, portMap.protocol) {
+				httpPortMaps[listenAddressNet.IP.String()] = append(httpPortMaps[listenAddressNet.IP.String()], portMap)
+			}
+		}
 	}
 
 	// IncusOS doesn't load br_netfilter as it breaks routed proxy traffic, so skip the warning there.
@@ -3281,6 +3575,11 @@ func (n *bridge) forwardSetupFirewall() error {
 		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
 	}
 
+	err = forwardHTTPApply(n.state, n.name, httpPortMaps)
+	if err != nil {
+		return fmt.Errorf("Failed applying HTTP address forwards: %w", err)
+	}
+
 	return nil
 }
 
@@ -54,6 +54,8 @@ type forwardPortMap struct {
 	protocol    string
 	target      forwardTarget
 	snat        bool
+	host        string
+	path        string
 }
 
 type loadBalancerPortMap struct {
@@ -939,12 +941,21 @@ func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwa
 	// Validate port rules.
 	validPortProcols := []string{"tcp", "udp"}
 
+	// HTTP routing is handled by the local daemon, which is only possible for bridge networks.
+	if n.netType == "bridge" {
+		validPortProcols = append(validPortProcols, "http", "https")
+	}
+
 	// Used to ensure that each listen port is only used once.
 	listenPorts := map[string]map[int64]struct{}{
 		"tcp": make(map[int64]struct{}),
 		"udp": make(map[int64]struct{}),
 	}
 
+	// HTTP listen ports can be shared by multiple port specifications with different routes.
+	httpPorts := map[int64]string{}
+	httpRoutes := map[string]struct{}{}
+
 	// Maps portSpecID to a portMap struct.
 	portMaps := make([]*forwardPortMap, 0, len(forward.Ports))
 	for portSpecID, portSpec := range forward.Ports {
@@ -952,6 +963,26 @@ func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwa
 			return nil, fmt.Errorf("Invalid port protocol in port specification %d, protocol must be one of: %s", portSpecID, strings.Join(validPortProcols, ", "))
 		}
 
+		isHTTP := slices.Contains([]string{"http", "https"}, portSpec.Protocol)
+		if isHTTP {
+			if portSpec.SNAT {
+				return nil, fmt.Errorf("SNAT cannot be used with protocol %q in port specification %d", portSpec.Protocol, portSpecID)
+			}
+
+			if portSpec.Host != "" {
+				err := validate.IsHostname(portSpec.Host)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid host in port specification %d: %w", portSpecID, err)
+				}
+			}
+
+			if portSpec.Path != "" && !strings.HasPrefix(portSpec.Path, "/") {
+				return nil, fmt.Errorf("Path must start with a \"/\" in port specification %d", portSpecID)
+			}
+		} else if portSpec.Host != "" || portSpec.Path != "" {
+			return nil, fmt.Errorf("Host and path can only be used with the http and https protocols in port specification %d", portSpecID)
+		}
+
 		targetAddress := net.ParseIP(portSpec.TargetAddress)
 		if targetAddress == nil {
 			return nil, fmt.Errorf("Invalid target address in port specification %d", portSpecID)
@@ -992,6 +1023,8 @@ func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwa
 			},
 			protocol: portSpec.Protocol,
 			snat:     portSpec.SNAT,
+			host:     portSpec.Host,
+			path:     portSpec.Path,
 		}
 
 		for _, pr := range listenPortRanges {
@@ -1000,8 +1033,35 @@ func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwa
 				return nil, fmt.Errorf("Invalid listen port in port specification %d: %w", portSpecID, err)
 			}
 
+			if isHTTP {
+				if len(listenPortRanges) > 1 || portRange > 1 {
+					return nil, fmt.Errorf("Only a single listen port can be used with protocol %q in port specification %d", portSpec.Protocol, portSpecID)
+				}
+
+				_, found := listenPorts["tcp"][portFirst]
+				if found || (httpPorts[portFirst] != "" && httpPorts[portFirst] != portSpec.Protocol) {
+					return nil, fmt.Errorf("Listen port %d is already used by another protocol in port specification %d", portFirst, portSpecID)
+				}
+
+				route := fmt.Sprintf("%d/%s%s", portFirst, portSpec.Host, strings.TrimSuffix(portSpec.Path, "/"))
+				_, found = httpRoutes[route]
+				if found {
+					return nil, fmt.Errorf("Duplicate route for listen port %d in port specification %d", portFirst, portSpecID)
+				}
+
+				httpPorts[portFirst] = portSpec.Protocol
+				httpRoutes[route] = struct{}{}
+				portMap.listenPorts = append(portMap.listenPorts, uint64(portFirst))
+
+				continue
+			}
+
 			for i := range portRange {
 				port := portFirst + i
+				if portSpec.Protocol == "tcp" && httpPorts[port] != "" {
+					return nil, fmt.Errorf("Listen port %d is already used by another protocol in port specification %d", port, portSpecID)
+				}
+
 				_, found := listenPorts[portSpec.Protocol][port]
 				if found {
 					return nil, fmt.Errorf("Duplicate listen port %d for protocol %q in port specification %d", port, portSpec.Protocol, portSpecID)
@@ -1039,6 +1099,10 @@ func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwa
 			// Only check if the target port count matches the listen port count if the target ports
 			// don't equal 1, because we allow many-to-one type mapping.
 			portSpectTargetPortsLen := len(portMap.target.ports)
+			if isHTTP && portSpectTargetPortsLen != 1 {
+				return nil, fmt.Errorf("Only a single target port can be used with protocol %q in port specification %d", portSpec.Protocol, portSpecID)
+			}
+
 			if portSpectTargetPortsLen != 1 && len(portMap.listenPorts) != portSpectTargetPortsLen {
 				return nil, fmt.Errorf("Mismatch of listen port(s) and target port(s) count in port specification %d", portSpecID)
 			}
@@ -245,11 +245,12 @@ func (n *ovn) State() (*api.NetworkState, error) {
 	}, nil
 }
 
-// uplinkRoutes parses ipv4.routes and ipv6.routes settings for an uplink network into a slice of *net.IPNet.
+// uplinkRoutes parses ipv4.routes and ipv6.routes settings as well as any delegated IPv6 prefix for an uplink
+// network into a slice of *net.IPNet.
 func (n *ovn) uplinkRoutes(uplink *api.Network) ([]*net.IPNet, error) {
 	var err error
 	var uplinkRoutes []*net.IPNet
-	for _, k := range []string{"ipv4.routes", "ipv6.routes"} {
+	for _, k := range []string{"ipv4.routes", "ipv6.routes", prefixDelegationVolatilePrefix} {
 		if uplink.Config[k] == "" {
 			continue
 		}
@@ -498,6 +499,14 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  shortdesc: Comma-separated list of unconfigured network interfaces to include in the bridge
 		"bridge.external_interfaces": validate.Optional(validateExternalInterfaces),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=flows.accounting)
+		//
+		// ---
+		//  type: bool
+		//  shortdesc: Whether to report flow accounting metrics for the instances on the network (see {ref}`network-flow-metrics`)
+		//  default: `false`
+		"flows.accounting": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.address)
 		//
 		// ---
@@ -566,6 +575,30 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  shortdesc: Static routes to provide via DHCP option 121, as a comma-separated list of alternating subnets (CIDR) and gateway addresses (same syntax as dnsmasq and OVN)
 		"ipv4.dhcp.routes": validate.Optional(validate.IsDHCPRouteList),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.next_server)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Address of the server to boot from over the network
+		"ipv4.dhcp.next_server": validate.Optional(validate.IsNetworkAddressV4),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.filename)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file name to provide to PXE clients
+		"ipv4.dhcp.filename": validate.IsAny,
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.ipxe_filename)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file name or URL to provide to iPXE clients (for chain-loading)
+		"ipv4.dhcp.ipxe_filename": validate.IsAny,
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.address)
 		//
 		// ---
@@ -616,6 +649,24 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  condition: IPv4 address
 		"ipv4.nat.address": validate.Optional(validate.IsNetworkAddressV4),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.prefix_delegation)
+		//
+		// ---
+		//  type: bool
+		//  condition: standalone server
+		//  shortdesc: Whether to derive `ipv6.address` from the prefix delegated to the uplink network (requires `ipv6.prefix_delegation` on the uplink)
+		//  default: `false`
+		"ipv6.prefix_delegation": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.prefix_delegation.subnet)
+		//
+		// ---
+		//  type: integer
+		//  condition: IPv6 prefix delegation
+		//  shortdesc: Index of the `/64` subnet of the delegated prefix to use for the network
+		//  default: derived from the network ID
+		"ipv6.prefix_delegation.subnet": validate.Optional(validate.IsUint32),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.nat)
 		//
 		// ---
@@ -651,6 +702,22 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  default: `false`
 		"ipv6.l3only": validate.Optional(validate.IsBool),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=limits.ingress)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Total bandwidth limit in bit/s for traffic going from the uplink to the instances on the network (various suffixes supported, see {ref}`instances-limit-units`)
+		//  default: -
+		"limits.ingress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=limits.egress)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Total bandwidth limit in bit/s for traffic going from the instances on the network to the uplink (various suffixes supported, see {ref}`instances-limit-units`)
+		//  default: -
+		"limits.egress": validate.Optional(validate.IsBitSize),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=dns.nameservers)
 		//
 		// ---
@@ -851,6 +918,21 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 				rules[k] = validate.Optional(validate.IsUint8)
 			}
 		}
+
+		// DHCP option keys have the option code in their name.
+		code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if found {
+			// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.option.NUMBER)
+			//
+			// ---
+			//  type: string
+			//  condition: IPv4 DHCP
+			//  shortdesc: Value of an additional DHCP option supported by OVN (see {ref}`network-ovn-netboot`)
+			rules[k] = func(value string) error {
+				_, _, err := networkOVN.DHCPv4Option(code, value)
+				return err
+			}
+		}
 	}
 
 	err := n.validate(config, rules)
@@ -2379,6 +2461,11 @@ func (n *ovn) FillConfig(config map[string]string) error {
 		config["ipv4.address"] = "auto"
 	}
 
+	// The IPv6 address comes from the delegated prefix.
+	if config["ipv6.address"] == "" && util.IsTrue(config["ipv6.prefix_delegation"]) {
+		config["ipv6.address"] = "none"
+	}
+
 	if config["ipv6.address"] == "" {
 		content, err := os.ReadFile("/proc/sys/net/ipv6/conf/default/disable_ipv6")
 		if err == nil && string(content) == "0\n" {
@@ -3214,6 +3301,11 @@ func (n *ovn) setup(update bool) error {
 		var deleteDHCPRecords []networkOVN.OVNDHCPOptionsUUID
 		if dhcpV4Subnet == nil && dhcpv4UUID != "" {
 			deleteDHCPRecords = append(deleteDHCPRecords, dhcpv4UUID)
+
+			err = n.ovnnb.DeleteLogicalSwitchPortDHCPOptions(context.TODO(), n.getIntSwitchName(), "")
+			if err != nil {
+				return fmt.Errorf("Failed deleting existing DHCP settings for instance ports: %w", err)
+			}
 		}
 
 		if dhcpV6Subnet == nil && dhcpv6UUID != "" {
@@ -3291,6 +3383,25 @@ func (n *ovn) setup(update bool) error {
 			DNSSearchList:      n.getDNSSearchList(),
 			StaticRoutes:       n.config["ipv4.dhcp.routes"],
 			RecursiveDNSServer: dnsIPv4,
+			NextServer:         net.ParseIP(n.config["ipv4.dhcp.next_server"]),
+			BootFileName:       n.config["ipv4.dhcp.filename"],
+			BootFileNameAlt:    n.config["ipv4.dhcp.ipxe_filename"],
+			Options:            map[string]string{},
+		}
+
+		// Add any user provided DHCP options.
+		for k, v := range n.config {
+			code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+			if !found {
+				continue
+			}
+
+			name, value, err := networkOVN.DHCPv4Option(code, v)
+			if err != nil {
+				return fmt.Errorf("Failed parsing %q: %w", k, err)
+			}
+
+			opts.Options[name] = value
 		}
 
 		err = n.ovnnb.UpdateLogicalSwitchDHCPv4Options(context.TODO(), n.getIntSwitchName(), dhcpv4UUID, dhcpV4Subnet, opts)
@@ -3300,6 +3411,12 @@ func (n *ovn) setup(update bool) error {
 
 		if dhcpv4UUID == "" {
 			dhcpv4Created = true
+		} else {
+			// Keep the option sets of the instance ports with DHCP overrides in sync.
+			err = n.ovnnb.RefreshLogicalSwitchPortDHCPv4Options(context.TODO(), n.getIntSwitchName(), dhcpv4UUID)
+			if err != nil {
+				return fmt.Errorf("Failed updating DHCPv4 settings for instance ports: %w", err)
+			}
 		}
 	}
 
@@ -3466,6 +3583,17 @@ func (n *ovn) setup(update bool) error {
 		reverter.Add(cleanup)
 	}
 
+	// Setup bandwidth limits.
+	err = n.setupLimits()
+	if err != nil {
+		return fmt.Errorf("Failed to setup bandwidth limits: %w", err)
+	}
+
+	// Enable the connection tracking accounting used by the flow metrics.
+	if util.IsTrue(n.config["flows.accounting"]) {
+		flowAccountingSetup()
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -4025,6 +4153,11 @@ func (n *ovn) Start() error {
 		return err
 	}
 
+	// Pick up any prefix already delegated to the uplink.
+	if util.IsTrue(n.config["ipv6.prefix_delegation"]) {
+		prefixDelegationRefresh(n.state)
+	}
+
 	reverter.Success()
 
 	// Ensure network is marked as available now its started.
@@ -4486,6 +4619,11 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 		return fmt.Errorf("Failed removing unused OVN address sets: %w", err)
 	}
 
+	// Pick up any prefix already delegated to the uplink.
+	if util.IsTrue(newNetwork.Config["ipv6.prefix_delegation"]) && (slices.Contains(changedKeys, "ipv6.prefix_delegation") || slices.Contains(changedKeys, "ipv6.prefix_delegation.subnet")) {
+		prefixDelegationRefresh(n.state)
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -4715,6 +4853,40 @@ func (n *ovn) forwardHasDefaultTarget(listenAddress string) (bool, error) {
 	return hasDefaultTarget, nil
 }
 
+// instanceDevicePortDHCPv4Overrides returns the DHCPv4 options overridden by an instance NIC, indexed by OVN
+// option name.
+func (n *ovn) instanceDevicePortDHCPv4Overrides(devConfig deviceConfig.Device) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	if devConfig["ipv4.dhcp.next_server"] != "" {
+		overrides["next_server"] = devConfig["ipv4.dhcp.next_server"]
+	}
+
+	if devConfig["ipv4.dhcp.filename"] != "" {
+		overrides["bootfile_name"] = fmt.Sprintf(`"%s"`, devConfig["ipv4.dhcp.filename"])
+	}
+
+	if devConfig["ipv4.dhcp.ipxe_filename"] != "" {
+		overrides["bootfile_name_alt"] = fmt.Sprintf(`"%s"`, devConfig["ipv4.dhcp.ipxe_filename"])
+	}
+
+	for k, v := range devConfig {
+		code, found := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if !found {
+			continue
+		}
+
+		name, value, err := networkOVN.DHCPv4Option(code, v)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing %q: %w", k, err)
+		}
+
+		overrides[name] = value
+	}
+
+	return overrides, nil
+}
+
 // InstanceDevicePortStart sets up an instance device port to the internal logical switch.
 // Accepts a list of ACLs being removed from the NIC device (if called as part of a NIC update).
 // Returns the logical switch port name and a list of IPs that were allocated to the port for DNS.
@@ -4835,6 +5007,30 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 		nestedPortVLAN = uint16(nestedPortVLANInt64)
 	}
 
+	// Give the port its own DHCPv4 option set if the NIC overrides any DHCP options.
+	if dhcpV4UUID != "" {
+		overrides, err := n.instanceDevicePortDHCPv4Overrides(opts.DeviceConfig)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(overrides) > 0 {
+			dhcpV4UUID, err = n.ovnnb.UpdateLogicalSwitchPortDHCPv4Options(context.TODO(), n.getIntSwitchName(), instancePortName, dhcpV4UUID, overrides)
+			if err != nil {
+				return "", nil, fmt.Errorf("Failed setting up DHCPv4 options for instance port: %w", err)
+			}
+
+			reverter.Add(func() {
+				_ = n.ovnnb.DeleteLogicalSwitchPortDHCPOptions(context.TODO(), n.getIntSwitchName(), instancePortName)
+			})
+		} else {
+			err = n.ovnnb.DeleteLogicalSwitchPortDHCPOptions(context.TODO(), n.getIntSwitchName(), instancePortName)
+			if err != nil {
+				return "", nil, fmt.Errorf("Failed removing DHCPv4 options for instance port: %w", err)
+			}
+		}
+	}
+
 	// Add port with mayExist set to true, so that if instance port exists, we don't fail and continue below
 	// to configure the port as needed. This is required in case the OVN northbound database was unavailable
 	// when the instance NIC was stopped and was unable to remove the port on last stop, which would otherwise
@@ -186,7 +186,17 @@ func (n *physical) Validate(config map[string]string, clientType request.ClientT
 		// shortdesc: Sets the method how OVN NIC external IPs will be advertised on uplink network: `l2proxy` (proxy ARP/NDP) or `routed`
 		"ovn.ingress_mode": validate.Optional(validate.IsOneOf("l2proxy", "routed")),
 
-		"volatile.last_state.created": validate.Optional(validate.IsBool),
+		// gendoc:generate(entity=network_physical, group=ipv6, key=ipv6.prefix_delegation)
+		//
+		// ---
+		// type: bool
+		// condition: standalone server
+		// defaultdesc: `false`
+		// shortdesc: Whether to request a delegated IPv6 prefix (DHCPv6-PD) on the parent interface for child OVN networks with `ipv6.prefix_delegation` enabled
+		"ipv6.prefix_delegation": validate.Optional(validate.IsBool),
+
+		"volatile.last_state.created":  validate.Optional(validate.IsBool),
+		prefixDelegationVolatilePrefix: validate.Optional(validate.IsNetworkV6),
 	}
 
 	// gendoc:generate(entity=network_physical, group=bgp, key=bgp.peers.NAME.address)
@@ -235,6 +245,11 @@ func (n *physical) Validate(config map[string]string, clientType request.ClientT
 		return err
 	}
 
+	// Prefix delegation relies on a DHCPv6 client running on this server only.
+	if util.IsTrue(config["ipv6.prefix_delegation"]) && n.state != nil && n.state.ServerClustered {
+		return errors.New("IPv6 prefix delegation isn't supported on clustered servers")
+	}
+
 	return nil
 }
 
@@ -410,6 +425,18 @@ func (n *physical) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Request a delegated IPv6 prefix.
+	if oldConfig != nil && util.IsTrue(oldConfig["ipv6.prefix_delegation"]) {
+		oldHostName := GetHostDevice(oldConfig["parent"], oldConfig["vlan"])
+		if oldHostName != hostName || util.IsFalseOrEmpty(n.config["ipv6.prefix_delegation"]) {
+			prefixDelegationStop(oldHostName, n.project, n.name)
+		}
+	}
+
+	if util.IsTrue(n.config["ipv6.prefix_delegation"]) && n.config["parent"] != "none" {
+		prefixDelegationStart(n.state, hostName, n.project, n.name)
+	}
+
 	// Setup BGP.
 	err = n.bgpSetup(oldConfig)
 	if err != nil {
@@ -432,6 +459,11 @@ func (n *physical) Stop() error {
 
 	hostName := GetHostDevice(n.config["parent"], n.config["vlan"])
 
+	// Stop requesting a delegated IPv6 prefix.
+	if util.IsTrue(n.config["ipv6.prefix_delegation"]) {
+		prefixDelegationStop(hostName, n.project, n.name)
+	}
+
 	// Only try and remove created VLAN interfaces.
 	if n.config["vlan"] != "" && util.IsTrue(n.config["volatile.last_state.created"]) && InterfaceExists(hostName) {
 		err := InterfaceRemove(hostName)
@@ -0,0 +1,119 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// bootHTTPPort is the port the network boot HTTP server listens on.
+const bootHTTPPort = "80"
+
+// bootHTTPServers tracks the network boot HTTP servers of each network, protected by bootHTTPMu.
+var bootHTTPServers = map[string]*bootHTTPServer{}
+var bootHTTPMu sync.Mutex
+
+// bootHTTPServer represents an HTTP server serving the boot directory of a network.
+type bootHTTPServer struct {
+	listenAddress string
+	server        *http.Server
+}
+
+// BridgeBootFileName returns the name under which a file of a bridge network's boot directory is handed to iPXE
+// clients. When the network serves its boot directory over HTTP, this is a URL as iPXE loads those faster than
+// over TFTP.
+func BridgeBootFileName(netConfig map[string]string, fileName string) string {
+	if util.IsTrue(netConfig["ipv4.dhcp.http"]) {
+		ipAddress, _, err := net.ParseCIDR(netConfig["ipv4.address"])
+		if err == nil {
+			return fmt.Sprintf("http://%s/%s", ipAddress.String(), fileName)
+		}
+	}
+
+	return fileName
+}
+
+// bootHTTPHandler returns an HTTP handler serving the files of a network's boot directory.
+func bootHTTPHandler(networkName string) http.Handler {
+	fileServer := http.FileServer(http.Dir(internalUtil.VarPath("networks", networkName, "tftp")))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Don't list the content of the boot directory.
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// bootHTTPStart starts serving the network's boot directory over HTTP on the listen address.
+// A running server for the network is kept if it already uses the listen address, and replaced otherwise.
+func bootHTTPStart(networkName string, listenIP net.IP) error {
+	bootHTTPMu.Lock()
+	defer bootHTTPMu.Unlock()
+
+	listenAddress := net.JoinHostPort(listenIP.String(), bootHTTPPort)
+
+	s := bootHTTPServers[networkName]
+	if s != nil {
+		if s.listenAddress == listenAddress {
+			return nil
+		}
+
+		_ = s.server.Close()
+		delete(bootHTTPServers, networkName)
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return fmt.Errorf("Failed listening on %q: %w", listenAddress, err)
+	}
+
+	s = &bootHTTPServer{
+		listenAddress: listenAddress,
+		server: &http.Server{
+			Handler:           bootHTTPHandler(networkName),
+			ReadHeaderTimeout: 30 * time.Second,
+		},
+	}
+
+	go func() {
+		err := s.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Network boot HTTP server failed", logger.Ctx{"network": networkName, "listen": listenAddress, "err": err})
+		}
+	}()
+
+	bootHTTPServers[networkName] = s
+
+	return nil
+}
+
+// bootHTTPStop stops serving the network's boot directory over HTTP (if running).
+func bootHTTPStop(networkName string) {
+	bootHTTPMu.Lock()
+	defer bootHTTPMu.Unlock()
+
+	s := bootHTTPServers[networkName]
+	if s == nil {
+		return
+	}
+
+	_ = s.server.Close()
+	delete(bootHTTPServers, networkName)
+}
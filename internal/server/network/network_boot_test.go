@@ -0,0 +1,49 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+)
+
+func TestBridgeBootFileName(t *testing.T) {
+	assert.Equal(t, "boot.ipxe", BridgeBootFileName(map[string]string{"ipv4.address": "10.0.0.1/24"}, "boot.ipxe"))
+	assert.Equal(t, "http://10.0.0.1/boot.ipxe", BridgeBootFileName(map[string]string{"ipv4.address": "10.0.0.1/24", "ipv4.dhcp.http": "true"}, "boot.ipxe"))
+	assert.Equal(t, "boot.ipxe", BridgeBootFileName(map[string]string{"ipv4.address": "none", "ipv4.dhcp.http": "true"}, "boot.ipxe"))
+}
+
+func TestBootHTTPHandler(t *testing.T) {
+	t.Setenv("INCUS_DIR", t.TempDir())
+
+	root := internalUtil.VarPath("networks", "incusbr0", "tftp")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "images"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "boot.ipxe"), []byte("#!ipxe\n"), 0o644))
+
+	handler := bootHTTPHandler("incusbr0")
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/boot.ipxe", http.StatusOK},
+		{http.MethodHead, "/boot.ipxe", http.StatusOK},
+		{http.MethodGet, "/missing", http.StatusNotFound},
+		{http.MethodGet, "/", http.StatusNotFound},
+		{http.MethodGet, "/images/", http.StatusNotFound},
+		{http.MethodPost, "/boot.ipxe", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.status, rec.Code, "%s %s", tt.method, tt.path)
+	}
+}
@@ -0,0 +1,426 @@
+package network
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/cluster/request"
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/device/nictype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/internal/server/ip"
+	"github.com/lxc/incus/v7/internal/server/metrics"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/state"
+	localUtil "github.com/lxc/incus/v7/internal/server/util"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// flowTopTalkers is the number of remote addresses reported per instance and network.
+const flowTopTalkers = 10
+
+// flowMetricsCacheDuration is how long the collected flow metrics are reused for.
+const flowMetricsCacheDuration = 8 * time.Second
+
+// flowMetricsCache holds the last collected flow metrics until flowMetricsCacheExpiry, protected by
+// flowMetricsCacheLock which also serializes the collections.
+var flowMetricsCache []flowSample
+var flowMetricsCacheExpiry time.Time
+var flowMetricsCacheLock sync.Mutex
+
+// flowAccountingOnce ensures that connection tracking accounting is only enabled once.
+var flowAccountingOnce sync.Once
+
+// flowEndpoint represents an instance NIC address on a network with flow accounting enabled.
+type flowEndpoint struct {
+	project  string
+	instance string
+	network  string
+}
+
+// flowListener represents a network forward or load balancer listen address.
+type flowListener struct {
+	metricType metrics.MetricType
+	project    string
+	network    string
+	address    string
+}
+
+// flowCounters represents the traffic between an instance and a remote address.
+type flowCounters struct {
+	remote      string
+	connections uint64
+	rxBytes     uint64
+	txBytes     uint64
+}
+
+// flowSample represents a collected flow metric sample.
+type flowSample struct {
+	metricType metrics.MetricType
+	labels     map[string]string
+	value      float64
+}
+
+// flowEndpointKey returns the key of an instance address in a connection tracking zone.
+// Bridge connections are tracked in the default zone while OVN tracks the connections of each logical port in the
+// port's own zone, which allows for the same address to be used on multiple OVN networks.
+func flowEndpointKey(zone uint16, address string) string {
+	return fmt.Sprintf("%d/%s", zone, address)
+}
+
+// flowAccountingSetup enables connection tracking accounting, which byte counters require.
+// Only connections established after that point have their bytes counted.
+func flowAccountingSetup() {
+	flowAccountingOnce.Do(func() {
+		acct, err := localUtil.SysctlGet("net/netfilter/nf_conntrack_acct")
+		if err == nil && strings.TrimSpace(acct) == "1" {
+			return
+		}
+
+		err = localUtil.SysctlSet("net/netfilter/nf_conntrack_acct", "1")
+		if err != nil {
+			logger.Warn("Failed enabling connection tracking accounting", logger.Ctx{"err": err})
+		}
+	})
+}
+
+// FlowMetrics returns the flow accounting metrics of this member's instances connected to the networks that have
+// flow accounting enabled, as well as the connection counts of those networks' forwards and load balancers.
+// If projectNames is not empty, only the samples of those projects are included.
+func FlowMetrics(s *state.State, projectNames []string) (*metrics.MetricSet, error) {
+	flowMetricsCacheLock.Lock()
+	defer flowMetricsCacheLock.Unlock()
+
+	if flowMetricsCacheExpiry.Before(time.Now()) {
+		samples, err := flowCollect(s)
+		if err != nil {
+			return nil, err
+		}
+
+		flowMetricsCache = samples
+		flowMetricsCacheExpiry = time.Now().Add(flowMetricsCacheDuration)
+	}
+
+	out := metrics.NewMetricSet(nil)
+	for _, sample := range flowMetricsCache {
+		if len(projectNames) > 0 && !slices.Contains(projectNames, sample.labels["project"]) {
+			continue
+		}
+
+		out.AddSamples(sample.metricType, metrics.Sample{Labels: maps.Clone(sample.labels), Value: sample.value})
+	}
+
+	return out, nil
+}
+
+// flowCollect collects the flow metrics from the connection tracking table.
+func flowCollect(s *state.State) ([]flowSample, error) {
+	// Get the networks with flow accounting enabled.
+	var networks map[string]map[int64]api.Network
+	listeners := map[string]flowListener{}
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networks, err = tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for projectName, projectNetworks := range networks {
+			for networkID, network := range projectNetworks {
+				if !slices.Contains([]string{"bridge", "ovn"}, network.Type) || util.IsFalseOrEmpty(network.Config["flows.accounting"]) {
+					delete(projectNetworks, networkID)
+					continue
+				}
+
+				forwards, err := dbCluster.GetNetworkForwards(ctx, tx.Tx(), dbCluster.NetworkForwardFilter{NetworkID: &networkID})
+				if err != nil {
+					return fmt.Errorf("Failed loading network forwards: %w", err)
+				}
+
+				for _, forward := range forwards {
+					listeners[forward.ListenAddress] = flowListener{metricType: metrics.NetworkForwardConnections, project: projectName, network: network.Name, address: forward.ListenAddress}
+				}
+
+				loadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{NetworkID: &networkID})
+				if err != nil {
+					return fmt.Errorf("Failed loading network load balancers: %w", err)
+				}
+
+				for _, loadBalancer := range loadBalancers {
+					listeners[loadBalancer.ListenAddress] = flowListener{metricType: metrics.NetworkLoadBalancerConnections, project: projectName, network: network.Name, address: loadBalancer.ListenAddress}
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to do if no network has flow accounting enabled.
+	enabled := false
+	for _, projectNetworks := range networks {
+		if len(projectNetworks) > 0 {
+			enabled = true
+			break
+		}
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	endpoints, err := flowEndpoints(s, networks)
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := ip.ConntrackList()
+	if err != nil {
+		return nil, err
+	}
+
+	return flowAggregate(flows, endpoints, listeners), nil
+}
+
+// flowAggregate aggregates the connection tracking entries into per instance and remote address samples as well as
+// per forward and load balancer connection counts.
+func flowAggregate(flows []ip.ConntrackFlow, endpoints map[string]flowEndpoint, listeners map[string]flowListener) []flowSample {
+	// Connection tracking entries can be duplicated across zones (as is the case with OVN), so only count each
+	// connection once for the listeners.
+	seen := map[string]bool{}
+	talkers := map[flowEndpoint]map[string]*flowCounters{}
+	listenerConnections := map[string]uint64{}
+
+	// Adds a connection to the counters of an instance endpoint.
+	addConnection := func(endpoint flowEndpoint, remote net.IP, rxBytes uint64, txBytes uint64) {
+		_, ok := talkers[endpoint]
+		if !ok {
+			talkers[endpoint] = map[string]*flowCounters{}
+		}
+
+		counters, ok := talkers[endpoint][remote.String()]
+		if !ok {
+			counters = &flowCounters{remote: remote.String()}
+			talkers[endpoint][remote.String()] = counters
+		}
+
+		counters.connections++
+		counters.rxBytes += rxBytes
+		counters.txBytes += txBytes
+	}
+
+	for _, flow := range flows {
+		key := fmt.Sprintf("%d/%s/%d/%s/%d", flow.Protocol, flow.Original.Src, flow.Original.SrcPort, flow.Original.Dst, flow.Original.DstPort)
+		if !seen[key] {
+			seen[key] = true
+
+			// Count the connections to forwards and load balancers.
+			_, ok := listeners[flow.Original.Dst.String()]
+			if ok {
+				listenerConnections[flow.Original.Dst.String()]++
+			}
+		}
+
+		// The instance initiating the connection is the original source (before any SNAT).
+		endpoint, ok := endpoints[flowEndpointKey(flow.Zone, flow.Original.Src.String())]
+		if ok {
+			addConnection(endpoint, flow.Original.Dst, flow.Reply.Bytes, flow.Original.Bytes)
+		}
+
+		// The instance responding to the connection is the reply source (after any DNAT).
+		endpoint, ok = endpoints[flowEndpointKey(flow.Zone, flow.Reply.Src.String())]
+		if ok {
+			addConnection(endpoint, flow.Original.Src, flow.Original.Bytes, flow.Reply.Bytes)
+		}
+	}
+
+	var samples []flowSample
+	for _, endpoint := range slices.SortedFunc(maps.Keys(talkers), func(a flowEndpoint, b flowEndpoint) int {
+		return cmp.Or(cmp.Compare(a.project, b.project), cmp.Compare(a.instance, b.instance), cmp.Compare(a.network, b.network))
+	}) {
+		remotes := talkers[endpoint]
+		labels := func() map[string]string {
+			return map[string]string{"project": endpoint.project, "name": endpoint.instance, "network": endpoint.network}
+		}
+
+		var connections uint64
+		for _, counters := range remotes {
+			connections += counters.connections
+		}
+
+		samples = append(samples, flowSample{metricType: metrics.NetworkFlowConnections, labels: labels(), value: float64(connections)})
+
+		// Only report the remote addresses with the most traffic.
+		sorted := slices.SortedFunc(maps.Values(remotes), func(a *flowCounters, b *flowCounters) int {
+			return cmp.Or(cmp.Compare(b.rxBytes+b.txBytes, a.rxBytes+a.txBytes), cmp.Compare(a.remote, b.remote))
+		})
+
+		for _, counters := range sorted[:min(len(sorted), flowTopTalkers)] {
+			rxLabels := labels()
+			rxLabels["remote"] = counters.remote
+			samples = append(samples, flowSample{metricType: metrics.NetworkFlowReceiveBytes, labels: rxLabels, value: float64(counters.rxBytes)})
+
+			txLabels := labels()
+			txLabels["remote"] = counters.remote
+			samples = append(samples, flowSample{metricType: metrics.NetworkFlowTransmitBytes, labels: txLabels, value: float64(counters.txBytes)})
+		}
+	}
+
+	for _, address := range slices.Sorted(maps.Keys(listeners)) {
+		listener := listeners[address]
+		labels := map[string]string{"project": listener.project, "network": listener.network, "listen_address": listener.address}
+		samples = append(samples, flowSample{metricType: listener.metricType, labels: labels, value: float64(listenerConnections[address])})
+	}
+
+	return samples
+}
+
+// flowEndpoints returns the addresses of this member's instance NICs connected to the specified networks, indexed
+// by flowEndpointKey. Bridge addresses are taken from the neighbour table of the bridge while OVN addresses come
+// from the leases and are keyed on the connection tracking zone of the NIC's logical port.
+func flowEndpoints(s *state.State, networks map[string]map[int64]api.Network) (map[string]flowEndpoint, error) {
+	// Index the networks by project and name.
+	enabled := map[string]map[string]string{}
+	for projectName, projectNetworks := range networks {
+		enabled[projectName] = map[string]string{}
+		for _, network := range projectNetworks {
+			enabled[projectName][network.Name] = network.Type
+		}
+	}
+
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := map[string]flowEndpoint{}
+	ovnNetworks := map[string]*ovn{}
+	ovnLeases := map[string]map[string][]string{}
+	var ovnZones map[string]uint16
+	for _, inst := range insts {
+		if !inst.IsRunning() {
+			continue
+		}
+
+		instProject := inst.Project()
+		for deviceName, d := range inst.ExpandedDevices() {
+			if d["type"] != "nic" || d["network"] == "" {
+				continue
+			}
+
+			nicType, err := nictype.NICType(s, instProject.Name, d)
+			if err != nil {
+				continue
+			}
+
+			// Bridge networks are always in the default project.
+			networkProject := api.ProjectDefaultName
+			if nicType == "ovn" {
+				networkProject = project.NetworkProjectFromRecord(&instProject)
+			}
+
+			networkType, ok := enabled[networkProject][d["network"]]
+			if !ok {
+				continue
+			}
+
+			hwaddr := d["hwaddr"]
+			if hwaddr == "" {
+				hwaddr = inst.LocalConfig()[fmt.Sprintf("volatile.%s.hwaddr", deviceName)]
+			}
+
+			mac, err := net.ParseMAC(hwaddr)
+			if err != nil {
+				continue
+			}
+
+			endpoint := flowEndpoint{project: instProject.Name, instance: inst.Name(), network: d["network"]}
+
+			if networkType == "bridge" {
+				neigh := &ip.Neigh{DevName: d["network"], MAC: mac}
+				neighbors, err := neigh.Show()
+				if err != nil {
+					continue
+				}
+
+				for _, neighbor := range neighbors {
+					endpoints[flowEndpointKey(0, neighbor.Addr.String())] = endpoint
+				}
+
+				continue
+			}
+
+			// Load the connection tracking zones of the logical ports bound to this member once.
+			if ovnZones == nil {
+				vswitch, err := s.OVS()
+				if err != nil {
+					return nil, fmt.Errorf("Failed to connect to OVS: %w", err)
+				}
+
+				ovnZones, err = vswitch.GetOVNConntrackZones(context.TODO(), s.GlobalConfig.NetworkOVNIntegrationBridge())
+				if err != nil {
+					return nil, fmt.Errorf("Failed getting OVN connection tracking zones: %w", err)
+				}
+			}
+
+			// Load the OVN network and its leases once.
+			networkKey := networkProject + "/" + d["network"]
+			_, ok = ovnLeases[networkKey]
+			if !ok {
+				ovnLeases[networkKey] = map[string][]string{}
+
+				n, err := LoadByName(s, networkProject, d["network"])
+				if err != nil {
+					continue
+				}
+
+				o, ok := n.(*ovn)
+				if !ok {
+					continue
+				}
+
+				ovnNetworks[networkKey] = o
+
+				leases, err := n.Leases(networkProject, request.ClientTypeNormal)
+				if err != nil {
+					logger.Warn("Failed getting network leases for flow accounting", logger.Ctx{"project": networkProject, "network": d["network"], "err": err})
+					continue
+				}
+
+				for _, lease := range leases {
+					ovnLeases[networkKey][lease.Hwaddr] = append(ovnLeases[networkKey][lease.Hwaddr], lease.Address)
+				}
+			}
+
+			o := ovnNetworks[networkKey]
+			if o == nil {
+				continue
+			}
+
+			zone, ok := ovnZones[string(o.getInstanceDevicePortName(inst.LocalConfig()["volatile.uuid"], deviceName))]
+			if !ok {
+				continue
+			}
+
+			for _, address := range ovnLeases[networkKey][mac.String()] {
+				endpoints[flowEndpointKey(zone, address)] = endpoint
+			}
+		}
+	}
+
+	return endpoints, nil
+}
@@ -0,0 +1,136 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/internal/server/ip"
+	"github.com/lxc/incus/v7/internal/server/metrics"
+)
+
+func TestFlowAggregate(t *testing.T) {
+	c1 := flowEndpoint{project: "default", instance: "c1", network: "incusbr0"}
+	c2 := flowEndpoint{project: "p1", instance: "c2", network: "ovn0"}
+	c3 := flowEndpoint{project: "p2", instance: "c3", network: "ovn1"}
+
+	// c2 and c3 use the same address on different OVN networks.
+	endpoints := map[string]flowEndpoint{
+		flowEndpointKey(0, "10.0.0.2"):  c1,
+		flowEndpointKey(5, "10.1.0.2"):  c2,
+		flowEndpointKey(7, "10.1.0.2"):  c3,
+		flowEndpointKey(0, "10.0.0.3"):  {project: "default", instance: "c4", network: "incusbr0"},
+		flowEndpointKey(9, "10.99.0.1"): {project: "default", instance: "unused", network: "ovn9"},
+	}
+
+	listeners := map[string]flowListener{
+		"192.0.2.10": {metricType: metrics.NetworkForwardConnections, project: "default", network: "incusbr0", address: "192.0.2.10"},
+	}
+
+	flows := []ip.ConntrackFlow{
+		// c1 connecting out, SNATed on the way.
+		{
+			Protocol: 6,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.0.0.2"), Dst: net.ParseIP("198.51.100.1"), SrcPort: 40000, DstPort: 443, Bytes: 100},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("198.51.100.1"), Dst: net.ParseIP("192.0.2.1"), SrcPort: 443, DstPort: 40000, Bytes: 1000},
+		},
+		// A remote connecting to c1 through a forward (DNATed).
+		{
+			Protocol: 6,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("203.0.113.5"), Dst: net.ParseIP("192.0.2.10"), SrcPort: 50000, DstPort: 80, Bytes: 10},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("10.0.0.2"), Dst: net.ParseIP("203.0.113.5"), SrcPort: 80, DstPort: 50000, Bytes: 20},
+		},
+		// The same connection seen in another zone is only counted once for the forward.
+		{
+			Protocol: 6,
+			Zone:     3,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("203.0.113.5"), Dst: net.ParseIP("192.0.2.10"), SrcPort: 50000, DstPort: 80, Bytes: 10},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("10.0.0.2"), Dst: net.ParseIP("203.0.113.5"), SrcPort: 80, DstPort: 50000, Bytes: 20},
+		},
+		// c2 and c3 connecting out from the same address, told apart by their zone.
+		{
+			Protocol: 17,
+			Zone:     5,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.1.0.2"), Dst: net.ParseIP("198.51.100.53"), SrcPort: 1000, DstPort: 53, Bytes: 50},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("198.51.100.53"), Dst: net.ParseIP("10.1.0.2"), SrcPort: 53, DstPort: 1000, Bytes: 60},
+		},
+		{
+			Protocol: 17,
+			Zone:     7,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.1.0.2"), Dst: net.ParseIP("198.51.100.53"), SrcPort: 1000, DstPort: 53, Bytes: 70},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("198.51.100.53"), Dst: net.ParseIP("10.1.0.2"), SrcPort: 53, DstPort: 1000, Bytes: 80},
+		},
+		// An address unknown in the zone isn't attributed.
+		{
+			Protocol: 17,
+			Zone:     8,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.1.0.2"), Dst: net.ParseIP("198.51.100.53"), SrcPort: 1000, DstPort: 53, Bytes: 70},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("198.51.100.53"), Dst: net.ParseIP("10.1.0.2"), SrcPort: 53, DstPort: 1000, Bytes: 80},
+		},
+		// c1 connecting to c4 on the same bridge counts for both.
+		{
+			Protocol: 6,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.0.0.2"), Dst: net.ParseIP("10.0.0.3"), SrcPort: 40001, DstPort: 22, Bytes: 5},
+			Reply:    ip.ConntrackTuple{Src: net.ParseIP("10.0.0.3"), Dst: net.ParseIP("10.0.0.2"), SrcPort: 22, DstPort: 40001, Bytes: 6},
+		},
+	}
+
+	samples := flowAggregate(flows, endpoints, listeners)
+
+	// Index the sample values by type, instance and remote.
+	values := map[metrics.MetricType]map[string]float64{}
+	for _, sample := range samples {
+		_, ok := values[sample.metricType]
+		if !ok {
+			values[sample.metricType] = map[string]float64{}
+		}
+
+		key := sample.labels["name"] + "/" + sample.labels["remote"]
+		if sample.labels["listen_address"] != "" {
+			key = sample.labels["listen_address"]
+		}
+
+		values[sample.metricType][key] = sample.value
+	}
+
+	assert.Equal(t, map[string]float64{"c1/": 3, "c2/": 1, "c3/": 1, "c4/": 1}, values[metrics.NetworkFlowConnections])
+	assert.Equal(t, map[string]float64{"192.0.2.10": 1}, values[metrics.NetworkForwardConnections])
+
+	assert.Equal(t, 1000.0, values[metrics.NetworkFlowReceiveBytes]["c1/198.51.100.1"])
+	assert.Equal(t, 100.0, values[metrics.NetworkFlowTransmitBytes]["c1/198.51.100.1"])
+	assert.Equal(t, 10.0, values[metrics.NetworkFlowReceiveBytes]["c1/203.0.113.5"])
+	assert.Equal(t, 20.0, values[metrics.NetworkFlowTransmitBytes]["c1/203.0.113.5"])
+
+	assert.Equal(t, 60.0, values[metrics.NetworkFlowReceiveBytes]["c2/198.51.100.53"])
+	assert.Equal(t, 80.0, values[metrics.NetworkFlowReceiveBytes]["c3/198.51.100.53"])
+
+	assert.Equal(t, 5.0, values[metrics.NetworkFlowReceiveBytes]["c4/10.0.0.2"])
+	assert.Equal(t, 6.0, values[metrics.NetworkFlowTransmitBytes]["c4/10.0.0.2"])
+}
+
+func TestFlowAggregateTopTalkers(t *testing.T) {
+	endpoints := map[string]flowEndpoint{flowEndpointKey(0, "10.0.0.2"): {project: "default", instance: "c1", network: "incusbr0"}}
+
+	var flows []ip.ConntrackFlow
+	for i := range flowTopTalkers + 5 {
+		remote := net.IPv4(198, 51, 100, byte(i+1))
+		flows = append(flows, ip.ConntrackFlow{
+			Protocol: 6,
+			Original: ip.ConntrackTuple{Src: net.ParseIP("10.0.0.2"), Dst: remote, SrcPort: 40000, DstPort: 443, Bytes: uint64(i)},
+			Reply:    ip.ConntrackTuple{Src: remote, Dst: net.ParseIP("10.0.0.2"), SrcPort: 443, DstPort: 40000},
+		})
+	}
+
+	remotes := []string{}
+	for _, sample := range flowAggregate(flows, endpoints, nil) {
+		if sample.metricType == metrics.NetworkFlowTransmitBytes {
+			remotes = append(remotes, sample.labels["remote"])
+		}
+	}
+
+	// Only the remotes with the most traffic are reported, busiest first.
+	assert.Len(t, remotes, flowTopTalkers)
+	assert.Equal(t, "198.51.100.15", remotes[0])
+	assert.NotContains(t, remotes, "198.51.100.1")
+}
@@ -0,0 +1,453 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v7/internal/server/acme"
+	"github.com/lxc/incus/v7/internal/server/ip"
+	"github.com/lxc/incus/v7/internal/server/state"
+	internalUtil "github.com/lxc/incus/v7/internal/util"
+	"github.com/lxc/incus/v7/shared/logger"
+	localtls "github.com/lxc/incus/v7/shared/tls"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// forwardHTTPACMEPath is the path of the ACME HTTP-01 challenges.
+const forwardHTTPACMEPath = "/.well-known/acme-challenge"
+
+// forwardHTTPRenewInterval is how often the HTTPS forward certificates are checked for renewal.
+const forwardHTTPRenewInterval = 24 * time.Hour
+
+// forwardHTTPNetworks tracks the HTTP proxies of each network, protected by forwardHTTPMu.
+var forwardHTTPNetworks = map[string]*forwardHTTPNetwork{}
+var forwardHTTPMu sync.Mutex
+
+// forwardHTTPNetwork represents the HTTP proxies of a network.
+type forwardHTTPNetwork struct {
+	// Listeners indexed by listen address and port.
+	listeners map[string]*forwardHTTPListener
+
+	// Listen addresses added to the loopback interface, as they weren't otherwise local.
+	addresses map[string]*ip.Addr
+}
+
+// forwardHTTPRoute represents a host and path based route of an HTTP proxy.
+type forwardHTTPRoute struct {
+	host  string
+	path  string
+	proxy *httputil.ReverseProxy
+}
+
+// forwardHTTPListener represents an HTTP or HTTPS proxy serving a forward listen address and port.
+type forwardHTTPListener struct {
+	state    *state.State
+	network  string
+	protocol string
+	server   *http.Server
+	cancel   context.CancelFunc
+
+	mu      sync.RWMutex
+	routes  []*forwardHTTPRoute
+	certs   map[string]*tls.Certificate
+	issuing map[string]bool
+}
+
+// forwardHTTPApply reconciles the HTTP proxies of a network with the HTTP port maps of its forwards, indexed by
+// listen address. Passing no port maps stops all the HTTP proxies of the network.
+func forwardHTTPApply(s *state.State, networkName string, portMaps map[string][]*forwardPortMap) error {
+	forwardHTTPMu.Lock()
+	defer forwardHTTPMu.Unlock()
+
+	fn := forwardHTTPNetworks[networkName]
+	if fn == nil {
+		if len(portMaps) == 0 {
+			return nil
+		}
+
+		fn = &forwardHTTPNetwork{
+			listeners: map[string]*forwardHTTPListener{},
+			addresses: map[string]*ip.Addr{},
+		}
+
+		forwardHTTPNetworks[networkName] = fn
+	}
+
+	// Group the port maps by listen address and port.
+	wanted := map[string][]*forwardPortMap{}
+	wantedAddresses := map[string]bool{}
+	for listenAddress, addressPortMaps := range portMaps {
+		for _, portMap := range addressPortMaps {
+			key := net.JoinHostPort(listenAddress, strconv.FormatUint(portMap.listenPorts[0], 10))
+			wanted[key] = append(wanted[key], portMap)
+			wantedAddresses[listenAddress] = true
+		}
+	}
+
+	// Stop the listeners that are no longer needed or whose protocol changed.
+	for key, l := range fn.listeners {
+		routes, ok := wanted[key]
+		if ok && routes[0].protocol == l.protocol {
+			continue
+		}
+
+		l.stop()
+		delete(fn.listeners, key)
+	}
+
+	// Make sure the listen addresses are local.
+	localAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return err
+	}
+
+	for listenAddress := range wantedAddresses {
+		_, ok := fn.addresses[listenAddress]
+		if ok {
+			continue
+		}
+
+		listenIP := net.ParseIP(listenAddress)
+		isLocal := slices.ContainsFunc(localAddresses, func(addr net.Addr) bool {
+			ipNet, ok := addr.(*net.IPNet)
+			return ok && ipNet.IP.Equal(listenIP)
+		})
+
+		if isLocal {
+			continue
+		}
+
+		listenNet := IPToNet(listenIP)
+		addr := &ip.Addr{DevName: "lo", Address: &listenNet, Family: ip.FamilyV4}
+		if listenIP.To4() == nil {
+			addr.Family = ip.FamilyV6
+		}
+
+		err = addr.Add()
+		if err != nil {
+			return err
+		}
+
+		fn.addresses[listenAddress] = addr
+	}
+
+	// Start or update the listeners.
+	for key, routes := range wanted {
+		l, ok := fn.listeners[key]
+		if !ok {
+			l, err = forwardHTTPStart(s, networkName, key, routes[0].protocol)
+			if err != nil {
+				return err
+			}
+
+			fn.listeners[key] = l
+		}
+
+		l.setRoutes(routes)
+	}
+
+	// Remove the listen addresses that are no longer used.
+	for listenAddress, addr := range fn.addresses {
+		if wantedAddresses[listenAddress] {
+			continue
+		}
+
+		err = addr.Delete()
+		if err != nil {
+			logger.Warn("Failed removing HTTP forward listen address", logger.Ctx{"network": networkName, "address": listenAddress, "err": err})
+		}
+
+		delete(fn.addresses, listenAddress)
+	}
+
+	if len(fn.listeners) == 0 && len(fn.addresses) == 0 {
+		delete(forwardHTTPNetworks, networkName)
+	}
+
+	return nil
+}
+
+// forwardHTTPStart starts a new HTTP or HTTPS proxy on the listen address.
+func forwardHTTPStart(s *state.State, networkName string, listenAddress string, protocol string) (*forwardHTTPListener, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listening on %q: %w", listenAddress, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l := &forwardHTTPListener{
+		state:    s,
+		network:  networkName,
+		protocol: protocol,
+		cancel:   cancel,
+		certs:    map[string]*tls.Certificate{},
+		issuing:  map[string]bool{},
+	}
+
+	l.server = &http.Server{
+		Handler:           l,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	if protocol == "https" {
+		l.server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: l.getCertificate,
+		}
+
+		// Periodically check whether the certificates need renewing.
+		go func() {
+			ticker := time.NewTicker(forwardHTTPRenewInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					l.ensureCertificates()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		var err error
+		if protocol == "https" {
+			err = l.server.ServeTLS(listener, "", "")
+		} else {
+			err = l.server.Serve(listener)
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP forward proxy failed", logger.Ctx{"network": networkName, "listen": listenAddress, "err": err})
+		}
+	}()
+
+	return l, nil
+}
+
+// stop stops the proxy.
+func (l *forwardHTTPListener) stop() {
+	l.cancel()
+	_ = l.server.Close()
+}
+
+// setRoutes replaces the routes of the proxy.
+func (l *forwardHTTPListener) setRoutes(portMaps []*forwardPortMap) {
+	routes := make([]*forwardHTTPRoute, 0, len(portMaps))
+	for _, portMap := range portMaps {
+		targetPort := portMap.listenPorts[0]
+		if len(portMap.target.ports) > 0 {
+			targetPort = portMap.target.ports[0]
+		}
+
+		target := &url.URL{Scheme: "http", Host: net.JoinHostPort(portMap.target.address.String(), strconv.FormatUint(targetPort, 10))}
+
+		routes = append(routes, &forwardHTTPRoute{
+			host: portMap.host,
+			path: portMap.path,
+			proxy: &httputil.ReverseProxy{
+				Rewrite: func(r *httputil.ProxyRequest) {
+					r.SetURL(target)
+					r.SetXForwarded()
+
+					// Preserve the original host for name based virtual hosting on the target.
+					r.Out.Host = r.In.Host
+				},
+			},
+		})
+	}
+
+	l.mu.Lock()
+	l.routes = routes
+	l.mu.Unlock()
+
+	if l.protocol == "https" {
+		l.ensureCertificates()
+	}
+}
+
+// forwardHTTPPathMatches returns whether the request path is within the route path, comparing whole path segments
+// so that "/api" matches "/api" and "/api/v1" but not "/apiv1". An empty route path matches any path.
+func forwardHTTPPathMatches(routePath string, requestPath string) bool {
+	routePath = strings.TrimSuffix(routePath, "/")
+	if routePath == "" {
+		return true
+	}
+
+	return requestPath == routePath || strings.HasPrefix(requestPath, routePath+"/")
+}
+
+// forwardHTTPMatchRoute returns the route with the most specific matching host and path, or nil if none matches.
+// Routes with a host are preferred over routes without one, then routes with a longer path.
+func forwardHTTPMatchRoute(routes []*forwardHTTPRoute, host string, requestPath string) *forwardHTTPRoute {
+	var match *forwardHTTPRoute
+	for _, route := range routes {
+		if route.host != "" && route.host != host {
+			continue
+		}
+
+		if !forwardHTTPPathMatches(route.path, requestPath) {
+			continue
+		}
+
+		if match != nil && (match.host != "" && route.host == "" || (match.host == "") == (route.host == "") && len(strings.TrimSuffix(match.path, "/")) >= len(strings.TrimSuffix(route.path, "/"))) {
+			continue
+		}
+
+		match = route
+	}
+
+	return match
+}
+
+// ServeHTTP routes the requests to the target with the most specific matching host and path.
+func (l *forwardHTTPListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Pass ACME HTTP-01 challenges to the local ACME client.
+	if l.protocol == "http" && forwardHTTPPathMatches(forwardHTTPACMEPath, r.URL.Path) {
+		addr := l.state.GlobalConfig.ACMEHTTP()
+		if strings.HasPrefix(addr, ":") {
+			addr = "127.0.0.1" + addr
+		}
+
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(&url.URL{Scheme: "http", Host: addr})
+				pr.Out.Host = pr.In.Host
+			},
+		}
+
+		proxy.ServeHTTP(w, r)
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	l.mu.RLock()
+	match := forwardHTTPMatchRoute(l.routes, strings.ToLower(host), r.URL.Path)
+	l.mu.RUnlock()
+
+	if match == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	match.proxy.ServeHTTP(w, r)
+}
+
+// getCertificate returns the certificate for the requested server name, falling back to the server certificate.
+func (l *forwardHTTPListener) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mu.RLock()
+	cert := l.certs[strings.ToLower(hello.ServerName)]
+	l.mu.RUnlock()
+
+	if cert != nil {
+		return cert, nil
+	}
+
+	keyPair := l.state.ServerCert().KeyPair()
+
+	return &keyPair, nil
+}
+
+// certificatePaths returns the paths of the stored certificate and key of the host.
+func (l *forwardHTTPListener) certificatePaths(host string) (string, string) {
+	return internalUtil.VarPath("networks", l.network, "forwards", fmt.Sprintf("%s.crt", host)), internalUtil.VarPath("networks", l.network, "forwards", fmt.Sprintf("%s.key", host))
+}
+
+// ensureCertificates loads the stored certificates of the hosts of the proxy and requests new ones from the ACME
+// server for the hosts whose certificate is missing or about to expire.
+func (l *forwardHTTPListener) ensureCertificates() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, route := range l.routes {
+		if route.host == "" || l.issuing[route.host] {
+			continue
+		}
+
+		cert := l.certs[route.host]
+		if cert == nil {
+			certPath, keyPath := l.certificatePaths(route.host)
+			if util.PathExists(certPath) {
+				keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+				if err == nil {
+					cert = &keyPair
+					l.certs[route.host] = cert
+				}
+			}
+		}
+
+		if cert != nil {
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			if err == nil && !localtls.CertificateNeedsUpdate(route.host, leaf, 30*24*time.Hour) {
+				continue
+			}
+		}
+
+		l.issuing[route.host] = true
+		go l.issueCertificate(route.host)
+	}
+}
+
+// issueCertificate requests a new certificate for the host from the ACME server and stores it.
+func (l *forwardHTTPListener) issueCertificate(host string) {
+	defer func() {
+		l.mu.Lock()
+		delete(l.issuing, host)
+		l.mu.Unlock()
+	}()
+
+	logCtx := logger.Ctx{"network": l.network, "host": host}
+
+	// The requests of all the proxies are serialized by the acme package as they share acme.http.port.
+	certKeyPair, err := acme.IssueCertificate(l.state, host)
+	if err != nil {
+		logger.Warn("Failed issuing HTTP forward certificate", logger.Ctx{"network": l.network, "host": host, "err": err})
+		return
+	}
+
+	keyPair, err := tls.X509KeyPair(certKeyPair.Certificate, certKeyPair.PrivateKey)
+	if err != nil {
+		logger.Warn("Failed parsing HTTP forward certificate", logger.Ctx{"network": l.network, "host": host, "err": err})
+		return
+	}
+
+	certPath, keyPath := l.certificatePaths(host)
+	err = os.MkdirAll(internalUtil.VarPath("networks", l.network, "forwards"), 0o700)
+	if err == nil {
+		err = os.WriteFile(certPath, certKeyPair.Certificate, 0o600)
+	}
+
+	if err == nil {
+		err = os.WriteFile(keyPath, certKeyPair.PrivateKey, 0o600)
+	}
+
+	if err != nil {
+		logger.Warn("Failed storing HTTP forward certificate", logger.Ctx{"network": l.network, "host": host, "err": err})
+	}
+
+	l.mu.Lock()
+	l.certs[host] = &keyPair
+	l.mu.Unlock()
+
+	logger.Info("Issued HTTP forward certificate", logCtx)
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardHTTPPathMatches(t *testing.T) {
+	tests := []struct {
+		routePath   string
+		requestPath string
+		want        bool
+	}{
+		{routePath: "", requestPath: "/", want: true},
+		{routePath: "", requestPath: "/api/v1", want: true},
+		{routePath: "/", requestPath: "/anything", want: true},
+		{routePath: "/api", requestPath: "/api", want: true},
+		{routePath: "/api", requestPath: "/api/", want: true},
+		{routePath: "/api", requestPath: "/api/v1", want: true},
+		{routePath: "/api/", requestPath: "/api", want: true},
+		{routePath: "/api/", requestPath: "/api/v1", want: true},
+		{routePath: "/api", requestPath: "/apiv1", want: false},
+		{routePath: "/api", requestPath: "/", want: false},
+		{routePath: "/api/v1", requestPath: "/api", want: false},
+		{routePath: forwardHTTPACMEPath, requestPath: "/.well-known/acme-challenge/token", want: true},
+		{routePath: forwardHTTPACMEPath, requestPath: "/.well-known/acme-challenges", want: false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, forwardHTTPPathMatches(test.routePath, test.requestPath), "route %q, request %q", test.routePath, test.requestPath)
+	}
+}
+
+func TestForwardHTTPMatchRoute(t *testing.T) {
+	catchAll := &forwardHTTPRoute{}
+	api := &forwardHTTPRoute{path: "/api"}
+	host := &forwardHTTPRoute{host: "www.example.net"}
+	hostAPI := &forwardHTTPRoute{host: "www.example.net", path: "/api/"}
+	hostAPIv2 := &forwardHTTPRoute{host: "www.example.net", path: "/api/v2"}
+
+	routes := []*forwardHTTPRoute{catchAll, api, host, hostAPI, hostAPIv2}
+
+	tests := []struct {
+		host string
+		path string
+		want *forwardHTTPRoute
+	}{
+		{host: "other.example.net", path: "/", want: catchAll},
+		{host: "other.example.net", path: "/api/v1", want: api},
+		{host: "other.example.net", path: "/apis", want: catchAll},
+		{host: "www.example.net", path: "/", want: host},
+		{host: "www.example.net", path: "/api", want: hostAPI},
+		{host: "www.example.net", path: "/api/v2/items", want: hostAPIv2},
+		{host: "www.example.net", path: "/api/v20", want: hostAPI},
+	}
+
+	for _, test := range tests {
+		assert.Same(t, test.want, forwardHTTPMatchRoute(routes, test.host, test.path), "host %q, path %q", test.host, test.path)
+	}
+
+	// Requests matching no route aren't routed.
+	assert.Nil(t, forwardHTTPMatchRoute([]*forwardHTTPRoute{api, hostAPI}, "other.example.net", "/"))
+	assert.Nil(t, forwardHTTPMatchRoute(nil, "www.example.net", "/"))
+}
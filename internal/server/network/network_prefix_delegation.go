@@ -0,0 +1,741 @@
+package network
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/nclient6"
+	"github.com/insomniacslk/dhcp/iana"
+
+	"github.com/lxc/incus/v7/internal/server/cluster/request"
+	"github.com/lxc/incus/v7/internal/server/db"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/util"
+)
+
+// prefixDelegationRetryInterval is how long to wait before retrying a failed prefix delegation request.
+const prefixDelegationRetryInterval = time.Minute
+
+// prefixDelegationTimeout bounds each DHCPv6 exchange with the servers.
+const prefixDelegationTimeout = 30 * time.Second
+
+// prefixDelegationReleaseTimeout bounds the release of the prefix when the client stops.
+const prefixDelegationReleaseTimeout = 5 * time.Second
+
+// prefixDelegationVolatilePrefix is the physical network key recording the prefix delegated on its parent.
+const prefixDelegationVolatilePrefix = "volatile.ipv6.prefix_delegation.prefix"
+
+// prefixDelegationIAID is the identity association used for the prefix delegation requests.
+var prefixDelegationIAID = [4]byte{0, 0, 0, 1}
+
+// errPrefixDelegationNoBinding is returned when the server doesn't know about the lease anymore.
+var errPrefixDelegationNoBinding = errors.New("Server has no binding for the delegated prefix")
+
+// prefixDelegations tracks the prefix delegation clients running on each interface, protected by prefixDelegationsMu.
+var prefixDelegations = map[string]*prefixDelegation{}
+var prefixDelegationsMu sync.Mutex
+
+// prefixDelegationApplyMu serializes the network updates following prefix changes.
+var prefixDelegationApplyMu sync.Mutex
+
+// prefixDelegation represents a DHCPv6 prefix delegation client running on a host interface.
+type prefixDelegation struct {
+	iface  string
+	cancel context.CancelFunc
+
+	// Closed once the client has stopped and released its prefix.
+	done chan struct{}
+
+	// Last delegated prefix.
+	prefix *net.IPNet
+
+	// Networks (project/name) relying on the delegated prefix.
+	users map[string]bool
+}
+
+// prefixDelegationStep is the next action of a prefix delegation client (RFC 8415 section 18.2).
+type prefixDelegationStep int
+
+const (
+	// Get a new lease through Solicit and Request.
+	prefixDelegationStepSolicit prefixDelegationStep = iota
+
+	// Extend the lease with the server which delegated it.
+	prefixDelegationStepRenew
+
+	// Extend the lease with any server.
+	prefixDelegationStepRebind
+
+	// Drop the expired lease.
+	prefixDelegationStepExpire
+)
+
+// prefixDelegationLease represents a prefix delegated by a DHCPv6 server.
+type prefixDelegationLease struct {
+	serverID dhcpv6.DUID
+	prefix   *net.IPNet
+
+	// When to renew (T1), rebind (T2) and when the prefix expires (valid lifetime).
+	renewAt   time.Time
+	rebindAt  time.Time
+	expiresAt time.Time
+}
+
+// prefixDelegationLeaseFromReply returns the lease delegated by the server in its reply.
+func prefixDelegationLeaseFromReply(reply *dhcpv6.Message, now time.Time) (*prefixDelegationLease, error) {
+	status := reply.Options.Status()
+	if status != nil && status.StatusCode != iana.StatusSuccess {
+		return nil, fmt.Errorf("Server refused the request: %s", status.StatusMessage)
+	}
+
+	iapd := reply.Options.OneIAPD()
+	if iapd == nil {
+		return nil, errors.New("Server didn't delegate a prefix")
+	}
+
+	status = iapd.Options.Status()
+	if status != nil && status.StatusCode == iana.StatusNoBinding {
+		return nil, errPrefixDelegationNoBinding
+	}
+
+	if status != nil && status.StatusCode != iana.StatusSuccess {
+		return nil, fmt.Errorf("Server refused to delegate a prefix: %s", status.StatusMessage)
+	}
+
+	var prefix *dhcpv6.OptIAPrefix
+	for _, p := range iapd.Options.Prefixes() {
+		if p.Prefix != nil && p.ValidLifetime > 0 {
+			prefix = p
+			break
+		}
+	}
+
+	if prefix == nil {
+		return nil, errors.New("Server didn't delegate a prefix")
+	}
+
+	ones, _ := prefix.Prefix.Mask.Size()
+	if ones > 64 {
+		return nil, fmt.Errorf("Delegated prefix %q is too small to contain a /64 subnet", prefix.Prefix.String())
+	}
+
+	serverID := reply.Options.ServerID()
+	if serverID == nil {
+		return nil, errors.New("Server didn't identify itself")
+	}
+
+	// Let the client pick T1 and T2 when the server doesn't, using the recommended 0.5 and 0.8 of the
+	// preferred lifetime (or of the valid lifetime if the prefix is already deprecated).
+	lifetime := prefix.PreferredLifetime
+	if lifetime <= 0 {
+		lifetime = prefix.ValidLifetime
+	}
+
+	t1 := iapd.T1
+	if t1 <= 0 {
+		t1 = lifetime / 2
+	}
+
+	t2 := iapd.T2
+	if t2 <= 0 {
+		t2 = lifetime * 4 / 5
+	}
+
+	t2 = max(t2, t1)
+	t2 = min(t2, prefix.ValidLifetime)
+	t1 = min(t1, t2)
+
+	return &prefixDelegationLease{
+		serverID:  serverID,
+		prefix:    &net.IPNet{IP: prefix.Prefix.IP.Mask(prefix.Prefix.Mask), Mask: prefix.Prefix.Mask},
+		renewAt:   now.Add(t1),
+		rebindAt:  now.Add(t2),
+		expiresAt: now.Add(prefix.ValidLifetime),
+	}, nil
+}
+
+// next returns the next step for the lease and how long to wait before taking it.
+func (l *prefixDelegationLease) next(now time.Time) (prefixDelegationStep, time.Duration) {
+	if l == nil {
+		return prefixDelegationStepSolicit, 0
+	}
+
+	if !now.Before(l.expiresAt) {
+		return prefixDelegationStepExpire, 0
+	}
+
+	if !now.Before(l.rebindAt) {
+		return prefixDelegationStepRebind, 0
+	}
+
+	return prefixDelegationStepRenew, max(l.renewAt.Sub(now), 0)
+}
+
+// retryAfter returns how long to wait before retrying a failed step, without going past the next deadline of
+// the lease.
+func (l *prefixDelegationLease) retryAfter(now time.Time) time.Duration {
+	retry := prefixDelegationRetryInterval
+	if l == nil {
+		return retry
+	}
+
+	for _, deadline := range []time.Time{l.rebindAt, l.expiresAt} {
+		wait := deadline.Sub(now)
+		if wait > 0 && wait < retry {
+			retry = wait
+		}
+	}
+
+	return retry
+}
+
+// prefixDelegationStart registers the network as relying on the prefix delegated on the interface and starts the
+// prefix delegation client of that interface if not already running. If a prefix was already delegated, the
+// networks are updated straight away.
+func prefixDelegationStart(s *state.State, iface string, projectName string, networkName string) {
+	prefixDelegationsMu.Lock()
+	defer prefixDelegationsMu.Unlock()
+
+	pd := prefixDelegations[iface]
+	if pd == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		pd = &prefixDelegation{
+			iface:  iface,
+			cancel: cancel,
+			done:   make(chan struct{}),
+			users:  map[string]bool{},
+		}
+
+		prefixDelegations[iface] = pd
+
+		go pd.run(ctx, s)
+	}
+
+	pd.users[projectName+"/"+networkName] = true
+
+	if pd.prefix != nil {
+		go pd.apply(s, pd.prefix)
+	}
+}
+
+// prefixDelegationRefresh updates the networks relying on any of the already delegated prefixes.
+func prefixDelegationRefresh(s *state.State) {
+	prefixDelegationsMu.Lock()
+	defer prefixDelegationsMu.Unlock()
+
+	for _, pd := range prefixDelegations {
+		if pd.prefix != nil {
+			go pd.apply(s, pd.prefix)
+		}
+	}
+}
+
+// prefixDelegationStop unregisters the network from the prefix delegated on the interface and stops the prefix
+// delegation client of that interface once no network relies on it anymore, waiting for it to release its prefix.
+func prefixDelegationStop(iface string, projectName string, networkName string) {
+	prefixDelegationsMu.Lock()
+
+	pd := prefixDelegations[iface]
+	if pd == nil {
+		prefixDelegationsMu.Unlock()
+		return
+	}
+
+	delete(pd.users, projectName+"/"+networkName)
+	if len(pd.users) > 0 {
+		prefixDelegationsMu.Unlock()
+		return
+	}
+
+	pd.cancel()
+	delete(prefixDelegations, iface)
+	prefixDelegationsMu.Unlock()
+
+	<-pd.done
+}
+
+// run obtains a prefix on the interface and keeps extending its lease, updating the networks whenever the prefix
+// changes or expires. The prefix is released when the client is stopped.
+func (pd *prefixDelegation) run(ctx context.Context, s *state.State) {
+	defer close(pd.done)
+
+	logCtx := logger.Ctx{"interface": pd.iface}
+
+	var lease *prefixDelegationLease
+
+	// wait sleeps for the given duration, releasing the prefix and returning false if the client is stopped.
+	wait := func(d time.Duration) bool {
+		select {
+		case <-ctx.Done():
+			if lease != nil {
+				pd.release(lease)
+			}
+
+			logger.Debug("Stopped IPv6 prefix delegation", logCtx)
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	for {
+		step, delay := lease.next(time.Now())
+		if delay > 0 {
+			if !wait(delay) {
+				return
+			}
+
+			continue
+		}
+
+		var newLease *prefixDelegationLease
+		var err error
+
+		switch step {
+		case prefixDelegationStepSolicit:
+			newLease, err = pd.solicit(ctx)
+		case prefixDelegationStepRenew:
+			newLease, err = pd.extend(ctx, dhcpv6.MessageTypeRenew, lease)
+		case prefixDelegationStepRebind:
+			newLease, err = pd.extend(ctx, dhcpv6.MessageTypeRebind, lease)
+		case prefixDelegationStepExpire:
+			logger.Warn("Delegated IPv6 prefix expired", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String()})
+			lease = nil
+			pd.setPrefix(s, nil)
+			continue
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				_ = wait(0)
+				return
+			}
+
+			// The server lost track of the lease, get a new one while keeping the current prefix until then.
+			if errors.Is(err, errPrefixDelegationNoBinding) {
+				logger.Warn("Delegated IPv6 prefix is unknown to the server, soliciting a new one", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String()})
+				lease = nil
+				continue
+			}
+
+			logger.Warn("Failed getting delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "err": err})
+			if !wait(lease.retryAfter(time.Now())) {
+				return
+			}
+
+			continue
+		}
+
+		lease = newLease
+		if pd.prefix == nil || pd.prefix.String() != lease.prefix.String() {
+			logger.Info("Received delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String()})
+			pd.setPrefix(s, lease.prefix)
+		}
+	}
+}
+
+// setPrefix records the delegated prefix (nil once expired) and updates the networks relying on it.
+func (pd *prefixDelegation) setPrefix(s *state.State, prefix *net.IPNet) {
+	prefixDelegationsMu.Lock()
+	pd.prefix = prefix
+	prefixDelegationsMu.Unlock()
+
+	pd.apply(s, prefix)
+}
+
+// apply updates the networks relying on the prefix, logging any failure.
+func (pd *prefixDelegation) apply(s *state.State, prefix *net.IPNet) {
+	err := prefixDelegationApply(s, pd.iface, prefix)
+	if err != nil {
+		logger.Error("Failed applying delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "prefix": prefix.String(), "err": err})
+	}
+}
+
+// client returns a DHCPv6 client on the interface along with the DUID identifying it.
+func (pd *prefixDelegation) client() (*nclient6.Client, *dhcpv6.DUIDLL, error) {
+	iface, err := net.InterfaceByName(pd.iface)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(iface.HardwareAddr) < 4 {
+		return nil, nil, fmt.Errorf("Interface %q doesn't have a usable hardware address", pd.iface)
+	}
+
+	client, err := nclient6.New(pd.iface)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed setting up DHCPv6 client: %w", err)
+	}
+
+	return client, &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: iface.HardwareAddr}, nil
+}
+
+// exchange sends a message of the given type for the IA_PD to the DHCPv6 servers and waits for the reply.
+// The server ID is left out when nil, as for Rebind messages which go to any server.
+func (pd *prefixDelegation) exchange(ctx context.Context, client *nclient6.Client, duid *dhcpv6.DUIDLL, messageType dhcpv6.MessageType, serverID dhcpv6.DUID, iapd *dhcpv6.OptIAPD) (*dhcpv6.Message, error) {
+	msg, err := dhcpv6.NewMessage(dhcpv6.WithClientID(duid))
+	if err != nil {
+		return nil, err
+	}
+
+	msg.MessageType = messageType
+	msg.AddOption(dhcpv6.OptElapsedTime(0))
+
+	if serverID != nil {
+		msg.AddOption(dhcpv6.OptServerID(serverID))
+	}
+
+	msg.AddOption(iapd)
+
+	return client.SendAndRead(ctx, nclient6.AllDHCPRelayAgentsAndServers, msg, nclient6.IsMessageType(dhcpv6.MessageTypeReply))
+}
+
+// leaseIAPD returns the IA_PD option identifying the lease in Renew, Rebind and Release messages.
+func (l *prefixDelegationLease) leaseIAPD() *dhcpv6.OptIAPD {
+	iapd := &dhcpv6.OptIAPD{IaId: prefixDelegationIAID}
+	iapd.Options.Add(&dhcpv6.OptIAPrefix{Prefix: l.prefix})
+
+	return iapd
+}
+
+// solicit asks the DHCPv6 servers reachable on the interface for a new prefix.
+func (pd *prefixDelegation) solicit(ctx context.Context) (*prefixDelegationLease, error) {
+	client, duid, err := pd.client()
+	if err != nil {
+		return nil, err
+	}
+
+	defer logger.WarnOnError(client.Close, "Failed to close DHCPv6 client")
+
+	ctx, cancel := context.WithTimeout(ctx, prefixDelegationTimeout)
+	defer cancel()
+
+	// Solicit a prefix only, without any address.
+	solicit, err := dhcpv6.NewSolicit(duid.LinkLayerAddr, dhcpv6.WithClientID(duid), dhcpv6.WithIAPD(prefixDelegationIAID))
+	if err != nil {
+		return nil, err
+	}
+
+	solicit.Options.Del(dhcpv6.OptionIANA)
+
+	advertise, err := client.SendAndRead(ctx, nclient6.AllDHCPRelayAgentsAndServers, solicit, nclient6.IsMessageType(dhcpv6.MessageTypeAdvertise))
+	if err != nil {
+		return nil, fmt.Errorf("Failed soliciting prefix: %w", err)
+	}
+
+	iapd := advertise.Options.OneIAPD()
+	if iapd == nil {
+		return nil, errors.New("Server didn't offer a prefix")
+	}
+
+	reply, err := pd.exchange(ctx, client, duid, dhcpv6.MessageTypeRequest, advertise.Options.ServerID(), iapd)
+	if err != nil {
+		return nil, fmt.Errorf("Failed requesting prefix: %w", err)
+	}
+
+	return prefixDelegationLeaseFromReply(reply, time.Now())
+}
+
+// extend renews (with the server which delegated it) or rebinds (with any server) the lease.
+func (pd *prefixDelegation) extend(ctx context.Context, messageType dhcpv6.MessageType, lease *prefixDelegationLease) (*prefixDelegationLease, error) {
+	client, duid, err := pd.client()
+	if err != nil {
+		return nil, err
+	}
+
+	defer logger.WarnOnError(client.Close, "Failed to close DHCPv6 client")
+
+	ctx, cancel := context.WithTimeout(ctx, prefixDelegationTimeout)
+	defer cancel()
+
+	var serverID dhcpv6.DUID
+	if messageType == dhcpv6.MessageTypeRenew {
+		serverID = lease.serverID
+	}
+
+	reply, err := pd.exchange(ctx, client, duid, messageType, serverID, lease.leaseIAPD())
+	if err != nil {
+		return nil, fmt.Errorf("Failed extending prefix (%s): %w", messageType.String(), err)
+	}
+
+	return prefixDelegationLeaseFromReply(reply, time.Now())
+}
+
+// release gives the prefix back to the server which delegated it, logging any failure.
+func (pd *prefixDelegation) release(lease *prefixDelegationLease) {
+	client, duid, err := pd.client()
+	if err != nil {
+		logger.Warn("Failed releasing delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String(), "err": err})
+		return
+	}
+
+	defer logger.WarnOnError(client.Close, "Failed to close DHCPv6 client")
+
+	ctx, cancel := context.WithTimeout(context.Background(), prefixDelegationReleaseTimeout)
+	defer cancel()
+
+	_, err = pd.exchange(ctx, client, duid, dhcpv6.MessageTypeRelease, lease.serverID, lease.leaseIAPD())
+	if err != nil {
+		logger.Warn("Failed releasing delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String(), "err": err})
+		return
+	}
+
+	logger.Info("Released delegated IPv6 prefix", logger.Ctx{"interface": pd.iface, "prefix": lease.prefix.String()})
+}
+
+// prefixDelegationSubnet returns the /64 subnet with the given ID within the delegated prefix.
+func prefixDelegationSubnet(prefix *net.IPNet, subnetID uint64) (*net.IPNet, error) {
+	ones, _ := prefix.Mask.Size()
+	if ones > 64 {
+		return nil, fmt.Errorf("Prefix %q is too small to contain a /64 subnet", prefix.String())
+	}
+
+	if ones > 0 && subnetID >= 1<<(64-ones) {
+		return nil, fmt.Errorf("Subnet %d is outside of prefix %q", subnetID, prefix.String())
+	}
+
+	subnet := make(net.IP, net.IPv6len)
+	binary.BigEndian.PutUint64(subnet[:8], binary.BigEndian.Uint64(prefix.IP.To16()[:8])|subnetID)
+
+	return &net.IPNet{IP: subnet, Mask: net.CIDRMask(64, 128)}, nil
+}
+
+// prefixDelegationAllocate assigns a /64 subnet ID within the delegated prefix to each network.
+// Networks with an explicit "ipv6.prefix_delegation.subnet" get that subnet while the others get a subnet derived
+// from their ID, moving on to the next free subnet on conflict.
+func prefixDelegationAllocate(prefix *net.IPNet, networks []api.Network, networkIDs []int64) map[string]uint64 {
+	ones, _ := prefix.Mask.Size()
+
+	size := uint64(1) << 32
+	if ones > 32 {
+		size = uint64(1) << (64 - ones)
+	}
+
+	allocated := map[string]uint64{}
+	used := map[uint64]bool{}
+
+	for _, network := range networks {
+		if network.Config["ipv6.prefix_delegation.subnet"] == "" {
+			continue
+		}
+
+		subnetID, err := strconv.ParseUint(network.Config["ipv6.prefix_delegation.subnet"], 10, 64)
+		if err != nil || subnetID >= size {
+			continue
+		}
+
+		allocated[network.Name] = subnetID
+		used[subnetID] = true
+	}
+
+	for i, network := range networks {
+		if network.Config["ipv6.prefix_delegation.subnet"] != "" {
+			continue
+		}
+
+		subnetID := uint64(networkIDs[i]) % size
+		for range size {
+			if !used[subnetID] {
+				allocated[network.Name] = subnetID
+				used[subnetID] = true
+				break
+			}
+
+			subnetID = (subnetID + 1) % size
+		}
+	}
+
+	return allocated
+}
+
+// prefixDelegationApply updates the networks relying on the prefix delegated on the interface.
+// Bridge networks get their IPv6 address from the prefix, physical networks record it so that it can be used as
+// a route by their OVN networks, which in turn get their IPv6 address from the prefix.
+// A nil prefix (expired lease) clears the recorded prefix and the IPv6 address of the networks.
+func prefixDelegationApply(s *state.State, iface string, prefix *net.IPNet) error {
+	prefixDelegationApplyMu.Lock()
+	defer prefixDelegationApplyMu.Unlock()
+
+	var networks map[string]map[int64]api.Network
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networks, err = tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Record the prefix on the physical networks first, so their OVN networks can use it.
+		for projectName, projectNetworks := range networks {
+			for _, network := range projectNetworks {
+				if network.Type != "physical" || util.IsFalseOrEmpty(network.Config["ipv6.prefix_delegation"]) || GetHostDevice(network.Config["parent"], network.Config["vlan"]) != iface {
+					continue
+				}
+
+				if prefix == nil {
+					if network.Config[prefixDelegationVolatilePrefix] == "" {
+						continue
+					}
+
+					delete(network.Config, prefixDelegationVolatilePrefix)
+				} else {
+					if network.Config[prefixDelegationVolatilePrefix] == prefix.String() {
+						continue
+					}
+
+					network.Config[prefixDelegationVolatilePrefix] = prefix.String()
+				}
+
+				err = tx.UpdateNetwork(ctx, projectName, network.Name, network.Description, network.Config)
+				if err != nil {
+					return fmt.Errorf("Failed saving delegated prefix of network %q: %w", network.Name, err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Find the bridge and OVN networks using the prefix.
+	type pdNetwork struct {
+		project string
+		network api.Network
+		id      int64
+	}
+
+	var users []pdNetwork
+	for projectName, projectNetworks := range networks {
+		for networkID, network := range projectNetworks {
+			switch network.Type {
+			case "bridge":
+				if network.Config["ipv6.prefix_delegation.interface"] != iface {
+					continue
+				}
+
+			case "ovn":
+				if util.IsFalseOrEmpty(network.Config["ipv6.prefix_delegation"]) {
+					continue
+				}
+
+				uplink, ok := networks[api.ProjectDefaultName]
+				if !ok {
+					continue
+				}
+
+				found := false
+				for _, uplinkNetwork := range uplink {
+					if uplinkNetwork.Name == network.Config["network"] && uplinkNetwork.Type == "physical" && util.IsTrue(uplinkNetwork.Config["ipv6.prefix_delegation"]) && GetHostDevice(uplinkNetwork.Config["parent"], uplinkNetwork.Config["vlan"]) == iface {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					continue
+				}
+
+			default:
+				continue
+			}
+
+			users = append(users, pdNetwork{project: projectName, network: network, id: networkID})
+		}
+	}
+
+	// Allocate the subnets in a stable order.
+	slices.SortFunc(users, func(a pdNetwork, b pdNetwork) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	// Drop the IPv6 address of the networks once the prefix expired.
+	if prefix == nil {
+		var errs []error
+		for _, user := range users {
+			if user.network.Config["ipv6.address"] == "none" {
+				continue
+			}
+
+			err := prefixDelegationSetAddress(s, user.project, user.network.Name, "none")
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	allocNetworks := make([]api.Network, 0, len(users))
+	allocIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		// Prefix the name with the project as OVN networks in different projects share the prefix.
+		network := user.network
+		network.Name = user.project + "/" + network.Name
+		allocNetworks = append(allocNetworks, network)
+		allocIDs = append(allocIDs, user.id)
+	}
+
+	allocated := prefixDelegationAllocate(prefix, allocNetworks, allocIDs)
+
+	var errs []error
+	for _, user := range users {
+		subnetID, ok := allocated[user.project+"/"+user.network.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("No free subnet in prefix %q for network %q in project %q", prefix.String(), user.network.Name, user.project))
+			continue
+		}
+
+		subnet, err := prefixDelegationSubnet(prefix, subnetID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Use the first address of the subnet for the network.
+		subnet.IP[net.IPv6len-1] = 1
+		address := subnet.String()
+		if user.network.Config["ipv6.address"] == address {
+			continue
+		}
+
+		err = prefixDelegationSetAddress(s, user.project, user.network.Name, address)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// prefixDelegationSetAddress updates the IPv6 address of a network relying on a delegated prefix.
+func prefixDelegationSetAddress(s *state.State, projectName string, networkName string, address string) error {
+	n, err := LoadByName(s, projectName, networkName)
+	if err != nil {
+		return err
+	}
+
+	config := util.CloneMap(n.Config())
+	config["ipv6.address"] = address
+
+	err = n.Update(api.NetworkPut{Config: config, Description: n.Description()}, "", request.ClientTypeNormal)
+	if err != nil {
+		return fmt.Errorf("Failed updating network %q in project %q: %w", networkName, projectName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prefixDelegationReply returns a DHCPv6 reply delegating the prefix with the given timers.
+func prefixDelegationReply(prefix string, t1 time.Duration, t2 time.Duration, preferred time.Duration, valid time.Duration) *dhcpv6.Message {
+	_, subnet, _ := net.ParseCIDR(prefix)
+
+	iapd := &dhcpv6.OptIAPD{IaId: prefixDelegationIAID, T1: t1, T2: t2}
+	iapd.Options.Add(&dhcpv6.OptIAPrefix{PreferredLifetime: preferred, ValidLifetime: valid, Prefix: subnet})
+
+	reply := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeReply}
+	reply.AddOption(dhcpv6.OptServerID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}}))
+	reply.AddOption(iapd)
+
+	return reply
+}
+
+func TestPrefixDelegationLeaseFromReply(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	// Timers given by the server.
+	lease, err := prefixDelegationLeaseFromReply(prefixDelegationReply("2001:db8:1200::/56", time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8:1200::/56", lease.prefix.String())
+	assert.Equal(t, now.Add(time.Hour), lease.renewAt)
+	assert.Equal(t, now.Add(2*time.Hour), lease.rebindAt)
+	assert.Equal(t, now.Add(4*time.Hour), lease.expiresAt)
+	assert.NotNil(t, lease.serverID)
+
+	// Timers left to the client.
+	lease, err = prefixDelegationLeaseFromReply(prefixDelegationReply("2001:db8:1200::/56", 0, 0, 10*time.Hour, 20*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(5*time.Hour), lease.renewAt)
+	assert.Equal(t, now.Add(8*time.Hour), lease.rebindAt)
+	assert.Equal(t, now.Add(20*time.Hour), lease.expiresAt)
+
+	// Timers past the valid lifetime.
+	lease, err = prefixDelegationLeaseFromReply(prefixDelegationReply("2001:db8:1200::/56", 3*time.Hour, 2*time.Hour, time.Hour, time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), lease.renewAt)
+	assert.Equal(t, now.Add(time.Hour), lease.rebindAt)
+	assert.Equal(t, now.Add(time.Hour), lease.expiresAt)
+
+	// Prefix too small.
+	_, err = prefixDelegationLeaseFromReply(prefixDelegationReply("2001:db8:1200::/72", time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour), now)
+	assert.Error(t, err)
+
+	// Expired prefix.
+	_, err = prefixDelegationLeaseFromReply(prefixDelegationReply("2001:db8:1200::/56", 0, 0, 0, 0), now)
+	assert.Error(t, err)
+
+	// No binding.
+	reply := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeReply}
+	iapd := &dhcpv6.OptIAPD{IaId: prefixDelegationIAID}
+	iapd.Options.Add(&dhcpv6.OptStatusCode{StatusCode: iana.StatusNoBinding})
+	reply.AddOption(iapd)
+
+	_, err = prefixDelegationLeaseFromReply(reply, now)
+	assert.ErrorIs(t, err, errPrefixDelegationNoBinding)
+}
+
+func TestPrefixDelegationLeaseNext(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	lease := &prefixDelegationLease{
+		renewAt:   now.Add(time.Hour),
+		rebindAt:  now.Add(2 * time.Hour),
+		expiresAt: now.Add(3 * time.Hour),
+	}
+
+	tests := []struct {
+		lease *prefixDelegationLease
+		now   time.Time
+		step  prefixDelegationStep
+		wait  time.Duration
+	}{
+		{lease: nil, now: now, step: prefixDelegationStepSolicit},
+		{lease: lease, now: now, step: prefixDelegationStepRenew, wait: time.Hour},
+		{lease: lease, now: now.Add(time.Hour), step: prefixDelegationStepRenew},
+		{lease: lease, now: now.Add(90 * time.Minute), step: prefixDelegationStepRenew},
+		{lease: lease, now: now.Add(2 * time.Hour), step: prefixDelegationStepRebind},
+		{lease: lease, now: now.Add(3 * time.Hour), step: prefixDelegationStepExpire},
+	}
+
+	for _, test := range tests {
+		step, wait := test.lease.next(test.now)
+		assert.Equal(t, test.step, step, "at %v", test.now.Sub(now))
+		assert.Equal(t, test.wait, wait, "at %v", test.now.Sub(now))
+	}
+}
+
+func TestPrefixDelegationLeaseRetryAfter(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	var noLease *prefixDelegationLease
+	assert.Equal(t, prefixDelegationRetryInterval, noLease.retryAfter(now))
+
+	lease := &prefixDelegationLease{
+		renewAt:   now,
+		rebindAt:  now.Add(30 * time.Second),
+		expiresAt: now.Add(40 * time.Second),
+	}
+
+	// Don't wait past rebinding.
+	assert.Equal(t, 30*time.Second, lease.retryAfter(now))
+
+	// Don't wait past expiry.
+	assert.Equal(t, 10*time.Second, lease.retryAfter(now.Add(30*time.Second)))
+
+	// Far from any deadline.
+	lease.rebindAt = now.Add(time.Hour)
+	lease.expiresAt = now.Add(2 * time.Hour)
+	assert.Equal(t, prefixDelegationRetryInterval, lease.retryAfter(now))
+}
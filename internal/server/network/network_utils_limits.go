@@ -0,0 +1,819 @@
+package network
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v7/internal/server/db"
+	dbCluster "github.com/lxc/incus/v7/internal/server/db/cluster"
+	"github.com/lxc/incus/v7/internal/server/device/nictype"
+	"github.com/lxc/incus/v7/internal/server/instance"
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v7/internal/server/ip"
+	networkOVN "github.com/lxc/incus/v7/internal/server/network/ovn"
+	ovnNB "github.com/lxc/incus/v7/internal/server/network/ovn/schema/ovn-nb"
+	"github.com/lxc/incus/v7/internal/server/project"
+	"github.com/lxc/incus/v7/internal/server/state"
+	"github.com/lxc/incus/v7/shared/api"
+	"github.com/lxc/incus/v7/shared/units"
+)
+
+// bridgeLimitsMutex protects bridgeLimitsTrees and serializes the changes to the bridge traffic control trees.
+var bridgeLimitsMutex sync.Mutex
+
+// bridgeLimitsTrees holds the traffic control trees set up on each managed bridge, protected by bridgeLimitsMutex.
+var bridgeLimitsTrees = map[string]*bridgeLimits{}
+
+// bridgeLimitsFilterPriority is the priority of the filters classifying the NIC traffic.
+const bridgeLimitsFilterPriority = 1
+
+// bridgeLimitsMinID and bridgeLimitsMaxID delimit the minor IDs used for the project and NIC classes.
+// The IDs are also used as u32 filter node IDs which are limited to 12 bits.
+const bridgeLimitsMinID = 0x100
+const bridgeLimitsMaxID = 0xfff
+
+// bridgeLimits represents the traffic control trees of a bridge, one per direction (nil if not shaped).
+type bridgeLimits struct {
+	ingress *bridgeLimitsTree
+	egress  *bridgeLimitsTree
+}
+
+// bridgeLimitsTree represents a HTB tree shaping the traffic of a bridge in one direction.
+//
+// The network limit (if any) is the root class with the project classes as its children. Each project class has
+// one child class per NIC, which packets are assigned to based on the NIC's MAC address.
+type bridgeLimitsTree struct {
+	dev      string
+	source   bool
+	rate     int64
+	projects map[string]*bridgeLimitsProjectClass
+}
+
+// bridgeLimitsProjectClass represents a project's class in a bridge traffic control tree.
+type bridgeLimitsProjectClass struct {
+	id   uint16
+	ceil int64
+	nics map[string]uint16
+}
+
+// bridgeLimitsProject represents the NICs of a project connected to a bridge.
+type bridgeLimitsProject struct {
+	config map[string]string
+	nics   []bridgeLimitsNIC
+}
+
+// bridgeLimitsNIC represents a NIC connected to a bridge.
+type bridgeLimitsNIC struct {
+	hwaddr net.HardwareAddr
+	config map[string]string
+}
+
+// bridgeLimitsIfbName returns the name of the ifb device used to shape the egress traffic of a bridge.
+// Bridge names too long to be suffixed get a name derived from a hash of the bridge name instead.
+func bridgeLimitsIfbName(bridgeName string) string {
+	name := fmt.Sprintf("%s-ifb", bridgeName)
+	if len(name) > 15 {
+		hash := sha256.Sum256([]byte(bridgeName))
+		name = fmt.Sprintf("ifb%x", hash[:6])
+	}
+
+	return name
+}
+
+// parseLimit parses a bandwidth limit in bit/s, returning 0 if not set.
+func parseLimit(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return units.ParseBitSizeString(value)
+}
+
+// bridgeLimitsNICRate returns the NIC limit in the direction of the key ("limits.ingress" or "limits.egress").
+func bridgeLimitsNICRate(config map[string]string, key string) (int64, error) {
+	if config["limits.max"] != "" {
+		key = "limits.max"
+	}
+
+	rate, err := parseLimit(config[key])
+	if err != nil {
+		return 0, fmt.Errorf("Failed parsing %s of NIC: %w", key, err)
+	}
+
+	return rate, nil
+}
+
+// bridgeLimitsGuaranteedRate returns the rate guaranteed to a class that may borrow up to ceil from its parent.
+// Child classes only get a small share guaranteed as HTB doesn't enforce the parent's limit on children sending
+// below their own rate.
+func bridgeLimitsGuaranteedRate(ceil int64) int64 {
+	return max(ceil/100, 8000)
+}
+
+// UpdateBridgeLimits rebuilds the traffic control trees enforcing the network and project level bandwidth
+// limits of the managed bridges on this member. If networkName is empty, all managed bridges are updated.
+func UpdateBridgeLimits(s *state.State, networkName string) error {
+	// Get all the networks.
+	var networks []string
+	if networkName == "" {
+		var err error
+
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Pass api.ProjectDefaultName here, as bridge networks do not support projects.
+			networks, err = tx.GetNetworks(ctx, api.ProjectDefaultName)
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		networks = []string{networkName}
+	}
+
+	projectNICs, err := bridgeLimitsProjectNICs(s, networks)
+	if err != nil {
+		return err
+	}
+
+	for _, network := range networks {
+		// Pass api.ProjectDefaultName here, as bridge networks do not support projects.
+		n, err := LoadByName(s, api.ProjectDefaultName, network)
+		if err != nil {
+			return fmt.Errorf("Failed to load network %q in project %q for limits update: %w", network, api.ProjectDefaultName, err)
+		}
+
+		// Skip networks that aren't running managed bridges.
+		b, ok := n.(*bridge)
+		if !ok || !b.isRunning() {
+			continue
+		}
+
+		err = b.applyLimits(projectNICs[network])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bridgeLimitsProjectNICs returns the bridged NICs of this member's instances in projects with network limits,
+// indexed by network and project.
+func bridgeLimitsProjectNICs(s *state.State, networks []string) (map[string]map[string]*bridgeLimitsProject, error) {
+	// Get all the instances.
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return nil, err
+	}
+
+	nics := map[string]map[string]*bridgeLimitsProject{}
+	for _, inst := range insts {
+		instProject := inst.Project()
+
+		// Skip instances in projects without network limits.
+		if instProject.Config["limits.network.ingress"] == "" && instProject.Config["limits.network.egress"] == "" {
+			continue
+		}
+
+		for deviceName, d := range inst.ExpandedDevices() {
+			if d["type"] != "nic" {
+				continue
+			}
+
+			nicType, err := nictype.NICType(s, instProject.Name, d)
+			if err != nil || nicType != "bridged" {
+				continue
+			}
+
+			parent := d["parent"]
+			if d["network"] != "" {
+				parent = d["network"]
+			}
+
+			if !slices.Contains(networks, parent) {
+				continue
+			}
+
+			hwaddr := d["hwaddr"]
+			if hwaddr == "" {
+				hwaddr = inst.LocalConfig()[fmt.Sprintf("volatile.%s.hwaddr", deviceName)]
+			}
+
+			mac, err := net.ParseMAC(hwaddr)
+			if err != nil {
+				continue
+			}
+
+			_, ok := nics[parent]
+			if !ok {
+				nics[parent] = map[string]*bridgeLimitsProject{}
+			}
+
+			_, ok = nics[parent][instProject.Name]
+			if !ok {
+				nics[parent][instProject.Name] = &bridgeLimitsProject{config: instProject.Config}
+			}
+
+			nics[parent][instProject.Name].nics = append(nics[parent][instProject.Name].nics, bridgeLimitsNIC{hwaddr: mac, config: d})
+		}
+	}
+
+	return nics, nil
+}
+
+// setupLimits sets up the network and project level bandwidth limits of the bridge.
+func (n *bridge) setupLimits() error {
+	projectNICs, err := bridgeLimitsProjectNICs(n.state, []string{n.name})
+	if err != nil {
+		return err
+	}
+
+	return n.applyLimits(projectNICs[n.name])
+}
+
+// applyLimits rebuilds the traffic control trees of the bridge.
+//
+// Traffic going from the host into the bridge (towards the instances) is shaped on the bridge interface itself
+// while traffic leaving the bridge is redirected to an ifb device and shaped there. In both cases, the NIC
+// classes are nested in their project's class, nested in the network class.
+func (n *bridge) applyLimits(projects map[string]*bridgeLimitsProject) error {
+	bridgeLimitsMutex.Lock()
+	defer bridgeLimitsMutex.Unlock()
+
+	ingress, err := parseLimit(n.config["limits.ingress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.ingress: %w", err)
+	}
+
+	egress, err := parseLimit(n.config["limits.egress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.egress: %w", err)
+	}
+
+	// Clear the existing trees.
+	delete(bridgeLimitsTrees, n.name)
+	err = clearBridgeLimits(n.name)
+	if err != nil {
+		return err
+	}
+
+	limits := &bridgeLimits{}
+
+	if ingress > 0 {
+		limits.ingress, err = newBridgeLimitsTree(n.name, ingress, false)
+		if err != nil {
+			return fmt.Errorf("Failed setting up ingress limits: %w", err)
+		}
+	}
+
+	if egress > 0 {
+		limits.egress, err = newBridgeLimitsTree(n.name, egress, true)
+		if err != nil {
+			return fmt.Errorf("Failed setting up egress limits: %w", err)
+		}
+	}
+
+	for _, projectName := range slices.Sorted(maps.Keys(projects)) {
+		project := projects[projectName]
+		for _, nic := range project.nics {
+			err = limits.addNIC(n.name, projectName, project.config, nic)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	bridgeLimitsTrees[n.name] = limits
+
+	return nil
+}
+
+// AddBridgeLimitsNIC adds an instance NIC to the traffic control trees of a managed bridge so that the network
+// limits of its project apply to it. This is a no-op if the NIC is already part of the trees.
+func AddBridgeLimitsNIC(bridgeName string, projectName string, projectConfig map[string]string, nicConfig map[string]string, hwaddr net.HardwareAddr) error {
+	bridgeLimitsMutex.Lock()
+	defer bridgeLimitsMutex.Unlock()
+
+	// Skip bridges whose limits haven't been set up.
+	limits := bridgeLimitsTrees[bridgeName]
+	if limits == nil {
+		return nil
+	}
+
+	return limits.addNIC(bridgeName, projectName, projectConfig, bridgeLimitsNIC{hwaddr: hwaddr, config: nicConfig})
+}
+
+// RemoveBridgeLimitsNIC removes an instance NIC from the traffic control trees of a managed bridge.
+func RemoveBridgeLimitsNIC(bridgeName string, hwaddr net.HardwareAddr) error {
+	bridgeLimitsMutex.Lock()
+	defer bridgeLimitsMutex.Unlock()
+
+	limits := bridgeLimitsTrees[bridgeName]
+	if limits == nil {
+		return nil
+	}
+
+	for _, tree := range []*bridgeLimitsTree{limits.ingress, limits.egress} {
+		if tree == nil {
+			continue
+		}
+
+		err := tree.removeNIC(hwaddr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addNIC adds a NIC to the traffic control trees of the bridge, setting up the trees and the classes of the
+// NIC's project as needed.
+func (l *bridgeLimits) addNIC(bridgeName string, projectName string, projectConfig map[string]string, nic bridgeLimitsNIC) error {
+	ingress, err := parseLimit(projectConfig["limits.network.ingress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.network.ingress of project %q: %w", projectName, err)
+	}
+
+	if ingress > 0 {
+		if l.ingress == nil {
+			l.ingress, err = newBridgeLimitsTree(bridgeName, 0, false)
+			if err != nil {
+				return fmt.Errorf("Failed setting up ingress limits: %w", err)
+			}
+		}
+
+		nicRate, err := bridgeLimitsNICRate(nic.config, "limits.ingress")
+		if err != nil {
+			return err
+		}
+
+		err = l.ingress.addNIC(projectName, ingress, nic.hwaddr, nicRate)
+		if err != nil {
+			return err
+		}
+	}
+
+	egress, err := parseLimit(projectConfig["limits.network.egress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.network.egress of project %q: %w", projectName, err)
+	}
+
+	if egress > 0 {
+		if l.egress == nil {
+			l.egress, err = newBridgeLimitsTree(bridgeName, 0, true)
+			if err != nil {
+				return fmt.Errorf("Failed setting up egress limits: %w", err)
+			}
+		}
+
+		nicRate, err := bridgeLimitsNICRate(nic.config, "limits.egress")
+		if err != nil {
+			return err
+		}
+
+		err = l.egress.addNIC(projectName, egress, nic.hwaddr, nicRate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newBridgeLimitsTree creates the root of a HTB tree for the bridge, with the network rate (if any) as the root
+// class. The egress tree is created on an ifb device the traffic leaving the bridge is redirected to.
+func newBridgeLimitsTree(bridgeName string, rate int64, egress bool) (*bridgeLimitsTree, error) {
+	dev := bridgeName
+	if egress {
+		dev = bridgeLimitsIfbName(bridgeName)
+		ifb := &ip.Ifb{Link: ip.Link{Name: dev}}
+		err := ifb.Add()
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating ifb device %q: %w", dev, err)
+		}
+
+		err = ifb.SetUp()
+		if err != nil {
+			return nil, fmt.Errorf("Failed bringing up ifb device %q: %w", dev, err)
+		}
+	}
+
+	// Unclassified traffic goes to the default class when a network rate is set, otherwise it isn't shaped.
+	qdisc := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: dev, Handle: "1:0", Parent: "root"}}
+	if rate > 0 {
+		qdisc.Default = 0x2
+	}
+
+	err := qdisc.Add()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create root tc qdisc: %w", err)
+	}
+
+	if rate > 0 {
+		class := &ip.ClassHTB{Class: ip.Class{Dev: dev, Parent: "1:0", Classid: "1:1"}, Rate: fmt.Sprintf("%dbit", rate)}
+		err = class.Add()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create limit tc class: %w", err)
+		}
+
+		class = &ip.ClassHTB{Class: ip.Class{Dev: dev, Parent: "1:1", Classid: "1:2"}, Rate: fmt.Sprintf("%dbit", bridgeLimitsGuaranteedRate(rate)), Ceil: fmt.Sprintf("%dbit", rate)}
+		err = class.Add()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create default tc class: %w", err)
+		}
+	}
+
+	if egress {
+		qdiscIngress := &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: bridgeName, Handle: "ffff:0"}}
+		err = qdiscIngress.Add()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create ingress tc qdisc: %w", err)
+		}
+
+		filter := &ip.U32Filter{Filter: ip.Filter{Dev: bridgeName, Parent: "ffff:0", Protocol: "all"}, Value: 0, Mask: 0, Actions: []ip.Action{&ip.ActionMirred{Dev: dev}}}
+		err = filter.Add()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create redirect tc filter: %w", err)
+		}
+	}
+
+	return &bridgeLimitsTree{dev: dev, source: egress, rate: rate, projects: map[string]*bridgeLimitsProjectClass{}}, nil
+}
+
+// allocateID returns the lowest class ID not used by a project or NIC class of the tree.
+func (t *bridgeLimitsTree) allocateID() (uint16, error) {
+	used := map[uint16]bool{}
+	for _, project := range t.projects {
+		used[project.id] = true
+		for _, id := range project.nics {
+			used[id] = true
+		}
+	}
+
+	for id := uint16(bridgeLimitsMinID); id <= bridgeLimitsMaxID; id++ {
+		if !used[id] {
+			return id, nil
+		}
+	}
+
+	return 0, errors.New("No traffic class ID available")
+}
+
+// addNIC adds a NIC class (limited to the NIC rate if set) and filter to the class of the NIC's project,
+// creating the project class (limited to the project rate) if needed.
+func (t *bridgeLimitsTree) addNIC(projectName string, projectRate int64, hwaddr net.HardwareAddr, nicRate int64) error {
+	project := t.projects[projectName]
+	if project == nil {
+		id, err := t.allocateID()
+		if err != nil {
+			return err
+		}
+
+		parent := "1:0"
+		ceil := projectRate
+		if t.rate > 0 {
+			parent = "1:1"
+			ceil = min(ceil, t.rate)
+		}
+
+		class := &ip.ClassHTB{Class: ip.Class{Dev: t.dev, Parent: parent, Classid: fmt.Sprintf("1:%x", id)}, Rate: fmt.Sprintf("%dbit", ceil)}
+		if t.rate > 0 {
+			class.Rate = fmt.Sprintf("%dbit", bridgeLimitsGuaranteedRate(ceil))
+			class.Ceil = fmt.Sprintf("%dbit", ceil)
+		}
+
+		err = class.Add()
+		if err != nil {
+			return fmt.Errorf("Failed to create tc class for project %q: %w", projectName, err)
+		}
+
+		project = &bridgeLimitsProjectClass{id: id, ceil: ceil, nics: map[string]uint16{}}
+		t.projects[projectName] = project
+	}
+
+	_, ok := project.nics[hwaddr.String()]
+	if ok {
+		return nil
+	}
+
+	id, err := t.allocateID()
+	if err != nil {
+		return err
+	}
+
+	ceil := project.ceil
+	if nicRate > 0 {
+		ceil = min(ceil, nicRate)
+	}
+
+	classID := fmt.Sprintf("1:%x", id)
+	class := &ip.ClassHTB{Class: ip.Class{Dev: t.dev, Parent: fmt.Sprintf("1:%x", project.id), Classid: classID}, Rate: fmt.Sprintf("%dbit", bridgeLimitsGuaranteedRate(ceil)), Ceil: fmt.Sprintf("%dbit", ceil)}
+	err = class.Add()
+	if err != nil {
+		return fmt.Errorf("Failed to create tc class for %q: %w", hwaddr, err)
+	}
+
+	filter := &ip.U32Filter{Filter: ip.Filter{Dev: t.dev, Parent: "1:0", Protocol: "all", Flowid: classID}, Matches: ip.U32MACMatches(hwaddr, t.source), Handle: ip.U32Handle(id), Priority: bridgeLimitsFilterPriority}
+	err = filter.Add()
+	if err != nil {
+		_ = class.Delete()
+		return fmt.Errorf("Failed to create tc filter for %q: %w", hwaddr, err)
+	}
+
+	project.nics[hwaddr.String()] = id
+
+	return nil
+}
+
+// removeNIC removes the filter and class of a NIC from the tree.
+func (t *bridgeLimitsTree) removeNIC(hwaddr net.HardwareAddr) error {
+	for _, project := range t.projects {
+		id, ok := project.nics[hwaddr.String()]
+		if !ok {
+			continue
+		}
+
+		filter := &ip.U32Filter{Filter: ip.Filter{Dev: t.dev, Parent: "1:0", Protocol: "all"}, Handle: ip.U32Handle(id), Priority: bridgeLimitsFilterPriority}
+		err := filter.Delete()
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("Failed to remove tc filter for %q: %w", hwaddr, err)
+		}
+
+		class := &ip.ClassHTB{Class: ip.Class{Dev: t.dev, Parent: fmt.Sprintf("1:%x", project.id), Classid: fmt.Sprintf("1:%x", id)}}
+		err = class.Delete()
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("Failed to remove tc class for %q: %w", hwaddr, err)
+		}
+
+		delete(project.nics, hwaddr.String())
+	}
+
+	return nil
+}
+
+// clearLimits removes the traffic control trees of the bridge.
+func (n *bridge) clearLimits() error {
+	bridgeLimitsMutex.Lock()
+	defer bridgeLimitsMutex.Unlock()
+
+	delete(bridgeLimitsTrees, n.name)
+
+	return clearBridgeLimits(n.name)
+}
+
+// clearBridgeLimits removes the traffic control trees of a bridge and its ifb device.
+func clearBridgeLimits(bridgeName string) error {
+	qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: bridgeName, Handle: "1:0", Parent: "root"}}
+	err := qdiscHTB.Delete()
+	if err != nil && !errors.Is(err, unix.ENOENT) {
+		return err
+	}
+
+	qdiscIngress := &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: bridgeName, Handle: "ffff:0"}}
+	err = qdiscIngress.Delete()
+	if err != nil && !errors.Is(err, unix.ENOENT) {
+		return err
+	}
+
+	ifbName := bridgeLimitsIfbName(bridgeName)
+	if InterfaceExists(ifbName) {
+		ifb := &ip.Link{Name: ifbName}
+		err = ifb.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting ifb device %q: %w", ifbName, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateOVNLimits reapplies the bandwidth limits of the OVN networks used by a project.
+func UpdateOVNLimits(s *state.State, projectName string) error {
+	networkProjectName, _, err := project.NetworkProject(s.DB.Cluster, projectName)
+	if err != nil {
+		return err
+	}
+
+	var networks []string
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networks, err = tx.GetNetworks(ctx, networkProjectName)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, network := range networks {
+		n, err := LoadByName(s, networkProjectName, network)
+		if err != nil {
+			return fmt.Errorf("Failed to load network %q in project %q for limits update: %w", network, networkProjectName, err)
+		}
+
+		o, ok := n.(*ovn)
+		if !ok {
+			continue
+		}
+
+		err = o.setupLimits()
+		if err != nil {
+			return fmt.Errorf("Failed updating limits of network %q in project %q: %w", network, networkProjectName, err)
+		}
+	}
+
+	return nil
+}
+
+// ovnLimitsProject represents the NICs of a project connected to an OVN network.
+type ovnLimitsProject struct {
+	ports   []string
+	hwaddrs []string
+}
+
+// setupLimits applies the network and project level bandwidth limits of the network.
+//
+// The network limits apply to the traffic going through the router port of the external switch, so to all the
+// traffic between the instances and the uplink. The project limits apply to the traffic between the router and
+// the NICs of each project's instances on the internal switch. As each of those and the NIC limits are applied
+// at a different stage, a NIC is subject to all of them.
+func (n *ovn) setupLimits() error {
+	ingressRate, err := parseLimit(n.config["limits.ingress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.ingress: %w", err)
+	}
+
+	egressRate, err := parseLimit(n.config["limits.egress"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing limits.egress: %w", err)
+	}
+
+	// Remove the existing rules.
+	intRouterPortName := n.getIntSwitchRouterPortName()
+	err = n.ovnnb.DeleteLogicalSwitchQoSRules(context.TODO(), n.getIntSwitchName(), intRouterPortName)
+	if err != nil {
+		return fmt.Errorf("Failed removing existing QoS rules: %w", err)
+	}
+
+	extRouterPortName := n.getExtSwitchRouterPortName()
+	if n.config["network"] != "none" {
+		err = n.ovnnb.DeleteLogicalSwitchQoSRules(context.TODO(), n.getExtSwitchName(), extRouterPortName)
+		if err != nil {
+			return fmt.Errorf("Failed removing existing QoS rules: %w", err)
+		}
+	}
+
+	// Apply the network limits on the external switch.
+	var rules []networkOVN.OVNQoSRule
+	if n.config["network"] != "none" {
+		// Traffic from the instances enters the external switch through the router port.
+		if egressRate > 0 {
+			rules = append(rules, networkOVN.OVNQoSRule{
+				Direction: ovnNB.QoSDirectionFromLport,
+				Action:    map[string]int{},
+				Bandwidth: map[string]int{
+					"rate": int(egressRate / 1000),
+				},
+				Match:    fmt.Sprintf("inport == \"%s\"", extRouterPortName),
+				Priority: 100,
+			})
+		}
+
+		// Traffic to the instances leaves the external switch through the router port.
+		if ingressRate > 0 {
+			rules = append(rules, networkOVN.OVNQoSRule{
+				Direction: ovnNB.QoSDirectionToLport,
+				Action:    map[string]int{},
+				Bandwidth: map[string]int{
+					"rate": int(ingressRate / 1000),
+				},
+				Match:    fmt.Sprintf("outport == \"%s\"", extRouterPortName),
+				Priority: 100,
+			})
+		}
+
+		if len(rules) > 0 {
+			err = n.ovnnb.AddLogicalSwitchQoSRules(context.TODO(), n.getExtSwitchName(), extRouterPortName, rules...)
+			if err != nil {
+				return fmt.Errorf("Failed adding network QoS rules: %w", err)
+			}
+		}
+	}
+
+	// Get the NICs connected to the network, by instance project.
+	projects := map[string]*ovnLimitsProject{}
+	err = UsedByInstanceDevices(n.state, n.project, n.name, n.Type(), func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		instanceUUID := inst.Config["volatile.uuid"]
+		if instanceUUID == "" {
+			return nil
+		}
+
+		hwaddr := nicConfig["hwaddr"]
+		if hwaddr == "" {
+			hwaddr = inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+		}
+
+		_, ok := projects[inst.Project]
+		if !ok {
+			projects[inst.Project] = &ovnLimitsProject{}
+		}
+
+		projects[inst.Project].ports = append(projects[inst.Project].ports, fmt.Sprintf("%q", n.getInstanceDevicePortName(instanceUUID, nicName)))
+
+		mac, err := net.ParseMAC(hwaddr)
+		if err == nil {
+			projects[inst.Project].hwaddrs = append(projects[inst.Project].hwaddrs, mac.String())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting NICs using network: %w", err)
+	}
+
+	// Get the configuration of those projects.
+	projectConfigs := map[string]map[string]string{}
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for projectName := range projects {
+			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return err
+			}
+
+			projectConfigs[projectName], err = dbCluster.GetProjectConfig(ctx, tx.Tx(), dbProject.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading project configuration: %w", err)
+	}
+
+	// Apply the project limits on the internal switch.
+	rules = nil
+	for _, projectName := range slices.Sorted(maps.Keys(projects)) {
+		projectNICs := projects[projectName]
+
+		projectEgress, err := parseLimit(projectConfigs[projectName]["limits.network.egress"])
+		if err != nil {
+			return fmt.Errorf("Failed parsing limits.network.egress of project %q: %w", projectName, err)
+		}
+
+		projectIngress, err := parseLimit(projectConfigs[projectName]["limits.network.ingress"])
+		if err != nil {
+			return fmt.Errorf("Failed parsing limits.network.ingress of project %q: %w", projectName, err)
+		}
+
+		// Traffic from the project's NICs to the router.
+		if projectEgress > 0 {
+			rules = append(rules, networkOVN.OVNQoSRule{
+				Direction: ovnNB.QoSDirectionToLport,
+				Action:    map[string]int{},
+				Bandwidth: map[string]int{
+					"rate": int(projectEgress / 1000),
+				},
+				Match:    fmt.Sprintf("outport == \"%s\" && inport == {%s}", intRouterPortName, strings.Join(projectNICs.ports, ", ")),
+				Priority: 100,
+			})
+		}
+
+		// Traffic from the router to the project's NICs. The output port isn't known yet at that stage.
+		if projectIngress > 0 && len(projectNICs.hwaddrs) > 0 {
+			rules = append(rules, networkOVN.OVNQoSRule{
+				Direction: ovnNB.QoSDirectionFromLport,
+				Action:    map[string]int{},
+				Bandwidth: map[string]int{
+					"rate": int(projectIngress / 1000),
+				},
+				Match:    fmt.Sprintf("inport == \"%s\" && eth.dst == {%s}", intRouterPortName, strings.Join(projectNICs.hwaddrs, ", ")),
+				Priority: 100,
+			})
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	err = n.ovnnb.AddLogicalSwitchQoSRules(context.TODO(), n.getIntSwitchName(), intRouterPortName, rules...)
+	if err != nil {
+		return fmt.Errorf("Failed adding project QoS rules: %w", err)
+	}
+
+	return nil
+}
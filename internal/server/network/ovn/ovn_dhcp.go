@@ -0,0 +1,103 @@
+package ovn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
+)
+
+// dhcpv4Option describes a DHCPv4 option supported by OVN.
+type dhcpv4Option struct {
+	name      string
+	valueType string
+}
+
+// dhcpv4Options lists the DHCPv4 options supported by OVN by option code.
+// Options that are managed through dedicated configuration keys aren't included.
+var dhcpv4Options = map[uint64]dhcpv4Option{
+	7:   {name: "log_server", valueType: "ipv4"},
+	9:   {name: "lpr_server", valueType: "ipv4"},
+	12:  {name: "hostname", valueType: "str"},
+	16:  {name: "swap_server", valueType: "ipv4"},
+	19:  {name: "ip_forward_enable", valueType: "bool"},
+	23:  {name: "default_ttl", valueType: "uint8"},
+	28:  {name: "broadcast_address", valueType: "ipv4"},
+	31:  {name: "router_discovery", valueType: "bool"},
+	32:  {name: "router_solicitation", valueType: "ipv4"},
+	35:  {name: "arp_cache_timeout", valueType: "uint32"},
+	36:  {name: "ethernet_encap", valueType: "bool"},
+	37:  {name: "tcp_ttl", valueType: "uint8"},
+	38:  {name: "tcp_keepalive_interval", valueType: "uint32"},
+	41:  {name: "nis_server", valueType: "ipv4"},
+	42:  {name: "ntp_server", valueType: "ipv4"},
+	44:  {name: "netbios_name_server", valueType: "ipv4"},
+	46:  {name: "netbios_node_type", valueType: "uint8"},
+	58:  {name: "T1", valueType: "uint32"},
+	59:  {name: "T2", valueType: "uint32"},
+	66:  {name: "tftp_server", valueType: "str"},
+	150: {name: "tftp_server_address", valueType: "ipv4"},
+	210: {name: "path_prefix", valueType: "str"},
+	252: {name: "wpad", valueType: "str"},
+}
+
+// DHCPv4Option returns the OVN name and value of a DHCPv4 option from its code and a value in the same syntax as
+// dnsmasq (comma-separated lists of addresses).
+func DHCPv4Option(code string, value string) (string, string, error) {
+	codeNum, err := strconv.ParseUint(code, 10, 8)
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid DHCP option code %q", code)
+	}
+
+	option, ok := dhcpv4Options[codeNum]
+	if !ok {
+		return "", "", fmt.Errorf("DHCP option %d isn't supported by OVN", codeNum)
+	}
+
+	switch option.valueType {
+	case "ipv4":
+		addresses := util.SplitNTrimSpace(value, ",", -1, false)
+		for _, address := range addresses {
+			err := validate.IsNetworkAddressV4(address)
+			if err != nil {
+				return "", "", err
+			}
+		}
+
+		if len(addresses) == 1 {
+			return option.name, addresses[0], nil
+		}
+
+		return option.name, fmt.Sprintf("{%s}", strings.Join(addresses, ", ")), nil
+	case "bool":
+		err := validate.IsBool(value)
+		if err != nil {
+			return "", "", err
+		}
+
+		if util.IsTrue(value) {
+			return option.name, "1", nil
+		}
+
+		return option.name, "0", nil
+	case "uint8":
+		err := validate.IsUint8(value)
+		if err != nil {
+			return "", "", err
+		}
+
+		return option.name, value, nil
+	case "uint32":
+		err := validate.IsUint32(value)
+		if err != nil {
+			return "", "", err
+		}
+
+		return option.name, value, nil
+	}
+
+	// Quote strings so that OVN doesn't attempt to parse them.
+	return option.name, strconv.Quote(value), nil
+}
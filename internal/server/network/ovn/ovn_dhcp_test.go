@@ -0,0 +1,78 @@
+package ovn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ovnNB "github.com/lxc/incus/v7/internal/server/network/ovn/schema/ovn-nb"
+)
+
+func TestDHCPv4Option(t *testing.T) {
+	tests := []struct {
+		code      string
+		value     string
+		wantName  string
+		wantValue string
+		wantErr   bool
+	}{
+		{code: "42", value: "10.0.0.1", wantName: "ntp_server", wantValue: "10.0.0.1"},
+		{code: "42", value: "10.0.0.1, 10.0.0.2", wantName: "ntp_server", wantValue: "{10.0.0.1, 10.0.0.2}"},
+		{code: "42", value: "ntp.example.net", wantErr: true},
+		{code: "42", value: "", wantErr: true},
+		{code: "12", value: "host1", wantName: "hostname", wantValue: `"host1"`},
+		{code: "252", value: `http://wpad/"x"`, wantName: "wpad", wantValue: `"http://wpad/\"x\""`},
+		{code: "19", value: "true", wantName: "ip_forward_enable", wantValue: "1"},
+		{code: "19", value: "no", wantName: "ip_forward_enable", wantValue: "0"},
+		{code: "19", value: "maybe", wantErr: true},
+		{code: "23", value: "64", wantName: "default_ttl", wantValue: "64"},
+		{code: "23", value: "256", wantErr: true},
+		{code: "58", value: "3600", wantName: "T1", wantValue: "3600"},
+		{code: "3", value: "10.0.0.1", wantErr: true},   // Managed through the network's router address.
+		{code: "67", value: "pxe.0", wantErr: true},     // Managed through ipv4.dhcp.filename.
+		{code: "224", value: "anything", wantErr: true}, // Unknown to OVN.
+		{code: "300", value: "anything", wantErr: true},
+		{code: "abc", value: "anything", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		name, value, err := DHCPv4Option(tt.code, tt.value)
+		if tt.wantErr {
+			assert.Error(t, err, "code %s value %q", tt.code, tt.value)
+			continue
+		}
+
+		assert.NoError(t, err, "code %s value %q", tt.code, tt.value)
+		assert.Equal(t, tt.wantName, name)
+		assert.Equal(t, tt.wantValue, value)
+	}
+}
+
+func TestLogicalSwitchPortDHCPv4OptionsApply(t *testing.T) {
+	switchOption := &ovnNB.DHCPOptions{
+		Cidr: "10.0.0.0/24",
+		Options: map[string]string{
+			"server_id":     "10.0.0.1",
+			"bootfile_name": `"pxe.0"`,
+			"ntp_server":    "10.0.0.1",
+		},
+	}
+
+	dhcpOption := &ovnNB.DHCPOptions{
+		ExternalIDs: map[string]string{},
+		Options:     map[string]string{"stale": "1"},
+	}
+
+	logicalSwitchPortDHCPv4OptionsApply(dhcpOption, switchOption, map[string]string{
+		"bootfile_name": `"custom.0"`,
+		"ntp_server":    "",
+	})
+
+	assert.Equal(t, "10.0.0.0/24", dhcpOption.Cidr)
+	assert.Equal(t, map[string]string{"server_id": "10.0.0.1", "bootfile_name": `"custom.0"`}, dhcpOption.Options)
+	assert.Equal(t, `"custom.0"`, dhcpOption.ExternalIDs[ovnExtIDIncusDHCPOptionPrefix+"bootfile_name"])
+	assert.Contains(t, dhcpOption.ExternalIDs, ovnExtIDIncusDHCPOptionPrefix+"ntp_server")
+
+	// The switch's option set is left untouched.
+	assert.Equal(t, "10.0.0.1", switchOption.Options["ntp_server"])
+}
@@ -83,6 +83,9 @@ const (
 	ovnExtIDIncusProjectID  = "incus_project_id"
 	ovnExtIDIncusPortGroup  = "incus_port_group"
 	ovnExtIDIncusLocation   = "incus_location"
+
+	// ovnExtIDIncusDHCPOptionPrefix prefixes the DHCP options overridden by a switch port's option set.
+	ovnExtIDIncusDHCPOptionPrefix = "incus_dhcp_option."
 )
 
 // OVNIPv6RAOpts IPv6 router advertisements options that can be applied to a router.
@@ -114,6 +117,10 @@ type OVNDHCPv4Opts struct {
 	Netmask            string
 	DNSSearchList      []string
 	StaticRoutes       string
+	NextServer         net.IP
+	BootFileName       string
+	BootFileNameAlt    string
+	Options            map[string]string // Additional options indexed by OVN option name (see DHCPv4Option).
 }
 
 // OVNDHCPv6Opts IPv6 DHCP option set that can be created (and then applied to a switch port by resulting ID).
@@ -1433,6 +1440,35 @@ func (o *NB) UpdateLogicalSwitchDHCPv4Options(ctx context.Context, switchName OV
 		delete(dhcpOption.Options, "classless_static_route")
 	}
 
+	if opts.NextServer != nil {
+		dhcpOption.Options["next_server"] = opts.NextServer.String()
+	} else {
+		delete(dhcpOption.Options, "next_server")
+	}
+
+	if opts.BootFileName != "" {
+		dhcpOption.Options["bootfile_name"] = fmt.Sprintf(`"%s"`, opts.BootFileName)
+	} else {
+		delete(dhcpOption.Options, "bootfile_name")
+	}
+
+	// OVN hands the alternative boot file name to iPXE clients.
+	if opts.BootFileNameAlt != "" {
+		dhcpOption.Options["bootfile_name_alt"] = fmt.Sprintf(`"%s"`, opts.BootFileNameAlt)
+	} else {
+		delete(dhcpOption.Options, "bootfile_name_alt")
+	}
+
+	// Apply the additional options, clearing any that aren't set anymore.
+	for _, option := range dhcpv4Options {
+		value, ok := opts.Options[option.name]
+		if ok {
+			dhcpOption.Options[option.name] = value
+		} else {
+			delete(dhcpOption.Options, option.name)
+		}
+	}
+
 	// Prepare the changes.
 	operations := []ovsdb.Operation{}
 	if dhcpOption.UUID == "" {
@@ -1560,7 +1596,8 @@ func (o *NB) GetLogicalSwitchDHCPOptions(ctx context.Context, switchName OVNSwit
 	// Get the matching DHCP options.
 	dhcpOptions := []ovnNB.DHCPOptions{}
 	err := o.client.WhereCache(func(do *ovnNB.DHCPOptions) bool {
-		return do.ExternalIDs != nil && do.ExternalIDs[ovnExtIDIncusSwitch] == string(switchName)
+		// Skip the option sets of individual switch ports.
+		return do.ExternalIDs != nil && do.ExternalIDs[ovnExtIDIncusSwitch] == string(switchName) && do.ExternalIDs[ovnExtIDIncusSwitchPort] == ""
 	}).List(ctx, &dhcpOptions)
 	if err != nil {
 		return nil, err
@@ -1619,6 +1656,190 @@ func (o *NB) DeleteLogicalSwitchDHCPOption(ctx context.Context, switchName OVNSw
 	return nil
 }
 
+// logicalSwitchPortDHCPOptions returns the DHCP option sets of the switch ports of a switch.
+// If portName is empty, the option sets of all the switch ports are returned.
+func (o *NB) logicalSwitchPortDHCPOptions(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort) ([]ovnNB.DHCPOptions, error) {
+	dhcpOptions := []ovnNB.DHCPOptions{}
+	err := o.client.WhereCache(func(do *ovnNB.DHCPOptions) bool {
+		if do.ExternalIDs == nil || do.ExternalIDs[ovnExtIDIncusSwitch] != string(switchName) || do.ExternalIDs[ovnExtIDIncusSwitchPort] == "" {
+			return false
+		}
+
+		return portName == "" || do.ExternalIDs[ovnExtIDIncusSwitchPort] == string(portName)
+	}).List(ctx, &dhcpOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return dhcpOptions, nil
+}
+
+// logicalSwitchPortDHCPv4OptionsApply sets a switch port's DHCPv4 option set to a copy of the switch's option set
+// with the port's overrides applied. Overrides with an empty value remove the option.
+func logicalSwitchPortDHCPv4OptionsApply(dhcpOption *ovnNB.DHCPOptions, switchOption *ovnNB.DHCPOptions, overrides map[string]string) {
+	dhcpOption.Cidr = switchOption.Cidr
+	dhcpOption.Options = make(map[string]string, len(switchOption.Options)+len(overrides))
+	for k, v := range switchOption.Options {
+		dhcpOption.Options[k] = v
+	}
+
+	for k, v := range overrides {
+		dhcpOption.ExternalIDs[ovnExtIDIncusDHCPOptionPrefix+k] = v
+
+		if v == "" {
+			delete(dhcpOption.Options, k)
+		} else {
+			dhcpOption.Options[k] = v
+		}
+	}
+}
+
+// UpdateLogicalSwitchPortDHCPv4Options creates or updates the DHCPv4 option set of a switch port. The option set is
+// a copy of the switch's option set identified by switchUUID with the overrides (indexed by OVN option name) applied.
+// Returns the UUID of the switch port's option set.
+func (o *NB) UpdateLogicalSwitchPortDHCPv4Options(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort, switchUUID OVNDHCPOptionsUUID, overrides map[string]string) (OVNDHCPOptionsUUID, error) {
+	// Load the switch's option set.
+	switchOption := ovnNB.DHCPOptions{UUID: string(switchUUID)}
+	err := o.get(ctx, &switchOption)
+	if err != nil {
+		return "", err
+	}
+
+	// Look for an existing option set for the port.
+	existing, err := o.logicalSwitchPortDHCPOptions(ctx, switchName, portName)
+	if err != nil {
+		return "", err
+	}
+
+	dhcpOption := ovnNB.DHCPOptions{}
+	if len(existing) > 0 {
+		dhcpOption = existing[0]
+	}
+
+	// Reset the external IDs so that removed overrides are forgotten.
+	dhcpOption.ExternalIDs = map[string]string{
+		ovnExtIDIncusSwitch:     string(switchName),
+		ovnExtIDIncusSwitchPort: string(portName),
+	}
+
+	logicalSwitchPortDHCPv4OptionsApply(&dhcpOption, &switchOption, overrides)
+
+	// Prepare the changes.
+	operations := []ovsdb.Operation{}
+	if dhcpOption.UUID == "" {
+		// Create a new record.
+		dhcpOption.UUID = "dhcp_options"
+
+		createOps, err := o.client.Create(&dhcpOption)
+		if err != nil {
+			return "", err
+		}
+
+		operations = append(operations, createOps...)
+	} else {
+		// Update the record.
+		updateOps, err := o.client.Where(&dhcpOption).Update(&dhcpOption)
+		if err != nil {
+			return "", err
+		}
+
+		operations = append(operations, updateOps...)
+	}
+
+	// Apply the database changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the UUID of the newly created record.
+	if dhcpOption.UUID == "dhcp_options" {
+		dhcpOption.UUID = resp[0].UUID.GoUUID
+	}
+
+	return OVNDHCPOptionsUUID(dhcpOption.UUID), nil
+}
+
+// RefreshLogicalSwitchPortDHCPv4Options updates the DHCPv4 option sets of all the switch ports of a switch after a
+// change to the switch's option set identified by switchUUID, keeping the overrides of each switch port.
+func (o *NB) RefreshLogicalSwitchPortDHCPv4Options(ctx context.Context, switchName OVNSwitch, switchUUID OVNDHCPOptionsUUID) error {
+	// Load the switch's option set.
+	switchOption := ovnNB.DHCPOptions{UUID: string(switchUUID)}
+	err := o.get(ctx, &switchOption)
+	if err != nil {
+		return err
+	}
+
+	existing, err := o.logicalSwitchPortDHCPOptions(ctx, switchName, "")
+	if err != nil {
+		return err
+	}
+
+	operations := []ovsdb.Operation{}
+	for _, dhcpOption := range existing {
+		// Only refresh the option sets of the same address family.
+		if dhcpOption.Cidr == "" || strings.Contains(dhcpOption.Cidr, ":") != strings.Contains(switchOption.Cidr, ":") {
+			continue
+		}
+
+		overrides := map[string]string{}
+		for k, v := range dhcpOption.ExternalIDs {
+			name, ok := strings.CutPrefix(k, ovnExtIDIncusDHCPOptionPrefix)
+			if ok {
+				overrides[name] = v
+			}
+		}
+
+		logicalSwitchPortDHCPv4OptionsApply(&dhcpOption, &switchOption, overrides)
+
+		updateOps, err := o.client.Where(&dhcpOption).Update(&dhcpOption)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, updateOps...)
+	}
+
+	// Check if there's anything to do.
+	if len(operations) == 0 {
+		return nil
+	}
+
+	// Apply the database changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLogicalSwitchPortDHCPOptions deletes the DHCP option sets of a switch port (if any).
+// If portName is empty, the option sets of all the switch ports are deleted.
+func (o *NB) DeleteLogicalSwitchPortDHCPOptions(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort) error {
+	existing, err := o.logicalSwitchPortDHCPOptions(ctx, switchName, portName)
+	if err != nil {
+		return err
+	}
+
+	uuids := make([]OVNDHCPOptionsUUID, 0, len(existing))
+	for _, dhcpOption := range existing {
+		uuids = append(uuids, OVNDHCPOptionsUUID(dhcpOption.UUID))
+	}
+
+	return o.DeleteLogicalSwitchDHCPOption(ctx, switchName, uuids...)
+}
+
 // UpdateLogicalSwitchACLRules applies a set of rules to the specified logical switch. Any existing rules are removed.
 func (o *NB) UpdateLogicalSwitchACLRules(ctx context.Context, switchName OVNSwitch, aclRules ...OVNACLRule) error {
 	operations := []ovsdb.Operation{}
@@ -2429,6 +2650,21 @@ func (o *NB) CleanupLogicalSwitchPort(ctx context.Context, portName OVNSwitchPor
 		operations = append(operations, deleteOps...)
 	}
 
+	// Remove the port's own DHCP option sets.
+	dhcpOptions, err := o.logicalSwitchPortDHCPOptions(ctx, switchName, portName)
+	if err != nil {
+		return err
+	}
+
+	for _, dhcpOption := range dhcpOptions {
+		deleteOps, err := o.client.Where(&dhcpOption).Delete()
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
 	// Apply the changes.
 	resp, err := o.client.Transact(ctx, operations...)
 	if err != nil {
@@ -3017,6 +3253,35 @@ func (o *NB) AddLogicalSwitchQoSRules(ctx context.Context, switchName OVNSwitch,
 	return nil
 }
 
+// DeleteLogicalSwitchQoSRules removes the QoS rules applied to the specified logical switch port.
+func (o *NB) DeleteLogicalSwitchQoSRules(ctx context.Context, switchName OVNSwitch, switchPortName OVNSwitchPort) error {
+	removeQoSRuleUUIDs, err := o.logicalSwitchPortQoSRules(ctx, switchPortName)
+	if err != nil {
+		return err
+	}
+
+	if len(removeQoSRuleUUIDs) == 0 {
+		return nil
+	}
+
+	operations, err := o.qosRuleDeleteOperations(ctx, "logical_switch", string(switchName), removeQoSRuleUUIDs)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (o *NB) qosRuleAddOperations(ctx context.Context, entityTable string, entityName string, externalIDs map[string]string, matchReplace map[string]string, qosRules ...OVNQoSRule) ([]ovsdb.Operation, error) {
 	operations := []ovsdb.Operation{}
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -531,6 +532,33 @@ func (o *VSwitch) GetOVNEncapIP(ctx context.Context) (net.IP, error) {
 	return encapIP, nil
 }
 
+// GetOVNConntrackZones returns the connection tracking zones assigned by OVN to the logical ports bound to this
+// chassis, indexed by logical port name.
+func (o *VSwitch) GetOVNConntrackZones(ctx context.Context, integrationBridgeName string) (map[string]uint16, error) {
+	bridge, err := o.GetBridge(ctx, integrationBridgeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The zones are recorded by ovn-controller as "ct-zone-<port>" external IDs of the integration bridge.
+	zones := map[string]uint16{}
+	for key, value := range bridge.ExternalIDs {
+		portName, found := strings.CutPrefix(key, "ct-zone-")
+		if !found {
+			continue
+		}
+
+		zone, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			continue
+		}
+
+		zones[portName] = uint16(zone)
+	}
+
+	return zones, nil
+}
+
 // GetOVNBridgeMappings gets the current OVN bridge mappings.
 func (o *VSwitch) GetOVNBridgeMappings(ctx context.Context, bridgeName string) ([]string, error) {
 	// Get the root switch.
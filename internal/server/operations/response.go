@@ -67,6 +67,16 @@ func (r *operationResponse) Code() int {
 	return http.StatusAccepted
 }
 
+// ResponseOperation returns the operation started by an operation response, or nil for other responses.
+func ResponseOperation(resp response.Response) *Operation {
+	opResp, ok := resp.(*operationResponse)
+	if !ok {
+		return nil
+	}
+
+	return opResp.op
+}
+
 // Forwarded operation response.
 //
 // Returned when the operation has been created on another node.
@@ -114,3 +124,16 @@ func (r *forwardedOperationResponse) String() string {
 func (r *forwardedOperationResponse) Code() int {
 	return http.StatusAccepted
 }
+
+// ResponseOperationID returns the ID of the operation behind an operation response, local or forwarded.
+// It returns an empty string for other responses.
+func ResponseOperationID(resp response.Response) string {
+	switch r := resp.(type) {
+	case *operationResponse:
+		return r.op.ID()
+	case *forwardedOperationResponse:
+		return r.op.ID
+	}
+
+	return ""
+}
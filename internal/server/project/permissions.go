@@ -72,6 +72,21 @@ func AllowImageDownload(tx *db.ClusterTx, projectName string, uri string) error
 	return nil
 }
 
+// ImageVerifyRequired returns whether the project requires downloaded images to be signed.
+func ImageVerifyRequired(ctx context.Context, tx *db.ClusterTx, projectName string) (bool, error) {
+	dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting project: %w", err)
+	}
+
+	config, err := cluster.GetProjectConfig(ctx, tx.Tx(), dbProject.ID)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting project configuration: %w", err)
+	}
+
+	return util.IsTrue(config["images.verify.required"]), nil
+}
+
 // AllowInstanceCreation returns an error if any project-specific limit or
 // restriction is violated when creating a new instance.
 func AllowInstanceCreation(tx *db.ClusterTx, projectName string, req api.InstancesPost) error {
@@ -352,7 +367,22 @@ func GetImageSpaceBudget(tx *db.ClusterTx, projectName string) (int64, error) {
 		return -1, nil
 	}
 
-	return getSpaceBudget(info)
+	budget, err := getSpaceBudget(info)
+	if err != nil {
+		return -1, err
+	}
+
+	// Apply the images specific limit.
+	limit, usage, err := getStorageLimit(context.Background(), tx, &info.Project, "limits.images.disk")
+	if err != nil {
+		return -1, err
+	}
+
+	if limit >= 0 && (budget < 0 || limit-usage < budget) {
+		budget = max(limit-usage, 0)
+	}
+
+	return budget, nil
 }
 
 // GetSpaceBudget returns how much disk space is left in the given project.
@@ -1209,6 +1239,12 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 			fallthrough
 		case "limits.disk":
 			aggregateKeys = append(aggregateKeys, key)
+
+		case "limits.snapshots", "limits.backups.disk", "limits.images.disk":
+			err := validateStorageLimit(tx, projectName, key, config[key])
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
 		}
 	}
 
@@ -1764,9 +1800,46 @@ func AllowBackupCreation(tx *db.ClusterTx, projectName string) error {
 		return fmt.Errorf("Project %q doesn't allow for backup creation", projectName)
 	}
 
+	limit, usage, err := getStorageLimit(ctx, tx, project, "limits.backups.disk")
+	if err != nil {
+		return err
+	}
+
+	if limit >= 0 && usage >= limit {
+		return fmt.Errorf("Project %q reached its backups disk limit of %s", projectName, units.GetByteSizeStringIEC(limit, 1))
+	}
+
 	return nil
 }
 
+// GetBackupSpaceBudget returns how much disk space is left in the given project
+// for writing backups.
+//
+// If no limit is in place, return -1.
+func GetBackupSpaceBudget(tx *db.ClusterTx, projectName string) (int64, error) {
+	ctx := context.Background()
+	dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	project, err := dbProject.ToAPI(ctx, tx.Tx())
+	if err != nil {
+		return -1, err
+	}
+
+	limit, usage, err := getStorageLimit(ctx, tx, project, "limits.backups.disk")
+	if err != nil {
+		return -1, err
+	}
+
+	if limit < 0 {
+		return -1, nil
+	}
+
+	return max(limit-usage, 0), nil
+}
+
 // AllowSnapshotCreation returns an error if any project-specific restriction is violated
 // when creating a new snapshot in a project.
 func AllowSnapshotCreation(p *api.Project) error {
@@ -1777,6 +1850,91 @@ func AllowSnapshotCreation(p *api.Project) error {
 	return nil
 }
 
+// CheckSnapshotsLimit returns an error if creating the given number of new snapshots in a project would
+// exceed its snapshots limit.
+func CheckSnapshotsLimit(tx *db.ClusterTx, p *api.Project, count int) error {
+	limit, usage, err := getStorageLimit(context.Background(), tx, p, "limits.snapshots")
+	if err != nil {
+		return err
+	}
+
+	if limit >= 0 && usage+int64(count) > limit {
+		if count == 1 {
+			return fmt.Errorf("Project %q reached its limit of %d snapshots", p.Name, limit)
+		}
+
+		return fmt.Errorf("Adding %d snapshots would exceed the limit of %d snapshots of project %q", count, limit, p.Name)
+	}
+
+	return nil
+}
+
+// storageLimitUsages returns the current usage of the snapshots, backups and images limits of a project.
+var storageLimitUsages = map[string]func(tx *db.ClusterTx, ctx context.Context, projectName string) (int64, error){
+	"limits.snapshots": func(tx *db.ClusterTx, ctx context.Context, projectName string) (int64, error) {
+		count, err := tx.GetProjectSnapshotsCount(ctx, projectName)
+
+		return int64(count), err
+	},
+	"limits.backups.disk": (*db.ClusterTx).GetProjectBackupsSize,
+	"limits.images.disk":  (*db.ClusterTx).GetProjectImagesSize,
+}
+
+// parseStorageLimit parses the value of a snapshots, backups or images limit.
+func parseStorageLimit(key string, value string) (int64, error) {
+	if key == "limits.snapshots" {
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	return units.ParseByteSizeString(value)
+}
+
+// getStorageLimit returns the value of a snapshots, backups or images limit of a project
+// (-1 if not set) along with its current usage.
+func getStorageLimit(ctx context.Context, tx *db.ClusterTx, p *api.Project, key string) (int64, int64, error) {
+	value := p.Config[key]
+	if value == "" {
+		return -1, -1, nil
+	}
+
+	limit, err := parseStorageLimit(key, value)
+	if err != nil {
+		return -1, -1, fmt.Errorf("Invalid value %q for limit %q: %w", value, key, err)
+	}
+
+	usage, err := storageLimitUsages[key](tx, ctx, p.Name)
+	if err != nil {
+		return -1, -1, fmt.Errorf("Failed getting usage of %q for project %q: %w", key, p.Name, err)
+	}
+
+	return limit, usage, nil
+}
+
+// validateStorageLimit checks that a new snapshots, backups or images limit isn't below the current usage.
+func validateStorageLimit(tx *db.ClusterTx, projectName string, key string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	p := &api.Project{Name: projectName, ProjectPut: api.ProjectPut{Config: map[string]string{key: value}}}
+
+	limit, usage, err := getStorageLimit(context.Background(), tx, p, key)
+	if err != nil {
+		return err
+	}
+
+	if limit < usage {
+		current := fmt.Sprintf("%d", usage)
+		if key != "limits.snapshots" {
+			current = units.GetByteSizeStringIEC(usage, 1)
+		}
+
+		return fmt.Errorf("%q is too low: current total is %q", key, current)
+	}
+
+	return nil
+}
+
 // GetRestrictedClusterGroups returns a slice of restricted cluster groups for the given project.
 func GetRestrictedClusterGroups(p *api.Project) []string {
 	return util.SplitNTrimSpace(p.Config["restricted.cluster.groups"], ",", -1, true)
@@ -110,5 +110,25 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 		Usage: int64(len(networks[projectName])),
 	}
 
+	// Get the snapshots, backups and images limits and usage.
+	for resource, key := range map[string]string{"snapshots": "limits.snapshots", "backups": "limits.backups.disk", "images": "limits.images.disk"} {
+		limit, usage, err := getStorageLimit(ctx, tx, &info.Project, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if limit < 0 {
+			usage, err = storageLimitUsages[key](tx, ctx, projectName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result[resource] = api.ProjectStateResource{
+			Limit: limit,
+			Usage: usage,
+		}
+	}
+
 	return result, nil
 }
@@ -28,6 +28,12 @@ const (
 
 	// CtxForwardedProtocol is the forwarded protocol field in request context.
 	CtxForwardedProtocol CtxKey = "forwarded_protocol"
+
+	// CtxAuthToken is the API token field in request context.
+	CtxAuthToken CtxKey = "auth_token"
+
+	// CtxPermissionCache is the permission cache field in request context.
+	CtxPermissionCache CtxKey = "permission_cache"
 )
 
 // Headers.
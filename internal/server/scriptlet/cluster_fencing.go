@@ -0,0 +1,139 @@
+package scriptlet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v7/internal/server/scriptlet/log"
+	apiScriptlet "github.com/lxc/incus/v7/shared/api/scriptlet"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/scriptlet"
+)
+
+// clusterFencingHTTPMaxBody is the maximum size of a response body returned to the cluster fencing scriptlet.
+const clusterFencingHTTPMaxBody = 1024 * 1024
+
+// clusterFencingHTTPRequest returns the http_request builtin, letting the fencing scriptlet talk to a BMC,
+// PDU or any other HTTP API able to power off the member.
+func clusterFencingHTTPRequest(ctx context.Context, client *http.Client) func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var url string
+		var body string
+		var headers *starlark.Dict
+		method := http.MethodGet
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &url, "method?", &method, "body?", &body, "headers?", &headers)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		if headers != nil {
+			for _, item := range headers.Items() {
+				key, ok := starlark.AsString(item[0])
+				if !ok {
+					return nil, fmt.Errorf("%s: header names must be strings", b.Name())
+				}
+
+				value, ok := starlark.AsString(item[1])
+				if !ok {
+					return nil, fmt.Errorf("%s: header %q must be a string", b.Name(), key)
+				}
+
+				req.Header.Set(key, value)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, clusterFencingHTTPMaxBody))
+		if err != nil {
+			return nil, err
+		}
+
+		rv := starlark.NewDict(2)
+		_ = rv.SetKey(starlark.String("status_code"), starlark.MakeInt(resp.StatusCode))
+		_ = rv.SetKey(starlark.String("body"), starlark.String(respBody))
+
+		return rv, nil
+	}
+}
+
+// ClusterFencingRun runs the cluster fencing scriptlet and returns whether the member was fenced.
+func ClusterFencingRun(ctx context.Context, l logger.Logger, member *apiScriptlet.ClusterFencingMember) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logFunc := log.CreateLogger(l, "Cluster fencing scriptlet")
+
+	// Remember to match the entries in scriptletLoad.ClusterFencingCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":     starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":     starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":    starlark.NewBuiltin("log_error", logFunc),
+		"http_request": starlark.NewBuiltin("http_request", clusterFencingHTTPRequest(ctx, http.DefaultClient)),
+	}
+
+	prog, thread, err := scriptletLoad.ClusterFencingProgram()
+	if err != nil {
+		return false, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		thread.Cancel("Request finished")
+	}()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return false, fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	fenceMember := globals["fence_member"]
+	if fenceMember == nil {
+		return false, errors.New("Scriptlet missing fence_member function")
+	}
+
+	memberv, err := scriptlet.StarlarkMarshal(member)
+	if err != nil {
+		return false, fmt.Errorf("Marshalling member failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, fenceMember, nil, []starlark.Tuple{
+		{
+			starlark.String("member"),
+			memberv,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	fenced, ok := v.(starlark.Bool)
+	if !ok {
+		return false, fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	return bool(fenced), nil
+}
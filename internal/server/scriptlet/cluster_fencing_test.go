@@ -0,0 +1,82 @@
+package scriptlet
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	apiScriptlet "github.com/lxc/incus/v7/shared/api/scriptlet"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+func TestClusterFencingRun(t *testing.T) {
+	defer func() { _ = scriptletLoad.ClusterFencingSet("") }()
+
+	var powerOff string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		powerOff = r.URL.Path + " " + string(body)
+
+		_, _ = w.Write([]byte("off"))
+	}))
+	defer server.Close()
+
+	member := &apiScriptlet.ClusterFencingMember{Name: "server01", Address: "10.0.0.1:8443"}
+
+	tests := []struct {
+		name   string
+		src    string
+		fenced bool
+		err    bool
+	}{
+		{
+			name:   "fenced",
+			src:    "def fence_member(member):\n    return member.name == \"server01\"\n",
+			fenced: true,
+		},
+		{
+			name: "not fenced",
+			src:  "def fence_member(member):\n    return False\n",
+		},
+		{
+			name: "unexpected return value",
+			src:  "def fence_member(member):\n    return 1\n",
+			err:  true,
+		},
+		{
+			name:   "http request",
+			src:    "def fence_member(member):\n    resp = http_request(\"" + server.URL + "/\" + member.name, method=\"post\", body=\"off\", headers={\"Authorization\": \"Bearer secret\"})\n    return resp[\"status_code\"] == 200 and resp[\"body\"] == \"off\"\n",
+			fenced: true,
+		},
+		{
+			name: "http request refused",
+			src:  "def fence_member(member):\n    resp = http_request(\"" + server.URL + "/\" + member.name)\n    return resp[\"status_code\"] == 200\n",
+		},
+	}
+
+	for _, test := range tests {
+		require.NoError(t, scriptletLoad.ClusterFencingSet(test.src), test.name)
+
+		fenced, err := ClusterFencingRun(context.Background(), logger.Log, member)
+		if test.err {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.fenced, fenced, test.name)
+	}
+
+	assert.Equal(t, "/server01 off", powerOff)
+}
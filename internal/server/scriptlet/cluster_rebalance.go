@@ -0,0 +1,207 @@
+package scriptlet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v7/internal/server/scriptlet/log"
+	"github.com/lxc/incus/v7/shared/api"
+	apiScriptlet "github.com/lxc/incus/v7/shared/api/scriptlet"
+	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/scriptlet"
+)
+
+// clusterRebalanceTimeout bounds each run of the cluster re-balancing scriptlet.
+const clusterRebalanceTimeout = 5 * time.Second
+
+// clusterRebalanceMaxSteps bounds the number of Starlark computation steps of each run of the cluster
+// re-balancing scriptlet.
+const clusterRebalanceMaxSteps = 10_000_000
+
+// clusterRebalanceFunction loads the cluster re-balancing scriptlet and returns the requested function.
+// The returned function is nil if the scriptlet doesn't define it.
+func clusterRebalanceFunction(ctx context.Context, l logger.Logger, name string, moveInstanceFunc func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error)) (*starlark.Thread, starlark.Value, error) {
+	logFunc := log.CreateLogger(l, "Cluster re-balancing scriptlet")
+
+	if moveInstanceFunc == nil {
+		moveInstanceFunc = func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			return nil, fmt.Errorf("%s can only be used from select_instances", b.Name())
+		}
+	}
+
+	// Remember to match the entries in scriptletLoad.ClusterRebalanceCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":      starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":      starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":     starlark.NewBuiltin("log_error", logFunc),
+		"move_instance": starlark.NewBuiltin("move_instance", moveInstanceFunc),
+	}
+
+	prog, thread, err := scriptletLoad.ClusterRebalanceProgram()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thread.SetMaxExecutionSteps(clusterRebalanceMaxSteps)
+
+	go func() {
+		<-ctx.Done()
+		thread.Cancel("Request finished")
+	}()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	return thread, globals[name], nil
+}
+
+// ClusterRebalanceScore runs the score_member function of the cluster re-balancing scriptlet.
+// It returns false if the scriptlet doesn't provide a score for cluster members.
+// The scriptlet must be loaded, and is stopped if it runs for too long.
+func ClusterRebalanceScore(ctx context.Context, l logger.Logger, member *apiScriptlet.ClusterRebalanceMember) (uint8, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, clusterRebalanceTimeout)
+	defer cancel()
+
+	thread, scoreMember, err := clusterRebalanceFunction(ctx, l, "score_member", nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if scoreMember == nil {
+		return 0, false, nil
+	}
+
+	memberv, err := scriptlet.StarlarkMarshal(member)
+	if err != nil {
+		return 0, false, fmt.Errorf("Marshalling member failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, scoreMember, nil, []starlark.Tuple{
+		{
+			starlark.String("member"),
+			memberv,
+		},
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	score, err := starlark.AsInt32(v)
+	if err != nil {
+		return 0, false, fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	return uint8(min(max(score, 0), 100)), true, nil
+}
+
+// ClusterRebalanceSelect runs the select_instances function of the cluster re-balancing scriptlet.
+// It returns false if the scriptlet doesn't select the instances to move itself.
+// The scriptlet must be loaded, and is stopped if it runs for too long.
+func ClusterRebalanceSelect(ctx context.Context, l logger.Logger, source *apiScriptlet.ClusterRebalanceMember, instances []apiScriptlet.ClusterRebalanceInstance, candidates []apiScriptlet.ClusterRebalanceMember) ([]api.ClusterRebalanceMigration, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, clusterRebalanceTimeout)
+	defer cancel()
+
+	migrations := []api.ClusterRebalanceMigration{}
+
+	moveInstanceFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var projectName string
+		var instanceName string
+		var memberName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "project", &projectName, "name", &instanceName, "member_name", &memberName)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, inst := range instances {
+			if inst.Project == projectName && inst.Name == instanceName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Invalid instance: %s/%s", projectName, instanceName)
+		}
+
+		found = false
+		for _, candidate := range candidates {
+			if candidate.Name == memberName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			l.Error("Cluster re-balancing scriptlet set invalid member target", logger.Ctx{"member": memberName})
+			return nil, fmt.Errorf("Invalid member name: %s", memberName)
+		}
+
+		migrations = append(migrations, api.ClusterRebalanceMigration{
+			Project:  projectName,
+			Instance: instanceName,
+			Source:   source.Name,
+			Target:   memberName,
+		})
+
+		return starlark.None, nil
+	}
+
+	thread, selectInstances, err := clusterRebalanceFunction(ctx, l, "select_instances", moveInstanceFunc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if selectInstances == nil {
+		return nil, false, nil
+	}
+
+	sourcev, err := scriptlet.StarlarkMarshal(source)
+	if err != nil {
+		return nil, false, fmt.Errorf("Marshalling source failed: %w", err)
+	}
+
+	instancesv, err := scriptlet.StarlarkMarshal(instances)
+	if err != nil {
+		return nil, false, fmt.Errorf("Marshalling instances failed: %w", err)
+	}
+
+	candidatesv, err := scriptlet.StarlarkMarshal(candidates)
+	if err != nil {
+		return nil, false, fmt.Errorf("Marshalling candidates failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, selectInstances, nil, []starlark.Tuple{
+		{
+			starlark.String("source"),
+			sourcev,
+		}, {
+			starlark.String("instances"),
+			instancesv,
+		}, {
+			starlark.String("candidates"),
+			candidatesv,
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	if v.Type() != "NoneType" {
+		return nil, false, fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	return migrations, true, nil
+}
@@ -0,0 +1,110 @@
+package scriptlet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	scriptletLoad "github.com/lxc/incus/v7/internal/server/scriptlet/load"
+	apiScriptlet "github.com/lxc/incus/v7/shared/api/scriptlet"
+	"github.com/lxc/incus/v7/shared/logger"
+)
+
+func TestClusterRebalanceScore(t *testing.T) {
+	defer func() { _ = scriptletLoad.ClusterRebalanceSet("") }()
+
+	member := &apiScriptlet.ClusterRebalanceMember{Name: "server01", Score: 40}
+
+	tests := []struct {
+		name   string
+		src    string
+		score  uint8
+		scored bool
+		err    bool
+	}{
+		{
+			name:   "score",
+			src:    "def score_member(member):\n    return member.score + 10\n",
+			score:  50,
+			scored: true,
+		},
+		{
+			name:   "clamped",
+			src:    "def score_member(member):\n    return 1000\n",
+			score:  100,
+			scored: true,
+		},
+		{
+			name: "no score function",
+			src:  "def select_instances(source, instances, candidates):\n    pass\n",
+		},
+		{
+			name: "unexpected return value",
+			src:  "def score_member(member):\n    return \"high\"\n",
+			err:  true,
+		},
+		{
+			name: "too many steps",
+			src:  "def score_member(member):\n    total = 0\n    for i in range(100000000):\n        total += i\n    return total\n",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		require.NoError(t, scriptletLoad.ClusterRebalanceSet(test.src), test.name)
+
+		score, scored, err := ClusterRebalanceScore(context.Background(), logger.Log, member)
+		if test.err {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.scored, scored, test.name)
+		assert.Equal(t, test.score, score, test.name)
+	}
+
+	// Failing to load the scriptlet is reported.
+	require.NoError(t, scriptletLoad.ClusterRebalanceSet(""))
+
+	_, _, err := ClusterRebalanceScore(context.Background(), logger.Log, member)
+	assert.Error(t, err)
+}
+
+func TestClusterRebalanceSelect(t *testing.T) {
+	defer func() { _ = scriptletLoad.ClusterRebalanceSet("") }()
+
+	source := &apiScriptlet.ClusterRebalanceMember{Name: "server01"}
+	instances := []apiScriptlet.ClusterRebalanceInstance{{Name: "c1", Project: "default"}, {Name: "c2", Project: "default"}}
+	candidates := []apiScriptlet.ClusterRebalanceMember{{Name: "server02"}}
+
+	src := `
+def select_instances(source, instances, candidates):
+    for inst in instances:
+        if inst.name == "c2":
+            move_instance(project=inst.project, name=inst.name, member_name=candidates[0].name)
+`
+
+	require.NoError(t, scriptletLoad.ClusterRebalanceSet(src))
+
+	migrations, selected, err := ClusterRebalanceSelect(context.Background(), logger.Log, source, instances, candidates)
+	require.NoError(t, err)
+	assert.True(t, selected)
+	require.Len(t, migrations, 1)
+	assert.Equal(t, "c2", migrations[0].Instance)
+	assert.Equal(t, "server01", migrations[0].Source)
+	assert.Equal(t, "server02", migrations[0].Target)
+
+	// Moving to a member which isn't a candidate fails.
+	src = `
+def select_instances(source, instances, candidates):
+    move_instance(project="default", name="c1", member_name="server03")
+`
+
+	require.NoError(t, scriptletLoad.ClusterRebalanceSet(src))
+
+	_, _, err = ClusterRebalanceSelect(context.Background(), logger.Log, source, instances, candidates)
+	assert.Error(t, err)
+}
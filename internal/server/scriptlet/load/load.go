@@ -15,6 +15,12 @@ const prefixQEMU = "qemu"
 // nameAuthorization is the name used in Starlark for the Authorization scriptlet.
 const nameAuthorization = "authorization"
 
+// nameClusterRebalance is the name used in Starlark for the cluster re-balancing scriptlet.
+const nameClusterRebalance = "cluster_rebalance"
+
+// nameClusterFencing is the name used in Starlark for the cluster fencing scriptlet.
+const nameClusterFencing = "cluster_fencing"
+
 var loader = scriptlet.NewLoader()
 
 // InstancePlacementCompile compiles the instance placement scriptlet.
@@ -129,3 +135,60 @@ func AuthorizationSet(src string) error {
 func AuthorizationProgram() (*starlark.Program, *starlark.Thread, error) {
 	return loader.Program("Authorization", nameAuthorization)
 }
+
+// ClusterRebalanceCompile compiles the cluster re-balancing scriptlet.
+func ClusterRebalanceCompile(name string, src string) (*starlark.Program, error) {
+	return scriptlet.Compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"move_instance",
+	})
+}
+
+// ClusterRebalanceValidate validates the cluster re-balancing scriptlet.
+func ClusterRebalanceValidate(src string) error {
+	return scriptlet.Validate(ClusterRebalanceCompile, nameClusterRebalance, src, scriptlet.Declaration{
+		scriptlet.Optional("score_member"):     {"member"},
+		scriptlet.Optional("select_instances"): {"source", "instances", "candidates"},
+	})
+}
+
+// ClusterRebalanceSet compiles the cluster re-balancing scriptlet into memory for use with ClusterRebalanceScore and ClusterRebalanceSelect.
+// If empty src is provided the current program is deleted.
+func ClusterRebalanceSet(src string) error {
+	return loader.Set(ClusterRebalanceCompile, nameClusterRebalance, src)
+}
+
+// ClusterRebalanceProgram returns the precompiled cluster re-balancing scriptlet program.
+func ClusterRebalanceProgram() (*starlark.Program, *starlark.Thread, error) {
+	return loader.Program("Cluster re-balancing", nameClusterRebalance)
+}
+
+// ClusterFencingCompile compiles the cluster fencing scriptlet.
+func ClusterFencingCompile(name string, src string) (*starlark.Program, error) {
+	return scriptlet.Compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"http_request",
+	})
+}
+
+// ClusterFencingValidate validates the cluster fencing scriptlet.
+func ClusterFencingValidate(src string) error {
+	return scriptlet.Validate(ClusterFencingCompile, nameClusterFencing, src, scriptlet.Declaration{
+		scriptlet.Required("fence_member"): {"member"},
+	})
+}
+
+// ClusterFencingSet compiles the cluster fencing scriptlet into memory for use with ClusterFencingRun.
+// If empty src is provided the current program is deleted.
+func ClusterFencingSet(src string) error {
+	return loader.Set(ClusterFencingCompile, nameClusterFencing, src)
+}
+
+// ClusterFencingProgram returns the precompiled cluster fencing scriptlet program.
+func ClusterFencingProgram() (*starlark.Program, *starlark.Thread, error) {
+	return loader.Program("Cluster fencing", nameClusterFencing)
+}
@@ -1069,6 +1069,14 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 		return fmt.Errorf("Failed generating instance copy config: %w", err)
 	}
 
+	// Make sure the source root disk no longer depends on its image.
+	if !src.IsSnapshot() {
+		err = srcPoolBackend.lazyImageFlatten(src, srcConfig.Volume.Config, op)
+		if err != nil {
+			return err
+		}
+	}
+
 	// If we are copying snapshots, retrieve a list of snapshots from source volume.
 	var snapshotNames []string
 	if snapshots {
@@ -1803,6 +1811,27 @@ func (b *backend) imageFiller(fingerprint string, op *operations.Operation) func
 	}
 }
 
+// lazyImageFiller returns a function that can be used as a filler function with CreateVolume().
+// The function returned will unpack the image metadata into the specified mount path and turn the
+// root block path into a qcow2 overlay on top of the image's root disk.
+func (b *backend) lazyImageFiller(inst instance.Instance, fingerprint string, op *operations.Operation) func(vol drivers.Volume, rootBlockPath string, allowUnsafeResize bool, targetIsZero bool, targetFormat string) (int64, error) {
+	return func(vol drivers.Volume, rootBlockPath string, allowUnsafeResize bool, targetIsZero bool, targetFormat string) (int64, error) {
+		var tracker *ioprogress.ProgressTracker
+		if op != nil {
+			metadata := make(map[string]any)
+			tracker = &ioprogress.ProgressTracker{
+				Handler: func(percent, speed int64) {
+					operations.SetProgressMetadata(metadata, "create_instance_from_image_unpack", "Unpacking image", percent, 0, speed)
+					_ = op.UpdateMetadata(metadata)
+				},
+			}
+		}
+
+		imageFile := internalUtil.VarPath("images", fingerprint)
+		return ImageLazyUnpack(imageFile, vol, rootBlockPath, LazyImageBackingPath(inst.ID()), b.state.OS, allowUnsafeResize, tracker)
+	}
+}
+
 // isoFiller returns a function that can be used as a filler function with CreateVolume().
 // The function returned will copy the ISO content into the specified mount path
 // provided.
@@ -1853,6 +1882,14 @@ func (b *backend) CreateInstanceFromImage(inst instance.Instance, fingerprint st
 		return err
 	}
 
+	// Determine whether the root disk should be lazily loaded from the image.
+	useLazyImage := !useOptimizedImage && b.shouldUseLazyImage(fingerprint, contentType, volumeConfig)
+	if useLazyImage {
+		volumeConfig["block.lazy_load"] = "true"
+	} else if volumeConfig["block.lazy_load"] != "" {
+		volumeConfig["block.lazy_load"] = "false"
+	}
+
 	// Validate config and create database entry for new storage volume.
 	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, volumeConfig, inst.CreationDate(), time.Time{}, contentType, true, false)
 	if err != nil {
@@ -1889,6 +1926,10 @@ func (b *backend) CreateInstanceFromImage(inst instance.Instance, fingerprint st
 			Fill:        b.imageFiller(fingerprint, op),
 		}
 
+		if useLazyImage {
+			volFiller.Fill = b.lazyImageFiller(inst, fingerprint, op)
+		}
+
 		err = b.driver.CreateVolume(vol, &volFiller, op)
 		if err != nil {
 			return err
@@ -2464,6 +2505,14 @@ func (b *backend) DeleteInstance(inst instance.Instance, op *operations.Operatio
 		}
 	}
 
+	// Release the image root disk of lazily loaded volumes.
+	if contentType == drivers.ContentTypeBlock {
+		err = LazyImageRelease(inst.ID())
+		if err != nil {
+			return err
+		}
+	}
+
 	// Remove symlinks.
 	err = b.removeInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name())
 	if err != nil {
@@ -2638,6 +2687,12 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 		return err
 	}
 
+	// Make sure the root disk no longer depends on its image.
+	err = b.lazyImageFlatten(inst, dbVol.Config, op)
+	if err != nil {
+		return err
+	}
+
 	// Generate the effective root device volume for instance.
 	volStorageName := project.Instance(inst.Project().Name, inst.Name())
 	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
@@ -2799,6 +2854,12 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		return err
 	}
 
+	// Make sure the root disk no longer depends on its image.
+	err = b.lazyImageFlatten(inst, dbVol.Config, op)
+	if err != nil {
+		return err
+	}
+
 	// Generate the effective root device volume for instance.
 	volStorageName := project.Instance(inst.Project().Name, inst.Name())
 	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
@@ -2974,7 +3035,7 @@ func (b *backend) SetInstanceQuota(inst instance.Instance, size string, vmStateS
 		}
 	}
 
-	if drivers.IsQcow2Block(vol) {
+	if drivers.IsQcow2Block(vol) || drivers.IsLazyImageBlock(vol) {
 		err = b.qcow2Resize(inst, vol, size, op)
 		if err != nil {
 			return err
@@ -3075,6 +3136,16 @@ func (b *backend) MountInstance(inst instance.Instance, op *operations.Operation
 		BackingPath: backingPaths,
 	}
 
+	// Pass the image root disk of lazily loaded volumes which haven't been fully loaded yet.
+	if drivers.IsLazyImageBlock(vol) && diskPath != "" {
+		imgInfo, err := drivers.Qcow2Info(diskPath)
+		if err != nil {
+			return nil, err
+		}
+
+		mountInfo.BackingImage = imgInfo.BackingFilename
+	}
+
 	reverter.Success() // From here on it is up to caller to call UnmountInstance() when done.
 
 	// Handle delegation.
@@ -3211,6 +3282,12 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 		return err
 	}
 
+	// Snapshots can't be taken while the root disk depends on its image.
+	err = b.lazyImageFlatten(src, srcDBVol.Config, op)
+	if err != nil {
+		return err
+	}
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -4093,6 +4170,32 @@ func (b *backend) EnsureImage(fingerprint string, op *operations.Operation) erro
 	return nil
 }
 
+// shouldUseLazyImage determines if a VM root disk should be created as a qcow2 overlay on top of the cached image.
+// This is only done when requested through the volume or pool config, on pools without optimized images and for
+// split images whose root disk can be used directly.
+func (b *backend) shouldUseLazyImage(fingerprint string, contentType drivers.ContentType, volConfig map[string]string) bool {
+	if contentType != drivers.ContentTypeBlock || b.driver.Info().OptimizedImages {
+		return false
+	}
+
+	lazyLoad := volConfig["block.lazy_load"]
+	if lazyLoad == "" {
+		lazyLoad = b.db.Config["volume.block.lazy_load"]
+	}
+
+	if util.IsFalseOrEmpty(lazyLoad) {
+		return false
+	}
+
+	// Volumes which are already qcow2 formatted handle their own backing chain.
+	if volConfig["block.type"] == drivers.BlockVolumeTypeQcow2 || (volConfig["block.type"] == "" && b.db.Config["volume.block.type"] == drivers.BlockVolumeTypeQcow2) {
+		return false
+	}
+
+	// Unified images need to be unpacked before their root disk can be used.
+	return util.PathExists(internalUtil.VarPath("images", fingerprint+".rootfs"))
+}
+
 // shouldUseOptimizedImage determines if an optimized image should be used based on the provided volume config.
 // It returns true if the volume config aligns with the pool's default configuration, and an optimized image does
 // not exist or also matches the pool's default configuration.
@@ -8588,7 +8691,7 @@ func (b *backend) qcow2DeleteSnapshot(vol drivers.Volume, snapVol drivers.Volume
 // qcow2Resize resizes a QCOW2 volume.
 func (b *backend) qcow2Resize(inst instance.Instance, vol drivers.Volume, size string, op *operations.Operation) error {
 	// Return if this is not a qcow2 image.
-	if !drivers.IsQcow2Block(vol) {
+	if !drivers.IsQcow2Block(vol) && !drivers.IsLazyImageBlock(vol) {
 		return nil
 	}
 
@@ -8633,6 +8736,68 @@ func (b *backend) qcow2Resize(inst instance.Instance, vol drivers.Volume, size s
 	return nil
 }
 
+// lazyImageFlatten makes sure that a lazily loaded instance root disk no longer depends on its image.
+// The root disk of a stopped instance is flattened in place, a running instance has to finish loading it first.
+func (b *backend) lazyImageFlatten(inst instance.Instance, volConfig map[string]string, op *operations.Operation) error {
+	if !lazyImageLoaded(inst.Type(), volConfig) {
+		return nil
+	}
+
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(drivers.VolumeTypeVM, drivers.ContentTypeBlock, volStorageName, volConfig)
+
+	err := b.driver.MountVolume(vol, op)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = b.driver.UnmountVolume(vol, false, op) }()
+
+	diskPath, err := b.driver.GetVolumeDiskPath(vol)
+	if err != nil {
+		return err
+	}
+
+	imgInfo, err := drivers.Qcow2Info(diskPath)
+	if err != nil {
+		return err
+	}
+
+	flatten, err := lazyImageFlattenNeeded(imgInfo.BackingFilename, inst.IsRunning())
+	if err != nil {
+		return err
+	}
+
+	if flatten {
+		b.logger.Debug("Flattening lazily loaded root disk", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "backing": imgInfo.BackingFilename})
+		err = drivers.Qcow2Flatten(diskPath)
+		if err != nil {
+			return fmt.Errorf("Failed flattening root disk: %w", err)
+		}
+	}
+
+	return LazyImageRelease(inst.ID())
+}
+
+// lazyImageLoaded returns whether the root disk of an instance was created as lazily loaded from its image.
+func lazyImageLoaded(instType instancetype.Type, volConfig map[string]string) bool {
+	return instType == instancetype.VM && util.IsTrue(volConfig["block.lazy_load"])
+}
+
+// lazyImageFlattenNeeded returns whether a lazily loaded root disk must be flattened before being copied.
+// Root disks still backed by their image can't be flattened while the instance is running.
+func lazyImageFlattenNeeded(backingFilename string, running bool) (bool, error) {
+	if backingFilename == "" {
+		return false, nil
+	}
+
+	if running {
+		return false, errors.New("Root disk is still being loaded from its image")
+	}
+
+	return true, nil
+}
+
 // qcow2BackingPaths returns information about the backing chain of a qcow2 image.
 func (b *backend) qcow2BackingPaths(vol drivers.Volume, diskPath string, projectName string) ([]string, error) {
 	if vol.Config()["block.type"] != drivers.BlockVolumeTypeQcow2 {
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v7/internal/server/instance/instancetype"
+)
+
+func TestLazyImageLoaded(t *testing.T) {
+	tests := []struct {
+		name     string
+		instType instancetype.Type
+		config   map[string]string
+		loaded   bool
+	}{
+		{name: "lazily loaded VM", instType: instancetype.VM, config: map[string]string{"block.lazy_load": "true"}, loaded: true},
+		{name: "VM", instType: instancetype.VM, config: map[string]string{}},
+		{name: "disabled", instType: instancetype.VM, config: map[string]string{"block.lazy_load": "false"}},
+		{name: "container", instType: instancetype.Container, config: map[string]string{"block.lazy_load": "true"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.loaded, lazyImageLoaded(test.instType, test.config), test.name)
+	}
+}
+
+func TestLazyImageFlattenNeeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		backing string
+		running bool
+		flatten bool
+		err     bool
+	}{
+		{name: "loaded", backing: ""},
+		{name: "loaded and running", backing: "", running: true},
+		{name: "stopped", backing: "/var/lib/incus/images/lazy/1.qcow2", flatten: true},
+		{name: "still loading", backing: "/var/lib/incus/images/lazy/1.qcow2", running: true, err: true},
+	}
+
+	for _, test := range tests {
+		flatten, err := lazyImageFlattenNeeded(test.backing, test.running)
+		if test.err {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.flatten, flatten, test.name)
+	}
+}
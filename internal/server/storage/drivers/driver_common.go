@@ -133,6 +133,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// block.lazy_load is only applied by the backend when creating VM volumes from images.
+		if volKey == "block.lazy_load" {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -222,6 +227,11 @@ func (d *common) updateVolume(vol Volume, changedConfig map[string]string) error
 		return errors.New("dependent cannot be changed")
 	}
 
+	_, changed = changedConfig["block.lazy_load"]
+	if changed {
+		return errors.New("block.lazy_load cannot be changed")
+	}
+
 	return nil
 }
 
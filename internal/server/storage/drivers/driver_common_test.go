@@ -0,0 +1,64 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLazyLoadTestDriver() *dir {
+	d := &dir{}
+	d.config = map[string]string{"volume.block.lazy_load": "true", "volume.snapshots.expiry": "1d"}
+	d.commonRules = &Validators{
+		VolumeRules: func(vol Volume) map[string]func(string) error {
+			return map[string]func(string) error{}
+		},
+	}
+
+	return d
+}
+
+func TestLazyLoadValidateVolume(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "unset", value: "", valid: true},
+		{name: "enabled", value: "true", valid: true},
+		{name: "disabled", value: "false", valid: true},
+		{name: "invalid", value: "sometimes"},
+	}
+
+	d := newLazyLoadTestDriver()
+
+	for _, test := range tests {
+		vol := Volume{name: "vm", driver: d, volType: VolumeTypeVM, contentType: ContentTypeBlock, config: map[string]string{"block.lazy_load": test.value}}
+
+		err := d.ValidateVolume(vol, false)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestLazyLoadUpdateVolume(t *testing.T) {
+	d := newLazyLoadTestDriver()
+
+	// Root disks can't be switched between lazily loaded and fully copied.
+	assert.Error(t, d.updateVolume(Volume{}, map[string]string{"block.lazy_load": "false"}))
+	assert.NoError(t, d.updateVolume(Volume{}, map[string]string{"snapshots.expiry": "2d"}))
+}
+
+func TestLazyLoadFillVolumeConfig(t *testing.T) {
+	d := newLazyLoadTestDriver()
+	vol := Volume{name: "vm", driver: d, volType: VolumeTypeVM, contentType: ContentTypeBlock, config: map[string]string{}}
+
+	// The pool default is applied by the backend when creating the volume, not stored on every volume.
+	err := d.fillVolumeConfig(&vol)
+	assert.NoError(t, err)
+	assert.Empty(t, vol.config["block.lazy_load"])
+	assert.Equal(t, "1d", vol.config["snapshots.expiry"])
+}
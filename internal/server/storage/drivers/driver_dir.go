@@ -130,7 +130,7 @@ func (d *dir) Validate(config map[string]string) error {
 	//  default: -
 	//  shortdesc: Path to an existing directory
 
-	return d.validatePool(config, nil, nil)
+	return d.validatePool(config, nil, d.commonVolumeRules())
 }
 
 // Update applies any driver changes required from a configuration change.
@@ -20,6 +20,7 @@ import (
 	"github.com/lxc/incus/v7/shared/revert"
 	"github.com/lxc/incus/v7/shared/units"
 	"github.com/lxc/incus/v7/shared/util"
+	"github.com/lxc/incus/v7/shared/validate"
 )
 
 // CreateVolume creates an empty volume and can optionally fill it by executing the supplied
@@ -71,7 +72,8 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 	// If we are creating a block volume, resize it to the requested size or the default.
 	// For block volumes, we expect the filler function to have converted the qcow2 image to raw into the rootBlockPath.
 	// For ISOs the content will just be copied.
-	if IsContentBlock(vol.contentType) {
+	// Lazily loaded volumes are qcow2 overlays already created with the right size by the filler.
+	if IsContentBlock(vol.contentType) && !(IsLazyImageBlock(vol) && filler != nil && filler.Fill != nil) {
 		// Convert to bytes.
 		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
 		if err != nil {
@@ -236,6 +238,21 @@ func (d *dir) FillVolumeConfig(vol Volume) error {
 	return nil
 }
 
+// commonVolumeRules returns validation rules which are common for pool and volume.
+func (d *dir) commonVolumeRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// gendoc:generate(entity=storage_volume_dir, group=common, key=block.lazy_load)
+		// When enabled, virtual machines created from split images start with a `qcow2` overlay on top of the cached image
+		// and copy the image data into their root disk in the background while running.
+		// ---
+		//  type: bool
+		//  condition: virtual-machine volume
+		//  default: same as `volume.block.lazy_load` or `false`
+		//  shortdesc: Whether to lazily load the root disk from the image
+		"block.lazy_load": validate.Optional(validate.IsBool),
+	}
+}
+
 // ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
 func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	// gendoc:generate(entity=storage_volume_dir, group=common, key=initial.gid)
@@ -326,7 +343,7 @@ func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	//  default: same as `volume.snapshot.schedule`
 	//  shortdesc: {{snapshot_schedule_format}}
 
-	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	err := d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
 	if err != nil {
 		return err
 	}
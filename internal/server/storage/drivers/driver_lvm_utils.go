@@ -393,15 +393,15 @@ func (d *lvm) roundedSizeBytesString(size string) (int64, error) {
 }
 
 // volumeBackingSizeBytes returns the size in bytes that the backing logical volume needs to be
-// to store the volume's content for the given size. For qcow2 block volumes the qcow2 metadata
-// overhead is added so a fully-allocated image always fits within the logical volume.
+// to store the volume's content for the given size. For qcow2 block volumes (including lazily loaded ones)
+// the qcow2 metadata overhead is added so a fully-allocated image always fits within the logical volume.
 func (d *lvm) volumeBackingSizeBytes(vol Volume, size string) (int64, error) {
 	sizeBytes, err := d.roundedSizeBytesString(size)
 	if err != nil {
 		return 0, err
 	}
 
-	if sizeBytes > 0 && IsQcow2Block(vol) {
+	if sizeBytes > 0 && (IsQcow2Block(vol) || IsLazyImageBlock(vol)) {
 		sizeBytes, err = Qcow2MeasureFullyAllocated(sizeBytes)
 		if err != nil {
 			return 0, err
@@ -131,7 +131,7 @@ func (d *lvm) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			}
 
 			// Move the GPT alt header to end of disk if needed.
-			if vol.IsVMBlock() {
+			if vol.IsVMBlock() && !IsLazyImageBlock(vol) {
 				err = d.moveGPTAltHeader(devPath)
 				if err != nil {
 					return err
@@ -366,6 +366,16 @@ func (d *lvm) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 	rules := map[string]func(value string) error{
+		// gendoc:generate(entity=storage_volume_lvm, group=common, key=block.lazy_load)
+		// When enabled, virtual machines created from split images on pools without thin provisioning start with a `qcow2` overlay
+		// on top of the cached image and copy the image data into their root disk in the background while running.
+		// ---
+		//  type: bool
+		//  condition: virtual-machine volume
+		//  default: same as `volume.block.lazy_load` or `false`
+		//  shortdesc: Whether to lazily load the root disk from the image
+		"block.lazy_load": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=storage_volume_lvm, group=common, key=block.mount_options)
 		//
 		// ---
@@ -16,6 +16,7 @@ import (
 	internalUtil "github.com/lxc/incus/v7/internal/util"
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/subprocess"
+	"github.com/lxc/incus/v7/shared/util"
 )
 
 // Type of the block volume.
@@ -134,6 +135,16 @@ func Qcow2Commit(path string) error {
 	return nil
 }
 
+// Qcow2Flatten copies all data from the backing chain into a qcow2 image and removes its backing file.
+func Qcow2Flatten(path string) error {
+	_, err := subprocess.RunCommand("qemu-img", "rebase", "-f", "qcow2", "-b", "", path)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Qcow2Info returns information about a qcow2 image.
 func Qcow2Info(path string) (*ImageInfo, error) {
 	imgJSON, err := subprocess.RunCommand("qemu-img", "info", "-U", "--output=json", path)
@@ -389,6 +400,11 @@ func IsQcow2Block(vol Volume) bool {
 	return vol.Config()["block.type"] == BlockVolumeTypeQcow2 && vol.ContentType() == ContentTypeBlock
 }
 
+// IsLazyImageBlock checks whether a volume is a VM block volume created as a qcow2 overlay on top of its image.
+func IsLazyImageBlock(vol Volume) bool {
+	return vol.IsVMBlock() && util.IsTrue(vol.Config()["block.lazy_load"])
+}
+
 // getFreeNbd returns the first free NBD device.
 func getFreeNbd() (string, error) {
 	nbdIndex := 0
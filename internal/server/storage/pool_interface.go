@@ -26,9 +26,10 @@ type VolumeUsage struct {
 
 // MountInfo represents info about the result of a mount operation.
 type MountInfo struct {
-	DiskPath    string                               // The location of the block disk (if supported).
-	BackingPath []string                             // The location of the block disk (backing disk for qcow2).
-	PostHooks   []func(inst instance.Instance) error // Hooks to be called following a mount.
+	DiskPath     string                               // The location of the block disk (if supported).
+	BackingPath  []string                             // The location of the block disk (backing disk for qcow2).
+	BackingImage string                               // The image root disk still backing a lazily loaded block disk.
+	PostHooks    []func(inst instance.Instance) error // Hooks to be called following a mount.
 }
 
 // Type represents an Incus storage pool type.
@@ -38,6 +38,7 @@ import (
 	"github.com/lxc/incus/v7/shared/archive"
 	"github.com/lxc/incus/v7/shared/ioprogress"
 	"github.com/lxc/incus/v7/shared/logger"
+	"github.com/lxc/incus/v7/shared/units"
 	"github.com/lxc/incus/v7/shared/util"
 	"github.com/lxc/incus/v7/shared/validate"
 )
@@ -845,6 +846,139 @@ func ImageUnpack(imageFile string, vol drivers.Volume, destBlockFile string, sys
 	return imgSize, nil
 }
 
+// LazyImageBackingPath returns the path of the image root disk used as the backing file of a lazily loaded
+// instance root disk.
+func LazyImageBackingPath(instID int) string {
+	return internalUtil.VarPath("images", "lazy", fmt.Sprintf("%d.qcow2", instID))
+}
+
+// LazyImageRelease removes the image root disk reference held by a lazily loaded instance root disk.
+// It must only be called once the root disk no longer depends on it.
+func LazyImageRelease(instID int) error {
+	err := os.Remove(LazyImageBackingPath(instID))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed removing image reference: %w", err)
+	}
+
+	return nil
+}
+
+// ImageLazyUnpack prepares a VM volume from a split image without converting the image's root disk.
+// The metadata tarball is unpacked into the volume's mount path, the qcow2 root disk is hardlinked to
+// backingPath and destBlockFile is turned into a qcow2 overlay using it as its backing file.
+// The image data is then copied into the overlay by QEMU while the instance runs.
+func ImageLazyUnpack(imageFile string, vol drivers.Volume, destBlockFile string, backingPath string, sysOS *sys.OS, allowUnsafeResize bool, tracker *ioprogress.ProgressTracker) (int64, error) {
+	l := logger.Log.AddContext(logger.Ctx{"imageFile": imageFile, "volName": vol.Name()})
+	l.Info("Lazy image unpack started")
+	defer l.Info("Lazy image unpack stopped")
+
+	imageRootfsFile := imageFile + ".rootfs"
+	if !util.PathExists(imageRootfsFile) {
+		return -1, fmt.Errorf("Image root disk not found: %s", imageRootfsFile)
+	}
+
+	// Check available memory.
+	maxMemory, err := linux.DeviceTotalMemory()
+	if err == nil {
+		// Cap the memory to 10%.
+		maxMemory = maxMemory / 10
+	} else {
+		maxMemory = 0
+	}
+
+	// Unpack the metadata tarball.
+	err = archive.Unpack(imageFile, vol.MountPath(), vol.IsBlockBacked(), maxMemory, tracker)
+	if err != nil {
+		return -1, err
+	}
+
+	// Get info about the qcow2 file, using the same restrictions as when converting it.
+	cmd := []string{"prlimit", "--cpu=2", "--as=1073741824", "qemu-img", "info", "-f", "qcow2", "--output=json", imageRootfsFile}
+	imgJSON, err := apparmor.QemuImg(sysOS, cmd, imageRootfsFile, destBlockFile, nil)
+	if err != nil {
+		return -1, fmt.Errorf("Failed reading image info %q: %w", imageRootfsFile, err)
+	}
+
+	imgInfo := struct {
+		Format          string `json:"format"`
+		VirtualSize     int64  `json:"virtual-size"`
+		BackingFilename string `json:"backing-filename"`
+	}{}
+
+	err = json.Unmarshal([]byte(imgJSON), &imgInfo)
+	if err != nil {
+		return -1, fmt.Errorf("Failed unmarshalling image info %q: %w (%q)", imageRootfsFile, err, imgJSON)
+	}
+
+	if imgInfo.Format != drivers.BlockVolumeTypeQcow2 {
+		return -1, fmt.Errorf("Unexpected image format %q", imgInfo.Format)
+	}
+
+	// Never let an image point the overlay at an arbitrary file on the host.
+	if imgInfo.BackingFilename != "" {
+		return -1, errors.New("Image root disk cannot have a backing file")
+	}
+
+	// Check whether the image fits in the volume and grow it if needed.
+	imgVolConfig := map[string]string{
+		"volatile.rootfs.size": fmt.Sprintf("%d", imgInfo.VirtualSize),
+	}
+
+	imgVol := drivers.NewVolume(nil, "", drivers.VolumeTypeImage, drivers.ContentTypeBlock, "", imgVolConfig, nil)
+
+	newVolSize, err := vol.ConfigSizeFromSource(imgVol)
+	if err != nil {
+		return -1, err
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(newVolSize)
+	if err != nil {
+		return -1, err
+	}
+
+	sizeBytes = max(sizeBytes, imgInfo.VirtualSize)
+
+	if util.PathExists(destBlockFile) {
+		volSizeBytes, err := drivers.BlockDiskSizeBytes(destBlockFile)
+		if err != nil {
+			return -1, fmt.Errorf("Error getting current size of %q: %w", destBlockFile, err)
+		}
+
+		if volSizeBytes < imgInfo.VirtualSize {
+			l.Debug("Increasing volume size", logger.Ctx{"dstPath": destBlockFile, "oldSize": volSizeBytes, "newSize": newVolSize})
+			err = vol.SetQuota(newVolSize, allowUnsafeResize, nil)
+			if err != nil {
+				return -1, fmt.Errorf("Error increasing volume size: %w", err)
+			}
+		}
+	}
+
+	// Reference the image root disk so it outlives the image itself.
+	err = os.MkdirAll(filepath.Dir(backingPath), 0o700)
+	if err != nil {
+		return -1, err
+	}
+
+	err = os.Remove(backingPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return -1, err
+	}
+
+	err = os.Link(imageRootfsFile, backingPath)
+	if err != nil {
+		return -1, fmt.Errorf("Failed referencing image root disk: %w", err)
+	}
+
+	l.Debug("Creating qcow2 overlay", logger.Ctx{"backingPath": backingPath, "dstPath": destBlockFile, "size": sizeBytes})
+	err = drivers.Qcow2Create(destBlockFile, backingPath, sizeBytes)
+	if err != nil {
+		_ = os.Remove(backingPath)
+		return -1, fmt.Errorf("Failed creating qcow2 overlay at %q: %w", destBlockFile, err)
+	}
+
+	return imgInfo.VirtualSize, nil
+}
+
 // InstanceContentType returns the instance's content type.
 func InstanceContentType(inst instance.ConfigReader) drivers.ContentType {
 	contentType := drivers.ContentTypeFS
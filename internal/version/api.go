@@ -550,6 +550,29 @@ var APIExtensions = []string{
 	"oci_network_config",
 	"infiniband_sriov_guid",
 	"instance_selinux",
+	"network_dhcp_boot",
+	"network_limits_aggregate",
+	"network_flow_metrics",
+	"network_forward_http",
+	"network_ipv6_prefix_delegation",
+	"auth_builtin",
+	"audit_log",
+	"auth_tokens",
+	"cluster_rebalance_scriptlet",
+	"instance_placement_rules",
+	"cluster_healing_fencing",
+	"cluster_rolling_upgrade",
+	"project_usage_history",
+	"projects_limits_storage",
+	"projects_templates",
+	"oci_registry_mirrors",
+	"image_export_oci",
+	"image_verify",
+	"image_delta_builtin",
+	"cluster_image_transfer",
+	"vm_lazy_image_load",
+	"instance_watchdog_device",
+	"instance_serial_device",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -1,9 +1,231 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// AuthenticationMethodTLS is the default authentication method for interacting with Incus remotely.
 	AuthenticationMethodTLS = "tls"
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodToken is an API token based authentication method.
+	AuthenticationMethodToken = "token"
 )
+
+// Permission represents an entitlement on an authorization object.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type Permission struct {
+	// The entitlement being granted
+	// Example: can_exec
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+
+	// The authorization object the entitlement applies to
+	// Example: instance:default/c1
+	Object string `json:"object" yaml:"object"`
+}
+
+// AuthGroupsPost represents the fields of a new authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupsPost struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: developers
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPut represents the modifiable fields of an authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupPut struct {
+	// Description of the group
+	// Example: Developers of the web application
+	Description string `json:"description" yaml:"description"`
+
+	// Permissions granted to the members of the group
+	Permissions []Permission `json:"permissions" yaml:"permissions"`
+}
+
+// AuthGroup represents an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroup struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: developers
+	Name string `json:"name" yaml:"name"`
+
+	// List of URLs of the identities in the group
+	// Read only: true
+	// Example: ["/1.0/auth/identities/oidc/jane@example.com"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a full AuthGroup struct into an AuthGroupPut struct (filters read-only fields).
+func (g *AuthGroup) Writable() AuthGroupPut {
+	return g.AuthGroupPut
+}
+
+// AuthGroupPost represents the fields required to rename an authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupPost struct {
+	// The new name for the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// IdentitiesPost represents the fields of a new identity
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type IdentitiesPost struct {
+	IdentityPut `yaml:",inline"`
+
+	// The authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// The identifier of the identity (certificate fingerprint or OIDC user name)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// IdentityPut represents the modifiable fields of an identity
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type IdentityPut struct {
+	// Description of the identity
+	// Example: Jane Doe
+	Description string `json:"description" yaml:"description"`
+
+	// Groups the identity is a member of
+	// Example: ["developers"]
+	Groups []string `json:"groups" yaml:"groups"`
+}
+
+// Identity represents an identity.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type Identity struct {
+	IdentityPut `yaml:",inline"`
+
+	// The authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// The identifier of the identity (certificate fingerprint or OIDC user name)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// Writable converts a full Identity struct into an IdentityPut struct (filters read-only fields).
+func (i *Identity) Writable() IdentityPut {
+	return i.IdentityPut
+}
+
+// AuthTokensPost represents the fields of a new API token
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokensPost struct {
+	AuthTokenPut `yaml:",inline"`
+
+	// The name of the token
+	// Example: ci
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthTokenPut represents the modifiable fields of an API token
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenPut struct {
+	// Description of the token
+	// Example: Token used by the CI pipeline
+	Description string `json:"description" yaml:"description"`
+
+	// When the token expires (zero value means it never expires)
+	// Example: 2027-02-24T19:00:45.452649098-05:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// Whether the token is restricted to a set of projects
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of allowed projects (applies when restricted)
+	// Example: ["default", "ci"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// List of allowed entitlements (empty means no restriction)
+	// Example: ["can_view", "can_exec"]
+	Entitlements []string `json:"entitlements" yaml:"entitlements"`
+}
+
+// AuthToken represents an API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthToken struct {
+	AuthTokenPut `yaml:",inline"`
+
+	// The name of the token
+	// Example: ci
+	Name string `json:"name" yaml:"name"`
+
+	// When the token was created
+	// Example: 2026-02-24T19:00:45.452649098-05:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// The authentication method of the identity which created the token
+	// Example: tls
+	CreatorAuthenticationMethod string `json:"creator_authentication_method" yaml:"creator_authentication_method"`
+
+	// The identifier of the identity which created the token (certificate fingerprint or OIDC user name)
+	// Example: 7f1ddc7bad2d1d9d1ba5e8e1fbbb5bf5b2a4a0e7a0a8f4d1c3f6bbd8f3c6e8a1
+	CreatorIdentifier string `json:"creator_identifier" yaml:"creator_identifier"`
+}
+
+// Writable converts a full AuthToken struct into an AuthTokenPut struct (filters read-only fields).
+func (t *AuthToken) Writable() AuthTokenPut {
+	return t.AuthTokenPut
+}
+
+// AuthTokenSecret represents the secret of a newly created API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenSecret struct {
+	// The name of the token
+	// Example: ci
+	Name string `json:"name" yaml:"name"`
+
+	// The secret value to use as a bearer token (only returned on creation)
+	// Example: incus_0b1c9a3ba8ab8d5d5e1fb4f4b1b27c26e6f1a7e2bff5d8c1a8a3c1c0cd16f4a2
+	Token string `json:"token" yaml:"token"`
+}
@@ -322,3 +322,59 @@ type ClusterGroupPut struct {
 func (c *ClusterGroup) Writable() ClusterGroupPut {
 	return c.ClusterGroupPut
 }
+
+// ClusterRebalancePlan represents the instance migrations planned by cluster re-balancing.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_scriptlet.
+type ClusterRebalancePlan struct {
+	// Load score of each online cluster member (0 to 100)
+	// Example: {"server01": 72, "server02": 18}
+	Scores map[string]uint8 `json:"scores" yaml:"scores"`
+
+	// List of planned instance migrations
+	Migrations []ClusterRebalanceMigration `json:"migrations" yaml:"migrations"`
+}
+
+// ClusterRebalanceMigration represents an instance migration planned by cluster re-balancing.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_scriptlet.
+type ClusterRebalanceMigration struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: vm01
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Cluster member currently running the instance
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance will be moved to
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+}
+
+// ClusterUpgradePost represents the fields required to perform a rolling upgrade of the cluster.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_upgrade.
+type ClusterUpgradePost struct {
+	// Cluster members to upgrade, in order (all members if empty)
+	// Example: ["server01", "server02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Version the members are expected to run once upgraded (any new version if empty)
+	// Example: 7.1
+	Version string `json:"version" yaml:"version"`
+
+	// Time in seconds to wait for each member to come back after triggering its upgrade (defaults to 1800)
+	// Example: 3600
+	Timeout int64 `json:"timeout" yaml:"timeout"`
+}
@@ -12,13 +12,14 @@ const (
 	EventTypeLogging    = "logging"
 	EventTypeOperation  = "operation"
 	EventTypeNetworkACL = "network-acl"
+	EventTypeAudit      = "audit"
 )
 
 // Event represents an event entry (over websocket)
 //
 // swagger:model
 type Event struct {
-	// Event type (one of operation, logging or lifecycle)
+	// Event type (one of operation, logging, lifecycle, network-acl or audit)
 	// Example: lifecycle
 	Type string `yaml:"type" json:"type"`
 
@@ -26,7 +27,7 @@ type Event struct {
 	// Example: 2021-02-24T19:00:45.452649098-05:00
 	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
 
-	// JSON encoded metadata (see EventLogging, EventLifecycle or Operation)
+	// JSON encoded metadata (see EventLogging, EventLifecycle, EventAudit or Operation)
 	// Example: {"action": "instance-started", "source": "/1.0/instances/c1", "context": {}}
 	Metadata json.RawMessage `yaml:"metadata" json:"metadata"`
 
@@ -96,6 +97,33 @@ func (event *Event) ToLogging() (EventLogRecord, error) {
 
 		return record, nil
 
+	case EventTypeAudit:
+		e := &EventAudit{}
+		err := json.Unmarshal(event.Metadata, &e)
+		if err != nil {
+			return EventLogRecord{}, err
+		}
+
+		record := EventLogRecord{
+			Time: event.Timestamp,
+			Lvl:  "info",
+			Msg:  fmt.Sprintf("Method: %s, URL: %s, Status: %d", e.Method, e.URL, e.StatusCode),
+			Ctx: []any{
+				"Sequence", e.Sequence,
+				"Project", e.Project,
+				"RequestDigest", e.RequestDigest,
+				"Error", e.Error,
+				"Hash", e.Hash,
+				"PreviousHash", e.PreviousHash,
+			},
+		}
+
+		if e.Requestor != nil {
+			record.Ctx = append(record.Ctx, "Requestor", fmt.Sprintf("%s/%s (%s)", e.Requestor.Protocol, e.Requestor.Username, e.Requestor.Address))
+		}
+
+		return record, nil
+
 	case EventTypeOperation:
 		e := &Operation{}
 		err := json.Unmarshal(event.Metadata, &e)
@@ -157,6 +185,66 @@ type EventLifecycle struct {
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
 }
 
+// EventAudit represents an audit type event entry (admin only)
+//
+// API extension: audit_log.
+type EventAudit struct {
+	// Position of the record in the audit log
+	// Example: 42
+	Sequence uint64 `yaml:"sequence" json:"sequence"`
+
+	// Time at which the request was received
+	// Example: 2021-02-24T19:00:45.452649098-05:00
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
+
+	// Identity which made the request
+	Requestor *EventLifecycleRequestor `yaml:"requestor,omitempty" json:"requestor,omitempty"`
+
+	// Project targeted by the request
+	// Example: default
+	Project string `yaml:"project" json:"project"`
+
+	// HTTP method of the request
+	// Example: POST
+	Method string `yaml:"method" json:"method"`
+
+	// Request URL
+	// Example: /1.0/instances?project=default
+	URL string `yaml:"url" json:"url"`
+
+	// Size of the request body in bytes
+	// Example: 257
+	RequestSize int64 `yaml:"request_size" json:"request_size"`
+
+	// SHA-256 digest of the request body
+	// Example: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	RequestDigest string `yaml:"request_digest" json:"request_digest"`
+
+	// Whether the request body wasn't read entirely, the digest then only covers the first request_size bytes
+	// Example: false
+	RequestPartial bool `yaml:"request_partial,omitempty" json:"request_partial,omitempty"`
+
+	// HTTP status code of the response
+	// Example: 202
+	StatusCode int `yaml:"status_code" json:"status_code"`
+
+	// Error message if the request failed
+	// Example: Instance not found
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+
+	// ID of the background operation started by the request
+	// Example: 6916c8a6-9b7d-4abd-90b3-aedfec7ec7da
+	Operation string `yaml:"operation,omitempty" json:"operation,omitempty"`
+
+	// Hash of the previous record in the audit log
+	// Example: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	PreviousHash string `yaml:"previous_hash" json:"previous_hash"`
+
+	// Hash of this record, covering all other fields
+	// Example: fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9
+	Hash string `yaml:"hash" json:"hash"`
+}
+
 // EventLifecycleRequestor represents the initial requestor for an event
 //
 // API extension: event_lifecycle_requestor.
@@ -2,6 +2,13 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthGroupCreated                  = "auth-group-created"
+	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
+	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
+	EventLifecycleAuthTokenCreated                  = "auth-token-created"
+	EventLifecycleAuthTokenDeleted                  = "auth-token-deleted"
+	EventLifecycleAuthTokenUpdated                  = "auth-token-updated"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -14,6 +21,8 @@ const (
 	EventLifecycleClusterGroupUpdated               = "cluster-group-updated"
 	EventLifecycleClusterMemberAdded                = "cluster-member-added"
 	EventLifecycleClusterMemberEvacuated            = "cluster-member-evacuated"
+	EventLifecycleClusterMemberFenced               = "cluster-member-fenced"
+	EventLifecycleClusterMemberFencingFailed        = "cluster-member-fencing-failed"
 	EventLifecycleClusterMemberHealed               = "cluster-member-healed"
 	EventLifecycleClusterMemberRemoved              = "cluster-member-removed"
 	EventLifecycleClusterMemberRenamed              = "cluster-member-renamed"
@@ -21,6 +30,9 @@ const (
 	EventLifecycleClusterMemberUpdated              = "cluster-member-updated"
 	EventLifecycleClusterTokenCreated               = "cluster-token-created"
 	EventLifecycleConfigUpdated                     = "config-updated"
+	EventLifecycleIdentityCreated                   = "identity-created"
+	EventLifecycleIdentityDeleted                   = "identity-deleted"
+	EventLifecycleIdentityUpdated                   = "identity-updated"
 	EventLifecycleImageAliasCreated                 = "image-alias-created"
 	EventLifecycleImageAliasDeleted                 = "image-alias-deleted"
 	EventLifecycleImageAliasRenamed                 = "image-alias-renamed"
@@ -44,6 +56,8 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceHealed                    = "instance-healed"
+	EventLifecycleInstanceHealingSkipped            = "instance-healing-skipped"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -68,6 +82,7 @@ const (
 	EventLifecycleInstanceStarted                   = "instance-started"
 	EventLifecycleInstanceStopped                   = "instance-stopped"
 	EventLifecycleInstanceUpdated                   = "instance-updated"
+	EventLifecycleInstanceWatchdogTriggered         = "instance-watchdog-triggered"
 	EventLifecycleNetworkACLCreated                 = "network-acl-created"
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
@@ -108,6 +123,10 @@ const (
 	EventLifecycleProjectDeleted                    = "project-deleted"
 	EventLifecycleProjectRenamed                    = "project-renamed"
 	EventLifecycleProjectUpdated                    = "project-updated"
+	EventLifecycleProjectTemplateCreated            = "project-template-created"
+	EventLifecycleProjectTemplateDeleted            = "project-template-deleted"
+	EventLifecycleProjectTemplateRenamed            = "project-template-renamed"
+	EventLifecycleProjectTemplateUpdated            = "project-template-updated"
 	EventLifecycleStorageBucketBackupCreated        = "storage-bucket-backup-created"
 	EventLifecycleStorageBucketBackupDeleted        = "storage-bucket-backup-deleted"
 	EventLifecycleStorageBucketBackupRenamed        = "storage-bucket-backup-renamed"
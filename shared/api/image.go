@@ -36,6 +36,18 @@ type ImageExportPost struct {
 	//
 	// API extension: image_copy_profile
 	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Protocol of the target server (incus or oci)
+	// Example: oci
+	//
+	// API extension: image_export_oci
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Instance configuration keys (oci.* and environment.*) to apply to the OCI image configuration
+	// Example: {"oci.entrypoint": "/usr/bin/app", "environment.PORT": "8080"}
+	//
+	// API extension: image_export_oci
+	Config map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
 }
 
 // ImagesPost represents the fields available for a new image
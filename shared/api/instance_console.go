@@ -33,4 +33,10 @@ type InstanceConsolePost struct {
 	//
 	// API extension: console_force
 	Force bool `json:"force" yaml:"force"`
+
+	// Serial device to attach to instead of the main console (console type only)
+	// Example: serial0
+	//
+	// API extension: instance_serial_device
+	Device string `json:"device" yaml:"device"`
 }
@@ -15,7 +15,7 @@ type NetworkForwardPort struct {
 	// Example: My web server forward
 	Description string `json:"description" yaml:"description"`
 
-	// Protocol for port forward (either tcp or udp)
+	// Protocol for port forward (either tcp, udp, http or https)
 	// Example: tcp
 	Protocol string `json:"protocol" yaml:"protocol"`
 
@@ -36,6 +36,18 @@ type NetworkForwardPort struct {
 	//
 	// API extension: network_forward_snat
 	SNAT bool `json:"snat" yaml:"snat"`
+
+	// Host name to match against the HTTP Host header or TLS SNI (http and https protocols only)
+	// Example: www.example.net
+	//
+	// API extension: network_forward_http
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+
+	// Path prefix to match against the HTTP request path (http and https protocols only)
+	// Example: /api
+	//
+	// API extension: network_forward_http
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
 // Normalise normalises the fields in the rule so that they are comparable with ones stored.
@@ -43,6 +55,8 @@ func (p *NetworkForwardPort) Normalise() {
 	p.Description = strings.TrimSpace(p.Description)
 	p.Protocol = strings.TrimSpace(p.Protocol)
 	p.TargetAddress = strings.TrimSpace(p.TargetAddress)
+	p.Host = strings.ToLower(strings.TrimSpace(p.Host))
+	p.Path = strings.TrimSpace(p.Path)
 
 	ip := net.ParseIP(p.TargetAddress)
 	if ip != nil {
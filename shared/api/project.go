@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ProjectDefaultName is the name of the default project that can never be deleted.
 const ProjectDefaultName = "default"
 
@@ -14,6 +18,12 @@ type ProjectsPost struct {
 	// The name of the new project
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
+
+	// Project template to create the project from
+	// Example: tenant
+	//
+	// API extension: projects_templates
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 // ProjectPost represents the fields required to rename a project
@@ -99,3 +109,59 @@ type ProjectStateResource struct {
 	// Example: 4
 	Usage int64
 }
+
+// ProjectUsage represents the historical resource usage of a project
+//
+// swagger:model
+//
+// API extension: project_usage_history.
+type ProjectUsage struct {
+	// Aggregation period of the entries (hourly or daily)
+	// Example: daily
+	Resolution string `json:"resolution" yaml:"resolution"`
+
+	// Usage per period, oldest first
+	Entries []ProjectUsageEntry `json:"entries" yaml:"entries"`
+
+	// Usage over all the returned periods
+	Total ProjectUsageEntry `json:"total" yaml:"total"`
+}
+
+// ProjectUsageEntry represents the resource usage of a project over a period of time
+//
+// swagger:model
+//
+// API extension: project_usage_history.
+type ProjectUsageEntry struct {
+	// Start of the period
+	// Example: 2026-10-01T00:00:00Z
+	Start time.Time `json:"start" yaml:"start"`
+
+	// End of the period
+	// Example: 2026-10-02T00:00:00Z
+	End time.Time `json:"end" yaml:"end"`
+
+	// CPU time consumed by the instances (in seconds)
+	// Example: 7200.5
+	CPUSeconds float64 `json:"cpu_seconds" yaml:"cpu_seconds"`
+
+	// Memory used by the running instances (in byte-hours)
+	// Example: 51539607552
+	MemoryByteHours float64 `json:"memory_byte_hours" yaml:"memory_byte_hours"`
+
+	// Disk space used by the instances (in byte-hours)
+	// Example: 515396075520
+	DiskByteHours float64 `json:"disk_byte_hours" yaml:"disk_byte_hours"`
+
+	// Bytes received by the instances
+	// Example: 1073741824
+	NetworkBytesReceived int64 `json:"network_bytes_received" yaml:"network_bytes_received"`
+
+	// Bytes sent by the instances
+	// Example: 536870912
+	NetworkBytesSent int64 `json:"network_bytes_sent" yaml:"network_bytes_sent"`
+
+	// Time spent running by the instances (in instance-hours)
+	// Example: 48
+	InstanceHours float64 `json:"instance_hours" yaml:"instance_hours"`
+}
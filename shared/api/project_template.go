@@ -0,0 +1,72 @@
+package api
+
+// ProjectTemplatesPost represents the fields of a new project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplatesPost struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The name of the new project template
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePost represents the fields required to rename a project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplatePost struct {
+	// The new name for the project template
+	// Example: tenant-small
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePut represents the modifiable fields of a project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplatePut struct {
+	// Description of the project template
+	// Example: Restricted tenant project
+	Description string `json:"description" yaml:"description"`
+
+	// Whether identities allowed to create projects but not to administer the server can use the template
+	// Example: true
+	SelfService bool `json:"self_service" yaml:"self_service"`
+
+	// Configuration of the created projects (refer to doc/projects.md)
+	// Example: {"features.networks": "true", "restricted": "true", "limits.instances": "10"}
+	Config ConfigMap `json:"config" yaml:"config"`
+
+	// Profiles created in the projects (replacing the default profile if named "default")
+	Profiles []ProfilesPost `json:"profiles" yaml:"profiles"`
+
+	// Networks created in the projects
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+
+	// Network ACLs created in the projects
+	NetworkACLs []NetworkACLsPost `json:"network_acls" yaml:"network_acls"`
+}
+
+// ProjectTemplate represents a project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplate struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The project template name
+	// Read only: true
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full ProjectTemplate struct into a ProjectTemplatePut struct (filters read-only fields).
+func (template *ProjectTemplate) Writable() ProjectTemplatePut {
+	return template.ProjectTemplatePut
+}
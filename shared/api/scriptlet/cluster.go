@@ -0,0 +1,44 @@
+package scriptlet
+
+import (
+	"time"
+
+	"github.com/lxc/incus/v7/shared/api"
+)
+
+// ClusterRebalanceMember represents a cluster member considered during cluster re-balancing.
+//
+// API extension: cluster_rebalance_scriptlet.
+type ClusterRebalanceMember struct {
+	Name         string            `json:"name" yaml:"name"`
+	Address      string            `json:"address" yaml:"address"`
+	Architecture string            `json:"architecture" yaml:"architecture"`
+	Groups       []string          `json:"groups" yaml:"groups"`
+	Config       map[string]string `json:"config" yaml:"config"`
+	Resources    *api.Resources    `json:"resources" yaml:"resources"`
+	Score        uint8             `json:"score" yaml:"score"`
+}
+
+// ClusterRebalanceInstance represents an instance which may be moved during cluster re-balancing.
+//
+// API extension: cluster_rebalance_scriptlet.
+type ClusterRebalanceInstance struct {
+	Name      string            `json:"name" yaml:"name"`
+	Project   string            `json:"project" yaml:"project"`
+	Type      string            `json:"type" yaml:"type"`
+	Config    map[string]string `json:"config" yaml:"config"`
+	Resources InstanceResources `json:"resources" yaml:"resources"`
+}
+
+// ClusterFencingMember represents an unreachable cluster member about to be fenced.
+//
+// API extension: cluster_healing_fencing.
+type ClusterFencingMember struct {
+	Name          string            `json:"name" yaml:"name"`
+	Address       string            `json:"address" yaml:"address"`
+	Architecture  string            `json:"architecture" yaml:"architecture"`
+	Roles         []string          `json:"roles" yaml:"roles"`
+	Groups        []string          `json:"groups" yaml:"groups"`
+	Config        map[string]string `json:"config" yaml:"config"`
+	LastHeartbeat time.Time         `json:"last_heartbeat" yaml:"last_heartbeat"`
+}
@@ -248,6 +248,26 @@ func (c *Config) GetInstanceServer(name string) (incus.InstanceServer, error) {
 	return nil, errors.Join(errs...)
 }
 
+// GetInstanceServerAddress returns a InstanceServer struct for the remote, connecting to the given address
+// instead of the remote's own. This is used to reach another member of the same cluster.
+func (c *Config) GetInstanceServerAddress(name string, addr string) (incus.InstanceServer, error) {
+	remote, ok := c.Remotes[name]
+	if !ok {
+		return nil, fmt.Errorf("The remote \"%s\" doesn't exist", name)
+	}
+
+	if remote.Public || remote.Protocol != "incus" {
+		return nil, errors.New("The remote isn't a private server")
+	}
+
+	args, err := c.getConnectionArgs(name, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getInstanceServer(args, remote, addr)
+}
+
 func (c *Config) getImageServer(args *incus.ConnectionArgs, remote Remote, addr string) (incus.ImageServer, error) {
 	// Unix socket
 	remoteAddr, hasUnixPrefix := strings.CutPrefix(addr, "unix:")
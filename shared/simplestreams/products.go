@@ -63,6 +63,28 @@ type ProductVersionItem struct {
 	DeltaBase                 string `json:"delta_base,omitempty"`
 }
 
+// combinedFingerprint returns the fingerprint of the image made of this metadata item and a root item of the given type.
+func (i ProductVersionItem) combinedFingerprint(rootType string) string {
+	switch rootType {
+	case "root.tar.xz":
+		if i.CombinedSha256RootXz != "" {
+			return i.CombinedSha256RootXz
+		}
+
+		return i.CombinedSha256
+	case "squashfs":
+		return i.CombinedSha256SquashFs
+	case "disk-kvm.img":
+		return i.CombinedSha256DiskKvmImg
+	case "disk1.img":
+		return i.CombinedSha256DiskImg
+	case "uefi1.img":
+		return i.CombinedSha256DiskUefiImg
+	}
+
+	return ""
+}
+
 // ToAPI converts the products data into a list of API images and associated downloadable files.
 func (s *Products) ToAPI() ([]api.Image, map[string][][]string) {
 	downloads := map[string][][]string{}
@@ -109,26 +131,7 @@ func (s *Products) ToAPI() ([]api.Image, map[string][][]string) {
 				// Figure out the fingerprint
 				fingerprint := ""
 				if root != nil {
-					switch root.FileType {
-					case "root.tar.xz":
-						if meta.CombinedSha256RootXz != "" {
-							fingerprint = meta.CombinedSha256RootXz
-						} else {
-							fingerprint = meta.CombinedSha256
-						}
-
-					case "squashfs":
-						fingerprint = meta.CombinedSha256SquashFs
-
-					case "disk-kvm.img":
-						fingerprint = meta.CombinedSha256DiskKvmImg
-
-					case "disk1.img":
-						fingerprint = meta.CombinedSha256DiskImg
-
-					case "uefi1.img":
-						fingerprint = meta.CombinedSha256DiskUefiImg
-					}
+					fingerprint = meta.combinedFingerprint(root.FileType)
 				} else {
 					fingerprint = meta.HashSha256
 				}
@@ -249,7 +252,7 @@ func (s *Products) ToAPI() ([]api.Image, map[string][][]string) {
 							continue
 						}
 
-						srcFingerprint = item.CombinedSha256SquashFs
+						srcFingerprint = item.combinedFingerprint(root.FileType)
 						break
 					}
 
@@ -1,6 +1,8 @@
 package simplestreams
 
 import (
+	"crypto"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +19,7 @@ import (
 	"github.com/lxc/incus/v7/shared/api"
 	"github.com/lxc/incus/v7/shared/logger"
 	"github.com/lxc/incus/v7/shared/osarch"
+	localtls "github.com/lxc/incus/v7/shared/tls"
 	"github.com/lxc/incus/v7/shared/util"
 )
 
@@ -58,6 +61,8 @@ type SimpleStreams struct {
 
 	cachePath   string
 	cacheExpiry time.Duration
+
+	verifyKeys []crypto.PublicKey
 }
 
 // SetCache configures the on-disk cache.
@@ -66,6 +71,11 @@ func (s *SimpleStreams) SetCache(path string, expiry time.Duration) {
 	s.cacheExpiry = expiry
 }
 
+// SetVerifyKeys requires the index files to be signed by one of the keys.
+func (s *SimpleStreams) SetVerifyKeys(keys []crypto.PublicKey) {
+	s.verifyKeys = keys
+}
+
 func (s *SimpleStreams) readCache(path string) ([]byte, bool) {
 	cacheName := filepath.Join(s.cachePath, path)
 
@@ -178,13 +188,42 @@ func (s *SimpleStreams) cachedDownload(path string) ([]byte, error) {
 	return body, nil
 }
 
+// verifiedDownload retrieves an index file, checking its detached signature if required.
+func (s *SimpleStreams) verifiedDownload(path string) ([]byte, error) {
+	body, err := s.cachedDownload(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.verifyKeys) == 0 {
+		return body, nil
+	}
+
+	encoded, err := s.cachedDownload(path + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("Failed retrieving the signature of %q: %w", path, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding the signature of %q: %w", path, err)
+	}
+
+	err = localtls.VerifySignature(s.verifyKeys, body, signature)
+	if err != nil {
+		return nil, fmt.Errorf("Failed verifying the signature of %q: %w", path, err)
+	}
+
+	return body, nil
+}
+
 func (s *SimpleStreams) parseStream() (*Stream, error) {
 	if s.cachedStream != nil {
 		return s.cachedStream, nil
 	}
 
 	path := "streams/v1/index.json"
-	body, err := s.cachedDownload(path)
+	body, err := s.verifiedDownload(path)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +247,7 @@ func (s *SimpleStreams) parseProducts(path string) (*Products, error) {
 		return s.cachedProducts[path], nil
 	}
 
-	body, err := s.cachedDownload(path)
+	body, err := s.verifiedDownload(path)
 	if err != nil {
 		return nil, err
 	}
@@ -338,6 +377,16 @@ func (s *SimpleStreams) getImages() ([]api.Image, []extendedAlias, error) {
 	return images, aliases, nil
 }
 
+// GetStream returns the index of the stream.
+func (s *SimpleStreams) GetStream() (*Stream, error) {
+	return s.parseStream()
+}
+
+// GetProducts returns the products listed in one of the stream's product files.
+func (s *SimpleStreams) GetProducts(path string) (*Products, error) {
+	return s.parseProducts(path)
+}
+
 // GetFiles returns a map of files for the provided image fingerprint.
 func (s *SimpleStreams) GetFiles(fingerprint string) (map[string]DownloadableFile, error) {
 	// Load the main stream
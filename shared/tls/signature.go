@@ -0,0 +1,137 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// ErrSignatureMismatch is returned when a signature doesn't match any of the trusted keys.
+var ErrSignatureMismatch = errors.New("Signature doesn't match any of the trusted keys")
+
+// ParsePublicKeys parses a list of PEM encoded public keys or certificates.
+// Only ECDSA, Ed25519 and RSA keys are supported.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key crypto.PublicKey
+		switch block.Type {
+		case "PUBLIC KEY":
+			var err error
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing public key: %w", err)
+			}
+
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing certificate: %w", err)
+			}
+
+			key = cert.PublicKey
+		default:
+			return nil, fmt.Errorf("Unsupported PEM block %q", block.Type)
+		}
+
+		switch key.(type) {
+		case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("Unsupported public key type %T", key)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("No PEM encoded public key found")
+	}
+
+	return keys, nil
+}
+
+// ParsePrivateKey parses a PEM encoded ECDSA, Ed25519 or RSA private key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM encoded private key found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("Unsupported PEM block %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing private key: %w", err)
+	}
+
+	switch key.(type) {
+	case *ecdsa.PrivateKey, ed25519.PrivateKey, *rsa.PrivateKey:
+		signer, _ := key.(crypto.Signer)
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("Unsupported private key type %T", key)
+	}
+}
+
+// SignContent returns the signature of the content.
+// ECDSA and RSA keys sign the SHA-256 hash of the content, Ed25519 keys sign the content itself.
+func SignContent(key crypto.Signer, content []byte) ([]byte, error) {
+	_, ok := key.(ed25519.PrivateKey)
+	if ok {
+		return key.Sign(rand.Reader, content, crypto.Hash(0))
+	}
+
+	hash := sha256.Sum256(content)
+
+	return key.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+// VerifySignature checks that the signature of the content was made by one of the keys.
+func VerifySignature(keys []crypto.PublicKey, content []byte, signature []byte) error {
+	hash := sha256.Sum256(content)
+
+	for _, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hash[:], signature) {
+				return nil
+			}
+
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, content, signature) {
+				return nil
+			}
+
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil {
+				return nil
+			}
+		}
+	}
+
+	return ErrSignatureMismatch
+}
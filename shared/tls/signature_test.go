@@ -0,0 +1,95 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+// Signatures made with each supported key type are verified against the matching public key only.
+func TestSignatures(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte(`{"format": "index:1.0"}`)
+
+	for _, key := range []crypto.Signer{ecKey, edKey, rsaKey} {
+		// Round-trip the keys through PEM.
+		privDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+		if err != nil {
+			t.Fatalf("ParsePrivateKey(%T) failed: %v", key, err)
+		}
+
+		pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := ParsePublicKeys(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+		if err != nil {
+			t.Fatalf("ParsePublicKeys(%T) failed: %v", key, err)
+		}
+
+		signature, err := SignContent(signer, content)
+		if err != nil {
+			t.Fatalf("SignContent(%T) failed: %v", key, err)
+		}
+
+		err = VerifySignature(keys, content, signature)
+		if err != nil {
+			t.Errorf("VerifySignature(%T) failed: %v", key, err)
+		}
+
+		err = VerifySignature(keys, append(content, ' '), signature)
+		if !errors.Is(err, ErrSignatureMismatch) {
+			t.Errorf("VerifySignature(%T) accepted altered content", key)
+		}
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifySignature([]crypto.PublicKey{otherKey.Public()}, content, signature)
+		if !errors.Is(err, ErrSignatureMismatch) {
+			t.Errorf("VerifySignature(%T) accepted an unknown signer", key)
+		}
+	}
+}
+
+// Parsing fails when no usable public key is provided.
+func TestParsePublicKeysInvalid(t *testing.T) {
+	_, err := ParsePublicKeys([]byte("not a key"))
+	if err == nil {
+		t.Error("expected an error for non-PEM input")
+	}
+
+	_, err = ParsePublicKeys(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")}))
+	if err == nil {
+		t.Error("expected an error for a private key")
+	}
+}
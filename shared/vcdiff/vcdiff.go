@@ -0,0 +1,452 @@
+// Package vcdiff implements a decoder for the VCDIFF delta format (RFC 3284), as produced by xdelta3.
+package vcdiff
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"io"
+)
+
+// ErrUnsupported is returned for valid deltas using features the decoder doesn't implement,
+// like secondary compression or custom code tables.
+var ErrUnsupported = errors.New("Unsupported VCDIFF feature")
+
+// ErrInvalid is returned for corrupt or truncated deltas.
+var ErrInvalid = errors.New("Invalid VCDIFF data")
+
+// Header and window indicator bits.
+const (
+	hdrDecompress = 0x01
+	hdrCodeTable  = 0x02
+	hdrAppHeader  = 0x04
+
+	winSource  = 0x01
+	winTarget  = 0x02
+	winAdler32 = 0x04 // xdelta3 extension.
+)
+
+// maxWindowSize limits the memory used by a single window, xdelta3 uses at most 16MiB.
+const maxWindowSize = 64 * 1024 * 1024
+
+// Instruction types.
+const (
+	instNoop = iota
+	instAdd
+	instRun
+	instCopy
+)
+
+// Address cache sizes of the default code table.
+const (
+	nearCacheSize = 4
+	sameCacheSize = 3
+)
+
+type instruction struct {
+	kind byte
+	size byte
+	mode byte
+}
+
+var defaultCodeTable = buildDefaultCodeTable()
+
+// buildDefaultCodeTable returns the default instruction code table (RFC 3284 section 5.6).
+func buildDefaultCodeTable() [256][2]instruction {
+	var table [256][2]instruction
+
+	i := 0
+	table[i][0] = instruction{kind: instRun}
+	i++
+
+	for size := byte(0); size <= 17; size++ {
+		table[i][0] = instruction{kind: instAdd, size: size}
+		i++
+	}
+
+	for mode := byte(0); mode <= 8; mode++ {
+		table[i][0] = instruction{kind: instCopy, mode: mode}
+		i++
+
+		for size := byte(4); size <= 18; size++ {
+			table[i][0] = instruction{kind: instCopy, size: size, mode: mode}
+			i++
+		}
+	}
+
+	for mode := byte(0); mode <= 5; mode++ {
+		for addSize := byte(1); addSize <= 4; addSize++ {
+			for copySize := byte(4); copySize <= 6; copySize++ {
+				table[i] = [2]instruction{{kind: instAdd, size: addSize}, {kind: instCopy, size: copySize, mode: mode}}
+				i++
+			}
+		}
+	}
+
+	for mode := byte(6); mode <= 8; mode++ {
+		for addSize := byte(1); addSize <= 4; addSize++ {
+			table[i] = [2]instruction{{kind: instAdd, size: addSize}, {kind: instCopy, size: 4, mode: mode}}
+			i++
+		}
+	}
+
+	for mode := byte(0); mode <= 8; mode++ {
+		table[i] = [2]instruction{{kind: instCopy, size: 4, mode: mode}, {kind: instAdd, size: 1}}
+		i++
+	}
+
+	return table
+}
+
+// byteReader is the subset of reader functions needed to parse integers.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// readInt reads a variable-length integer (RFC 3284 section 2).
+func readInt(r io.ByteReader) (uint64, error) {
+	var value uint64
+	for range 10 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, ErrInvalid
+		}
+
+		if value > (1<<64-1)>>7 {
+			return 0, ErrInvalid
+		}
+
+		value = value<<7 | uint64(b&0x7f)
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+
+	return 0, ErrInvalid
+}
+
+// addressCache decodes COPY addresses (RFC 3284 section 5.3).
+type addressCache struct {
+	near     [nearCacheSize]uint64
+	nextSlot int
+	same     [sameCacheSize * 256]uint64
+}
+
+func (c *addressCache) decode(mode byte, here uint64, addresses *bytes.Reader) (uint64, error) {
+	var addr uint64
+
+	switch {
+	case mode == 0:
+		value, err := readInt(addresses)
+		if err != nil {
+			return 0, err
+		}
+
+		addr = value
+	case mode == 1:
+		value, err := readInt(addresses)
+		if err != nil {
+			return 0, err
+		}
+
+		if value > here {
+			return 0, ErrInvalid
+		}
+
+		addr = here - value
+	case mode < 2+nearCacheSize:
+		value, err := readInt(addresses)
+		if err != nil {
+			return 0, err
+		}
+
+		addr = c.near[mode-2] + value
+	case mode < 2+nearCacheSize+sameCacheSize:
+		b, err := addresses.ReadByte()
+		if err != nil {
+			return 0, ErrInvalid
+		}
+
+		addr = c.same[int(mode-2-nearCacheSize)*256+int(b)]
+	default:
+		return 0, ErrInvalid
+	}
+
+	if addr >= here {
+		return 0, ErrInvalid
+	}
+
+	c.near[c.nextSlot] = addr
+	c.nextSlot = (c.nextSlot + 1) % nearCacheSize
+	c.same[addr%(sameCacheSize*256)] = addr
+
+	return addr, nil
+}
+
+// Decode applies a delta to the source, writing the result to the target.
+// It returns the number of bytes written.
+func Decode(source io.ReaderAt, delta io.Reader, target io.Writer) (int64, error) {
+	r := bufio.NewReader(delta)
+
+	err := decodeHeader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	var written int64
+	for {
+		window, err := decodeWindow(source, r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return written, nil
+			}
+
+			return written, err
+		}
+
+		n, err := target.Write(window)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// decodeHeader checks the file header and skips the application header.
+func decodeHeader(r byteReader) error {
+	header := make([]byte, 5)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !bytes.Equal(header[:3], []byte{0xd6, 0xc3, 0xc4}) {
+		return fmt.Errorf("%w: Bad magic", ErrInvalid)
+	}
+
+	if header[3] != 0 {
+		return fmt.Errorf("%w: Version %d", ErrUnsupported, header[3])
+	}
+
+	indicator := header[4]
+	if indicator&^(hdrDecompress|hdrCodeTable|hdrAppHeader) != 0 {
+		return fmt.Errorf("%w: Bad header indicator", ErrInvalid)
+	}
+
+	if indicator&hdrDecompress != 0 {
+		return fmt.Errorf("%w: Secondary compression", ErrUnsupported)
+	}
+
+	if indicator&hdrCodeTable != 0 {
+		return fmt.Errorf("%w: Custom code table", ErrUnsupported)
+	}
+
+	if indicator&hdrAppHeader != 0 {
+		length, err := readInt(r)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.CopyN(io.Discard, r, int64(length))
+		if err != nil {
+			return ErrInvalid
+		}
+	}
+
+	return nil
+}
+
+// decodeWindow decodes the next window, returning io.EOF once all windows were decoded.
+func decodeWindow(source io.ReaderAt, r byteReader) ([]byte, error) {
+	indicator, err := r.ReadByte()
+	if err != nil {
+		return nil, io.EOF
+	}
+
+	if indicator&winTarget != 0 {
+		return nil, fmt.Errorf("%w: Target window copies", ErrUnsupported)
+	}
+
+	if indicator&^(winSource|winAdler32) != 0 {
+		return nil, fmt.Errorf("%w: Bad window indicator", ErrInvalid)
+	}
+
+	var sourceLength, sourcePosition uint64
+	if indicator&winSource != 0 {
+		sourceLength, err = readInt(r)
+		if err != nil {
+			return nil, err
+		}
+
+		sourcePosition, err = readInt(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if source == nil {
+			return nil, fmt.Errorf("%w: Missing source", ErrInvalid)
+		}
+	}
+
+	// Read the whole delta encoding.
+	deltaLength, err := readInt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if deltaLength > 3*maxWindowSize {
+		return nil, fmt.Errorf("%w: Window too large", ErrInvalid)
+	}
+
+	encoding := make([]byte, deltaLength)
+	_, err = io.ReadFull(r, encoding)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	e := bytes.NewReader(encoding)
+
+	targetLength, err := readInt(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetLength > maxWindowSize {
+		return nil, fmt.Errorf("%w: Window too large", ErrInvalid)
+	}
+
+	deltaIndicator, err := e.ReadByte()
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	if deltaIndicator != 0 {
+		return nil, fmt.Errorf("%w: Secondary compression", ErrUnsupported)
+	}
+
+	sectionLengths := make([]uint64, 3)
+	for i := range sectionLengths {
+		sectionLengths[i], err = readInt(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var checksum []byte
+	if indicator&winAdler32 != 0 {
+		checksum = make([]byte, 4)
+		_, err = io.ReadFull(e, checksum)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+	}
+
+	// Check the sections one at a time as their sum could overflow.
+	remaining := uint64(e.Len())
+	for _, length := range sectionLengths {
+		if length > remaining {
+			return nil, fmt.Errorf("%w: Bad section lengths", ErrInvalid)
+		}
+
+		remaining -= length
+	}
+
+	if remaining != 0 {
+		return nil, fmt.Errorf("%w: Bad section lengths", ErrInvalid)
+	}
+
+	rest := encoding[len(encoding)-e.Len():]
+	data := bytes.NewReader(rest[:sectionLengths[0]])
+	instructions := bytes.NewReader(rest[sectionLengths[0] : sectionLengths[0]+sectionLengths[1]])
+	addresses := bytes.NewReader(rest[sectionLengths[0]+sectionLengths[1]:])
+
+	window := make([]byte, 0, targetLength)
+	cache := addressCache{}
+
+	for instructions.Len() > 0 {
+		index, _ := instructions.ReadByte()
+
+		for _, inst := range defaultCodeTable[index] {
+			if inst.kind == instNoop {
+				continue
+			}
+
+			size := uint64(inst.size)
+			if size == 0 {
+				size, err = readInt(instructions)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if size > targetLength-uint64(len(window)) {
+				return nil, fmt.Errorf("%w: Target window overflow", ErrInvalid)
+			}
+
+			switch inst.kind {
+			case instAdd:
+				if size > uint64(data.Len()) {
+					return nil, ErrInvalid
+				}
+
+				start := len(window)
+				window = window[:start+int(size)]
+				_, _ = data.Read(window[start:])
+			case instRun:
+				b, err := data.ReadByte()
+				if err != nil {
+					return nil, ErrInvalid
+				}
+
+				for range size {
+					window = append(window, b)
+				}
+
+			case instCopy:
+				here := sourceLength + uint64(len(window))
+
+				addr, err := cache.decode(inst.mode, here, addresses)
+				if err != nil {
+					return nil, err
+				}
+
+				// Copy from the source segment.
+				if addr < sourceLength {
+					n := min(size, sourceLength-addr)
+
+					start := len(window)
+					window = window[:start+int(n)]
+					_, err = source.ReadAt(window[start:], int64(sourcePosition+addr))
+					if err != nil {
+						return nil, fmt.Errorf("Failed reading delta source: %w", err)
+					}
+
+					addr += n
+					size -= n
+				}
+
+				// Copy from the target window, which may overlap with the data being written.
+				targetAddr := addr - sourceLength
+				for size > 0 {
+					n := min(size, uint64(len(window))-targetAddr)
+					window = append(window, window[targetAddr:targetAddr+n]...)
+					targetAddr += n
+					size -= n
+				}
+			}
+		}
+	}
+
+	if uint64(len(window)) != targetLength || data.Len() != 0 || addresses.Len() != 0 {
+		return nil, fmt.Errorf("%w: Incomplete target window", ErrInvalid)
+	}
+
+	if checksum != nil && adler32.Checksum(window) != uint32(checksum[0])<<24|uint32(checksum[1])<<16|uint32(checksum[2])<<8|uint32(checksum[3]) {
+		return nil, fmt.Errorf("%w: Checksum mismatch", ErrInvalid)
+	}
+
+	return window, nil
+}
@@ -0,0 +1,208 @@
+package vcdiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/adler32"
+	"testing"
+)
+
+// appendInt appends a variable-length integer.
+func appendInt(buf []byte, value uint64) []byte {
+	digits := []byte{byte(value & 0x7f)}
+	for value >>= 7; value > 0; value >>= 7 {
+		digits = append([]byte{byte(value&0x7f) | 0x80}, digits...)
+	}
+
+	return append(buf, digits...)
+}
+
+// buildDelta returns a single window delta using the default code table.
+func buildDelta(sourceLength int, target []byte, data []byte, instructions []byte, addresses []byte, checksum bool) []byte {
+	encoding := appendInt(nil, uint64(len(target)))
+	encoding = append(encoding, 0)
+	encoding = appendInt(encoding, uint64(len(data)))
+	encoding = appendInt(encoding, uint64(len(instructions)))
+	encoding = appendInt(encoding, uint64(len(addresses)))
+
+	indicator := byte(0)
+	if sourceLength > 0 {
+		indicator |= winSource
+	}
+
+	if checksum {
+		indicator |= winAdler32
+		encoding = binary.BigEndian.AppendUint32(encoding, adler32.Checksum(target))
+	}
+
+	encoding = append(encoding, data...)
+	encoding = append(encoding, instructions...)
+	encoding = append(encoding, addresses...)
+
+	delta := []byte{0xd6, 0xc3, 0xc4, 0x00, 0x00, indicator}
+	if sourceLength > 0 {
+		delta = appendInt(delta, uint64(sourceLength))
+		delta = appendInt(delta, 0)
+	}
+
+	delta = appendInt(delta, uint64(len(encoding)))
+
+	return append(delta, encoding...)
+}
+
+func TestReadInt(t *testing.T) {
+	for _, value := range []uint64{0, 1, 127, 128, 16383, 16384, 123456789, 1<<64 - 1} {
+		got, err := readInt(bytes.NewReader(appendInt(nil, value)))
+		if err != nil {
+			t.Fatalf("readInt(%d) failed: %v", value, err)
+		}
+
+		if got != value {
+			t.Errorf("readInt(%d) returned %d", value, got)
+		}
+	}
+
+	_, err := readInt(bytes.NewReader([]byte{0x80}))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("readInt accepted a truncated integer")
+	}
+}
+
+func TestDefaultCodeTable(t *testing.T) {
+	tests := []struct {
+		index int
+		want  [2]instruction
+	}{
+		{0, [2]instruction{{kind: instRun}}},
+		{1, [2]instruction{{kind: instAdd}}},
+		{18, [2]instruction{{kind: instAdd, size: 17}}},
+		{19, [2]instruction{{kind: instCopy}}},
+		{34, [2]instruction{{kind: instCopy, size: 18}}},
+		{162, [2]instruction{{kind: instCopy, size: 18, mode: 8}}},
+		{163, [2]instruction{{kind: instAdd, size: 1}, {kind: instCopy, size: 4}}},
+		{234, [2]instruction{{kind: instAdd, size: 4}, {kind: instCopy, size: 6, mode: 5}}},
+		{246, [2]instruction{{kind: instAdd, size: 4}, {kind: instCopy, size: 4, mode: 8}}},
+		{247, [2]instruction{{kind: instCopy, size: 4}, {kind: instAdd, size: 1}}},
+		{255, [2]instruction{{kind: instCopy, size: 4, mode: 8}, {kind: instAdd, size: 1}}},
+	}
+
+	for _, tt := range tests {
+		if defaultCodeTable[tt.index] != tt.want {
+			t.Errorf("Code table entry %d is %v, expected %v", tt.index, defaultCodeTable[tt.index], tt.want)
+		}
+	}
+}
+
+// sectionsDelta returns a single window delta without source and with the given section lengths and no sections.
+func sectionsDelta(data uint64, instructions uint64, addresses uint64) []byte {
+	encoding := appendInt(nil, 0)
+	encoding = append(encoding, 0)
+	encoding = appendInt(encoding, data)
+	encoding = appendInt(encoding, instructions)
+	encoding = appendInt(encoding, addresses)
+
+	delta := []byte{0xd6, 0xc3, 0xc4, 0x00, 0x00, 0x00}
+	delta = appendInt(delta, uint64(len(encoding)))
+
+	return append(delta, encoding...)
+}
+
+func TestDecode(t *testing.T) {
+	source := []byte("abcdefghijklmnop")
+
+	tests := []struct {
+		name         string
+		target       string
+		data         string
+		instructions []byte
+		addresses    []byte
+	}{
+		{
+			// COPY 10 from the source, ADD "XYZ", RUN 4 "Z" and an overlapping COPY 9 from the target (VCD_HERE).
+			name:         "mixed",
+			target:       "abcdefghijXYZZZZZXYZZZZZXY",
+			data:         "XYZZ",
+			instructions: []byte{26, 4, 0, 4, 41},
+			addresses:    []byte{0, 7},
+		},
+		{
+			// COPY 4 from the source, then the same address through the same cache.
+			name:         "same cache",
+			target:       "ijklijkl",
+			instructions: []byte{20, 116},
+			addresses:    []byte{8, 8},
+		},
+		{
+			// ADD 1 + COPY 4 from the near cache, then COPY 4 (VCD_SELF) + ADD 1.
+			name:         "double instructions",
+			target:       "!bcdeabcd?",
+			data:         "!?",
+			instructions: []byte{187, 247},
+			addresses:    []byte{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := buildDelta(len(source), []byte(tt.target), []byte(tt.data), tt.instructions, tt.addresses, true)
+
+			var target bytes.Buffer
+			n, err := Decode(bytes.NewReader(source), bytes.NewReader(delta), &target)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+
+			if target.String() != tt.target || n != int64(len(tt.target)) {
+				t.Errorf("Decode returned %q (%d bytes), expected %q", target.String(), n, tt.target)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	source := []byte("abcdefghijklmnop")
+	valid := buildDelta(len(source), []byte("abcd"), nil, []byte{20}, []byte{0}, true)
+
+	badChecksum := bytes.Clone(valid)
+	badChecksum[len(badChecksum)-3]++
+
+	tests := []struct {
+		name  string
+		delta []byte
+		err   error
+	}{
+		{"bad magic", []byte{0xd6, 0xc3, 0xc5, 0x00, 0x00}, ErrInvalid},
+		{"secondary compression", []byte{0xd6, 0xc3, 0xc4, 0x00, hdrDecompress, 0x01}, ErrUnsupported},
+		{"custom code table", []byte{0xd6, 0xc3, 0xc4, 0x00, hdrCodeTable}, ErrUnsupported},
+		{"truncated", valid[:len(valid)-1], ErrInvalid},
+		{"checksum mismatch", badChecksum, ErrInvalid},
+		{"copy out of range", buildDelta(len(source), []byte("abcd"), nil, []byte{20}, []byte{16}, false), ErrInvalid},
+		{"target overflow", buildDelta(len(source), []byte("abcd"), nil, []byte{21}, []byte{0}, false), ErrInvalid},
+		{"section lengths overflow", sectionsDelta(1<<64-1, 1, 0), ErrInvalid},
+		{"section lengths wrap", sectionsDelta(1<<63, 1<<63, 0), ErrInvalid},
+		{"section too long", sectionsDelta(0, 0, 1), ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(source), bytes.NewReader(tt.delta), &bytes.Buffer{})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Decode returned %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	source := []byte("abcdefghijklmnop")
+
+	f.Add(buildDelta(len(source), []byte("abcdefghijXYZZZZZXYZZZZZXY"), []byte("XYZZ"), []byte{26, 4, 0, 4, 41}, []byte{0, 7}, true))
+	f.Add(buildDelta(len(source), []byte("ijklijkl"), nil, []byte{20, 116}, []byte{8, 8}, false))
+	f.Add(sectionsDelta(1<<64-1, 1, 0))
+
+	f.Fuzz(func(t *testing.T, delta []byte) {
+		// Invalid deltas must be rejected without panicking.
+		_, _ = Decode(bytes.NewReader(source), bytes.NewReader(delta), &bytes.Buffer{})
+	})
+}